@@ -0,0 +1,212 @@
+package crema
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultAbsentFilterExpectedKeys      = 100_000
+	defaultAbsentFilterFalsePositiveRate = 0.01
+	defaultAbsentFilterRotateInterval    = time.Minute
+)
+
+// AbsentKeyFilterPolicy configures WithAbsentKeyFilter.
+type AbsentKeyFilterPolicy[V any] struct {
+	// IsAbsent reports whether a loaded or cached value means the key does
+	// not exist. Defaults to the zero value check used for negative caching.
+	IsAbsent func(value V) bool
+	// ExpectedKeys is the number of absent keys a filter generation is sized
+	// for; a generation that reaches it is retired early. Defaults to 100000.
+	ExpectedKeys int
+	// FalsePositiveRate is the target rate of existing keys wrongly reported
+	// as absent at ExpectedKeys. Defaults to 0.01.
+	FalsePositiveRate float64
+	// RotateInterval is how long a filter generation collects keys before it
+	// is retired; keys are forgotten one to two intervals after they were
+	// last seen absent, or sooner when ExpectedKeys absent keys arrive within
+	// an interval. Defaults to one minute.
+	RotateInterval time.Duration
+}
+
+// absentFilter is a bloom filter of absent keys rotating between two
+// generations, so keys age out without deletes.
+type absentFilter[V any] struct {
+	isAbsent       func(value V) bool
+	words          int
+	hashes         int
+	expectedKeys   int64
+	rotateInterval time.Duration
+	mu             sync.Mutex
+	current        atomic.Pointer[bloomBits]
+	previous       atomic.Pointer[bloomBits]
+	rotateAt       atomic.Int64
+	// added counts the keys recorded in the current generation.
+	added atomic.Int64
+}
+
+type bloomBits []atomic.Uint64
+
+// WithAbsentKeyFilter keeps a rotating bloom filter of keys known not to
+// exist, populated from negative results: loaded or cached values policy
+// reports as absent. GetOrLoad calls that miss in the provider for a key in
+// the filter return the zero value without running the loader, cheaply
+// absorbing enumeration-style traffic for nonexistent IDs; entries the
+// provider holds are always served. Each filtered call is reported through
+// RecordAbsentKeyFiltered. Set and Delete do not remove keys from the
+// filter, so a key that starts to exist without being stored is served as
+// absent until its filter generations rotate out, and other missing keys
+// skip their load at about FalsePositiveRate.
+func WithAbsentKeyFilter[V any, S any](policy AbsentKeyFilterPolicy[V]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.absentFilter = newAbsentFilter(policy)
+	}
+}
+
+func newAbsentFilter[V any](policy AbsentKeyFilterPolicy[V]) *absentFilter[V] {
+	if policy.IsAbsent == nil {
+		policy.IsAbsent = func(value V) bool {
+			return reflect.ValueOf(&value).Elem().IsZero()
+		}
+	}
+	if policy.ExpectedKeys <= 0 {
+		policy.ExpectedKeys = defaultAbsentFilterExpectedKeys
+	}
+	if policy.FalsePositiveRate <= 0 || policy.FalsePositiveRate >= 1 {
+		policy.FalsePositiveRate = defaultAbsentFilterFalsePositiveRate
+	}
+	if policy.RotateInterval <= 0 {
+		policy.RotateInterval = defaultAbsentFilterRotateInterval
+	}
+	bits := math.Ceil(-float64(policy.ExpectedKeys) * math.Log(policy.FalsePositiveRate) / (math.Ln2 * math.Ln2))
+	f := &absentFilter[V]{
+		isAbsent:       policy.IsAbsent,
+		words:          int(math.Ceil(bits / 64)),
+		hashes:         max(1, int(math.Round(bits/float64(policy.ExpectedKeys)*math.Ln2))),
+		expectedKeys:   int64(policy.ExpectedKeys),
+		rotateInterval: policy.RotateInterval,
+	}
+	f.current.Store(f.newBits())
+	f.previous.Store(f.newBits())
+
+	return f
+}
+
+func (f *absentFilter[V]) newBits() *bloomBits {
+	bits := make(bloomBits, f.words)
+
+	return &bits
+}
+
+// rotate retires the previous generation once the current one has collected
+// keys for the rotate interval, and both once they are older than that.
+func (f *absentFilter[V]) rotate(now time.Time) {
+	rotateAt := f.rotateAt.Load()
+	if rotateAt != 0 && now.UnixNano() < rotateAt {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch rotateAt = f.rotateAt.Load(); {
+	case rotateAt == 0:
+	case now.UnixNano() < rotateAt:
+		return
+	case now.UnixNano() < rotateAt+int64(f.rotateInterval):
+		f.previous.Store(f.current.Load())
+		f.current.Store(f.newBits())
+	default:
+		// both generations are older than the interval
+		f.previous.Store(f.newBits())
+		f.current.Store(f.newBits())
+	}
+	f.added.Store(0)
+	f.rotateAt.Store(now.Add(f.rotateInterval).UnixNano())
+}
+
+// rotateFull retires the previous generation once the current one holds
+// expectedKeys keys, so a burst of absent keys cannot push its false-positive
+// rate past the configured one.
+func (f *absentFilter[V]) rotateFull(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.added.Load() < f.expectedKeys {
+		return
+	}
+	f.previous.Store(f.current.Load())
+	f.current.Store(f.newBits())
+	f.added.Store(0)
+	f.rotateAt.Store(now.Add(f.rotateInterval).UnixNano())
+}
+
+// positions calls fn with the bit positions of key until fn returns false.
+func (f *absentFilter[V]) positions(key string, fn func(word int, mask uint64) bool) {
+	h := hashKey(key)
+	h1, h2 := h&math.MaxUint32, h>>32|1
+	size := uint64(f.words) * 64
+	for i := range uint64(f.hashes) {
+		bit := (h1 + i*h2) % size
+		if !fn(int(bit/64), 1<<(bit%64)) {
+			return
+		}
+	}
+}
+
+// add records key as absent.
+func (f *absentFilter[V]) add(now time.Time, key string) {
+	f.rotate(now)
+	bits := *f.current.Load()
+	added := false
+	f.positions(key, func(word int, mask uint64) bool {
+		if bits[word].Load()&mask == 0 {
+			bits[word].Or(mask)
+			added = true
+		}
+
+		return true
+	})
+	if added && f.added.Add(1) >= f.expectedKeys {
+		f.rotateFull(now)
+	}
+}
+
+// contains reports whether key may have been recorded as absent in either
+// generation.
+func (f *absentFilter[V]) contains(now time.Time, key string) bool {
+	f.rotate(now)
+
+	return f.containsIn(*f.current.Load(), key) || f.containsIn(*f.previous.Load(), key)
+}
+
+func (f *absentFilter[V]) containsIn(bits bloomBits, key string) bool {
+	found := true
+	f.positions(key, func(word int, mask uint64) bool {
+		found = bits[word].Load()&mask != 0
+
+		return found
+	})
+
+	return found
+}
+
+// filterAbsent reports whether the absent key filter holds key, recording
+// the filtered call.
+func (c *cacheImpl[V, S]) filterAbsent(ctx context.Context, key string) bool {
+	if c.absentFilter == nil || !c.absentFilter.contains(c.now(), key) {
+		return false
+	}
+	c.metrics.RecordAbsentKeyFiltered(ctx)
+
+	return true
+}
+
+// observeAbsent adds key to the absent key filter when value reports it as
+// not existing.
+func (c *cacheImpl[V, S]) observeAbsent(key string, value V) {
+	if c.absentFilter != nil && c.absentFilter.isAbsent(value) {
+		c.absentFilter.add(c.now(), key)
+	}
+}
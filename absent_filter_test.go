@@ -0,0 +1,157 @@
+package crema
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type absentFilterMetricsProvider struct {
+	BaseMetricsProvider
+	filtered atomic.Int32
+}
+
+func (m *absentFilterMetricsProvider) RecordAbsentKeyFiltered(context.Context) {
+	m.filtered.Add(1)
+}
+
+func TestWithAbsentKeyFilter(t *testing.T) {
+	t.Parallel()
+
+	metrics := &absentFilterMetricsProvider{}
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithAbsentKeyFilter[int, CacheObject[int]](AbsentKeyFilterPolicy[int]{RotateInterval: time.Minute}),
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(1)
+	ctx := context.Background()
+
+	loads := 0
+	loader := func(context.Context) (int, error) {
+		loads++
+
+		return 0, nil
+	}
+	if _, err := cache.GetOrLoad(ctx, "missing", time.Minute, loader); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	delete(provider.items, "missing")
+	for range 3 {
+		if v, err := cache.GetOrLoad(ctx, "missing", time.Minute, loader); err != nil || v != 0 {
+			t.Fatalf("expected filtered zero value, got %d, %v", v, err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected 1 load, got %d", loads)
+	}
+	if got := metrics.filtered.Load(); got != 3 {
+		t.Fatalf("expected 3 filtered calls, got %d", got)
+	}
+
+	now = now.Add(2*time.Minute + time.Second)
+	if _, err := cache.GetOrLoad(ctx, "missing", time.Minute, loader); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loads != 2 {
+		t.Fatalf("expected key to age out after two rotations, got %d loads", loads)
+	}
+}
+
+func TestWithAbsentKeyFilterIgnoresExistingKeys(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(NewMemoryCacheProvider[[]byte](), JSONByteStringCodec[string]{},
+		WithAbsentKeyFilter[string, []byte](AbsentKeyFilterPolicy[string]{
+			IsAbsent: func(value string) bool { return value == "not-found" },
+		}),
+	)
+	ctx := context.Background()
+	for i := range 100 {
+		key := fmt.Sprintf("key-%d", i)
+		if _, err := cache.GetOrLoad(ctx, key, time.Minute, func(context.Context) (string, error) {
+			return key, nil
+		}); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	}
+	if _, err := cache.GetOrLoad(ctx, "absent", time.Minute, func(context.Context) (string, error) {
+		return "not-found", nil
+	}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := cache.(*cacheImpl[string, []byte]).filterAbsent(ctx, "key-1"); got {
+		t.Fatal("expected existing key not to be filtered")
+	}
+	if got := cache.(*cacheImpl[string, []byte]).filterAbsent(ctx, "absent"); !got {
+		t.Fatal("expected absent key to be filtered")
+	}
+}
+
+func TestAbsentFilterFalsePositiveRate(t *testing.T) {
+	t.Parallel()
+
+	filter := newAbsentFilter(AbsentKeyFilterPolicy[int]{ExpectedKeys: 10_000, FalsePositiveRate: 0.01})
+	now := time.Now()
+	for i := range 10_000 {
+		filter.add(now, fmt.Sprintf("absent-%d", i))
+	}
+	falsePositives := 0
+	for i := range 10_000 {
+		if filter.contains(now, fmt.Sprintf("present-%d", i)) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 300 {
+		t.Fatalf("expected about 1%% false positives, got %d of 10000", falsePositives)
+	}
+}
+
+func TestWithAbsentKeyFilterServesStoredEntries(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithAbsentKeyFilter[int, CacheObject[int]](AbsentKeyFilterPolicy[int]{}),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(1)
+	ctx := context.Background()
+
+	impl.absentFilter.add(now, "key")
+	provider.items["key"] = CacheObject[int]{Value: 7, ExpireAtMillis: now.Add(time.Minute).UnixMilli()}
+	v, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+		t.Fatal("expected no load")
+
+		return 0, nil
+	})
+	if err != nil || v != 7 {
+		t.Fatalf("expected stored value 7, got %d, %v", v, err)
+	}
+}
+
+func TestAbsentFilterRotatesWhenFull(t *testing.T) {
+	t.Parallel()
+
+	filter := newAbsentFilter(AbsentKeyFilterPolicy[int]{ExpectedKeys: 1_000, FalsePositiveRate: 0.01})
+	now := time.Now()
+	for i := range 50_000 {
+		filter.add(now, fmt.Sprintf("absent-%d", i))
+	}
+	falsePositives := 0
+	for i := range 10_000 {
+		if filter.contains(now, fmt.Sprintf("present-%d", i)) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 500 {
+		t.Fatalf("expected the false-positive rate to stay bounded, got %d of 10000", falsePositives)
+	}
+}
@@ -0,0 +1,153 @@
+package crema
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAdaptiveTTLFactor    = 2
+	defaultAdaptiveTTLMaxGroups = 10000
+)
+
+// AdaptiveTTLPolicy configures WithAdaptiveTTL.
+type AdaptiveTTLPolicy[V any] struct {
+	// MinTTL and MaxTTL bound the adapted ttl. MaxTTL must be positive;
+	// a non-positive MinTTL lets volatile groups shrink to the requested
+	// ttl divided by Factor repeatedly, down to one millisecond.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+	// Factor multiplies a group's ttl when a reload returns an unchanged
+	// value and divides it when the value changed. Values below or equal to
+	// 1 default to 2.
+	Factor float64
+	// GroupFunc maps a key to the group sharing one adapted ttl, such as a
+	// key prefix. Nil adapts each key on its own.
+	GroupFunc func(key string) string
+	// Hash fingerprints values to detect changes. Nil hashes the JSON
+	// encoding of the value; values that fail to encode are not compared.
+	Hash func(value V) (uint64, error)
+	// MaxGroups caps the number of tracked groups. When exceeded, tracking
+	// restarts from the requested ttl. Zero defaults to 10000.
+	MaxGroups int
+}
+
+// adaptiveTTL tracks the adapted ttl of each key group.
+type adaptiveTTL[V any] struct {
+	policy AdaptiveTTLPolicy[V]
+	mu     sync.Mutex
+	groups map[string]time.Duration
+}
+
+// WithAdaptiveTTL adjusts the ttl of loaded values from how often a reload
+// actually changes them. Each revalidation compares the hash of the reloaded
+// value with the one it replaces: an unchanged value grows its group's ttl by
+// Factor up to MaxTTL, a changed one shrinks it down to MinTTL. Stable keys
+// therefore get longer ttls automatically and volatile keys shorter ones,
+// starting from the ttl passed to GetOrLoad. Misses carry no previous value
+// and keep the group's current ttl. A policy without a positive MaxTTL
+// disables it.
+func WithAdaptiveTTL[V any, S any](policy AdaptiveTTLPolicy[V]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if policy.MaxTTL <= 0 {
+			c.adaptiveTTL = nil
+
+			return
+		}
+		if policy.Factor <= 1 {
+			policy.Factor = defaultAdaptiveTTLFactor
+		}
+		if policy.Hash == nil {
+			policy.Hash = hashJSON[V]
+		}
+		if policy.MaxGroups <= 0 {
+			policy.MaxGroups = defaultAdaptiveTTLMaxGroups
+		}
+		c.adaptiveTTL = &adaptiveTTL[V]{policy: policy, groups: make(map[string]time.Duration)}
+	}
+}
+
+// adapt returns the ttl for a value loaded for key, updating its group from
+// whether value differs from prev. ttl is the requested ttl, used for
+// groups seen for the first time.
+func (a *adaptiveTTL[V]) adapt(key string, ttl time.Duration, prev *CacheObject[V], value V) (time.Duration, bool) {
+	group := key
+	if a.policy.GroupFunc != nil {
+		group = a.policy.GroupFunc(key)
+	}
+	changed, compared := a.changed(prev, value)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	current, ok := a.groups[group]
+	if !ok {
+		current = a.clamp(ttl)
+	}
+	if !compared {
+		return current, false
+	}
+	factor := a.policy.Factor
+	if changed {
+		factor = 1 / factor
+	}
+	adapted := a.clamp(time.Duration(float64(current) * factor))
+	if !ok && len(a.groups) >= a.policy.MaxGroups {
+		clear(a.groups)
+	}
+	a.groups[group] = adapted
+
+	return adapted, adapted != current
+}
+
+// changed reports whether value differs from prev, and false for compared
+// when there is nothing to compare.
+func (a *adaptiveTTL[V]) changed(prev *CacheObject[V], value V) (changed bool, compared bool) {
+	if prev == nil {
+		return false, false
+	}
+	prevHash, err := a.policy.Hash(prev.Value)
+	if err != nil {
+		return false, false
+	}
+	hash, err := a.policy.Hash(value)
+	if err != nil {
+		return false, false
+	}
+
+	return prevHash != hash, true
+}
+
+func (a *adaptiveTTL[V]) clamp(ttl time.Duration) time.Duration {
+	ttl = max(ttl, time.Millisecond, a.policy.MinTTL)
+
+	return min(ttl, a.policy.MaxTTL)
+}
+
+// adaptTTL returns the ttl for a value loaded for key, adapted by the
+// configured policy.
+func (c *cacheImpl[V, S]) adaptTTL(key string, ttl time.Duration, prev *CacheObject[V], value V) time.Duration {
+	if c.adaptiveTTL == nil || !c.experimentEnabled(ExperimentAdaptiveTTL, key) {
+		return ttl
+	}
+	adapted, moved := c.adaptiveTTL.adapt(key, ttl, prev, value)
+	if moved {
+		c.logger.Debug("adapted cache ttl", slog.String("key", key),
+			slog.Duration("requested", ttl), slog.Duration("ttl", adapted))
+	}
+
+	return adapted
+}
+
+func hashJSON[V any](value V) (uint64, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+
+	return h.Sum64(), nil
+}
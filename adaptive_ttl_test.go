@@ -0,0 +1,82 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithAdaptiveTTL_GrowsStableAndShrinksVolatileValues(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithAdaptiveTTL[int, CacheObject[int]](AdaptiveTTLPolicy[int]{MinTTL: 5 * time.Second, MaxTTL: 40 * time.Second}),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(1)
+
+	value := 1
+	loader := func(context.Context) (int, error) {
+		return value, nil
+	}
+	ctx := context.Background()
+	steps := []struct {
+		value   int
+		wantTTL time.Duration
+	}{
+		{value: 1, wantTTL: 10 * time.Second},
+		{value: 1, wantTTL: 20 * time.Second},
+		{value: 1, wantTTL: 40 * time.Second},
+		{value: 1, wantTTL: 40 * time.Second},
+		{value: 2, wantTTL: 20 * time.Second},
+		{value: 3, wantTTL: 10 * time.Second},
+		{value: 4, wantTTL: 5 * time.Second},
+		{value: 5, wantTTL: 5 * time.Second},
+	}
+	for i, step := range steps {
+		value = step.value
+		if _, err := cache.GetOrLoad(ctx, "key", 10*time.Second, loader); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		provider.mu.Lock()
+		got := time.UnixMilli(provider.items["key"].ExpireAtMillis).Sub(now)
+		provider.mu.Unlock()
+		if got != step.wantTTL {
+			t.Fatalf("step %d: expected ttl %v, got %v", i, step.wantTTL, got)
+		}
+		now = now.Add(got + time.Second)
+	}
+}
+
+func TestAdaptiveTTL_SharesGroups(t *testing.T) {
+	t.Parallel()
+
+	a := &adaptiveTTL[string]{
+		policy: AdaptiveTTLPolicy[string]{
+			MaxTTL:    time.Hour,
+			Factor:    2,
+			GroupFunc: func(key string) string { return key[:1] },
+			Hash:      hashJSON[string],
+			MaxGroups: 1,
+		},
+		groups: make(map[string]time.Duration),
+	}
+	if ttl, moved := a.adapt("a1", time.Minute, nil, "x"); ttl != time.Minute || moved {
+		t.Fatalf("expected unchanged ttl on a miss, got %v, %v", ttl, moved)
+	}
+	if ttl, _ := a.adapt("a1", time.Minute, &CacheObject[string]{Value: "x"}, "x"); ttl != 2*time.Minute {
+		t.Fatalf("expected 2m, got %v", ttl)
+	}
+	if ttl, _ := a.adapt("a2", time.Minute, &CacheObject[string]{Value: "y"}, "y"); ttl != 4*time.Minute {
+		t.Fatalf("expected group ttl 4m, got %v", ttl)
+	}
+	if ttl, _ := a.adapt("b1", time.Minute, &CacheObject[string]{Value: "y"}, "y"); ttl != 2*time.Minute {
+		t.Fatalf("expected new group to start from the requested ttl, got %v", ttl)
+	}
+	if len(a.groups) != 1 {
+		t.Fatalf("expected groups to be capped at 1, got %d", len(a.groups))
+	}
+}
@@ -0,0 +1,225 @@
+package crema
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// AdminProvider is an optional CacheProvider extension for enumerating stored
+// entries. Caches use it for operational tooling such as Export.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type AdminProvider[S any] interface {
+	CacheProvider[S]
+	// Scan calls fn for every stored entry whose key matches pattern until fn
+	// returns false. The pattern supports '*' and '?' wildcards, and an empty
+	// pattern matches every key. Entries written during a scan may be missed.
+	Scan(ctx context.Context, pattern string, fn func(key string, value S) bool) error
+}
+
+const dumpFormatVersion = 1
+
+var (
+	// ErrAdminNotSupported is returned when the provider does not implement AdminProvider.
+	ErrAdminNotSupported = errors.New("cache provider does not support admin operations")
+	// ErrDumpVersionMismatch is returned when an import stream has an unsupported format version.
+	ErrDumpVersionMismatch = errors.New("unsupported cache dump version")
+)
+
+type dumpHeader struct {
+	Version int `json:"version"`
+}
+
+type dumpRecord[V any] struct {
+	Key            string `json:"key"`
+	ExpireAtMillis int64  `json:"expireAtMillis"`
+	Value          V      `json:"value"`
+}
+
+// Export streams every unexpired entry as newline-delimited JSON, preceded by
+// a version header line. Values must be JSON-marshalable. Refresh markers,
+// tombstones and load locks are not entries and are skipped.
+func (c *cacheImpl[V, S]) Export(ctx context.Context, w io.Writer) error {
+	admin, ok := providerAs[AdminProvider[S]](c.provider)
+	if !ok {
+		return ErrAdminNotSupported
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(dumpHeader{Version: dumpFormatVersion}); err != nil {
+		return err
+	}
+
+	nowMillis := c.now().UnixMilli()
+	var exportErr error
+	err := admin.Scan(ctx, "", func(key string, value S) bool {
+		if err := ctx.Err(); err != nil {
+			exportErr = err
+
+			return false
+		}
+		if isMarkerKey(key) {
+			return true
+		}
+		co, err := c.decode(key, value)
+		if err != nil {
+			exportErr = fmt.Errorf("decode %q: %w", key, err)
+
+			return false
+		}
+		if co.ExpireAtMillis <= nowMillis {
+			return true
+		}
+		if err := enc.Encode(dumpRecord[V]{Key: key, ExpireAtMillis: co.ExpireAtMillis, Value: co.Value}); err != nil {
+			exportErr = err
+
+			return false
+		}
+
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if exportErr != nil {
+		return exportErr
+	}
+
+	return bw.Flush()
+}
+
+// Import reads a stream produced by Export and stores each entry with its
+// original expiry. Entries that expired since the export are skipped.
+func (c *cacheImpl[V, S]) Import(ctx context.Context, r io.Reader) error {
+	dec := json.NewDecoder(r)
+	var header dumpHeader
+	if err := dec.Decode(&header); err != nil {
+		return err
+	}
+	if header.Version != dumpFormatVersion {
+		return fmt.Errorf("%w: %d", ErrDumpVersionMismatch, header.Version)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var record dumpRecord[V]
+		if err := dec.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+		co := CacheObject[V]{Value: record.Value, ExpireAtMillis: record.ExpireAtMillis}
+		if err := c.Set(ctx, record.Key, co); err != nil {
+			return fmt.Errorf("set %q: %w", record.Key, err)
+		}
+	}
+}
+
+// Entries returns an iterator over the unexpired entries whose key matches
+// pattern, as AdminProvider.Scan does, decoding each entry only when it is
+// reached so tooling can stream over large keyspaces. Breaking out of the
+// loop or canceling ctx stops the scan. Entries that fail to decode and
+// the cache's own marker entries are skipped. Since an iterator cannot
+// return an error, a provider that does not implement AdminProvider or a
+// failed scan ends the iteration and is reported to the error observer with
+// ErrorPhaseProviderScan and logged.
+func (c *cacheImpl[V, S]) Entries(ctx context.Context, pattern string) iter.Seq2[string, CacheObject[V]] {
+	return func(yield func(string, CacheObject[V]) bool) {
+		started := time.Now()
+		admin, ok := providerAs[AdminProvider[S]](c.provider)
+		if !ok {
+			c.observeScanError(ctx, pattern, started, ErrAdminNotSupported)
+
+			return
+		}
+		nowMillis := c.now().UnixMilli()
+		err := admin.Scan(ctx, pattern, func(key string, value S) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+			if isMarkerKey(key) {
+				return true
+			}
+			decodeStarted := time.Now()
+			co, err := c.decode(key, value)
+			if err != nil {
+				_ = c.observeError(ctx, ErrorPhaseDecode, key, decodeStarted, err)
+
+				return true
+			}
+			if co.ExpireAtMillis <= nowMillis {
+				return true
+			}
+
+			return yield(key, co)
+		})
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			c.observeScanError(ctx, pattern, started, err)
+		}
+	}
+}
+
+// observeScanError reports a scan of pattern that failed with err.
+func (c *cacheImpl[V, S]) observeScanError(ctx context.Context, pattern string, started time.Time, err error) {
+	c.logger.Warn("failed to scan entries", slog.String("pattern", pattern), slog.String("error", err.Error()))
+	_ = c.observeError(ctx, ErrorPhaseProviderScan, pattern, started, err)
+}
+
+// isMarkerKey reports whether key holds one of the sibling markers the
+// cache stores next to entries.
+func isMarkerKey(key string) bool {
+	return strings.HasSuffix(key, refreshMarkerSuffix) ||
+		strings.HasSuffix(key, tombstoneSuffix) ||
+		strings.HasSuffix(key, loadLockSuffix)
+}
+
+// MatchKeyPattern reports whether key matches a glob pattern with '*' (any
+// sequence) and '?' (any single byte) wildcards. An empty pattern matches
+// every key. It is intended for AdminProvider implementations without a
+// native pattern matcher.
+func MatchKeyPattern(pattern string, key string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	p, k := 0, 0
+	starP, starK := -1, 0
+	for k < len(key) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == key[k]):
+			p++
+			k++
+		case p < len(pattern) && pattern[p] == '*':
+			starP = p
+			starK = k
+			p++
+		case starP >= 0:
+			p = starP + 1
+			starK++
+			k = starK
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
@@ -0,0 +1,157 @@
+package crema
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_ExportImport(t *testing.T) {
+	t.Parallel()
+
+	source := &testMemoryProvider[string]{items: map[string]CacheObject[string]{
+		"a":                       {Value: "alpha", ExpireAtMillis: 5000},
+		"b":                       {Value: "<b>", ExpireAtMillis: 6000},
+		"expired":                 {Value: "gone", ExpireAtMillis: 500},
+		"a" + refreshMarkerSuffix: {ExpireAtMillis: 5000},
+		"b" + tombstoneSuffix:     {ExpireAtMillis: 6000},
+		"b" + loadLockSuffix:      {ExpireAtMillis: 6000},
+	}}
+	cache := NewCache(source, NoopCacheStorageCodec[string]{})
+	cache.(*cacheImpl[string, CacheObject[string]]).now = func() time.Time { return time.UnixMilli(1000) }
+
+	var buf bytes.Buffer
+	if err := cache.Export(context.Background(), &buf); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	want := "{\"version\":1}\n" +
+		"{\"key\":\"a\",\"expireAtMillis\":5000,\"value\":\"alpha\"}\n" +
+		"{\"key\":\"b\",\"expireAtMillis\":6000,\"value\":\"<b>\"}\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected dump:\n%s", buf.String())
+	}
+
+	target := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	restored := NewCache(target, NoopCacheStorageCodec[string]{})
+	restored.(*cacheImpl[string, CacheObject[string]]).now = func() time.Time { return time.UnixMilli(5500) }
+	if err := restored.Import(context.Background(), &buf); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(target.items) != 1 {
+		t.Fatalf("expected only unexpired entries to be imported, got %v", target.items)
+	}
+	if got := target.items["b"]; got.Value != "<b>" || got.ExpireAtMillis != 6000 {
+		t.Fatalf("unexpected imported entry: %+v", got)
+	}
+}
+
+func TestCache_ExportRequiresAdminProvider(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache[int](NewNoopCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{})
+	err := cache.Export(context.Background(), &bytes.Buffer{})
+	if !errors.Is(err, ErrAdminNotSupported) {
+		t.Fatalf("expected ErrAdminNotSupported, got %v", err)
+	}
+}
+
+func TestCache_ImportVersionMismatch(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	err := cache.Import(context.Background(), strings.NewReader("{\"version\":99}\n"))
+	if !errors.Is(err, ErrDumpVersionMismatch) {
+		t.Fatalf("expected ErrDumpVersionMismatch, got %v", err)
+	}
+}
+
+func TestMatchKeyPattern(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"", "anything", true},
+		{"*", "", true},
+		{"user:*", "user:1", true},
+		{"user:*", "item:1", false},
+		{"user:?:profile", "user:1:profile", true},
+		{"user:?:profile", "user:12:profile", false},
+		{"*:profile", "user:12:profile", true},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+	}
+	for _, tc := range cases {
+		if got := MatchKeyPattern(tc.pattern, tc.key); got != tc.want {
+			t.Errorf("MatchKeyPattern(%q, %q) = %v, want %v", tc.pattern, tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestCache_Entries(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: map[string]CacheObject[string]{
+		"user:1":                   {Value: "alice", ExpireAtMillis: 5000},
+		"user:2":                   {Value: "bob", ExpireAtMillis: 6000},
+		"user:3":                   {Value: "gone", ExpireAtMillis: 500},
+		"user:1" + tombstoneSuffix: {ExpireAtMillis: 5000},
+		"item:1":                   {Value: "book", ExpireAtMillis: 5000},
+	}}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{})
+	cache.(*cacheImpl[string, CacheObject[string]]).now = func() time.Time { return time.UnixMilli(1000) }
+
+	got := make(map[string]string)
+	for key, co := range cache.Entries(context.Background(), "user:*") {
+		got[key] = co.Value
+	}
+	if len(got) != 2 || got["user:1"] != "alice" || got["user:2"] != "bob" {
+		t.Fatalf("expected the unexpired user entries, got %v", got)
+	}
+
+	seen := 0
+	for range cache.Entries(context.Background(), "") {
+		seen++
+
+		break
+	}
+	if seen != 1 {
+		t.Fatalf("expected the iteration to stop after break, got %d entries", seen)
+	}
+}
+
+func TestCache_EntriesReportsUnsupportedProvider(t *testing.T) {
+	t.Parallel()
+
+	var events []ErrorEvent
+	cache := NewCache[int](NewNoopCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{},
+		WithErrorObserver[int, CacheObject[int]](func(_ context.Context, event ErrorEvent) {
+			events = append(events, event)
+		}),
+	)
+	for key := range cache.Entries(context.Background(), "*") {
+		t.Fatalf("expected no entries, got %q", key)
+	}
+	if len(events) != 1 || events[0].Phase != ErrorPhaseProviderScan || !errors.Is(events[0].Err, ErrAdminNotSupported) {
+		t.Fatalf("expected an unsupported scan to be observed, got %+v", events)
+	}
+}
+
+func TestMarkerKeySuffixes_AreMemcacheLegal(t *testing.T) {
+	t.Parallel()
+
+	for _, suffix := range []string{loadLockSuffix, refreshMarkerSuffix, tombstoneSuffix} {
+		for i := 0; i < len(suffix); i++ {
+			// memcache rejects keys holding spaces, control characters or DEL
+			if suffix[i] <= ' ' || suffix[i] == 0x7f {
+				t.Fatalf("expected memcache-legal suffix, got byte %#x in %q", suffix[i], suffix)
+			}
+		}
+	}
+}
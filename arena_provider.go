@@ -0,0 +1,322 @@
+package crema
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+const (
+	defaultArenaChunkSize = 1 << 20
+	arenaKeyLenSize       = 4
+)
+
+// ArenaCacheProviderOption customizes the ArenaCacheProvider.
+type ArenaCacheProviderOption func(*ArenaCacheProvider)
+
+// arenaSlot locates a record in the arena. It holds no pointers, so the
+// index map is not scanned by the garbage collector.
+type arenaSlot struct {
+	chunk    uint32
+	offset   uint32
+	keyLen   uint32
+	valueLen uint32
+	// expireAt is the expiry in Unix nanoseconds, or zero for none.
+	expireAt int64
+}
+
+// size returns the number of arena bytes the record occupies.
+func (s arenaSlot) size() int {
+	return arenaKeyLenSize + int(s.keyLen) + int(s.valueLen)
+}
+
+type arenaChunk struct {
+	data []byte
+	// live is the number of bytes of data still referenced by the index.
+	live int
+}
+
+// ArenaCacheProvider stores []byte entries packed into large append-only
+// chunks behind a pointer-free index, so the garbage collector scans a
+// handful of chunks instead of one object per entry. It suits processes
+// holding millions of entries or very large values in memory, where
+// MemoryCacheProvider makes GC marking time grow with the entry count.
+// Values are stored encoded and decoded by the codec on every read.
+//
+// Keys are indexed by their 64-bit hash; writing a key whose hash collides
+// with another stored key evicts that key. Get returns slices of the arena,
+// which must not be modified. Deleted, overwritten and expired records are
+// reclaimed by dropping chunks without live records and by compacting when
+// more than half of the arena is garbage. Expired entries are removed lazily
+// on access or compaction. It has no capacity bound.
+type ArenaCacheProvider struct {
+	_         noCopy
+	mu        sync.RWMutex
+	index     map[uint64]arenaSlot
+	chunks    []arenaChunk
+	free      []uint32
+	current   int
+	chunkSize int
+	allocated int64
+	live      int64
+	now       func() time.Time
+}
+
+var (
+	_ CacheProvider[[]byte]      = (*ArenaCacheProvider)(nil)
+	_ SizedProvider              = (*ArenaCacheProvider)(nil)
+	_ DeleteFuncProvider[[]byte] = (*ArenaCacheProvider)(nil)
+)
+
+// NewArenaCacheProvider constructs an empty ArenaCacheProvider.
+func NewArenaCacheProvider(opts ...ArenaCacheProviderOption) *ArenaCacheProvider {
+	provider := &ArenaCacheProvider{
+		index:     make(map[uint64]arenaSlot),
+		current:   -1,
+		chunkSize: defaultArenaChunkSize,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(provider)
+	}
+
+	return provider
+}
+
+// WithArenaChunkSize sets the size of the chunks records are packed into,
+// 1 MiB by default. Records larger than a chunk get a chunk of their own.
+// Non-positive sizes are ignored.
+func WithArenaChunkSize(bytes int) ArenaCacheProviderOption {
+	return func(provider *ArenaCacheProvider) {
+		if bytes > 0 {
+			provider.chunkSize = bytes
+		}
+	}
+}
+
+// Get retrieves a value from the cache by key. The returned slice aliases
+// the arena and must not be modified.
+func (a *ArenaCacheProvider) Get(_ context.Context, key string) ([]byte, bool, error) {
+	h := hashKey(key)
+	a.mu.RLock()
+	slot, ok := a.index[h]
+	if !ok || !a.keyEquals(slot, key) {
+		a.mu.RUnlock()
+
+		return nil, false, nil
+	}
+	value := a.valueOf(slot)
+	a.mu.RUnlock()
+	if slot.expireAt != 0 && a.now().UnixNano() >= slot.expireAt {
+		a.mu.Lock()
+		if current, ok := a.index[h]; ok && current == slot {
+			a.removeLocked(h, slot)
+		}
+		a.mu.Unlock()
+
+		return nil, false, nil
+	}
+
+	return value, true, nil
+}
+
+// Set copies value into the arena under key. A non-positive ttl keeps the
+// entry until it is deleted.
+func (a *ArenaCacheProvider) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	var expireAt int64
+	if ttl > 0 {
+		expireAt = a.now().Add(ttl).UnixNano()
+	}
+	h := hashKey(key)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if prev, ok := a.index[h]; ok {
+		a.removeLocked(h, prev)
+	}
+	a.index[h] = a.appendLocked(key, value, expireAt)
+	a.compactIfNeededLocked()
+
+	return nil
+}
+
+// Delete removes a value from the cache by key.
+func (a *ArenaCacheProvider) Delete(_ context.Context, key string) error {
+	h := hashKey(key)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if slot, ok := a.index[h]; ok && a.keyEquals(slot, key) {
+		a.removeLocked(h, slot)
+	}
+
+	return nil
+}
+
+// DeleteFunc removes the entries whose key fn reports true for, including
+// expired entries, and returns how many were removed.
+func (a *ArenaCacheProvider) DeleteFunc(_ context.Context, fn func(key string) bool) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	n := 0
+	for h, slot := range a.index {
+		if fn(a.keyOf(slot)) {
+			a.removeLocked(h, slot)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// Len returns the number of stored entries, including expired entries that
+// have not been removed yet.
+func (a *ArenaCacheProvider) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return len(a.index)
+}
+
+// SizeBytes returns the number of arena bytes held by stored keys and values.
+func (a *ArenaCacheProvider) SizeBytes() int64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.live
+}
+
+// ArenaBytes returns the number of bytes allocated for chunks, including
+// garbage not reclaimed yet.
+func (a *ArenaCacheProvider) ArenaBytes() int64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.allocated
+}
+
+func (a *ArenaCacheProvider) keyOf(slot arenaSlot) string {
+	data := a.chunks[slot.chunk].data
+	start := int(slot.offset) + arenaKeyLenSize
+
+	return string(data[start : start+int(slot.keyLen)])
+}
+
+// keyEquals reports whether slot holds key without allocating.
+func (a *ArenaCacheProvider) keyEquals(slot arenaSlot, key string) bool {
+	data := a.chunks[slot.chunk].data
+	start := int(slot.offset) + arenaKeyLenSize
+
+	return string(data[start:start+int(slot.keyLen)]) == key
+}
+
+func (a *ArenaCacheProvider) valueOf(slot arenaSlot) []byte {
+	data := a.chunks[slot.chunk].data
+	start := int(slot.offset) + arenaKeyLenSize + int(slot.keyLen)
+	end := start + int(slot.valueLen)
+
+	return data[start:end:end]
+}
+
+// appendLocked writes a record for key and value and returns its slot.
+func (a *ArenaCacheProvider) appendLocked(key string, value []byte, expireAt int64) arenaSlot {
+	size := arenaKeyLenSize + len(key) + len(value)
+	var chunk int
+	if size > a.chunkSize {
+		chunk = a.newChunkLocked(size)
+	} else {
+		if a.current < 0 || cap(a.chunks[a.current].data)-len(a.chunks[a.current].data) < size {
+			previous := a.current
+			a.current = a.newChunkLocked(a.chunkSize)
+			if previous >= 0 && a.chunks[previous].live == 0 {
+				a.releaseChunkLocked(uint32(previous))
+			}
+		}
+		chunk = a.current
+	}
+
+	c := &a.chunks[chunk]
+	offset := len(c.data)
+	c.data = binary.BigEndian.AppendUint32(c.data, uint32(len(key)))
+	c.data = append(c.data, key...)
+	c.data = append(c.data, value...)
+	c.live += size
+	a.live += int64(size)
+
+	return arenaSlot{
+		chunk:    uint32(chunk),
+		offset:   uint32(offset),
+		keyLen:   uint32(len(key)),
+		valueLen: uint32(len(value)),
+		expireAt: expireAt,
+	}
+}
+
+// newChunkLocked allocates a chunk with capacity size and returns its index.
+func (a *ArenaCacheProvider) newChunkLocked(size int) int {
+	c := arenaChunk{data: make([]byte, 0, size)}
+	a.allocated += int64(size)
+	if n := len(a.free); n > 0 {
+		i := a.free[n-1]
+		a.free = a.free[:n-1]
+		a.chunks[i] = c
+
+		return int(i)
+	}
+	a.chunks = append(a.chunks, c)
+
+	return len(a.chunks) - 1
+}
+
+// removeLocked drops slot from the index and releases its chunk once no
+// live record remains in it. Released chunks stay valid for slices
+// returned by Get.
+func (a *ArenaCacheProvider) removeLocked(h uint64, slot arenaSlot) {
+	delete(a.index, h)
+	size := slot.size()
+	a.live -= int64(size)
+	c := &a.chunks[slot.chunk]
+	c.live -= size
+	if c.live == 0 && int(slot.chunk) != a.current {
+		a.releaseChunkLocked(slot.chunk)
+	}
+}
+
+// releaseChunkLocked drops chunk i for reuse of its index.
+func (a *ArenaCacheProvider) releaseChunkLocked(i uint32) {
+	a.allocated -= int64(cap(a.chunks[i].data))
+	a.chunks[i] = arenaChunk{}
+	a.free = append(a.free, i)
+}
+
+// compactIfNeededLocked copies live, unexpired records into fresh chunks
+// when more than half of the allocated arena is garbage.
+func (a *ArenaCacheProvider) compactIfNeededLocked() {
+	garbage := a.allocated - a.live
+	if garbage <= int64(a.chunkSize) || garbage <= a.allocated/2 {
+		return
+	}
+
+	now := a.now().UnixNano()
+	old := a.chunks
+	index := a.index
+	a.index = make(map[uint64]arenaSlot, len(index))
+	a.chunks = nil
+	a.free = nil
+	a.current = -1
+	a.allocated = 0
+	a.live = 0
+	for h, slot := range index {
+		if slot.expireAt != 0 && now >= slot.expireAt {
+			continue
+		}
+		data := old[slot.chunk].data
+		start := int(slot.offset) + arenaKeyLenSize
+		key := data[start : start+int(slot.keyLen)]
+		value := data[start+int(slot.keyLen) : start+int(slot.keyLen)+int(slot.valueLen)]
+		a.index[h] = a.appendLocked(string(key), value, slot.expireAt)
+	}
+}
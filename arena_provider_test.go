@@ -0,0 +1,172 @@
+package crema
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArenaCacheProvider_GetSetDelete(t *testing.T) {
+	t.Parallel()
+
+	provider := NewArenaCacheProvider()
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	value, ok, err := provider.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !ok || string(value) != "value" {
+		t.Fatalf("unexpected value: %q, %v", value, ok)
+	}
+	if err := provider.Set(ctx, "key", []byte("updated"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if value, _, _ := provider.Get(ctx, "key"); string(value) != "updated" {
+		t.Fatalf("expected updated value, got %q", value)
+	}
+
+	if err := provider.Delete(ctx, "key"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok, _ := provider.Get(ctx, "key"); ok {
+		t.Fatal("expected value to be deleted")
+	}
+	if provider.Len() != 0 || provider.SizeBytes() != 0 {
+		t.Fatalf("expected empty provider, got %d entries and %d bytes", provider.Len(), provider.SizeBytes())
+	}
+}
+
+func TestArenaCacheProvider_TTL(t *testing.T) {
+	t.Parallel()
+
+	provider := NewArenaCacheProvider()
+	now := time.UnixMilli(1000)
+	provider.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", []byte("value"), time.Second); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := provider.Set(ctx, "forever", []byte("value"), 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	now = now.Add(time.Second)
+
+	if _, ok, _ := provider.Get(ctx, "key"); ok {
+		t.Fatal("expected value to expire")
+	}
+	if _, ok, _ := provider.Get(ctx, "forever"); !ok {
+		t.Fatal("expected value without ttl to remain")
+	}
+	if provider.Len() != 1 {
+		t.Fatalf("expected expired entry to be removed, got %d entries", provider.Len())
+	}
+}
+
+func TestArenaCacheProvider_ReclaimsGarbage(t *testing.T) {
+	t.Parallel()
+
+	provider := NewArenaCacheProvider(WithArenaChunkSize(1024))
+	ctx := context.Background()
+	value := bytes.Repeat([]byte("x"), 100)
+	var held []byte
+	for i := range 1000 {
+		if err := provider.Set(ctx, fmt.Sprintf("key-%d", i%5), value, time.Minute); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		if i == 0 {
+			held, _, _ = provider.Get(ctx, "key-0")
+		}
+	}
+	if got := provider.ArenaBytes(); got > 4*1024 {
+		t.Fatalf("expected garbage to be reclaimed, got %d arena bytes", got)
+	}
+	if !bytes.Equal(held, value) {
+		t.Fatalf("expected value read before compaction to stay intact, got %q", held)
+	}
+	for i := range 5 {
+		if got, ok, _ := provider.Get(ctx, fmt.Sprintf("key-%d", i)); !ok || !bytes.Equal(got, value) {
+			t.Fatalf("unexpected value for key-%d: %q, %v", i, got, ok)
+		}
+	}
+}
+
+func TestArenaCacheProvider_LargeValues(t *testing.T) {
+	t.Parallel()
+
+	provider := NewArenaCacheProvider(WithArenaChunkSize(64))
+	ctx := context.Background()
+	large := []byte(strings.Repeat("large", 100))
+	if err := provider.Set(ctx, "large", large, time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := provider.Set(ctx, "small", []byte("small"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got, _, _ := provider.Get(ctx, "large"); !bytes.Equal(got, large) {
+		t.Fatalf("unexpected large value of %d bytes", len(got))
+	}
+	if err := provider.Delete(ctx, "large"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if got := provider.ArenaBytes(); got != 64 {
+		t.Fatalf("expected dedicated chunk to be released, got %d arena bytes", got)
+	}
+}
+
+func TestArenaCacheProvider_DeleteFunc(t *testing.T) {
+	t.Parallel()
+
+	provider := NewArenaCacheProvider()
+	ctx := context.Background()
+	for _, key := range []string{"user:1:a", "user:1:b", "user:2:a"} {
+		if err := provider.Set(ctx, key, []byte(key), time.Minute); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	n, err := provider.DeleteFunc(ctx, func(key string) bool { return strings.HasPrefix(key, "user:1:") })
+	if err != nil {
+		t.Fatalf("delete func: %v", err)
+	}
+	if n != 2 || provider.Len() != 1 {
+		t.Fatalf("expected 2 deleted and 1 left, got %d and %d", n, provider.Len())
+	}
+}
+
+// BenchmarkProviderGC measures a full GC cycle with many entries stored in
+// MemoryCacheProvider and ArenaCacheProvider.
+func BenchmarkProviderGC(b *testing.B) {
+	const entries = 1_000_000
+	value := bytes.Repeat([]byte("v"), 64)
+	providers := []struct {
+		name string
+		new  func() CacheProvider[[]byte]
+	}{
+		{name: "memory", new: func() CacheProvider[[]byte] { return NewMemoryCacheProvider[[]byte]() }},
+		{name: "arena", new: func() CacheProvider[[]byte] { return NewArenaCacheProvider() }},
+	}
+	for _, p := range providers {
+		b.Run(p.name, func(b *testing.B) {
+			provider := p.new()
+			ctx := context.Background()
+			for i := range entries {
+				_ = provider.Set(ctx, fmt.Sprintf("key-%d", i), bytes.Clone(value), time.Hour)
+			}
+			runtime.GC()
+			b.ResetTimer()
+			for b.Loop() {
+				runtime.GC()
+			}
+			b.StopTimer()
+			runtime.KeepAlive(provider)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// LoadTrigger is why a loader ran.
+type LoadTrigger int
+
+const (
+	// LoadTriggerMiss means the key had no cached entry.
+	LoadTriggerMiss LoadTrigger = iota
+	// LoadTriggerEarlyRefresh means a live entry was revalidated early.
+	LoadTriggerEarlyRefresh
+	// LoadTriggerExpired means the cached entry had expired.
+	LoadTriggerExpired
+)
+
+// String returns the trigger name for audit logs and metric labels.
+func (t LoadTrigger) String() string {
+	switch t {
+	case LoadTriggerEarlyRefresh:
+		return "early-refresh"
+	case LoadTriggerExpired:
+		return "expired"
+	default:
+		return "miss"
+	}
+}
+
+// LoadRecord describes one execution of a loader.
+type LoadRecord struct {
+	// Key is the load key, which differs from the cache key with WithLoadKeyFunc.
+	Key string
+	// Trigger is why the loader ran, as seen by the caller that started it.
+	Trigger LoadTrigger
+	// StartedAt is when the loader started.
+	StartedAt time.Time
+	// Duration is how long the loader ran.
+	Duration time.Duration
+	// Outcome is LoadOutcomeSuccess, LoadOutcomeError, or LoadOutcomeCanceled
+	// when CancelLoad aborted the load.
+	Outcome LoadOutcome
+	// Err is the error returned by the loader, if any.
+	Err error
+	// WaitersServed is the number of callers, including the leader, that
+	// received the result.
+	WaitersServed int
+}
+
+// WithLoadAuditor calls auditor with a LoadRecord after every loader
+// execution, for example to feed a data-freshness audit pipeline. Sampled
+// and shadow loads are not recorded. The auditor runs on the loading
+// goroutine and must not block.
+func WithLoadAuditor[V any, S any](auditor func(LoadRecord)) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.loadAuditor = auditor
+	}
+}
+
+type loadTriggerKey struct{}
+
+// withLoadTrigger records trigger in ctx for the load auditor.
+func (c *cacheImpl[V, S]) withLoadTrigger(ctx context.Context, found bool, expireAtMillis int64) context.Context {
+	if c.loadAuditor == nil {
+		return ctx
+	}
+	trigger := LoadTriggerMiss
+	switch {
+	case found && expireAtMillis > c.now().UnixMilli():
+		trigger = LoadTriggerEarlyRefresh
+	case found:
+		trigger = LoadTriggerExpired
+	}
+
+	return context.WithValue(ctx, loadTriggerKey{}, trigger)
+}
+
+// auditLoad builds the hook loaders call after each execution.
+func (c *cacheImpl[V, S]) auditLoad() loadFinishedFunc {
+	auditor := c.loadAuditor
+
+	return func(ctx context.Context, key string, info LoadInfo, outcome LoadOutcome, served int) {
+		trigger, _ := ctx.Value(loadTriggerKey{}).(LoadTrigger)
+		auditor(LoadRecord{
+			Key:           key,
+			Trigger:       trigger,
+			StartedAt:     info.StartedAt,
+			Duration:      info.Duration,
+			Outcome:       outcome,
+			Err:           info.Err,
+			WaitersServed: served,
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type loadRecorder struct {
+	mu      sync.Mutex
+	records []LoadRecord
+}
+
+func (r *loadRecorder) record(record LoadRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+}
+
+func (r *loadRecorder) snapshot() []LoadRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]LoadRecord(nil), r.records...)
+}
+
+func TestWithLoadAuditor_RecordsTriggers(t *testing.T) {
+	t.Parallel()
+
+	for _, direct := range []bool{false, true} {
+		recorder := &loadRecorder{}
+		opts := []CacheOption[int, CacheObject[int]]{
+			WithLoadAuditor[int, CacheObject[int]](recorder.record),
+			WithRevalidationWindow[int, CacheObject[int]](time.Minute),
+		}
+		if direct {
+			opts = append(opts, WithDirectLoader[int, CacheObject[int]]())
+		}
+		cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{}, opts...)
+		impl := cache.(*cacheImpl[int, CacheObject[int]])
+		now := time.UnixMilli(1_000_000)
+		impl.now = func() time.Time { return now }
+		impl.random = fakeRandom(0)
+		errLoad := errors.New("origin down")
+		ctx := context.Background()
+
+		if _, err := cache.GetOrLoad(ctx, "key", 30*time.Second, func(context.Context) (int, error) { return 1, nil }); err != nil {
+			t.Fatalf("miss: %v", err)
+		}
+		now = now.Add(10 * time.Second)
+		if _, err := cache.GetOrLoad(ctx, "key", 30*time.Second, func(context.Context) (int, error) { return 2, nil }); err != nil {
+			t.Fatalf("early refresh: %v", err)
+		}
+		now = now.Add(time.Minute)
+		if _, err := cache.GetOrLoad(ctx, "key", 30*time.Second, func(context.Context) (int, error) { return 0, errLoad }); !errors.Is(err, errLoad) {
+			t.Fatalf("expected load error, got %v", err)
+		}
+
+		want := []struct {
+			trigger LoadTrigger
+			outcome LoadOutcome
+		}{
+			{trigger: LoadTriggerMiss, outcome: LoadOutcomeSuccess},
+			{trigger: LoadTriggerEarlyRefresh, outcome: LoadOutcomeSuccess},
+			{trigger: LoadTriggerExpired, outcome: LoadOutcomeError},
+		}
+		got := recorder.snapshot()
+		if len(got) != len(want) {
+			t.Fatalf("direct=%v: expected %d records, got %+v", direct, len(want), got)
+		}
+		for i, w := range want {
+			if got[i].Key != "key" || got[i].Trigger != w.trigger || got[i].Outcome != w.outcome || got[i].WaitersServed != 1 {
+				t.Fatalf("direct=%v: record %d: expected %s/%s, got %+v", direct, i, w.trigger, w.outcome, got[i])
+			}
+		}
+		if !errors.Is(got[2].Err, errLoad) {
+			t.Fatalf("expected recorded error, got %v", got[2].Err)
+		}
+	}
+}
+
+func TestWithLoadAuditor_CountsWaitersServed(t *testing.T) {
+	t.Parallel()
+
+	recorder := &loadRecorder{}
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithLoadAuditor[int, CacheObject[int]](recorder.record),
+	)
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+				<-release
+
+				return 1, nil
+			})
+		}()
+	}
+	deadline := time.After(time.Second)
+	for {
+		loads := cache.InflightLoads()
+		if len(loads) == 1 && loads[0].Waiters == 3 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for 3 waiters, got %v", loads)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	got := recorder.snapshot()
+	if len(got) != 1 || got[0].WaitersServed != 3 {
+		t.Fatalf("expected one load serving 3 waiters, got %+v", got)
+	}
+}
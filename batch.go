@@ -0,0 +1,205 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchStatus tells how one key of a batch call was served.
+type BatchStatus int
+
+const (
+	// BatchHit means the value was served from the cache.
+	BatchHit BatchStatus = iota
+	// BatchLoaded means the value was loaded because the entry was missing or
+	// due for revalidation.
+	BatchLoaded
+	// BatchStale means a stale value was served: GetMany found an expired
+	// entry, or GetOrLoadMany served the cached value after a failed load
+	// while degraded.
+	BatchStale
+	// BatchMiss means GetMany found no entry for the key.
+	BatchMiss
+	// BatchError means the key failed; the item's Err holds the failure.
+	BatchError
+)
+
+// String returns the status name.
+func (s BatchStatus) String() string {
+	switch s {
+	case BatchHit:
+		return "hit"
+	case BatchLoaded:
+		return "loaded"
+	case BatchStale:
+		return "stale"
+	case BatchMiss:
+		return "miss"
+	case BatchError:
+		return "error"
+	default:
+		return fmt.Sprintf("BatchStatus(%d)", int(s))
+	}
+}
+
+// BatchItem is the outcome of one key of a batch call.
+type BatchItem[V any] struct {
+	// Key is the requested cache key.
+	Key string
+	// Value is the served value, the zero value on a miss or failure.
+	Value V
+	// Err is the failure for the key when Status is BatchError.
+	Err error
+	// Status tells how the key was served.
+	Status BatchStatus
+}
+
+// OK reports whether the item carries a value.
+func (i BatchItem[V]) OK() bool {
+	return i.Status == BatchHit || i.Status == BatchLoaded || i.Status == BatchStale
+}
+
+// BatchResult holds the per-key outcomes of a batch call in request order, so
+// one failing key does not fail the whole batch.
+type BatchResult[V any] struct {
+	Items []BatchItem[V]
+}
+
+// Values returns the values of the items that carry one, by key.
+func (r BatchResult[V]) Values() map[string]V {
+	values := make(map[string]V, len(r.Items))
+	for _, item := range r.Items {
+		if item.OK() {
+			values[item.Key] = item.Value
+		}
+	}
+
+	return values
+}
+
+// Errors returns the failures of the failed items, by key.
+func (r BatchResult[V]) Errors() map[string]error {
+	errs := make(map[string]error)
+	for _, item := range r.Items {
+		if item.Status == BatchError {
+			errs[item.Key] = item.Err
+		}
+	}
+
+	return errs
+}
+
+// Split returns the items that carry a value and the remaining items, which
+// missed or failed, each in request order.
+func (r BatchResult[V]) Split() (succeeded []BatchItem[V], rest []BatchItem[V]) {
+	for _, item := range r.Items {
+		if item.OK() {
+			succeeded = append(succeeded, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+
+	return succeeded, rest
+}
+
+// Err joins the failures of the failed items, or returns nil when none failed.
+func (r BatchResult[V]) Err() error {
+	var errs []error
+	for _, item := range r.Items {
+		if item.Status == BatchError {
+			errs = append(errs, fmt.Errorf("%q: %w", item.Key, item.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// GetMany returns the cached entries for keys. A failed read only fails its
+// own key.
+func (c *cacheImpl[V, S]) GetMany(ctx context.Context, keys []string) BatchResult[V] {
+	ctx = c.metricsContext(ctx)
+	items := make([]BatchItem[V], len(keys))
+	nowMillis := c.now().UnixMilli()
+	for i, key := range keys {
+		items[i].Key = key
+		co, ok, err := c.Get(ctx, key)
+		switch {
+		case err != nil:
+			items[i].Err = err
+			items[i].Status = BatchError
+		case !ok:
+			items[i].Status = BatchMiss
+		case c.skewedExpiry(ctx, nowMillis, co.ExpireAtMillis) <= nowMillis:
+			items[i].Value = co.Value
+			items[i].Status = BatchStale
+		default:
+			items[i].Value = co.Value
+			items[i].Status = BatchHit
+		}
+	}
+
+	return BatchResult[V]{Items: items}
+}
+
+// defaultBatchConcurrency is the number of keys GetOrLoadMany serves at once
+// unless WithBatchConcurrency says otherwise.
+const defaultBatchConcurrency = 16
+
+// WithBatchConcurrency sets how many keys GetOrLoadMany serves at once, 16 by
+// default, bounding the provider reads and loads one call can start.
+// Non-positive values are ignored.
+func WithBatchConcurrency[V any, S any](n int) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if n > 0 {
+			c.batchConcurrency = n
+		}
+	}
+}
+
+// GetOrLoadMany runs GetOrLoad for every key, loading each with loader, on up
+// to the WithBatchConcurrency limit of goroutines. A failed load only fails
+// its own key.
+func (c *cacheImpl[V, S]) GetOrLoadMany(ctx context.Context, keys []string, ttl time.Duration, loader func(ctx context.Context, key string) (V, error)) BatchResult[V] {
+	ctx = c.metricsContext(ctx)
+	items := make([]BatchItem[V], len(keys))
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+	for range min(c.batchConcurrency, len(keys)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < len(keys); i = int(next.Add(1) - 1) {
+				c.loadBatchItem(ctx, &items[i], keys[i], ttl, loader)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return BatchResult[V]{Items: items}
+}
+
+// loadBatchItem serves key into item for GetOrLoadMany.
+func (c *cacheImpl[V, S]) loadBatchItem(ctx context.Context, item *BatchItem[V], key string, ttl time.Duration, loader func(ctx context.Context, key string) (V, error)) {
+	item.Key = key
+	var out loadOutcome
+	v, err := c.getOrLoadBound(ctx, key, nil, ttl, func(*CacheObject[V]) CacheLoadFunc[V] {
+		return func(ctx context.Context) (V, error) {
+			return loader(ctx, key)
+		}
+	}, &out)
+	if err != nil {
+		item.Err = err
+		item.Status = BatchError
+
+		return
+	}
+	item.Value = v
+	item.Status = out.status
+}
@@ -0,0 +1,120 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_GetOrLoadManyReportsPerKey(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"cached": {Value: 1, ExpireAtMillis: time.Now().Add(time.Hour).UnixMilli()},
+	}}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	errBad := errors.New("bad key")
+
+	result := cache.GetOrLoadMany(context.Background(), []string{"cached", "fresh", "bad"}, time.Hour, func(_ context.Context, key string) (int, error) {
+		if key == "bad" {
+			return 0, errBad
+		}
+
+		return 2, nil
+	})
+
+	want := []struct {
+		key    string
+		value  int
+		status BatchStatus
+	}{
+		{key: "cached", value: 1, status: BatchHit},
+		{key: "fresh", value: 2, status: BatchLoaded},
+		{key: "bad", value: 0, status: BatchError},
+	}
+	for i, w := range want {
+		item := result.Items[i]
+		if item.Key != w.key || item.Value != w.value || item.Status != w.status {
+			t.Fatalf("expected %s=%d (%s), got %s=%d (%s)", w.key, w.value, w.status, item.Key, item.Value, item.Status)
+		}
+	}
+	if values := result.Values(); len(values) != 2 || values["fresh"] != 2 {
+		t.Fatalf("expected values for cached and fresh, got %v", values)
+	}
+	if errs := result.Errors(); len(errs) != 1 || !errors.Is(errs["bad"], errBad) {
+		t.Fatalf("expected error for bad, got %v", errs)
+	}
+	if err := result.Err(); !errors.Is(err, errBad) {
+		t.Fatalf("expected joined error to wrap errBad, got %v", err)
+	}
+	succeeded, rest := result.Split()
+	if len(succeeded) != 2 || len(rest) != 1 || rest[0].Key != "bad" {
+		t.Fatalf("expected 2 succeeded and bad failed, got %v and %v", succeeded, rest)
+	}
+}
+
+func TestCache_GetManyReportsPerKey(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"hit":     {Value: 1, ExpireAtMillis: time.Now().Add(time.Hour).UnixMilli()},
+		"expired": {Value: 2, ExpireAtMillis: 1},
+	}}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+
+	result := cache.GetMany(context.Background(), []string{"hit", "expired", "missing"})
+	want := []BatchStatus{BatchHit, BatchStale, BatchMiss}
+	for i, status := range want {
+		if got := result.Items[i].Status; got != status {
+			t.Fatalf("expected %s for %s, got %s", status, result.Items[i].Key, got)
+		}
+	}
+	if err := result.Err(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if values := result.Values(); len(values) != 2 {
+		t.Fatalf("expected 2 values, got %v", values)
+	}
+}
+
+func TestCache_GetOrLoadManyBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithBatchConcurrency[int, CacheObject[int]](3),
+	)
+	keys := make([]string, 50)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key:%d", i)
+	}
+	var running, maxRunning atomic.Int32
+	result := cache.GetOrLoadMany(context.Background(), keys, time.Hour, func(_ context.Context, key string) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			prev := maxRunning.Load()
+			if n <= prev || maxRunning.CompareAndSwap(prev, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		return len(key), nil
+	})
+
+	if err := result.Err(); err != nil {
+		t.Fatalf("expected every key loaded, got %v", err)
+	}
+	for i, item := range result.Items {
+		if item.Key != keys[i] || item.Value != len(keys[i]) || item.Status != BatchLoaded {
+			t.Fatalf("expected %s loaded, got %+v", keys[i], item)
+		}
+	}
+	if got := maxRunning.Load(); got > 3 {
+		t.Fatalf("expected at most 3 concurrent loads, got %d", got)
+	}
+}
@@ -0,0 +1,69 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// BoundKey is a handle for one cache key that precomputes what every call for
+// the key would otherwise derive again: the load key from WithLoadKeyFunc,
+// the singleflight shard, and the pprof labels of WithPprofLabels. It suits
+// services that hit a small, fixed set of keys at very high rates. A BoundKey
+// is safe for concurrent use and stays valid for the life of its cache.
+type BoundKey[V any, S any] struct {
+	cache   *cacheImpl[V, S]
+	key     string
+	loadKey string
+	target  loadTarget[V]
+}
+
+// Bind returns a BoundKey for key.
+func (c *cacheImpl[V, S]) Bind(key string) *BoundKey[V, S] {
+	b := &BoundKey[V, S]{cache: c, key: key, loadKey: c.loadKey(key)}
+	if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+		b.target.shard = loader.shardFor(b.loadKey)
+		if loader.pprofLabels {
+			labels := loader.loadLabels(b.loadKey)
+			b.target.labels = &labels
+		}
+	}
+
+	return b
+}
+
+// loadBound runs loader for bound through the configured loader, reusing the
+// precomputed singleflight target.
+func (c *cacheImpl[V, S]) loadBound(ctx context.Context, bound *BoundKey[V, S], loader CacheLoadFunc[V]) (V, bool, error) {
+	if sf, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+		return sf.loadTarget(ctx, bound.loadKey, bound.target, loader)
+	}
+
+	return c.internalLoader.load(ctx, bound.loadKey, loader)
+}
+
+// Key returns the cache key the handle is bound to.
+func (b *BoundKey[V, S]) Key() string {
+	return b.key
+}
+
+// Get is Cache.Get for the bound key.
+func (b *BoundKey[V, S]) Get(ctx context.Context) (CacheObject[V], bool, error) {
+	return b.cache.Get(ctx, b.key)
+}
+
+// Set is Cache.Set for the bound key.
+func (b *BoundKey[V, S]) Set(ctx context.Context, value CacheObject[V]) error {
+	return b.cache.Set(ctx, b.key, value)
+}
+
+// Delete is Cache.Delete for the bound key.
+func (b *BoundKey[V, S]) Delete(ctx context.Context) error {
+	return b.cache.Delete(ctx, b.key)
+}
+
+// GetOrLoad is Cache.GetOrLoad for the bound key.
+func (b *BoundKey[V, S]) GetOrLoad(ctx context.Context, ttl time.Duration, loader CacheLoadFunc[V]) (V, error) {
+	return b.cache.getOrLoadBound(ctx, b.key, b, ttl, func(*CacheObject[V]) CacheLoadFunc[V] {
+		return loader
+	}, nil)
+}
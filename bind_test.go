@@ -0,0 +1,103 @@
+package crema
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBoundKey_SharesLoadsWithUnboundCalls(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithLoadKeyFunc[string, CacheObject[string]](func(key string) string {
+			base, _, _ := strings.Cut(key, "@")
+
+			return base
+		}),
+	)
+	bound := cache.Bind("doc:1@alice")
+	if bound.Key() != "doc:1@alice" {
+		t.Fatalf("expected doc:1@alice, got %q", bound.Key())
+	}
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		loads.Add(1)
+		<-release
+
+		return "doc", nil
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if v, err := bound.GetOrLoad(context.Background(), time.Minute, load); err != nil || v != "doc" {
+			t.Errorf("expected doc, got %q, %v", v, err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		if v, err := cache.GetOrLoad(context.Background(), "doc:1@bob", time.Minute, load); err != nil || v != "doc" {
+			t.Errorf("expected doc, got %q, %v", v, err)
+		}
+	}()
+	deadline := time.After(time.Second)
+	for {
+		loads := cache.InflightLoads()
+		if len(loads) == 1 && loads[0].Waiters == 2 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for both callers to join, got %v", loads)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Fatalf("expected 1 shared load, got %d", got)
+	}
+	co, ok, err := bound.Get(context.Background())
+	if err != nil || !ok || co.Value != "doc" {
+		t.Fatalf("expected stored doc, got %+v, %v, %v", co, ok, err)
+	}
+	if err := bound.Delete(context.Background()); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok := provider.items["doc:1@alice"]; ok {
+		t.Fatal("expected bound key to be deleted")
+	}
+}
+
+func TestBoundKey_UsesPrecomputedPprofLabels(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithCacheName[int, CacheObject[int]]("users"),
+		WithPprofLabels[int, CacheObject[int]](true),
+	)
+	bound := cache.Bind("user:1")
+	if err := bound.Set(context.Background(), CacheObject[int]{Value: 1, ExpireAtMillis: 1}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	var group string
+	if _, err := bound.GetOrLoad(context.Background(), time.Minute, func(ctx context.Context) (int, error) {
+		group, _ = pprof.Label(ctx, "crema_key_group")
+
+		return 2, nil
+	}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if group != "user" {
+		t.Fatalf("expected key group user, got %q", group)
+	}
+}
@@ -2,9 +2,15 @@ package crema
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
 	"log/slog"
 	"math"
 	"math/rand/v2"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,25 +25,159 @@ type Cache[V any, S any] interface {
 	Set(ctx context.Context, key string, value CacheObject[V]) error
 	// Delete removes a cached entry for key.
 	Delete(ctx context.Context, key string) error
+	// DeleteFunc removes every entry whose key fn reports true for and
+	// returns how many were removed.
+	DeleteFunc(ctx context.Context, fn func(key string) bool) (int, error)
+	// ExtendTTL pushes the expiry of the entry for key d further without
+	// running a loader, reporting false when key is not cached.
+	ExtendTTL(ctx context.Context, key string, d time.Duration) (bool, error)
+	// SoftDelete makes the entry for key revalidate after revalidateAfter
+	// while keeping its payload as a fallback for failed reloads served
+	// with WithSoftDeleteFallback.
+	SoftDelete(ctx context.Context, key string, revalidateAfter time.Duration) (bool, error)
+	// Inspect returns the expiry and writer metadata of the entry stored
+	// for key without serving it.
+	Inspect(ctx context.Context, key string) (EntryInfo, bool, error)
+	// Take returns the cached entry for key and removes it.
+	Take(ctx context.Context, key string) (CacheObject[V], bool, error)
 	// GetOrLoad returns a cached value or uses loader when missing or revalidating.
 	GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader CacheLoadFunc[V]) (V, error)
+	// GetOrLoadResult is like GetOrLoad but also reports how the value was
+	// served.
+	GetOrLoadResult(ctx context.Context, key string, ttl time.Duration, loader CacheLoadFunc[V]) (Result[V], error)
+	// GetOrLoadConditional is like GetOrLoad but passes the entry being
+	// revalidated to loader, which can report it as not modified.
+	GetOrLoadConditional(ctx context.Context, key string, ttl time.Duration, loader ConditionalLoadFunc[V]) (V, error)
+	// GetMany returns the cached entries for keys with a status per key.
+	GetMany(ctx context.Context, keys []string) BatchResult[V]
+	// GetOrLoadMany runs GetOrLoad for every key concurrently, bounded by
+	// WithBatchConcurrency, with a status per key, so one failing key does not
+	// fail the batch.
+	GetOrLoadMany(ctx context.Context, keys []string, ttl time.Duration, loader func(ctx context.Context, key string) (V, error)) BatchResult[V]
+	// GetOrBatchLoad returns a cached value or loads it with the batch loader
+	// configured by WithLoadCoalescing.
+	GetOrBatchLoad(ctx context.Context, key string, ttl time.Duration) (V, error)
+	// Export streams all unexpired entries to w. The provider must implement AdminProvider.
+	Export(ctx context.Context, w io.Writer) error
+	// RefreshTag reloads every key recorded under tag through the loaders of
+	// registry.
+	RefreshTag(ctx context.Context, tag string, registry *LoaderRegistry[V], opts ...RefreshTagOption) (RefreshTagResult, error)
+	// Entries iterates over the unexpired entries whose key matches pattern.
+	// The provider must implement AdminProvider.
+	Entries(ctx context.Context, pattern string) iter.Seq2[string, CacheObject[V]]
+	// Import stores entries read from a stream produced by Export.
+	Import(ctx context.Context, r io.Reader) error
+	// PreloadFromProvider copies up to maxEntries entries matching pattern
+	// from the source configured with WithPreloadSource into the provider.
+	PreloadFromProvider(ctx context.Context, pattern string, maxEntries int) (int, error)
+	// Errors returns a channel receiving failures from background work when
+	// no error handler is configured. The channel is closed by Close.
+	Errors() <-chan error
+	// Flush waits until pending background work finishes or ctx is done.
+	Flush(ctx context.Context) error
+	// Close rejects new GetOrLoad calls with ErrCacheClosed, then waits for
+	// running calls and background work until ctx is done.
+	Close(ctx context.Context) error
+	// SelfTest round-trips a canary entry through the codec and provider
+	// and reports the latency and error of each step.
+	SelfTest(ctx context.Context) (SelfTestReport, error)
+	// Stats returns a snapshot of the cache state.
+	Stats() CacheStats
+	// Pin exempts key from probabilistic early revalidation.
+	Pin(key string)
+	// Unpin makes key subject to probabilistic early revalidation again.
+	Unpin(key string)
+	// Pinned reports whether key is pinned.
+	Pinned(key string) bool
+	// CancelLoad aborts the inflight singleflight load for key.
+	CancelLoad(key string) bool
+	// LastLoadInfo returns the most recent finished singleflight load of key.
+	LastLoadInfo(key string) (LoadInfo, bool)
+	// InflightLoads returns the singleflight loads running right now, oldest first.
+	InflightLoads() []InflightInfo
+	// Bind returns a handle for key that precomputes its per-call work.
+	Bind(key string) *BoundKey[V, S]
+	// Settings returns the runtime settings currently in effect.
+	Settings() Settings
+	// UpdateSettings atomically replaces the runtime settings. Start from
+	// Settings(), or use ModifySettings, so unset fields are not reset.
+	UpdateSettings(settings Settings)
+	// ModifySettings atomically applies fn to a copy of the runtime settings
+	// and stores the result.
+	ModifySettings(fn func(*Settings))
 }
 
 type cacheImpl[V any, S any] struct {
-	_                              noCopy
-	provider                       CacheProvider[S]
-	codec                          CacheStorageCodec[V, S]
-	logger                         *slog.Logger
-	metrics                        MetricsProvider
-	internalLoader                 internalLoader[V]
-	now                            func() time.Time
-	steepness                      float64
-	revalidationWindowMilliseconds int64
-	maxLoadTimeout                 time.Duration
-	random                         func() float64 // must goroutine safe
+	_                       noCopy
+	provider                CacheProvider[S]
+	codec                   CacheStorageCodec[V, S]
+	objectStorage           bool
+	logger                  *slog.Logger
+	metrics                 MetricsProvider
+	internalLoader          internalLoader[V]
+	now                     func() time.Time
+	settings                atomic.Pointer[cacheSettings]
+	settingsMu              sync.Mutex // serializes settings updates
+	maxLoadTimeout          time.Duration
+	maxLoadTimeoutFunc      func(key string) time.Duration
+	random                  func() float64 // must goroutine safe
+	recencyTracker          RecencyTracker
+	recencyQueue            *recencyQueue
+	recencySampleRate       float64
+	runner                  *asyncRunner
+	shadow                  *shadowLoader[V]
+	doubleRead              *doubleReadVerifier[V, S]
+	coalescer               *loadCoalescer[V]
+	batchConcurrency        int
+	loadLimiter             *loadLimiter
+	errorObserver           func(ctx context.Context, event ErrorEvent)
+	skipZeroValues          bool
+	isCacheable             func(value V) bool
+	ttlConflicts            *ttlConflictTracker
+	skipIdenticalWrites     bool
+	identicalWriteTolerance time.Duration
+	replicationHook         ReplicationHook[S]
+	replication             replicationQueue
+	pins                    pinSet
+	loadSampler             *loadSampler[V]
+	preloadSource           AdminProvider[S]
+	degradation             *degradationController
+	name                    string
+	setTimeout              time.Duration
+	atomicMissFill          bool
+	loadKeyFunc             func(key string) string
+	coldStartWindow         time.Duration
+	startedAt               time.Time
+	skipProviderMetrics     bool
+	ttlBounds               *ttlBounds
+	calls                   callTracker
+	refreshDebounce         *refreshDebouncer
+	setRetry                *setRetryQueue[S]
+	loadAuditor             func(LoadRecord)
+	adaptiveTTL             *adaptiveTTL[V]
+	refreshMarker           *refreshMarker
+	softDeleteFallback      bool
+	shardIsolation          *shardIsolation
+	loadedValueCost         *loadedValueCost[V]
+	metricsExtractor        func(ctx context.Context) []MetricsAttribute
+	metricLabels            *metricLabelLimiter
+	circuitBreakers         *circuitBreakerProvider[S]
+	clockSkewTolerance      time.Duration
+	sharedGroup             *Group[V]
+	absentFilter            *absentFilter[V]
+	writerIdentity          *WriterIdentity
+	effectiveness           *effectivenessAnalyzer[V]
+	distributedSingleflight *distributedSingleflight
+	loaderIdentity          LoaderIdentityMode
+	reencode                *reencoder[S]
+	cloner                  Cloner[V]
+	mutationCheck           *mutationCheck[V]
+	tagIndex                TagIndex
 }
 
 // CacheObject wraps a cached value with its absolute expiration time.
+// When V is a pointer, map or slice, caches may share one value between the
+// stored entry and every caller served from it; see WithCloneOnHit.
 type CacheObject[V any] struct {
 	// Value is the cached value.
 	Value V
@@ -62,6 +202,15 @@ func WithLogger[V any, S any](logger *slog.Logger) CacheOption[V, S] {
 	}
 }
 
+// WithErrorHandler routes failures of background work, such as recency
+// tracking, to handler instead of the channel returned by Errors.
+// The handler is called from background goroutines and must not block.
+func WithErrorHandler[V any, S any](handler func(error)) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.runner.errorHandler = handler
+	}
+}
+
 // WithMetricsProvider overrides the default metrics provider.
 func WithMetricsProvider[V any, S any](metrics MetricsProvider) CacheOption[V, S] {
 	return func(c *cacheImpl[V, S]) {
@@ -82,13 +231,50 @@ func WithDirectLoader[V any, S any]() CacheOption[V, S] {
 	}
 }
 
+// WithCacheName names the cache in pprof labels added by WithPprofLabels.
+func WithCacheName[V any, S any](name string) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.name = name
+		if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+			loader.name = name
+		}
+	}
+}
+
+// WithPprofLabels labels background singleflight loads with the cache name
+// from WithCacheName, the key group (the key up to its first ':') and
+// crema_op=load, so CPU profiles and goroutine dumps attribute load work to
+// the right cache. The labels are also visible to the loader through its
+// context. It has no effect with WithDirectLoader or on loads run by
+// WithSynchronousLeader, which keep the caller's labels.
+func WithPprofLabels[V any, S any](enabled bool) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+			loader.pprofLabels = enabled
+		}
+	}
+}
+
+// WithSynchronousLeader runs the loader on the goroutine of the caller that
+// leads a singleflight load instead of a spawned goroutine, preserving
+// goroutine-local profiling labels and pprof stacks and saving scheduling
+// overhead for fast loaders. It applies only to loads without a timeout from
+// WithMaxLoadTimeout or WithMaxLoadTimeoutFunc; the leader then waits for the
+// loader even if its context is cancelled, while followers can still give up.
+func WithSynchronousLeader[V any, S any]() CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+			loader.synchronousLeader = true
+		}
+	}
+}
+
 // WithRevalidationWindow sets the target revalidation window duration.
 func WithRevalidationWindow[V any, S any](duration time.Duration) CacheOption[V, S] {
-	steepness, revalidationWindowMilliseconds := calculateSteepnessAndRevalidationWindow(duration.Milliseconds())
-
 	return func(c *cacheImpl[V, S]) {
-		c.steepness = steepness
-		c.revalidationWindowMilliseconds = revalidationWindowMilliseconds
+		c.updateSettings(func(s *Settings) {
+			s.RevalidationWindow = duration
+		})
 	}
 }
 
@@ -104,106 +290,477 @@ func WithMaxLoadTimeout[V any, S any](duration time.Duration) CacheOption[V, S]
 	}
 }
 
-// NewCache constructs a Cache with defaults and optional overrides.
+// WithFollowerTimeoutRetry lets callers that joined a singleflight load which
+// hit its max load timeout retry the load once, electing a new leader, while
+// their own contexts are still live, instead of all failing with the
+// leader's context.DeadlineExceeded. The caller that led the timed-out load
+// does not retry.
+func WithFollowerTimeoutRetry[V any, S any]() CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+			loader.followerTimeoutRetry = true
+		}
+	}
+}
+
+// WithLoadKeyFunc sets the singleflight key used to deduplicate loads of
+// key, so that variants stored under different keys, such as per-user
+// renderings of one document, can share a single expensive load. Every caller
+// still stores the shared result under its own key, so loaders of keys
+// mapped together must return interchangeable values. CancelLoad and
+// LastLoadInfo accept storage keys and map them the same way.
+func WithLoadKeyFunc[V any, S any](fn func(key string) string) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.loadKeyFunc = fn
+	}
+}
+
+// loadKey returns the singleflight key for key.
+func (c *cacheImpl[V, S]) loadKey(key string) string {
+	if c.loadKeyFunc == nil {
+		return key
+	}
+
+	return c.loadKeyFunc(key)
+}
+
+// WithRecencyTracker records key accesses into tracker for a sampled fraction
+// of reads and writes. sampleRate is clamped to [0, 1]; 1 records every access.
+func WithRecencyTracker[V any, S any](tracker RecencyTracker, sampleRate float64) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.recencyTracker = tracker
+		c.recencyQueue = newRecencyQueue()
+		c.recencySampleRate = min(max(sampleRate, 0), 1)
+	}
+}
+
+// WithMaxLoadTimeoutFunc sets the maximum loader execution duration per key,
+// taking precedence over WithMaxLoadTimeout. A non-positive duration returned
+// for a key disables the timeout for that load.
+func WithMaxLoadTimeoutFunc[V any, S any](timeoutFunc func(key string) time.Duration) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.maxLoadTimeoutFunc = timeoutFunc
+		switch loader := c.internalLoader.(type) {
+		case *singleflightLoader[V]:
+			loader.maxLoadTimeoutFunc = timeoutFunc
+		}
+	}
+}
+
+// loadTimeout returns the maximum duration of a load of key run outside the
+// internal loader, resolved like the singleflight loader does.
+func (c *cacheImpl[V, S]) loadTimeout(key string) time.Duration {
+	if c.maxLoadTimeoutFunc != nil {
+		return c.maxLoadTimeoutFunc(key)
+	}
+
+	return c.maxLoadTimeout
+}
+
+// NewCache constructs a Cache with defaults and optional overrides. codec may
+// be nil when S is CacheObject[V]; entries are then stored without encoding.
 func NewCache[V any, S any](provider CacheProvider[S], codec CacheStorageCodec[V, S], opts ...CacheOption[V, S]) Cache[V, S] {
-	steepness, revalidationWindowMilliseconds := calculateSteepnessAndRevalidationWindow(defaultRevalidationWindowMilliseconds)
 	metrics := NoopMetricsProvider{}
 	cache := &cacheImpl[V, S]{
-		provider:                       provider,
-		codec:                          codec,
-		logger:                         slog.New(noopLogHandler{}),
-		metrics:                        metrics,
-		internalLoader:                 newSingleflightLoader[V](metrics, 0),
-		now:                            time.Now,
-		random:                         rand.Float64,
-		steepness:                      steepness,
-		revalidationWindowMilliseconds: revalidationWindowMilliseconds,
-		maxLoadTimeout:                 0,
+		provider:       provider,
+		codec:          codec,
+		logger:         slog.New(noopLogHandler{}),
+		metrics:        metrics,
+		internalLoader: newSingleflightLoader[V](metrics, 0),
+		now:            time.Now,
+		random:         rand.Float64,
+		maxLoadTimeout: 0,
+		runner:         newAsyncRunner(),
+		loadLimiter:    newLoadLimiter(0),
+		metricLabels:   newMetricLabelLimiter(),
+
+		batchConcurrency: defaultBatchConcurrency,
 	}
+	cache.storeSettings(Settings{
+		RevalidationWindow: defaultRevalidationWindowMilliseconds * time.Millisecond,
+		FailOpen:           true,
+	})
 	for _, opt := range opts {
 		if opt == nil {
 			continue
 		}
 		opt(cache)
 	}
+	if cache.writerIdentity != nil {
+		cache.codec = withWriterHeader(cache.codec, *cache.writerIdentity, func() time.Time { return cache.now() })
+	}
+	cache.resolveObjectStorage()
+	cache.resolveReencode()
+	if _, noop := cache.metrics.(NoopMetricsProvider); !noop && !cache.skipProviderMetrics {
+		cache.provider = NewInstrumentedProvider(cache.provider, cache.metrics)
+	}
+	if cache.circuitBreakers != nil {
+		cache.circuitBreakers.inner = cache.provider
+		cache.provider = cache.circuitBreakers
+	}
+	if cache.loadAuditor != nil {
+		switch loader := cache.internalLoader.(type) {
+		case *singleflightLoader[V]:
+			loader.onFinish = cache.auditLoad()
+		case directLoader[V]:
+			loader.onFinish = cache.auditLoad()
+			cache.internalLoader = loader
+		}
+	}
+	cache.startedAt = cache.now()
+	cache.runner.onDrop = func(err error) {
+		cache.logger.Warn("dropped background error", slog.String("error", err.Error()))
+	}
 
 	return cache
 }
 
 // Get returns the cached entry for key, if present.
 func (c *cacheImpl[V, S]) Get(ctx context.Context, key string) (CacheObject[V], bool, error) {
+	co, ok, err := c.get(ctx, key)
+	if ok {
+		co.Value = c.cloneValue(co.Value)
+	}
+
+	return co, ok, err
+}
+
+// get implements Get, returning the value shared with the provider or the
+// request scope.
+func (c *cacheImpl[V, S]) get(ctx context.Context, key string) (CacheObject[V], bool, error) {
+	ctx = c.metricsContext(ctx)
+	if co, ok := c.scopeGet(ctx, key); ok {
+		return co, true, nil
+	}
 	c.metrics.RecordCacheGet(ctx)
 
+	started := time.Now()
 	rv, exists, err := c.provider.Get(ctx, key)
 	if err != nil {
-		return CacheObject[V]{}, false, err
+		return CacheObject[V]{}, false, c.observeError(ctx, ErrorPhaseProviderGet, key, started, err)
 	}
 	if !exists {
 		return CacheObject[V]{}, false, nil
 	}
 
-	co, err := c.codec.Decode(rv)
+	started = time.Now()
+	co, err := c.decode(key, rv)
 	if err != nil {
-		return CacheObject[V]{}, false, err
+		return CacheObject[V]{}, false, c.observeError(ctx, ErrorPhaseDecode, key, started, err)
 	}
 	c.metrics.RecordCacheHit(ctx)
+	c.checkMutation(ctx, key, co)
+	c.touchRecency(key)
+	c.verifyDoubleRead(ctx, key, co)
+	c.reencodeOutdated(ctx, key, rv, co)
+	c.scopeStore(ctx, key, co)
 
 	return co, true, nil
 }
 
 // Set stores a cache entry, skipping writes when already expired.
 func (c *cacheImpl[V, S]) Set(ctx context.Context, key string, value CacheObject[V]) error {
+	ctx = c.metricsContext(ctx)
+
+	return c.set(ctx, key, c.boundExpiry(ctx, key, value))
+}
+
+// set implements Set for entries whose expiry is already bounded.
+func (c *cacheImpl[V, S]) set(ctx context.Context, key string, value CacheObject[V]) error {
 	c.metrics.RecordCacheSet(ctx)
+	c.mutationCheck.forget(key)
 
-	encoded, err := c.codec.Encode(value)
+	started := time.Now()
+	raw, err := c.encode(key, value)
 	if err != nil {
-		return err
+		return c.observeError(ctx, ErrorPhaseEncode, key, started, err)
 	}
+	encoded := NewEncodedValue(raw)
 	ttl := time.UnixMilli(value.ExpireAtMillis).Sub(c.now())
 	if ttl <= 0 {
 		return nil
 	}
+	if c.skipIdenticalWrites && c.isIdenticalWrite(ctx, key, value) {
+		c.metrics.RecordIdenticalWriteSkipped(ctx)
+		c.scopeStore(ctx, key, value)
+
+		return nil
+	}
+
+	started = time.Now()
+	if err := c.provider.Set(ctx, key, encoded.Value(), ttl); err != nil {
+		return c.writeFailed(ctx, key, encoded.Value(), value.ExpireAtMillis, started, err)
+	}
+	c.cancelSetRetry(key)
+	c.written(ctx, key, value, encoded, ttl)
 
-	return c.provider.Set(ctx, key, encoded, ttl)
+	return nil
+}
+
+// writeFailed queues a retry of the failed provider write of value for key
+// and reports err.
+func (c *cacheImpl[V, S]) writeFailed(ctx context.Context, key string, value S, expireAtMillis int64, started time.Time, err error) error {
+	c.enqueueSetRetry(ctx, key, value, time.UnixMilli(expireAtMillis), err)
+
+	return c.observeError(ctx, ErrorPhaseProviderSet, key, started, err)
+}
+
+// written runs the bookkeeping that follows storing value for key.
+func (c *cacheImpl[V, S]) written(ctx context.Context, key string, value CacheObject[V], encoded EncodedValue[S], ttl time.Duration) {
+	if sized, ok := providerAs[SizedProvider](c.provider); ok {
+		c.metrics.RecordCacheSize(ctx, sized.Len(), sized.SizeBytes())
+	}
+	c.touchRecency(key)
+	c.tagKey(ctx, key)
+	c.scopeStore(ctx, key, value)
+	c.replicateSet(ctx, key, encoded, ttl)
 }
 
 // Delete removes a cached entry for key.
 func (c *cacheImpl[V, S]) Delete(ctx context.Context, key string) error {
+	ctx = c.metricsContext(ctx)
 	c.metrics.RecordCacheDelete(ctx)
+	c.scopeDelete(ctx, key)
+	c.cancelSetRetry(key)
+
+	started := time.Now()
+	if err := c.provider.Delete(ctx, key); err != nil {
+		return c.observeError(ctx, ErrorPhaseProviderDelete, key, started, err)
+	}
+	c.forgetRecency(key)
+	c.forgetTags(key)
+	c.replicateDelete(ctx, key)
+
+	return nil
+}
+
+// Errors returns the channel receiving background failures.
+func (c *cacheImpl[V, S]) Errors() <-chan error {
+	return c.runner.errors()
+}
 
-	return c.provider.Delete(ctx, key)
+// Flush waits until pending background work finishes or ctx is done.
+func (c *cacheImpl[V, S]) Flush(ctx context.Context) error {
+	return c.runner.flush(ctx)
+}
+
+// Close makes new GetOrLoad calls return ErrCacheClosed and waits until
+// running calls finish or ctx is done. Singleflight loads still running after
+// that are cancelled, so their waiters return ErrLoadCanceled. Background work
+// is then drained and stopped the same way.
+func (c *cacheImpl[V, S]) Close(ctx context.Context) error {
+	err := c.calls.close(ctx)
+	if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+		loader.cancelAll()
+	}
+	if closeErr := c.runner.close(ctx); err == nil {
+		err = closeErr
+	}
+
+	return err
 }
 
 // GetOrLoad returns a cached value or uses loader when missing or revalidating.
 func (c *cacheImpl[V, S]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader CacheLoadFunc[V]) (V, error) {
-	value, found, err := c.Get(ctx, key)
+	return c.getOrLoad(ctx, key, ttl, func(*CacheObject[V]) CacheLoadFunc[V] {
+		return loader
+	})
+}
+
+// getOrLoad implements GetOrLoad with a loader built from the entry being
+// revalidated, which is nil on a miss.
+func (c *cacheImpl[V, S]) getOrLoad(ctx context.Context, key string, ttl time.Duration, newLoader func(prev *CacheObject[V]) CacheLoadFunc[V]) (V, error) {
+	return c.getOrLoadBound(ctx, key, nil, ttl, newLoader, nil)
+}
+
+// getOrLoadBound is getOrLoad reusing the load key and singleflight target
+// precomputed by bound, when it is not nil. When out is not nil, it
+// receives how a successful call was served.
+func (c *cacheImpl[V, S]) getOrLoadBound(ctx context.Context, key string, bound *BoundKey[V, S], ttl time.Duration, newLoader func(prev *CacheObject[V]) CacheLoadFunc[V], out *loadOutcome) (V, error) {
+	v, err := c.serveOrLoad(ctx, key, bound, ttl, newLoader, out)
+	if err != nil {
+		return v, err
+	}
+
+	return c.cloneValue(v), nil
+}
+
+// serveOrLoad implements getOrLoadBound, returning the value shared with
+// the provider, the request scope and other callers of the same load.
+func (c *cacheImpl[V, S]) serveOrLoad(ctx context.Context, key string, bound *BoundKey[V, S], ttl time.Duration, newLoader func(prev *CacheObject[V]) CacheLoadFunc[V], out *loadOutcome) (V, error) {
+	ctx = c.metricsContext(ctx)
+	if !c.calls.enter() {
+		var zero V
+
+		return zero, ErrCacheClosed
+	}
+	defer c.calls.exit()
+	sample := c.sampleEffectiveness(key, out)
+	if sample != nil {
+		out = &sample.outcome
+		defer c.finishEffectiveness(sample)
+	}
+	ttl, err := c.resolveTTL(ctx, key, c.boundTTL(ctx, key, c.effectiveTTL(ttl)))
+	if err != nil {
+		var zero V
+
+		return zero, err
+	}
+	forced := forcedRefreshFrom(ctx)
+	if co, ok := c.scopeGet(ctx, key); ok && forced == nil {
+		out.served(BatchHit, co.ExpireAtMillis, c.entryAge(ttl, co.ExpireAtMillis))
+
+		return co.Value, nil
+	}
+	value, found, err := c.get(ctx, key)
 	if err != nil {
+		if !c.failOpen() {
+			var zero V
+
+			return zero, err
+		}
 		c.logger.Warn("failed to get from cache", slog.String("key", key), slog.String("error", err.Error()))
 		found = false
 	}
-	if found && !c.shouldRevalidate(c.now().UnixMilli(), value.ExpireAtMillis) {
+	// only a read that succeeded proves the key absent; a failed or
+	// undecodable read must not keep the stored payload
+	absent := !found && err == nil
+	if absent && forced != nil {
+		forced.uncached = true
+		var zero V
+
+		return zero, nil
+	}
+	if absent && c.filterAbsent(ctx, key) {
+		out.served(BatchHit, 0, 0)
+		var zero V
+
+		return zero, nil
+	}
+	nowMillis := c.now().UnixMilli()
+	expireAtMillis := value.ExpireAtMillis
+	if found {
+		expireAtMillis = c.skewedExpiry(ctx, nowMillis, expireAtMillis)
+	}
+	if found && forced == nil && (!c.shouldRevalidateKey(key, nowMillis, expireAtMillis) || !c.claimRefresh(ctx, key, nowMillis, expireAtMillis)) {
+		c.sampleLoad(ctx, key, value.Value, newLoader(&value))
+		c.observeAbsent(key, value.Value)
+		out.served(BatchHit, value.ExpireAtMillis, c.entryAge(ttl, value.ExpireAtMillis))
+
 		return value.Value, nil
 	}
 
-	v, leader, err := c.internalLoader.load(ctx, key, loader)
+	var prev *CacheObject[V]
+	if found {
+		prev = &value
+	}
+	started := time.Now()
+	ctx = c.withLoadTrigger(ctx, found, expireAtMillis)
+	var loadKey string
+	if bound != nil {
+		loadKey = bound.loadKey
+	} else {
+		loadKey = c.loadKey(key)
+	}
+	rl := c.newRemoteLoad(key, found, expireAtMillis)
+	defer c.releaseLoadLock(ctx, rl)
+	loaderFn := newLoader(prev)
+	ctx, flightKey := c.identifyLoader(ctx, loadKey, loaderFn)
+	loader := c.shareLoad(flightKey, c.coordinateLoad(rl, c.limitLoader(key, c.guardLoadedCost(key, loaderFn))))
+	var (
+		v      V
+		leader bool
+	)
+	if bound != nil && flightKey == loadKey {
+		v, leader, err = c.loadBound(ctx, bound, loader)
+	} else {
+		v, leader, err = c.internalLoader.load(ctx, flightKey, loader)
+	}
+	if leader {
+		c.recordLoadOutcome(ctx, err)
+	}
+	out.loaded(found, leader, time.Since(started))
 	if err != nil {
+		err = c.observeError(ctx, ErrorPhaseLoad, key, started, err)
+		if forced != nil {
+			var zero V
+
+			return zero, err
+		}
+		if stale, ok := c.serveStale(ctx, key, found, value, err); ok {
+			out.served(BatchStale, value.ExpireAtMillis, c.entryAge(ttl, value.ExpireAtMillis))
+
+			return stale, nil
+		}
 		var zero V
 
 		return zero, err
 	}
-	if leader {
-		co := CacheObject[V]{
-			Value:          v,
-			ExpireAtMillis: c.now().Add(ttl).UnixMilli(),
+	c.markRefreshed(key)
+	c.observeAbsent(key, v)
+	c.observeRefresh(sample, prev, v)
+	// followers of a load shared across keys store the value under their own key
+	if leader || loadKey != key {
+		loaded := v
+		ttl = c.adaptTTL(key, ttl, prev, v)
+		if c.cacheable(v) {
+			co := CacheObject[V]{
+				Value:          v,
+				ExpireAtMillis: c.now().Add(c.degradedTTL(ttl)).UnixMilli(),
+			}
+			v = c.storeLoaded(ctx, key, co, absent)
 		}
-		if err := c.Set(ctx, key, co); err != nil {
-			c.logger.Warn("failed to set cache", slog.String("key", key), slog.String("error", err.Error()))
+		if leader {
+			c.runShadowLoad(ctx, key, loaded)
 		}
 	}
+	expireAtMillis = c.now().Add(ttl).UnixMilli()
+	c.scopeStore(ctx, key, CacheObject[V]{Value: v, ExpireAtMillis: expireAtMillis})
+	out.served(BatchLoaded, expireAtMillis, 0)
 
 	return v, nil
 }
 
+// touchRecency queues an access to key for a sampled fraction of calls. A
+// single background worker drains the queue, so bursts of hits cost one
+// goroutine and one Touch per distinct key.
+func (c *cacheImpl[V, S]) touchRecency(key string) {
+	if c.recencyTracker == nil || !c.sampled(c.recencySampleRate) {
+		return
+	}
+	if !c.recencyQueue.add(key, c.now()) {
+		return
+	}
+	if !c.runner.goAsync(c.drainRecency) {
+		c.recencyQueue.stop()
+	}
+}
+
+// drainRecency records queued accesses until the queue is empty.
+func (c *cacheImpl[V, S]) drainRecency(ctx context.Context) error {
+	var errs []error
+	for {
+		batch := c.recencyQueue.take()
+		if batch == nil {
+			return errors.Join(errs...)
+		}
+		for key, at := range batch {
+			if err := c.recencyTracker.Touch(ctx, key, at); err != nil {
+				errs = append(errs, fmt.Errorf("record recency for %q: %w", key, err))
+			}
+		}
+	}
+}
+
+// sampled reports whether a call is selected for a feature sampled at rate.
+func (c *cacheImpl[V, S]) sampled(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+
+	return rate >= 1 || c.random() < rate
+}
+
 // shouldRevalidate returns true if the entry is expired, or if the remaining
 // TTL is within the revalidation window and a random draw falls under the
 // revalidation probability p(t)=1-exp(-steepness*t).
@@ -213,11 +770,12 @@ func (c *cacheImpl[V, S]) shouldRevalidate(nowMillis int64, expireAtMillis int64
 		return true
 	}
 
-	if remainMillis > c.revalidationWindowMilliseconds {
+	settings := c.settings.Load()
+	if remainMillis > settings.revalidationWindowMilliseconds {
 		return false
 	}
 
-	p := 1.0 - math.Exp(-c.steepness*float64(remainMillis))
+	p := (1.0 - math.Exp(-settings.steepness*float64(remainMillis))) * c.coldStartFactor(nowMillis)
 
 	return c.random() < p
 }
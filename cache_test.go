@@ -275,11 +275,8 @@ func TestCache_SetEncodeError(t *testing.T) {
 func TestCache_ShouldRevalidateProbability(t *testing.T) {
 	t.Parallel()
 
-	steepness, window := calculateSteepnessAndRevalidationWindow(1000)
-	cache := &cacheImpl[int, CacheObject[int]]{
-		steepness:                      steepness,
-		revalidationWindowMilliseconds: window,
-	}
+	cache := &cacheImpl[int, CacheObject[int]]{}
+	cache.settings.Store(newCacheSettings(Settings{RevalidationWindow: time.Second}))
 
 	cache.random = fakeRandom(0)
 	if !cache.shouldRevalidate(0, 500) {
@@ -423,6 +420,34 @@ func TestWithMaxLoadTimeout_SetsSingleflightTimeout(t *testing.T) {
 	}
 }
 
+func TestWithMaxLoadTimeoutFunc_SetsSingleflightTimeoutFunc(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithMaxLoadTimeout[int, CacheObject[int]](time.Second),
+		WithMaxLoadTimeoutFunc[int, CacheObject[int]](func(key string) time.Duration {
+			if key == "config" {
+				return 5 * time.Millisecond
+			}
+
+			return 800 * time.Millisecond
+		}),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+
+	loader, ok := impl.internalLoader.(*singleflightLoader[int])
+	if !ok {
+		t.Fatalf("expected internal loader to be singleflightLoader")
+	}
+	if got := loader.loadTimeout("config"); got != 5*time.Millisecond {
+		t.Fatalf("expected config timeout 5ms, got %v", got)
+	}
+	if got := loader.loadTimeout("recommendation"); got != 800*time.Millisecond {
+		t.Fatalf("expected recommendation timeout 800ms, got %v", got)
+	}
+}
+
 func TestWithRevalidationWindow_SetsValues(t *testing.T) {
 	t.Parallel()
 
@@ -431,13 +456,13 @@ func TestWithRevalidationWindow_SetsValues(t *testing.T) {
 	expectedSteepness, expectedWindow := calculateSteepnessAndRevalidationWindow(target.Milliseconds())
 
 	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithRevalidationWindow[int, CacheObject[int]](target))
-	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	settings := cache.(*cacheImpl[int, CacheObject[int]]).settings.Load()
 
-	if impl.steepness != expectedSteepness {
-		t.Fatalf("expected steepness %f, got %f", expectedSteepness, impl.steepness)
+	if settings.steepness != expectedSteepness {
+		t.Fatalf("expected steepness %f, got %f", expectedSteepness, settings.steepness)
 	}
-	if impl.revalidationWindowMilliseconds != expectedWindow {
-		t.Fatalf("expected revalidation window %d, got %d", expectedWindow, impl.revalidationWindowMilliseconds)
+	if settings.revalidationWindowMilliseconds != expectedWindow {
+		t.Fatalf("expected revalidation window %d, got %d", expectedWindow, settings.revalidationWindowMilliseconds)
 	}
 }
 
@@ -446,13 +471,13 @@ func TestWithRevalidationWindow_DefaultsOnZero(t *testing.T) {
 
 	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
 	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithRevalidationWindow[int, CacheObject[int]](0))
-	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	settings := cache.(*cacheImpl[int, CacheObject[int]]).settings.Load()
 
-	if impl.steepness != 0 {
-		t.Fatalf("expected steepness 0, got %f", impl.steepness)
+	if settings.steepness != 0 {
+		t.Fatalf("expected steepness 0, got %f", settings.steepness)
 	}
-	if impl.revalidationWindowMilliseconds != 0 {
-		t.Fatalf("expected revalidation window 0, got %d", impl.revalidationWindowMilliseconds)
+	if settings.revalidationWindowMilliseconds != 0 {
+		t.Fatalf("expected revalidation window 0, got %d", settings.revalidationWindowMilliseconds)
 	}
 }
 
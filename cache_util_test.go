@@ -2,6 +2,8 @@ package crema
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 )
@@ -35,6 +37,19 @@ func (m *testMemoryProvider[V]) Delete(_ context.Context, key string) error {
 	return nil
 }
 
+func (m *testMemoryProvider[V]) Scan(_ context.Context, pattern string, fn func(key string, value CacheObject[V]) bool) error {
+	m.mu.Lock()
+	items := maps.Clone(m.items)
+	m.mu.Unlock()
+	for _, key := range slices.Sorted(maps.Keys(items)) {
+		if MatchKeyPattern(pattern, key) && !fn(key, items[key]) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
 type byteProvider struct {
 	mu    sync.Mutex
 	items map[string][]byte
@@ -100,3 +115,7 @@ func fakeRandom(value float64) func() float64 {
 		return value
 	}
 }
+
+func newTestCache[V any]() Cache[V, CacheObject[V]] {
+	return NewCache(&testMemoryProvider[V]{items: make(map[string]CacheObject[V])}, NoopCacheStorageCodec[V]{})
+}
@@ -0,0 +1,33 @@
+package crema
+
+import "reflect"
+
+// WithCacheZeroValues controls whether GetOrLoad stores loaded zero values.
+// Zero values are stored by default, which negatively caches empty results;
+// pass false to reload them on every call instead.
+func WithCacheZeroValues[V any, S any](cacheZeroValues bool) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.skipZeroValues = !cacheZeroValues
+	}
+}
+
+// WithIsCacheable sets a predicate deciding whether GetOrLoad stores a loaded
+// value. Values it rejects are still returned to callers. It is applied after
+// WithCacheZeroValues.
+func WithIsCacheable[V any, S any](isCacheable func(value V) bool) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.isCacheable = isCacheable
+	}
+}
+
+// cacheable reports whether a loaded value should be stored.
+func (c *cacheImpl[V, S]) cacheable(value V) bool {
+	if c.skipZeroValues && reflect.ValueOf(&value).Elem().IsZero() {
+		return false
+	}
+	if c.isCacheable != nil {
+		return c.isCacheable(value)
+	}
+
+	return true
+}
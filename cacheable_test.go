@@ -0,0 +1,71 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithCacheZeroValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		cacheZeroValues bool
+		expectStored    bool
+	}{
+		{name: "stored", cacheZeroValues: true, expectStored: true},
+		{name: "skipped", cacheZeroValues: false, expectStored: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &testMemoryProvider[[]string]{items: make(map[string]CacheObject[[]string])}
+			cache := NewCache(provider, NoopCacheStorageCodec[[]string]{},
+				WithCacheZeroValues[[]string, CacheObject[[]string]](tt.cacheZeroValues),
+			)
+			if _, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) ([]string, error) {
+				return nil, nil
+			}); err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if _, ok := provider.items["key"]; ok != tt.expectStored {
+				t.Fatalf("expected stored=%v, got %v", tt.expectStored, ok)
+			}
+		})
+	}
+}
+
+func TestWithIsCacheable(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[[]string]{items: make(map[string]CacheObject[[]string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[[]string]{},
+		WithIsCacheable[[]string, CacheObject[[]string]](func(value []string) bool {
+			return len(value) > 0
+		}),
+	)
+
+	value, err := cache.GetOrLoad(context.Background(), "empty", time.Minute, func(context.Context) ([]string, error) {
+		return []string{}, nil
+	})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if value == nil {
+		t.Fatal("expected rejected value to be returned to the caller")
+	}
+	if _, ok := provider.items["empty"]; ok {
+		t.Fatal("expected empty value not to be stored")
+	}
+
+	if _, err := cache.GetOrLoad(context.Background(), "full", time.Minute, func(context.Context) ([]string, error) {
+		return []string{"a"}, nil
+	}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, ok := provider.items["full"]; !ok {
+		t.Fatal("expected non-empty value to be stored")
+	}
+}
@@ -0,0 +1,20 @@
+package crema
+
+import "errors"
+
+// ErrLoadCanceled is returned to callers waiting for a load aborted with CancelLoad.
+var ErrLoadCanceled = errors.New("load canceled")
+
+// CancelLoad aborts the inflight singleflight load for key, for example after
+// an upstream invalidation made its result useless. The loader context is
+// cancelled, waiting callers return ErrLoadCanceled, and the next GetOrLoad
+// starts a new load. It reports whether a load was aborted, and always
+// returns false with WithDirectLoader.
+func (c *cacheImpl[V, S]) CancelLoad(key string) bool {
+	loader, ok := c.internalLoader.(*singleflightLoader[V])
+	if !ok {
+		return false
+	}
+
+	return loader.cancelLoad(c.loadKey(key))
+}
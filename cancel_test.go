@@ -0,0 +1,83 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache_CancelLoadWakesWaiters(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	loader := impl.internalLoader.(*singleflightLoader[int])
+
+	loaderCanceled := make(chan struct{})
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				close(loaderCanceled)
+
+				return 0, ctx.Err()
+			})
+			errs <- err
+		}()
+	}
+	deadline := time.After(time.Second)
+	for {
+		shard := loader.shardFor("key")
+		shard.mu.Lock()
+		refs := 0
+		if inf, ok := shard.inflight["key"]; ok {
+			refs = inf.refs
+		}
+		shard.mu.Unlock()
+		if refs == 2 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for callers to join")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	if !cache.CancelLoad("key") {
+		t.Fatal("expected an inflight load to be canceled")
+	}
+	for range 2 {
+		if err := <-errs; !errors.Is(err, ErrLoadCanceled) {
+			t.Fatalf("expected ErrLoadCanceled, got %v", err)
+		}
+	}
+	select {
+	case <-loaderCanceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected loader context to be canceled")
+	}
+	if cache.CancelLoad("key") {
+		t.Fatal("expected no inflight load after cancel")
+	}
+
+	value, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+		return 3, nil
+	})
+	if err != nil || value != 3 {
+		t.Fatalf("expected a fresh load, got %d, %v", value, err)
+	}
+}
+
+func TestCache_CancelLoadDirectLoader(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithDirectLoader[int, CacheObject[int]]())
+	if cache.CancelLoad("key") {
+		t.Fatal("expected direct loader to have nothing to cancel")
+	}
+}
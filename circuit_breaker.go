@@ -0,0 +1,205 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by provider calls rejected by an open circuit
+// breaker configured with WithProviderCircuitBreakers.
+var ErrCircuitOpen = errors.New("cache provider circuit open")
+
+// CircuitState is the state of a provider circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed passes every call to the provider.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects calls with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a single probe call through to decide whether
+	// the circuit closes again.
+	CircuitHalfOpen
+)
+
+// String returns the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerPolicy configures WithProviderCircuitBreakers.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed calls that opens
+	// a circuit.
+	FailureThreshold int
+	// OpenDuration is how long an open circuit rejects calls before letting
+	// a probe through.
+	OpenDuration time.Duration
+}
+
+// circuitBreaker tracks consecutive failures of one provider path.
+type circuitBreaker struct {
+	_        noCopy
+	name     string
+	policy   CircuitBreakerPolicy
+	now      func() time.Time
+	logger   func() *slog.Logger
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// allow reports whether a call may proceed. A true result must be followed
+// by done with the call's error.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.policy.OpenDuration {
+			return false
+		}
+		b.transitionLocked(CircuitHalfOpen)
+		b.probing = true
+
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// done records the result of an allowed call. Canceled calls say nothing
+// about the provider and only end a probe.
+func (b *circuitBreaker) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	probe := b.state == CircuitHalfOpen
+	if probe {
+		b.probing = false
+	}
+	switch {
+	case ClassifyProviderError(err) == ProviderErrorCanceled:
+	case err == nil:
+		b.failures = 0
+		if probe {
+			b.transitionLocked(CircuitClosed)
+		}
+	default:
+		b.failures++
+		if probe || b.failures >= b.policy.FailureThreshold {
+			b.openedAt = b.now()
+			b.transitionLocked(CircuitOpen)
+		}
+	}
+}
+
+func (b *circuitBreaker) transitionLocked(state CircuitState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	b.logger().Warn("cache provider circuit changed", slog.String("path", b.name), slog.String("state", state.String()))
+}
+
+func (b *circuitBreaker) currentState() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.policy.OpenDuration {
+		return CircuitHalfOpen
+	}
+
+	return b.state
+}
+
+// circuitBreakerProvider guards the read and write paths of a provider with
+// independent circuit breakers.
+type circuitBreakerProvider[S any] struct {
+	inner CacheProvider[S]
+	read  *circuitBreaker
+	write *circuitBreaker
+}
+
+var _ CacheProvider[any] = &circuitBreakerProvider[any]{}
+
+// WithProviderCircuitBreakers guards provider reads (Get) and writes (Set
+// and Delete) with independent circuit breakers, so a replica failure
+// affecting reads does not also stop writes, or the other way around. A
+// circuit opens after policy.FailureThreshold consecutive failures and then
+// rejects calls on its path with ErrCircuitOpen; GetOrLoad treats a rejected
+// read like any other read error and loads when fail-open is enabled. After
+// policy.OpenDuration one probe call is let through: its success closes the
+// circuit and its failure keeps it open for another OpenDuration. States are
+// reported by Stats and transitions are logged as warnings. Optional provider
+// interfaces such as GetOrSetProvider are not guarded. A non-positive
+// threshold or duration disables the breakers.
+func WithProviderCircuitBreakers[V any, S any](policy CircuitBreakerPolicy) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if policy.FailureThreshold <= 0 || policy.OpenDuration <= 0 {
+			c.circuitBreakers = nil
+
+			return
+		}
+		now := func() time.Time { return c.now() }
+		logger := func() *slog.Logger { return c.logger }
+		c.circuitBreakers = &circuitBreakerProvider[S]{
+			read:  &circuitBreaker{name: "read", policy: policy, now: now, logger: logger},
+			write: &circuitBreaker{name: "write", policy: policy, now: now, logger: logger},
+		}
+	}
+}
+
+func (p *circuitBreakerProvider[S]) Get(ctx context.Context, key string) (S, bool, error) {
+	if !p.read.allow() {
+		var zero S
+
+		return zero, false, ErrCircuitOpen
+	}
+	value, ok, err := p.inner.Get(ctx, key)
+	p.read.done(err)
+
+	return value, ok, err
+}
+
+func (p *circuitBreakerProvider[S]) Set(ctx context.Context, key string, value S, ttl time.Duration) error {
+	if !p.write.allow() {
+		return ErrCircuitOpen
+	}
+	err := p.inner.Set(ctx, key, value, ttl)
+	p.write.done(err)
+
+	return err
+}
+
+func (p *circuitBreakerProvider[S]) Delete(ctx context.Context, key string) error {
+	if !p.write.allow() {
+		return ErrCircuitOpen
+	}
+	err := p.inner.Delete(ctx, key)
+	p.write.done(err)
+
+	return err
+}
+
+// Unwrap returns the wrapped provider.
+func (p *circuitBreakerProvider[S]) Unwrap() CacheProvider[S] {
+	return p.inner
+}
@@ -0,0 +1,102 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestWithProviderCircuitBreakers_IsolatesReadsAndWrites(t *testing.T) {
+	t.Parallel()
+
+	errRead := errors.New("replica down")
+	provider := &errorProvider[CacheObject[int]]{getErr: errRead}
+	cache := NewCache[int, CacheObject[int]](provider, NoopCacheStorageCodec[int]{},
+		WithProviderCircuitBreakers[int, CacheObject[int]](CircuitBreakerPolicy{FailureThreshold: 2, OpenDuration: time.Second}),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 2 {
+		if _, _, err := cache.Get(ctx, "key"); !errors.Is(err, errRead) {
+			t.Fatalf("expected provider error, got %v", err)
+		}
+	}
+	if _, _, err := cache.Get(ctx, "key"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if stats := cache.Stats(); stats.ReadCircuit != CircuitOpen || stats.WriteCircuit != CircuitClosed {
+		t.Fatalf("expected open reads and closed writes, got %v and %v", stats.ReadCircuit, stats.WriteCircuit)
+	}
+	if err := cache.Set(ctx, "key", CacheObject[int]{Value: 1, ExpireAtMillis: now.Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("expected writes to pass, got %v", err)
+	}
+	if v, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) { return 2, nil }); err != nil || v != 2 {
+		t.Fatalf("expected fail-open load, got %d, %v", v, err)
+	}
+
+	// A failed probe keeps the circuit open.
+	now = now.Add(time.Second)
+	if got := cache.Stats().ReadCircuit; got != CircuitHalfOpen {
+		t.Fatalf("expected half-open, got %v", got)
+	}
+	if _, _, err := cache.Get(ctx, "key"); !errors.Is(err, errRead) {
+		t.Fatalf("expected probe to reach the provider, got %v", err)
+	}
+	if _, _, err := cache.Get(ctx, "key"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen after failed probe, got %v", err)
+	}
+
+	// A successful probe closes it.
+	now = now.Add(time.Second)
+	provider.getErr = nil
+	if _, _, err := cache.Get(ctx, "key"); err != nil {
+		t.Fatalf("expected probe to succeed, got %v", err)
+	}
+	if got := cache.Stats().ReadCircuit; got != CircuitClosed {
+		t.Fatalf("expected closed, got %v", got)
+	}
+}
+
+func TestCircuitBreaker_SingleProbeAndCancellation(t *testing.T) {
+	t.Parallel()
+
+	now := time.UnixMilli(0)
+	b := &circuitBreaker{
+		name:   "read",
+		policy: CircuitBreakerPolicy{FailureThreshold: 1, OpenDuration: time.Second},
+		now:    func() time.Time { return now },
+		logger: func() *slog.Logger { return slog.New(noopLogHandler{}) },
+	}
+	if !b.allow() {
+		t.Fatal("expected closed circuit to allow")
+	}
+	b.done(context.Canceled)
+	if b.currentState() != CircuitClosed {
+		t.Fatalf("expected canceled call not to count, got %v", b.currentState())
+	}
+	b.allow()
+	b.done(errors.New("boom"))
+	if b.allow() {
+		t.Fatal("expected open circuit to reject")
+	}
+	now = now.Add(time.Second)
+	if !b.allow() {
+		t.Fatal("expected probe to be allowed")
+	}
+	if b.allow() {
+		t.Fatal("expected a second concurrent probe to be rejected")
+	}
+	b.done(context.Canceled)
+	if !b.allow() {
+		t.Fatal("expected a new probe after a canceled one")
+	}
+	b.done(nil)
+	if b.currentState() != CircuitClosed {
+		t.Fatalf("expected closed, got %v", b.currentState())
+	}
+}
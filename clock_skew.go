@@ -0,0 +1,33 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// WithClockSkewTolerance treats entries as unexpired until tolerance after
+// their ExpireAtMillis when deciding whether to serve, revalidate or report
+// them as stale. Instances in other regions write expiries from their own
+// clocks, and a local clock running ahead would otherwise see their entries
+// expire early and refresh them redundantly. Each decision the tolerance
+// changes is reported through MetricsProvider.RecordClockSkewTolerated. A
+// non-positive tolerance disables it.
+func WithClockSkewTolerance[V any, S any](tolerance time.Duration) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.clockSkewTolerance = max(tolerance, 0)
+	}
+}
+
+// skewedExpiry returns expireAtMillis extended by the clock skew tolerance,
+// recording when that keeps an entry that expired at nowMillis alive.
+func (c *cacheImpl[V, S]) skewedExpiry(ctx context.Context, nowMillis int64, expireAtMillis int64) int64 {
+	if c.clockSkewTolerance <= 0 {
+		return expireAtMillis
+	}
+	skewed := expireAtMillis + c.clockSkewTolerance.Milliseconds()
+	if expireAtMillis <= nowMillis && nowMillis < skewed {
+		c.metrics.RecordClockSkewTolerated(ctx)
+	}
+
+	return skewed
+}
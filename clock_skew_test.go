@@ -0,0 +1,61 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type clockSkewMetricsProvider struct {
+	BaseMetricsProvider
+	tolerated atomic.Int32
+}
+
+func (m *clockSkewMetricsProvider) RecordClockSkewTolerated(context.Context) {
+	m.tolerated.Add(1)
+}
+
+func TestWithClockSkewTolerance(t *testing.T) {
+	t.Parallel()
+
+	metrics := &clockSkewMetricsProvider{}
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithClockSkewTolerance[int, CacheObject[int]](5*time.Second),
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(1)
+	ctx := context.Background()
+
+	loads := 0
+	loader := func(context.Context) (int, error) {
+		loads++
+
+		return 2, nil
+	}
+	provider.items["key"] = CacheObject[int]{Value: 1, ExpireAtMillis: now.Add(-time.Second).UnixMilli()}
+	if v, err := cache.GetOrLoad(ctx, "key", time.Minute, loader); err != nil || v != 1 {
+		t.Fatalf("expected entry within tolerance to be served, got %d, %v", v, err)
+	}
+	if got := cache.GetMany(ctx, []string{"key"}).Items[0].Status; got != BatchHit {
+		t.Fatalf("expected hit within tolerance, got %v", got)
+	}
+	if loads != 0 {
+		t.Fatalf("expected no load, got %d", loads)
+	}
+	if got := metrics.tolerated.Load(); got != 2 {
+		t.Fatalf("expected 2 tolerated decisions, got %d", got)
+	}
+
+	provider.items["key"] = CacheObject[int]{Value: 1, ExpireAtMillis: now.Add(-6 * time.Second).UnixMilli()}
+	if v, err := cache.GetOrLoad(ctx, "key", time.Minute, loader); err != nil || v != 2 {
+		t.Fatalf("expected entry beyond tolerance to be reloaded, got %d, %v", v, err)
+	}
+	if got := metrics.tolerated.Load(); got != 2 {
+		t.Fatalf("expected no further tolerated decisions, got %d", got)
+	}
+}
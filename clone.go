@@ -0,0 +1,153 @@
+package crema
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+const maxMutationCheckKeys = 4096
+
+// Cloner returns a deep copy of value that shares no mutable state with it.
+// Caches holding pointers, maps or slices use it with WithCloneOnHit so
+// callers can modify returned values.
+type Cloner[V any] func(value V) V
+
+// MethodCloner returns a Cloner calling the Clone method of values, which
+// must deep copy them. Nil pointers are returned as is by typical Clone
+// implementations.
+func MethodCloner[V interface{ Clone() V }]() Cloner[V] {
+	return func(value V) V {
+		return value.Clone()
+	}
+}
+
+// ShallowCloner returns a Cloner copying the struct a pointer points to. It
+// is a deep copy only for structs without pointer, map, slice or channel
+// fields; nil pointers are returned as is.
+func ShallowCloner[T any]() Cloner[*T] {
+	return func(value *T) *T {
+		if value == nil {
+			return nil
+		}
+		clone := *value
+
+		return &clone
+	}
+}
+
+// WithCloneOnHit returns a copy made by cloner from Get, GetMany and the
+// GetOrLoad family instead of the value the cache shares. Without it, a
+// pointer, map or slice value is shared between every caller served from a
+// NoopCacheStorageCodec provider, a request scope or one singleflight load,
+// and also with the stored entry, so a caller modifying it corrupts the
+// cache for everyone. Values passed to Set or returned by loaders must still
+// not be modified after the call. A nil cloner disables cloning.
+func WithCloneOnHit[V any, S any](cloner Cloner[V]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.cloner = cloner
+	}
+}
+
+// cloneValue returns a copy of v made by the configured cloner, or v.
+func (c *cacheImpl[V, S]) cloneValue(v V) V {
+	if c.cloner == nil {
+		return v
+	}
+
+	return c.cloner(v)
+}
+
+// MutationCheckPolicy configures WithMutationCheck.
+type MutationCheckPolicy[V any] struct {
+	// SampleRate is the fraction of hits checked, in (0, 1]. Defaults to 1.
+	SampleRate float64
+	// Hash fingerprints values. Nil hashes the JSON encoding of values,
+	// which only covers exported fields.
+	Hash func(value V) (uint64, error)
+	// OnMutation is called with the key of every mutated entry found. Nil
+	// logs a warning; tests may fail or panic instead.
+	OnMutation func(ctx context.Context, key string)
+}
+
+// mutationCheck remembers the fingerprints of entries served from the
+// provider.
+type mutationCheck[V any] struct {
+	policy  MutationCheckPolicy[V]
+	mu      sync.Mutex
+	entries map[string]mutationFingerprint
+}
+
+type mutationFingerprint struct {
+	expireAtMillis int64
+	hash           uint64
+}
+
+// WithMutationCheck is a debugging aid that detects callers modifying cached
+// values after they were returned, a bug only possible when values are
+// pointers, maps or slices shared by the provider, such as a
+// MemoryCacheProvider with a NoopCacheStorageCodec. It fingerprints entries
+// read from the provider and reports an entry whose value changed between two
+// reads without being rewritten through RecordValueMutation and
+// policy.OnMutation. Hashing every hit is expensive, so enable it in tests
+// and development or with a low SampleRate. Up to 4096 keys are tracked at a
+// time.
+func WithMutationCheck[V any, S any](policy MutationCheckPolicy[V]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if policy.SampleRate <= 0 || policy.SampleRate > 1 {
+			policy.SampleRate = 1
+		}
+		if policy.Hash == nil {
+			policy.Hash = hashJSON[V]
+		}
+		c.mutationCheck = &mutationCheck[V]{policy: policy, entries: make(map[string]mutationFingerprint)}
+	}
+}
+
+// checkMutation compares the fingerprint of co read for key with the one
+// recorded when the same entry was read before.
+func (c *cacheImpl[V, S]) checkMutation(ctx context.Context, key string, co CacheObject[V]) {
+	m := c.mutationCheck
+	if m == nil || !c.sampled(m.policy.SampleRate) {
+		return
+	}
+	hash, err := m.policy.Hash(co.Value)
+	if err != nil {
+		return
+	}
+	if !m.observe(key, mutationFingerprint{expireAtMillis: co.ExpireAtMillis, hash: hash}) {
+		return
+	}
+	c.metrics.RecordValueMutation(ctx)
+	if m.policy.OnMutation != nil {
+		m.policy.OnMutation(ctx, key)
+
+		return
+	}
+	c.logger.Warn("cached value was modified after it was returned; use WithCloneOnHit or stop modifying it",
+		slog.String("key", key))
+}
+
+// observe records fp for key and reports whether the entry read before with
+// the same expiry had a different value.
+func (m *mutationCheck[V]) observe(key string, fp mutationFingerprint) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prev, ok := m.entries[key]
+	if !ok && len(m.entries) >= maxMutationCheckKeys {
+		clear(m.entries)
+	}
+	m.entries[key] = fp
+
+	return ok && prev.expireAtMillis == fp.expireAtMillis && prev.hash != fp.hash
+}
+
+// forget drops the fingerprint of key after it was rewritten.
+func (m *mutationCheck[V]) forget(key string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
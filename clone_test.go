@@ -0,0 +1,130 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type clonedUser struct {
+	Name string
+	Tags []string
+}
+
+func (u *clonedUser) Clone() *clonedUser {
+	if u == nil {
+		return nil
+	}
+	clone := *u
+	clone.Tags = append([]string(nil), u.Tags...)
+
+	return &clone
+}
+
+type mutationMetricsProvider struct {
+	BaseMetricsProvider
+	mutations atomic.Int64
+}
+
+func (m *mutationMetricsProvider) RecordValueMutation(context.Context) {
+	m.mutations.Add(1)
+}
+
+func TestWithCloneOnHit_IsolatesCallers(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[CacheObject[*clonedUser]]()
+	cache := NewCache(provider, NoopCacheStorageCodec[*clonedUser]{},
+		WithCloneOnHit[*clonedUser, CacheObject[*clonedUser]](MethodCloner[*clonedUser]()),
+	)
+	ctx := context.Background()
+	loader := func(context.Context) (*clonedUser, error) {
+		return &clonedUser{Name: "alice", Tags: []string{"admin"}}, nil
+	}
+
+	loaded, err := cache.GetOrLoad(ctx, "user", time.Minute, loader)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	loaded.Name = "mallory"
+	loaded.Tags[0] = "root"
+
+	hit, err := cache.GetOrLoad(ctx, "user", time.Minute, loader)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if hit.Name != "alice" || hit.Tags[0] != "admin" {
+		t.Fatalf("expected the cached value untouched, got %+v", hit)
+	}
+	hit.Name = "eve"
+	co, ok, err := cache.Get(ctx, "user")
+	if err != nil || !ok || co.Value.Name != "alice" {
+		t.Fatalf("expected Get to return an untouched copy, got %+v, %v, %v", co.Value, ok, err)
+	}
+	if co.Value == hit {
+		t.Fatal("expected every call to receive its own copy")
+	}
+}
+
+func TestShallowCloner(t *testing.T) {
+	t.Parallel()
+
+	cloner := ShallowCloner[clonedUser]()
+	if cloner(nil) != nil {
+		t.Fatal("expected nil to stay nil")
+	}
+	user := &clonedUser{Name: "alice"}
+	clone := cloner(user)
+	clone.Name = "bob"
+	if user.Name != "alice" {
+		t.Fatalf("expected the original untouched, got %q", user.Name)
+	}
+}
+
+func TestWithMutationCheck_DetectsSharedMutation(t *testing.T) {
+	t.Parallel()
+
+	metrics := &mutationMetricsProvider{}
+	var mutated []string
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[*clonedUser]](), NoopCacheStorageCodec[*clonedUser]{},
+		WithMetricsProvider[*clonedUser, CacheObject[*clonedUser]](metrics),
+		WithMutationCheck[*clonedUser, CacheObject[*clonedUser]](MutationCheckPolicy[*clonedUser]{
+			OnMutation: func(_ context.Context, key string) { mutated = append(mutated, key) },
+		}),
+	)
+	ctx := context.Background()
+	expireAt := time.Now().Add(time.Minute).UnixMilli()
+	if err := cache.Set(ctx, "user", CacheObject[*clonedUser]{Value: &clonedUser{Name: "alice"}, ExpireAtMillis: expireAt}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	co, _, err := cache.Get(ctx, "user")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if _, _, err := cache.Get(ctx, "user"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(mutated) != 0 {
+		t.Fatalf("expected no mutation before modifying the value, got %v", mutated)
+	}
+
+	co.Value.Name = "mallory"
+	if _, _, err := cache.Get(ctx, "user"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(mutated) != 1 || mutated[0] != "user" || metrics.mutations.Load() != 1 {
+		t.Fatalf("expected one mutation of user, got %v and %d", mutated, metrics.mutations.Load())
+	}
+
+	if err := cache.Set(ctx, "user", CacheObject[*clonedUser]{Value: &clonedUser{Name: "bob"}, ExpireAtMillis: expireAt}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, _, err := cache.Get(ctx, "user"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if len(mutated) != 1 {
+		t.Fatalf("expected a rewrite not to count as a mutation, got %v", mutated)
+	}
+}
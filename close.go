@@ -0,0 +1,78 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrCacheClosed is returned by GetOrLoad and the calls built on it once
+// Close has been called.
+var ErrCacheClosed = errors.New("cache closed")
+
+// callTracker counts the GetOrLoad calls in progress so that Close can stop
+// new calls and wait for running ones to finish.
+type callTracker struct {
+	mu     sync.Mutex
+	closed bool
+	active int
+	idleCh chan struct{}
+}
+
+// enter registers a call. It returns false once the tracker is closed.
+func (t *callTracker) enter() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	t.active++
+
+	return true
+}
+
+func (t *callTracker) exit() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active--
+	if t.active == 0 && t.idleCh != nil {
+		close(t.idleCh)
+		t.idleCh = nil
+	}
+}
+
+// close rejects further calls and waits until the running ones finish or ctx
+// is done.
+func (t *callTracker) close(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	if t.active == 0 {
+		t.mu.Unlock()
+
+		return nil
+	}
+	if t.idleCh == nil {
+		t.idleCh = make(chan struct{})
+	}
+	idleCh := t.idleCh
+	t.mu.Unlock()
+
+	select {
+	case <-idleCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelAll aborts every unfinished inflight load like cancelLoad.
+func (l *singleflightLoader[V]) cancelAll() {
+	for i := range l.shards {
+		shard := &l.shards[i]
+		shard.mu.Lock()
+		for key := range shard.inflight {
+			l.abortLocked(shard, key)
+		}
+		shard.mu.Unlock()
+	}
+}
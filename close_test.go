@@ -0,0 +1,173 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitForInflightLoads(t *testing.T, cache Cache[int, CacheObject[int]], want int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for len(cache.InflightLoads()) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d inflight loads, got %d", want, len(cache.InflightLoads()))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCache_CloseRejectsNewLoads(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	var calls atomic.Int32
+	_, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+		calls.Add(1)
+
+		return 1, nil
+	})
+	if !errors.Is(err, ErrCacheClosed) {
+		t.Fatalf("expected ErrCacheClosed, got %v", err)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("expected loader not to run, got %d calls", got)
+	}
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatalf("expected repeated close to succeed, got %v", err)
+	}
+}
+
+func TestCache_CloseDrainsRunningLoads(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	release := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+			<-release
+
+			return 1, nil
+		})
+		result <- err
+	}()
+	waitForInflightLoads(t, cache, 1)
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- cache.Close(context.Background())
+	}()
+	select {
+	case err := <-closed:
+		t.Fatalf("expected close to wait for the running load, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+
+	if err := <-closed; err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Fatalf("expected drained load to succeed, got %v", err)
+	}
+	if _, ok := provider.items["key"]; !ok {
+		t.Fatal("expected drained load to be stored")
+	}
+}
+
+func TestCache_CloseCancelsLoadsAfterDeadline(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	loadCanceled := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			close(loadCanceled)
+
+			return 0, ctx.Err()
+		})
+		result <- err
+	}()
+	waitForInflightLoads(t, cache, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := cache.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if err := <-result; !errors.Is(err, ErrLoadCanceled) {
+		t.Fatalf("expected ErrLoadCanceled, got %v", err)
+	}
+	select {
+	case <-loadCanceled:
+	case <-time.After(time.Second):
+		t.Fatal("expected loader context to be cancelled")
+	}
+}
+
+func TestCache_CloseDuringConcurrentLoads(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	var (
+		wg   sync.WaitGroup
+		stop atomic.Bool
+	)
+	failures := make(chan error, 32)
+	for i := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; !stop.Load(); n++ {
+				key := fmt.Sprintf("key-%d", (i+n)%8)
+				_, err := cache.GetOrLoad(context.Background(), key, time.Millisecond, func(context.Context) (int, error) {
+					time.Sleep(time.Millisecond)
+
+					return n, nil
+				})
+				switch {
+				case err == nil, errors.Is(err, ErrLoadCanceled):
+				case errors.Is(err, ErrCacheClosed):
+					return
+				default:
+					failures <- err
+
+					return
+				}
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_ = cache.Close(ctx)
+	stop.Store(true)
+	wg.Wait()
+
+	close(failures)
+	for err := range failures {
+		t.Fatalf("expected only closed or canceled errors, got %v", err)
+	}
+	if loads := cache.InflightLoads(); len(loads) != 0 {
+		t.Fatalf("expected no inflight loads after close, got %v", loads)
+	}
+	if _, err := cache.GetOrLoad(context.Background(), "key-0", time.Minute, func(context.Context) (int, error) {
+		return 1, nil
+	}); !errors.Is(err, ErrCacheClosed) {
+		t.Fatalf("expected ErrCacheClosed, got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchLoadFunc loads values for multiple keys in one call. Keys missing from
+// the returned map fail with ErrBatchKeyNotLoaded.
+type BatchLoadFunc[V any] func(ctx context.Context, keys []string) (map[string]V, error)
+
+var (
+	// ErrLoadCoalescingDisabled is returned by GetOrBatchLoad when WithLoadCoalescing is not configured.
+	ErrLoadCoalescingDisabled = errors.New("load coalescing is not configured")
+	// ErrBatchKeyNotLoaded is returned when a batch loader omits a requested key.
+	ErrBatchKeyNotLoaded = errors.New("batch loader returned no value for key")
+)
+
+// defaultMaxCoalescedBatch is the default number of distinct keys after which
+// a coalesced batch is loaded without waiting for the window to end.
+const defaultMaxCoalescedBatch = 1000
+
+// ErrBatchLoaderPanicked is returned to the callers of a batch whose loader
+// panicked.
+var ErrBatchLoaderPanicked = errors.New("batch loader panicked")
+
+type coalescedResult[V any] struct {
+	val V
+	err error
+}
+
+type coalescedBatch[V any] struct {
+	ctx     context.Context
+	timer   *time.Timer
+	waiters map[string][]chan coalescedResult[V]
+	keys    []string
+}
+
+// loadCoalescer collects distinct keys requested within a window and loads
+// them with a single batch loader call.
+type loadCoalescer[V any] struct {
+	_        noCopy
+	mu       sync.Mutex
+	window   time.Duration
+	maxBatch int
+	loader   BatchLoadFunc[V]
+	runner   *asyncRunner
+	pending  *coalescedBatch[V]
+}
+
+// LoadCoalescingOption configures WithLoadCoalescing.
+type LoadCoalescingOption func(*loadCoalescingConfig)
+
+type loadCoalescingConfig struct {
+	maxBatch int
+}
+
+// WithMaxBatchSize caps the distinct keys of one batch, 1000 by default. A
+// batch that reaches it is loaded at once instead of at the end of the
+// window. Non-positive values are ignored.
+func WithMaxBatchSize(n int) LoadCoalescingOption {
+	return func(cfg *loadCoalescingConfig) {
+		if n > 0 {
+			cfg.maxBatch = n
+		}
+	}
+}
+
+func newLoadCoalescer[V any](window time.Duration, loader BatchLoadFunc[V], runner *asyncRunner, maxBatch int) *loadCoalescer[V] {
+	return &loadCoalescer[V]{window: window, maxBatch: maxBatch, loader: loader, runner: runner}
+}
+
+// WithLoadCoalescing batches loads started by GetOrBatchLoad within window
+// into a single call to loader, turning a burst of point lookups into one
+// backend query. The batch runs in the background with the values and
+// deadline, such as one set by WithMaxLoadTimeout, of its first caller's load
+// context, detached from its cancellation, and is cancelled when the cache
+// closes. A panic in loader fails every
+// caller of the batch with ErrBatchLoaderPanicked.
+func WithLoadCoalescing[V any, S any](window time.Duration, loader BatchLoadFunc[V], opts ...LoadCoalescingOption) CacheOption[V, S] {
+	cfg := loadCoalescingConfig{maxBatch: defaultMaxCoalescedBatch}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return func(c *cacheImpl[V, S]) {
+		if loader == nil {
+			c.coalescer = nil
+
+			return
+		}
+		c.coalescer = newLoadCoalescer(max(window, 0), loader, c.runner, cfg.maxBatch)
+	}
+}
+
+// load enqueues key into the pending batch and waits for its result.
+func (l *loadCoalescer[V]) load(ctx context.Context, key string) (V, error) {
+	ch := make(chan coalescedResult[V], 1)
+
+	l.mu.Lock()
+	if l.pending == nil {
+		batch := &coalescedBatch[V]{
+			ctx:     ctx,
+			waiters: make(map[string][]chan coalescedResult[V]),
+		}
+		batch.timer = time.AfterFunc(l.window, func() { l.flush(batch) })
+		l.pending = batch
+	}
+	batch := l.pending
+	if _, ok := batch.waiters[key]; !ok {
+		batch.keys = append(batch.keys, key)
+	}
+	batch.waiters[key] = append(batch.waiters[key], ch)
+	full := len(batch.keys) >= l.maxBatch
+	l.mu.Unlock()
+	if full {
+		batch.timer.Stop()
+		l.flush(batch)
+	}
+
+	select {
+	case <-ctx.Done():
+		var zero V
+
+		return zero, ctx.Err()
+	case res := <-ch:
+		return res.val, res.err
+	}
+}
+
+// flush takes batch out of the pending slot, unless another flush already
+// did, and loads it in the background.
+func (l *loadCoalescer[V]) flush(batch *coalescedBatch[V]) {
+	l.mu.Lock()
+	if l.pending != batch {
+		l.mu.Unlock()
+
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+
+	started := l.runner.goDetached(batch.ctx, func(ctx context.Context) error {
+		if deadline, ok := batch.ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, deadline)
+			defer cancel()
+		}
+		values, err := l.run(ctx, batch.keys)
+		batch.deliver(values, err)
+
+		return nil
+	})
+	if !started {
+		batch.deliver(nil, ErrCacheClosed)
+	}
+}
+
+// run calls the batch loader, turning a panic into ErrBatchLoaderPanicked.
+func (l *loadCoalescer[V]) run(ctx context.Context, keys []string) (values map[string]V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			values, err = nil, fmt.Errorf("%w: %v", ErrBatchLoaderPanicked, r)
+		}
+	}()
+
+	return l.loader(ctx, keys)
+}
+
+// deliver sends every waiter of the batch its key's result.
+func (b *coalescedBatch[V]) deliver(values map[string]V, err error) {
+	for key, waiters := range b.waiters {
+		res := coalescedResult[V]{err: err}
+		if err == nil {
+			v, ok := values[key]
+			if ok {
+				res.val = v
+			} else {
+				res.err = ErrBatchKeyNotLoaded
+			}
+		}
+		for _, ch := range waiters {
+			ch <- res
+		}
+	}
+}
+
+// GetOrBatchLoad returns a cached value or loads it through the batch loader
+// configured with WithLoadCoalescing, sharing the call with other keys
+// requested within the coalescing window.
+func (c *cacheImpl[V, S]) GetOrBatchLoad(ctx context.Context, key string, ttl time.Duration) (V, error) {
+	coalescer := c.coalescer
+	if coalescer == nil {
+		var zero V
+
+		return zero, ErrLoadCoalescingDisabled
+	}
+
+	return c.GetOrLoad(ctx, key, ttl, func(ctx context.Context) (V, error) {
+		return coalescer.load(ctx, key)
+	})
+}
@@ -0,0 +1,205 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrBatchLoad_CoalescesKeys(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		batches [][]string
+	)
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithLoadCoalescing[string, CacheObject[string]](20*time.Millisecond, func(_ context.Context, keys []string) (map[string]string, error) {
+			mu.Lock()
+			batches = append(batches, slices.Sorted(slices.Values(keys)))
+			mu.Unlock()
+			values := make(map[string]string, len(keys))
+			for _, key := range keys {
+				if key != "absent" {
+					values[key] = "value:" + key
+				}
+			}
+
+			return values, nil
+		}),
+	)
+	ctx := context.Background()
+
+	keys := []string{"a", "b", "c", "absent"}
+	results := make([]string, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = cache.GetOrBatchLoad(ctx, key, time.Minute)
+		}()
+	}
+	wg.Wait()
+
+	if len(batches) != 1 || !slices.Equal(batches[0], []string{"a", "absent", "b", "c"}) {
+		t.Fatalf("expected a single batch with all keys, got %v", batches)
+	}
+	for i, key := range keys[:3] {
+		if errs[i] != nil || results[i] != "value:"+key {
+			t.Fatalf("unexpected result for %s: %q, %v", key, results[i], errs[i])
+		}
+	}
+	if !errors.Is(errs[3], ErrBatchKeyNotLoaded) {
+		t.Fatalf("expected ErrBatchKeyNotLoaded for absent key, got %v", errs[3])
+	}
+	if _, ok := provider.items["a"]; !ok {
+		t.Fatal("expected batch-loaded value to be stored")
+	}
+}
+
+func TestGetOrBatchLoad_PropagatesBatchError(t *testing.T) {
+	t.Parallel()
+
+	expectErr := errors.New("backend down")
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithLoadCoalescing[string, CacheObject[string]](0, func(context.Context, []string) (map[string]string, error) {
+			return nil, expectErr
+		}),
+	)
+
+	if _, err := cache.GetOrBatchLoad(context.Background(), "a", time.Minute); !errors.Is(err, expectErr) {
+		t.Fatalf("expected %v, got %v", expectErr, err)
+	}
+}
+
+func TestGetOrBatchLoad_RequiresCoalescing(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{})
+
+	if _, err := cache.GetOrBatchLoad(context.Background(), "a", time.Minute); !errors.Is(err, ErrLoadCoalescingDisabled) {
+		t.Fatalf("expected ErrLoadCoalescingDisabled, got %v", err)
+	}
+}
+
+func TestGetOrBatchLoad_KeepsLoadDeadline(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithLoadCoalescing[string, CacheObject[string]](0, func(ctx context.Context, _ []string) (map[string]string, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				return nil, errors.New("expected a deadline")
+			}
+			<-ctx.Done()
+
+			return nil, ctx.Err()
+		}),
+		WithMaxLoadTimeout[string, CacheObject[string]](20*time.Millisecond),
+	)
+
+	if _, err := cache.GetOrBatchLoad(context.Background(), "a", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the batch bounded by the caller deadline, got %v", err)
+	}
+}
+
+func TestGetOrBatchLoad_FlushesFullBatch(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		batches [][]string
+	)
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithLoadCoalescing[string, CacheObject[string]](time.Hour, func(_ context.Context, keys []string) (map[string]string, error) {
+			mu.Lock()
+			batches = append(batches, slices.Sorted(slices.Values(keys)))
+			mu.Unlock()
+			values := make(map[string]string, len(keys))
+			for _, key := range keys {
+				values[key] = "value:" + key
+			}
+
+			return values, nil
+		}, WithMaxBatchSize(2)),
+	)
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := cache.GetOrBatchLoad(context.Background(), key, time.Minute); err != nil || v != "value:"+key {
+				t.Errorf("unexpected result for %s: %q, %v", key, v, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(batches) != 1 || !slices.Equal(batches[0], []string{"a", "b"}) {
+		t.Fatalf("expected the full batch loaded before the window ended, got %v", batches)
+	}
+}
+
+func TestGetOrBatchLoad_RecoversLoaderPanic(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithLoadCoalescing[string, CacheObject[string]](0, func(context.Context, []string) (map[string]string, error) {
+			panic("boom")
+		}),
+	)
+
+	if _, err := cache.GetOrBatchLoad(context.Background(), "a", time.Minute); !errors.Is(err, ErrBatchLoaderPanicked) {
+		t.Fatalf("expected ErrBatchLoaderPanicked, got %v", err)
+	}
+}
+
+func TestGetOrBatchLoad_CloseWaitsForBatch(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithLoadCoalescing[string, CacheObject[string]](0, func(context.Context, []string) (map[string]string, error) {
+			close(started)
+			<-release
+			finished.Store(true)
+
+			return map[string]string{"a": "value"}, nil
+		}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrBatchLoad(ctx, "a", time.Minute)
+		done <- err
+	}()
+	<-started
+	cancel()
+	<-done
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if !finished.Load() {
+		t.Fatal("expected Close to wait for the running batch")
+	}
+}
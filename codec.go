@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 )
 
@@ -18,6 +19,34 @@ type CacheStorageCodec[V any, S any] interface {
 	Decode(data S) (CacheObject[V], error)
 }
 
+// KeyedCacheStorageCodec is an optional extension of CacheStorageCodec for
+// codecs whose encoding depends on the cache key, such as per-key-pattern
+// serialization or per-tenant encryption. The cache prefers the keyed methods
+// when a codec implements them.
+type KeyedCacheStorageCodec[V any, S any] interface {
+	CacheStorageCodec[V, S]
+	// EncodeKeyed returns the cache object for key encoded into storage value.
+	EncodeKeyed(key string, value CacheObject[V]) (S, error)
+	// DecodeKeyed reads the storage value for key into a cache object.
+	DecodeKeyed(key string, data S) (CacheObject[V], error)
+}
+
+func encodeKeyed[V any, S any](codec CacheStorageCodec[V, S], key string, value CacheObject[V]) (S, error) {
+	if keyed, ok := codec.(KeyedCacheStorageCodec[V, S]); ok {
+		return keyed.EncodeKeyed(key, value)
+	}
+
+	return codec.Encode(value)
+}
+
+func decodeKeyed[V any, S any](codec CacheStorageCodec[V, S], key string, data S) (CacheObject[V], error) {
+	if keyed, ok := codec.(KeyedCacheStorageCodec[V, S]); ok {
+		return keyed.DecodeKeyed(key, data)
+	}
+
+	return codec.Decode(data)
+}
+
 // BufferReleasePolicy declares whether Decode can safely release buffer-backed input.
 type BufferReleasePolicy interface {
 	CanReleaseBufferOnDecode() bool
@@ -81,8 +110,18 @@ const (
 	// above which values are compressed in BinaryCompressionCodec.
 	DefaultCompressThresholdBytes = 1024 * 2 // 2 KiB
 
+	// CompressionTypeIDNone and CompressionTypeIDZlib are the raw header
+	// bytes of CompressionNone and CompressionZlib.
 	CompressionTypeIDNone byte = 0x00
 	CompressionTypeIDZlib byte = 0x01
+	// CompressionTypeIDProviderManaged is the raw header byte of
+	// CompressionProviderManaged.
+	CompressionTypeIDProviderManaged byte = 0xff
+	// CompressionTypeIDSnappy and CompressionTypeIDZstd are the header bytes
+	// reserved for snappy and zstd, which ext/compress registers with
+	// RegisterCompression.
+	CompressionTypeIDSnappy byte = 0x03
+	CompressionTypeIDZstd   byte = 0x04
 )
 
 var (
@@ -90,14 +129,63 @@ var (
 	ErrUnsupportedCompressionTypeID = errors.New("unsupported compression type ID")
 )
 
+// CompressionBand selects the compression algorithm for payloads below a size.
+type CompressionBand struct {
+	// MaxBytes is the exclusive upper bound of payload sizes in the band.
+	// Zero makes the band unbounded.
+	MaxBytes int
+	// TypeID is the algorithm used for the band: CompressionTypeIDNone,
+	// CompressionTypeIDZlib, CompressionTypeIDProviderManaged, or an ID
+	// registered with RegisterCompression.
+	TypeID byte
+	// Level is the algorithm-specific compression level, such as
+	// zlib.BestSpeed. Zero uses the algorithm default.
+	Level int
+}
+
+// DefaultCompressionBands returns the bands used by WithAutoCompression when
+// none are given: no compression below 1 KiB, snappy below 32 KiB, and zstd
+// otherwise. Snappy and zstd need ext/compress registered first; until both
+// are, fast zlib and best zlib compression take their places, so the defaults
+// never write a payload this process cannot decode.
+func DefaultCompressionBands() []CompressionBand {
+	_, snappy := lookupCompressor(CompressionType(CompressionTypeIDSnappy))
+	_, zstd := lookupCompressor(CompressionType(CompressionTypeIDZstd))
+	if snappy && zstd {
+		return []CompressionBand{
+			{MaxBytes: 1024, TypeID: CompressionTypeIDNone},
+			{MaxBytes: 32 * 1024, TypeID: CompressionTypeIDSnappy},
+			{TypeID: CompressionTypeIDZstd},
+		}
+	}
+
+	return []CompressionBand{
+		{MaxBytes: 1024, TypeID: CompressionTypeIDNone},
+		{MaxBytes: 32 * 1024, TypeID: CompressionTypeIDZlib, Level: zlib.BestSpeed},
+		{TypeID: CompressionTypeIDZlib, Level: zlib.BestCompression},
+	}
+}
+
+// CompressionPredicate reports whether the encoded payload for key should be compressed.
+type CompressionPredicate func(key string, payload []byte) bool
+
+// BinaryCompressionCodecOption customizes the codec built by NewBinaryCompressionCodec.
+type BinaryCompressionCodecOption[V any] func(*binaryCompressionCodec[V])
+
 type binaryCompressionCodec[V any] struct {
 	inner                    CacheStorageCodec[V, []byte]
 	compressThresholdBytes   int
+	compressionPredicate     CompressionPredicate
+	compressionBands         []CompressionBand
+	providerManaged          bool
 	bufPool                  sync.Pool
 	canReleaseBufferOnDecode bool
 }
 
-var _ CacheStorageCodec[any, []byte] = &binaryCompressionCodec[any]{}
+var (
+	_ CacheStorageCodec[any, []byte]      = &binaryCompressionCodec[any]{}
+	_ KeyedCacheStorageCodec[any, []byte] = &binaryCompressionCodec[any]{}
+)
 
 // NewBinaryCompressionCodec returns a codec that conditionally compresses
 // encoded values with zlib when they reach the threshold.
@@ -105,13 +193,14 @@ var _ CacheStorageCodec[any, []byte] = &binaryCompressionCodec[any]{}
 func NewBinaryCompressionCodec[V any](
 	inner CacheStorageCodec[V, []byte],
 	compressThresholdBytes int,
+	opts ...BinaryCompressionCodecOption[V],
 ) CacheStorageCodec[V, []byte] {
 	canReleaseBufferOnDecode := false
 	if policy, ok := any(inner).(BufferReleasePolicy); ok {
 		canReleaseBufferOnDecode = policy.CanReleaseBufferOnDecode()
 	}
 
-	return &binaryCompressionCodec[V]{
+	codec := &binaryCompressionCodec[V]{
 		inner:                  inner,
 		compressThresholdBytes: compressThresholdBytes,
 		bufPool: sync.Pool{
@@ -121,61 +210,155 @@ func NewBinaryCompressionCodec[V any](
 		},
 		canReleaseBufferOnDecode: canReleaseBufferOnDecode,
 	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(codec)
+	}
+
+	return codec
+}
+
+// WithCompressionPredicate decides per key and payload whether to compress,
+// replacing the threshold check. When the codec is used through a Cache, the
+// cache key is passed; direct Encode calls pass an empty key.
+func WithCompressionPredicate[V any](predicate CompressionPredicate) BinaryCompressionCodecOption[V] {
+	return func(b *binaryCompressionCodec[V]) {
+		b.compressionPredicate = predicate
+	}
+}
+
+// WithAutoCompression picks the compression algorithm and level by payload
+// size from the first band whose MaxBytes exceeds the payload size, replacing
+// the threshold check. Payloads beyond every band are stored uncompressed.
+// Without bands, DefaultCompressionBands at the time of the call is used, so
+// register ext/compress first. A compression predicate set with
+// WithCompressionPredicate still decides whether to compress at all.
+func WithAutoCompression[V any](bands ...CompressionBand) BinaryCompressionCodecOption[V] {
+	if len(bands) == 0 {
+		bands = DefaultCompressionBands()
+	}
+	bands = slices.Clone(bands)
+
+	return func(b *binaryCompressionCodec[V]) {
+		b.compressionBands = bands
+	}
+}
+
+// WithProviderManagedCompression leaves compression to the provider: payloads
+// that would be compressed are stored uncompressed behind the
+// CompressionProviderManaged header instead, which tells providers such as
+// ext/rueidis with server-side compression enabled to have the backend
+// compress them. The provider must return such payloads decompressed.
+func WithProviderManagedCompression[V any]() BinaryCompressionCodecOption[V] {
+	return func(b *binaryCompressionCodec[V]) {
+		b.providerManaged = true
+	}
 }
 
 func (b *binaryCompressionCodec[V]) Encode(value CacheObject[V]) ([]byte, error) {
-	innerBuf, err := b.inner.Encode(value)
+	return b.EncodeKeyed("", value)
+}
+
+// EncodeKeyed encodes value, consulting the compression predicate with key.
+// The key is forwarded to the inner codec when it is keyed as well.
+func (b *binaryCompressionCodec[V]) EncodeKeyed(key string, value CacheObject[V]) ([]byte, error) {
+	innerBuf, err := encodeKeyed(b.inner, key, value)
 	if err != nil {
 		return nil, err
 	}
-	if b.compressThresholdBytes < 0 || len(innerBuf) < b.compressThresholdBytes {
+	band := b.compressionBand(key, innerBuf)
+	if b.providerManaged && band.TypeID != CompressionTypeIDNone {
+		band.TypeID = CompressionTypeIDProviderManaged
+	}
+	if band.TypeID == CompressionTypeIDNone || band.TypeID == CompressionTypeIDProviderManaged {
 		buf := make([]byte, 1+len(innerBuf))
-		buf[0] = CompressionTypeIDNone
+		buf[0] = band.TypeID
 		copy(buf[1:], innerBuf)
 
 		return buf, nil
 	}
+	compressor, ok := lookupCompressor(CompressionType(band.TypeID))
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedCompressionTypeID, band.TypeID)
+	}
 
 	// compressBuf MUST NOT be used outside of this function scope
 	compressBuf := b.acquireBuffer()
 	defer b.returnBuffer(compressBuf)
 
-	if err := compressZlib(compressBuf, innerBuf); err != nil {
+	if err := compressor.Compress(compressBuf, innerBuf, band.Level); err != nil {
 		return nil, err
 	}
 
 	buf := make([]byte, 1+compressBuf.Len())
-	buf[0] = CompressionTypeIDZlib
+	buf[0] = band.TypeID
 	copy(buf[1:], compressBuf.Bytes())
 
 	return buf, nil
 }
 
+// compressionBand returns how payload for key is compressed.
+func (b *binaryCompressionCodec[V]) compressionBand(key string, payload []byte) CompressionBand {
+	if b.compressionPredicate != nil && !b.compressionPredicate(key, payload) {
+		return CompressionBand{TypeID: CompressionTypeIDNone}
+	}
+	if b.compressionBands != nil {
+		for _, band := range b.compressionBands {
+			if band.MaxBytes <= 0 || len(payload) < band.MaxBytes {
+				return band
+			}
+		}
+
+		return CompressionBand{TypeID: CompressionTypeIDNone}
+	}
+	if b.compressionPredicate != nil || (b.compressThresholdBytes >= 0 && len(payload) >= b.compressThresholdBytes) {
+		return CompressionBand{TypeID: CompressionTypeIDZlib}
+	}
+
+	return CompressionBand{TypeID: CompressionTypeIDNone}
+}
+
 func (b *binaryCompressionCodec[V]) Decode(data []byte) (CacheObject[V], error) {
+	return b.DecodeKeyed("", data)
+}
+
+// DecodeKeyed decompresses data and decodes it with the inner codec, forwarding
+// key when the inner codec is keyed. Errors name the key when it is known.
+func (b *binaryCompressionCodec[V]) DecodeKeyed(key string, data []byte) (CacheObject[V], error) {
 	if len(data) == 0 {
 		return CacheObject[V]{}, ErrDecompressZeroLengthData
 	}
 	compressionTypeID := data[0]
 	compressedData := data[1:]
-	switch compressionTypeID {
-	case CompressionTypeIDNone:
-		return b.inner.Decode(compressedData)
-	case CompressionTypeIDZlib:
-		decompressBuf := b.acquireBuffer()
-		if b.canReleaseBufferOnDecode {
-			// decompressBuf MUST NOT be used outside of this function scope
-			defer b.returnBuffer(decompressBuf)
+	if compressionTypeID == CompressionTypeIDNone || compressionTypeID == CompressionTypeIDProviderManaged {
+		return decodeKeyed(b.inner, key, compressedData)
+	}
+	compressor, ok := lookupCompressor(CompressionType(compressionTypeID))
+	if !ok {
+		if key != "" {
+			return CacheObject[V]{}, fmt.Errorf("%w for %q: %s", ErrUnsupportedCompressionTypeID, key, CompressionType(compressionTypeID))
 		}
 
-		err := decompressZlib(decompressBuf, compressedData)
-		if err != nil {
-			return CacheObject[V]{}, err
+		return CacheObject[V]{}, fmt.Errorf("%w: %s", ErrUnsupportedCompressionTypeID, CompressionType(compressionTypeID))
+	}
+
+	decompressBuf := b.acquireBuffer()
+	if b.canReleaseBufferOnDecode {
+		// decompressBuf MUST NOT be used outside of this function scope
+		defer b.returnBuffer(decompressBuf)
+	}
+
+	if err := compressor.Decompress(decompressBuf, compressedData); err != nil {
+		if key != "" {
+			return CacheObject[V]{}, fmt.Errorf("decompress %q: %w", key, err)
 		}
 
-		return b.inner.Decode(decompressBuf.Bytes())
-	default:
-		return CacheObject[V]{}, fmt.Errorf("unsupported compression type: %d", compressionTypeID)
+		return CacheObject[V]{}, err
 	}
+
+	return decodeKeyed(b.inner, key, decompressBuf.Bytes())
 }
 
 func (b *binaryCompressionCodec[V]) acquireBuffer() *bytes.Buffer {
@@ -190,8 +373,14 @@ func (b *binaryCompressionCodec[V]) returnBuffer(buf *bytes.Buffer) {
 	b.bufPool.Put(buf)
 }
 
-func compressZlib(buf *bytes.Buffer, data []byte) error {
-	writer := zlib.NewWriter(buf)
+func compressZlib(buf *bytes.Buffer, data []byte, level int) error {
+	if level == 0 {
+		level = zlib.DefaultCompression
+	}
+	writer, err := zlib.NewWriterLevel(buf, level)
+	if err != nil {
+		return err
+	}
 	if _, err := writer.Write(data); err != nil {
 		_ = writer.Close()
 
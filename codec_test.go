@@ -2,11 +2,14 @@ package crema
 
 import (
 	"bytes"
+	"compress/zlib"
+	"context"
 	"errors"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestJSONByteStringCodec_RoundTrip(t *testing.T) {
@@ -326,7 +329,7 @@ func TestBinaryCompressionCodec_DecodeTruncatedPayload(t *testing.T) {
 	t.Parallel()
 
 	compressBuf := bytes.NewBuffer(nil)
-	if err := compressZlib(compressBuf, []byte("hello")); err != nil {
+	if err := compressZlib(compressBuf, []byte("hello"), 0); err != nil {
 		t.Fatalf("compressZlib() error = %v", err)
 	}
 	compressed := compressBuf.Bytes()
@@ -423,7 +426,7 @@ func TestBinaryCompressionCodec_CanReleaseBufferOnDecodeTrueUsesBuffer(t *testin
 	}
 
 	compressBuf := bytes.NewBuffer(nil)
-	if err := compressZlib(compressBuf, []byte("hello")); err != nil {
+	if err := compressZlib(compressBuf, []byte("hello"), 0); err != nil {
 		t.Fatalf("compressZlib() error = %v", err)
 	}
 	data := append([]byte{CompressionTypeIDZlib}, compressBuf.Bytes()...)
@@ -435,3 +438,190 @@ func TestBinaryCompressionCodec_CanReleaseBufferOnDecodeTrueUsesBuffer(t *testin
 		t.Fatal("expected decode to pass pooled buffer to inner codec")
 	}
 }
+
+func TestBinaryCompressionCodec_CompressionPredicate(t *testing.T) {
+	t.Parallel()
+
+	codec := NewBinaryCompressionCodec(binaryCompressionTestCodec{}, -1,
+		WithCompressionPredicate[string](func(key string, payload []byte) bool {
+			return strings.HasPrefix(key, "config:")
+		}),
+	)
+	keyed, ok := codec.(KeyedCacheStorageCodec[string, []byte])
+	if !ok {
+		t.Fatal("expected codec to implement keyed encoding")
+	}
+	input := CacheObject[string]{Value: "hello", ExpireAtMillis: 1234}
+
+	encoded, err := keyed.EncodeKeyed("config:flags", input)
+	if err != nil {
+		t.Fatalf("expected encode to succeed, got %v", err)
+	}
+	if encoded[0] != CompressionTypeIDZlib {
+		t.Fatalf("expected predicate to force compression, got %v", encoded[0])
+	}
+	decoded, err := codec.Decode(encoded)
+	if err != nil {
+		t.Fatalf("expected decode to succeed, got %v", err)
+	}
+	if decoded != input {
+		t.Fatalf("expected decoded value %+v, got %+v", input, decoded)
+	}
+
+	encoded, err = keyed.EncodeKeyed("manifest:1", input)
+	if err != nil {
+		t.Fatalf("expected encode to succeed, got %v", err)
+	}
+	if encoded[0] != CompressionTypeIDNone {
+		t.Fatalf("expected predicate to skip compression, got %v", encoded[0])
+	}
+}
+
+func TestBinaryCompressionCodec_AutoCompressionBands(t *testing.T) {
+	t.Parallel()
+
+	codec := NewBinaryCompressionCodec(binaryCompressionTestCodec{}, 0,
+		WithAutoCompression[string](
+			CompressionBand{MaxBytes: 16, TypeID: CompressionTypeIDNone},
+			CompressionBand{MaxBytes: 64, TypeID: CompressionTypeIDZlib, Level: zlib.BestSpeed},
+		),
+	)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected byte
+	}{
+		{name: "small", value: "tiny", expected: CompressionTypeIDNone},
+		{name: "medium", value: strings.Repeat("a", 32), expected: CompressionTypeIDZlib},
+		{name: "beyond bands", value: strings.Repeat("a", 128), expected: CompressionTypeIDNone},
+	}
+	for _, tt := range tests {
+		input := CacheObject[string]{Value: tt.value, ExpireAtMillis: 1234}
+		encoded, err := codec.Encode(input)
+		if err != nil {
+			t.Fatalf("%s: expected encode to succeed, got %v", tt.name, err)
+		}
+		if encoded[0] != tt.expected {
+			t.Fatalf("%s: expected type %d, got %d", tt.name, tt.expected, encoded[0])
+		}
+		decoded, err := codec.Decode(encoded)
+		if err != nil {
+			t.Fatalf("%s: expected decode to succeed, got %v", tt.name, err)
+		}
+		if decoded != input {
+			t.Fatalf("%s: expected decoded value %+v, got %+v", tt.name, input, decoded)
+		}
+	}
+}
+
+func TestBinaryCompressionCodec_AutoCompressionDefaults(t *testing.T) {
+	t.Parallel()
+
+	codec := NewBinaryCompressionCodec(binaryCompressionTestCodec{}, 0, WithAutoCompression[string]())
+	for size, expected := range map[int]byte{
+		100:       CompressionTypeIDNone,
+		4 * 1024:  CompressionTypeIDZlib,
+		64 * 1024: CompressionTypeIDZlib,
+	} {
+		encoded, err := codec.Encode(CacheObject[string]{Value: strings.Repeat("a", size)})
+		if err != nil {
+			t.Fatalf("expected encode to succeed, got %v", err)
+		}
+		if encoded[0] != expected {
+			t.Fatalf("size %d: expected type %d, got %d", size, expected, encoded[0])
+		}
+	}
+}
+
+func TestBinaryCompressionCodec_AutoCompressionUnsupportedType(t *testing.T) {
+	t.Parallel()
+
+	codec := NewBinaryCompressionCodec(binaryCompressionTestCodec{}, 0,
+		WithAutoCompression[string](CompressionBand{TypeID: 0x7f}),
+	)
+	if _, err := codec.Encode(CacheObject[string]{Value: "a"}); !errors.Is(err, ErrUnsupportedCompressionTypeID) {
+		t.Fatalf("expected ErrUnsupportedCompressionTypeID, got %v", err)
+	}
+}
+
+func TestCache_SetPassesKeyToCompressionPredicate(t *testing.T) {
+	t.Parallel()
+
+	var gotKey string
+	codec := NewBinaryCompressionCodec(binaryCompressionTestCodec{}, 0,
+		WithCompressionPredicate[string](func(key string, payload []byte) bool {
+			gotKey = key
+
+			return false
+		}),
+	)
+	provider := &byteProvider{items: make(map[string][]byte)}
+	cache := NewCache(provider, codec)
+	cache.(*cacheImpl[string, []byte]).now = func() time.Time { return time.UnixMilli(1000) }
+
+	if err := cache.Set(context.Background(), "manifest:1", CacheObject[string]{Value: "m3u8", ExpireAtMillis: 2000}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if gotKey != "manifest:1" {
+		t.Fatalf("expected predicate to receive cache key, got %q", gotKey)
+	}
+	if provider.items["manifest:1"][0] != CompressionTypeIDNone {
+		t.Fatalf("expected stored entry to be uncompressed")
+	}
+}
+
+type tenantPrefixCodec struct{}
+
+func (tenantPrefixCodec) Encode(value CacheObject[string]) ([]byte, error) {
+	return tenantPrefixCodec{}.EncodeKeyed("", value)
+}
+
+func (tenantPrefixCodec) Decode(data []byte) (CacheObject[string], error) {
+	return tenantPrefixCodec{}.DecodeKeyed("", data)
+}
+
+func (tenantPrefixCodec) EncodeKeyed(key string, value CacheObject[string]) ([]byte, error) {
+	tenant, _, _ := strings.Cut(key, ":")
+
+	return []byte(tenant + "|" + value.Value), nil
+}
+
+func (tenantPrefixCodec) DecodeKeyed(key string, data []byte) (CacheObject[string], error) {
+	tenant, _, _ := strings.Cut(key, ":")
+	value, ok := strings.CutPrefix(string(data), tenant+"|")
+	if !ok {
+		return CacheObject[string]{}, errors.New("tenant mismatch for key " + strconv.Quote(key))
+	}
+
+	return CacheObject[string]{Value: value}, nil
+}
+
+func TestCache_PrefersKeyedCodec(t *testing.T) {
+	t.Parallel()
+
+	provider := &byteProvider{items: make(map[string][]byte)}
+	cache := NewCache[string](provider, NewBinaryCompressionCodec[string](tenantPrefixCodec{}, 0))
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "acme:profile", CacheObject[string]{Value: "hello", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got, ok, err := cache.Get(ctx, "acme:profile")
+	if err != nil || !ok {
+		t.Fatalf("expected hit, got ok=%v err=%v", ok, err)
+	}
+	if got.Value != "hello" {
+		t.Fatalf("expected hello, got %q", got.Value)
+	}
+
+	provider.items["other:profile"] = provider.items["acme:profile"]
+	if _, _, err := cache.Get(ctx, "other:profile"); err == nil || !strings.Contains(err.Error(), "other:profile") {
+		t.Fatalf("expected keyed decode error naming the key, got %v", err)
+	}
+
+	provider.items["broken"] = []byte{0x7f}
+	if _, _, err := cache.Get(ctx, "broken"); err == nil || !strings.Contains(err.Error(), `"broken"`) {
+		t.Fatalf("expected unsupported compression error naming the key, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package crema
+
+import "time"
+
+// WithColdStartSmoothing damps probabilistic early revalidation for window
+// after the cache is constructed: the revalidation probability is scaled by
+// the elapsed fraction of window, so it ramps from zero to its normal value.
+// This keeps a freshly restarted fleet, whose entries all look equally stale,
+// from refreshing in lockstep against the origin. Expired entries are still
+// reloaded immediately.
+func WithColdStartSmoothing[V any, S any](window time.Duration) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.coldStartWindow = window
+	}
+}
+
+// coldStartFactor returns the factor in [0, 1] applied to the early
+// revalidation probability at nowMillis.
+func (c *cacheImpl[V, S]) coldStartFactor(nowMillis int64) float64 {
+	windowMillis := c.coldStartWindow.Milliseconds()
+	if windowMillis <= 0 {
+		return 1
+	}
+	elapsed := nowMillis - c.startedAt.UnixMilli()
+	if elapsed >= windowMillis {
+		return 1
+	}
+
+	return float64(max(elapsed, 0)) / float64(windowMillis)
+}
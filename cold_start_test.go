@@ -0,0 +1,44 @@
+package crema
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithColdStartSmoothing_RampsRevalidation(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithRevalidationWindow[int, CacheObject[int]](time.Second),
+		WithColdStartSmoothing[int, CacheObject[int]](10*time.Second),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	impl.startedAt = time.UnixMilli(0)
+	// p(500ms) is about 0.97 with a one second window
+	impl.random = fakeRandom(0.4)
+
+	if impl.shouldRevalidate(0, 500) {
+		t.Fatal("expected no early revalidation right after start")
+	}
+	if !impl.shouldRevalidate(0, -1) {
+		t.Fatal("expected expired entries to revalidate during cold start")
+	}
+	if impl.shouldRevalidate(2_000, 2_500) {
+		t.Fatal("expected damped probability early in the window")
+	}
+	if !impl.shouldRevalidate(5_000, 5_500) {
+		t.Fatal("expected revalidation once the ramp exceeds the draw")
+	}
+	if got := impl.coldStartFactor(20_000); got != 1 {
+		t.Fatalf("expected full probability after the window, got %v", got)
+	}
+}
+
+func TestCache_ColdStartFactorWithoutSmoothing(t *testing.T) {
+	t.Parallel()
+
+	impl := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{}).(*cacheImpl[int, CacheObject[int]])
+	if got := impl.coldStartFactor(impl.startedAt.UnixMilli()); got != 1 {
+		t.Fatalf("expected factor 1 without smoothing, got %v", got)
+	}
+}
@@ -0,0 +1,139 @@
+package crema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CompressionType identifies the compression algorithm of a payload written
+// by BinaryCompressionCodec. It is stored as the first byte of the payload.
+type CompressionType byte
+
+const (
+	// CompressionNone marks an uncompressed payload.
+	CompressionNone = CompressionType(CompressionTypeIDNone)
+	// CompressionZlib marks a zlib-compressed payload.
+	CompressionZlib = CompressionType(CompressionTypeIDZlib)
+	// CompressionProviderManaged marks a payload stored uncompressed by the
+	// codec for the provider or backend to compress; see
+	// WithProviderManagedCompression.
+	CompressionProviderManaged = CompressionType(CompressionTypeIDProviderManaged)
+)
+
+var (
+	// ErrUnknownCompressionType is returned by ParseCompressionType for names
+	// that are not registered.
+	ErrUnknownCompressionType = errors.New("unknown compression type")
+	// ErrCompressionTypeConflict is returned when registering a compression
+	// type whose ID or name is already taken.
+	ErrCompressionTypeConflict = errors.New("compression type already registered")
+)
+
+var compressionTypes = struct {
+	mu          sync.RWMutex
+	names       map[CompressionType]string
+	byName      map[string]CompressionType
+	compressors map[CompressionType]Compressor
+}{
+	names: map[CompressionType]string{
+		CompressionNone:            "none",
+		CompressionZlib:            "zlib",
+		CompressionProviderManaged: "provider-managed",
+	},
+	byName: map[string]CompressionType{
+		"none":             CompressionNone,
+		"zlib":             CompressionZlib,
+		"provider-managed": CompressionProviderManaged,
+	},
+	compressors: map[CompressionType]Compressor{CompressionZlib: zlibCompressor{}},
+}
+
+// RegisterCompressionType names a compression type ID used by a custom
+// algorithm, so that String, ParseCompressionType and payload validation
+// recognize it. Names are case-insensitive. It returns an error wrapping
+// ErrCompressionTypeConflict when the ID or name is already registered, or
+// the ID is 0xc3, which starts WithWriterIdentity headers.
+// Use RegisterCompression to register the algorithm itself.
+func RegisterCompressionType(t CompressionType, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("%w: empty name for %#02x", ErrUnknownCompressionType, byte(t))
+	}
+	compressionTypes.mu.Lock()
+	defer compressionTypes.mu.Unlock()
+
+	return registerCompressionTypeLocked(t, name)
+}
+
+// reservedCompressionTypes are IDs that cannot be registered because payloads
+// starting with them carry another framing.
+var reservedCompressionTypes = map[CompressionType]string{
+	CompressionType(writerHeaderMagic[0]): "writer header",
+}
+
+func registerCompressionTypeLocked(t CompressionType, name string) error {
+	if framing, ok := reservedCompressionTypes[t]; ok {
+		return fmt.Errorf("%w: %#02x is reserved for the %s", ErrCompressionTypeConflict, byte(t), framing)
+	}
+	if existing, ok := compressionTypes.names[t]; ok {
+		return fmt.Errorf("%w: %#02x is %q", ErrCompressionTypeConflict, byte(t), existing)
+	}
+	if existing, ok := compressionTypes.byName[name]; ok {
+		return fmt.Errorf("%w: %q is %#02x", ErrCompressionTypeConflict, name, byte(existing))
+	}
+	compressionTypes.names[t] = name
+	compressionTypes.byName[name] = t
+
+	return nil
+}
+
+// String returns the registered name of t, or its hexadecimal ID when it is
+// not registered.
+func (t CompressionType) String() string {
+	compressionTypes.mu.RLock()
+	defer compressionTypes.mu.RUnlock()
+	if name, ok := compressionTypes.names[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("compression(%#02x)", byte(t))
+}
+
+// Registered reports whether t is a built-in or registered compression type.
+func (t CompressionType) Registered() bool {
+	compressionTypes.mu.RLock()
+	defer compressionTypes.mu.RUnlock()
+	_, ok := compressionTypes.names[t]
+
+	return ok
+}
+
+// ParseCompressionType returns the compression type registered under name,
+// ignoring case, such as "none" or "zlib".
+func ParseCompressionType(name string) (CompressionType, error) {
+	compressionTypes.mu.RLock()
+	defer compressionTypes.mu.RUnlock()
+	if t, ok := compressionTypes.byName[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return t, nil
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnknownCompressionType, name)
+}
+
+// CompressionTypeOf validates the framing of a payload written by
+// BinaryCompressionCodec and returns its compression type. It fails with
+// ErrDecompressZeroLengthData for empty payloads and with an error wrapping
+// ErrUnsupportedCompressionTypeID for unregistered types.
+func CompressionTypeOf(data []byte) (CompressionType, error) {
+	if len(data) == 0 {
+		return 0, ErrDecompressZeroLengthData
+	}
+	t := CompressionType(data[0])
+	if !t.Registered() {
+		return t, fmt.Errorf("%w: %d", ErrUnsupportedCompressionTypeID, data[0])
+	}
+
+	return t, nil
+}
@@ -0,0 +1,123 @@
+package crema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompressionType_StringAndParse(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		typ  CompressionType
+		name string
+	}{
+		{CompressionNone, "none"},
+		{CompressionZlib, "zlib"},
+	}
+	for _, tc := range cases {
+		if got := tc.typ.String(); got != tc.name {
+			t.Fatalf("expected %q, got %q", tc.name, got)
+		}
+		parsed, err := ParseCompressionType(tc.name)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if parsed != tc.typ {
+			t.Fatalf("expected %v, got %v", tc.typ, parsed)
+		}
+	}
+
+	if parsed, err := ParseCompressionType(" ZLIB "); err != nil || parsed != CompressionZlib {
+		t.Fatalf("expected case-insensitive zlib, got %v, %v", parsed, err)
+	}
+	if _, err := ParseCompressionType("lz4"); !errors.Is(err, ErrUnknownCompressionType) {
+		t.Fatalf("expected ErrUnknownCompressionType, got %v", err)
+	}
+	if got := CompressionType(0xfe).String(); got != "compression(0xfe)" {
+		t.Fatalf("expected compression(0xfe), got %q", got)
+	}
+}
+
+func TestRegisterCompressionType(t *testing.T) {
+	t.Parallel()
+
+	custom := CompressionType(0xe0)
+	if err := RegisterCompressionType(custom, "Test-Custom"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := custom.String(); got != "test-custom" {
+		t.Fatalf("expected test-custom, got %q", got)
+	}
+	if parsed, err := ParseCompressionType("test-custom"); err != nil || parsed != custom {
+		t.Fatalf("expected %v, got %v, %v", custom, parsed, err)
+	}
+	if err := RegisterCompressionType(custom, "other"); !errors.Is(err, ErrCompressionTypeConflict) {
+		t.Fatalf("expected ID conflict, got %v", err)
+	}
+	if err := RegisterCompressionType(CompressionType(0xe1), "zlib"); !errors.Is(err, ErrCompressionTypeConflict) {
+		t.Fatalf("expected name conflict, got %v", err)
+	}
+	if err := RegisterCompressionType(CompressionType(0xe2), " "); err == nil {
+		t.Fatal("expected error for empty name, got nil")
+	}
+}
+
+func TestCompressionTypeOf(t *testing.T) {
+	t.Parallel()
+
+	if _, err := CompressionTypeOf(nil); !errors.Is(err, ErrDecompressZeroLengthData) {
+		t.Fatalf("expected ErrDecompressZeroLengthData, got %v", err)
+	}
+	if typ, err := CompressionTypeOf([]byte{CompressionTypeIDZlib, 0x78}); err != nil || typ != CompressionZlib {
+		t.Fatalf("expected zlib, got %v, %v", typ, err)
+	}
+	if _, err := CompressionTypeOf([]byte{0xfd}); !errors.Is(err, ErrUnsupportedCompressionTypeID) {
+		t.Fatalf("expected ErrUnsupportedCompressionTypeID, got %v", err)
+	}
+
+	codec := NewBinaryCompressionCodec[string](JSONByteStringCodec[string]{}, 0)
+	encoded, err := codec.Encode(CacheObject[string]{Value: "hello"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if typ, err := CompressionTypeOf(encoded); err != nil || typ != CompressionZlib {
+		t.Fatalf("expected zlib, got %v, %v", typ, err)
+	}
+	if _, err := codec.Decode([]byte{0xfd}); !errors.Is(err, ErrUnsupportedCompressionTypeID) {
+		t.Fatalf("expected ErrUnsupportedCompressionTypeID, got %v", err)
+	}
+}
+
+func TestWithProviderManagedCompression(t *testing.T) {
+	t.Parallel()
+
+	codec := NewBinaryCompressionCodec[string](JSONByteStringCodec[string]{}, 64, WithProviderManagedCompression[string]())
+	large := strings.Repeat("a", 128)
+	encoded, err := codec.Encode(CacheObject[string]{Value: large})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if typ, err := CompressionTypeOf(encoded); err != nil || typ != CompressionProviderManaged {
+		t.Fatalf("expected provider-managed, got %v, %v", typ, err)
+	}
+	if !strings.Contains(string(encoded), large) {
+		t.Fatal("expected the payload to be left uncompressed")
+	}
+	decoded, err := codec.Decode(encoded)
+	if err != nil || decoded.Value != large {
+		t.Fatalf("expected round trip, got %q, %v", decoded.Value, err)
+	}
+
+	small, err := codec.Encode(CacheObject[string]{Value: "a"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if small[0] != CompressionTypeIDNone {
+		t.Fatalf("expected payloads below the threshold to stay untagged, got %#x", small[0])
+	}
+	if got := CompressionProviderManaged.String(); got != "provider-managed" {
+		t.Fatalf("expected provider-managed, got %q", got)
+	}
+}
@@ -0,0 +1,75 @@
+package crema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Compressor is a compression algorithm that BinaryCompressionCodec can
+// select through a CompressionBand. Implementations must be safe for
+// concurrent use.
+type Compressor interface {
+	// Name is the lower-case name reported by CompressionType.String and
+	// accepted by ParseCompressionType, such as "brotli".
+	Name() string
+	// Compress appends the compressed form of src to dst. level is the
+	// CompressionBand level; zero selects the algorithm default.
+	Compress(dst *bytes.Buffer, src []byte, level int) error
+	// Decompress appends the decompressed form of src to dst.
+	Decompress(dst *bytes.Buffer, src []byte) error
+}
+
+// ErrNilCompressor is returned by RegisterCompression for a nil compressor.
+var ErrNilCompressor = errors.New("nil compressor")
+
+// RegisterCompression makes compressor available to every
+// BinaryCompressionCodec under id. Payloads written with a CompressionBand
+// whose TypeID is id start with that byte and are routed back to compressor
+// on decode. Register from an init function, before any codec encodes or
+// decodes with id, and keep ids stable across releases since they are
+// persisted in cached payloads.
+//
+// It returns an error wrapping ErrCompressionTypeConflict when id or the
+// compressor's name is already registered, including the built-in none and
+// zlib types, and for 0xc3, which starts WithWriterIdentity headers.
+func RegisterCompression(id byte, compressor Compressor) error {
+	if compressor == nil {
+		return ErrNilCompressor
+	}
+	name := strings.ToLower(strings.TrimSpace(compressor.Name()))
+	if name == "" {
+		return fmt.Errorf("%w: empty name for %#02x", ErrUnknownCompressionType, id)
+	}
+	compressionTypes.mu.Lock()
+	defer compressionTypes.mu.Unlock()
+	t := CompressionType(id)
+	if err := registerCompressionTypeLocked(t, name); err != nil {
+		return err
+	}
+	compressionTypes.compressors[t] = compressor
+
+	return nil
+}
+
+// lookupCompressor returns the compressor registered for t.
+func lookupCompressor(t CompressionType) (Compressor, bool) {
+	compressionTypes.mu.RLock()
+	defer compressionTypes.mu.RUnlock()
+	compressor, ok := compressionTypes.compressors[t]
+
+	return compressor, ok
+}
+
+type zlibCompressor struct{}
+
+func (zlibCompressor) Name() string { return "zlib" }
+
+func (zlibCompressor) Compress(dst *bytes.Buffer, src []byte, level int) error {
+	return compressZlib(dst, src, level)
+}
+
+func (zlibCompressor) Decompress(dst *bytes.Buffer, src []byte) error {
+	return decompressZlib(dst, src)
+}
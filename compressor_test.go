@@ -0,0 +1,102 @@
+package crema
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// xorCompressor is a reversible stand-in for an external algorithm.
+type xorCompressor struct{ name string }
+
+func (x xorCompressor) Name() string { return x.name }
+
+func (x xorCompressor) Compress(dst *bytes.Buffer, src []byte, level int) error {
+	for _, b := range src {
+		dst.WriteByte(b ^ 0x5a)
+	}
+
+	return nil
+}
+
+func (x xorCompressor) Decompress(dst *bytes.Buffer, src []byte) error {
+	return x.Compress(dst, src, 0)
+}
+
+func TestRegisterCompression_RoutesEncodeAndDecode(t *testing.T) {
+	t.Parallel()
+
+	const id byte = 0xd0
+	if err := RegisterCompression(id, xorCompressor{name: "test-xor"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	codec := NewBinaryCompressionCodec[string](
+		JSONByteStringCodec[string]{},
+		0,
+		WithAutoCompression[string](CompressionBand{TypeID: id}),
+	)
+	encoded, err := codec.Encode(CacheObject[string]{Value: "hello"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if typ, err := CompressionTypeOf(encoded); err != nil || typ != CompressionType(id) {
+		t.Fatalf("expected %v, got %v, %v", CompressionType(id), typ, err)
+	}
+	if got := CompressionType(id).String(); got != "test-xor" {
+		t.Fatalf("expected test-xor, got %q", got)
+	}
+
+	// A codec without the band still decodes the payload by its header.
+	plain := NewBinaryCompressionCodec[string](JSONByteStringCodec[string]{}, 0)
+	decoded, err := plain.Decode(encoded)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if decoded.Value != "hello" {
+		t.Fatalf("expected hello, got %q", decoded.Value)
+	}
+}
+
+func TestRegisterCompression_Collisions(t *testing.T) {
+	t.Parallel()
+
+	if err := RegisterCompression(CompressionTypeIDZlib, xorCompressor{name: "test-zlib-id"}); !errors.Is(err, ErrCompressionTypeConflict) {
+		t.Fatalf("expected conflict on built-in ID, got %v", err)
+	}
+	if err := RegisterCompression(0xd1, xorCompressor{name: "zlib"}); !errors.Is(err, ErrCompressionTypeConflict) {
+		t.Fatalf("expected conflict on built-in name, got %v", err)
+	}
+	if err := RegisterCompression(0xd2, xorCompressor{name: "test-dup"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := RegisterCompression(0xd2, xorCompressor{name: "test-dup-2"}); !errors.Is(err, ErrCompressionTypeConflict) {
+		t.Fatalf("expected conflict on registered ID, got %v", err)
+	}
+	if err := RegisterCompression(writerHeaderMagic[0], xorCompressor{name: "test-writer-id"}); !errors.Is(err, ErrCompressionTypeConflict) {
+		t.Fatalf("expected conflict on the writer header ID, got %v", err)
+	}
+	if err := RegisterCompression(0xd3, nil); !errors.Is(err, ErrNilCompressor) {
+		t.Fatalf("expected ErrNilCompressor, got %v", err)
+	}
+}
+
+func TestRegisterCompressionType_WithoutCompressorIsNotEncodable(t *testing.T) {
+	t.Parallel()
+
+	const id byte = 0xd4
+	if err := RegisterCompressionType(CompressionType(id), "test-name-only"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	codec := NewBinaryCompressionCodec[string](
+		JSONByteStringCodec[string]{},
+		0,
+		WithAutoCompression[string](CompressionBand{TypeID: id}),
+	)
+	if _, err := codec.Encode(CacheObject[string]{Value: "a"}); !errors.Is(err, ErrUnsupportedCompressionTypeID) {
+		t.Fatalf("expected ErrUnsupportedCompressionTypeID, got %v", err)
+	}
+	if _, err := codec.Decode([]byte{id, '"', 'a', '"'}); !errors.Is(err, ErrUnsupportedCompressionTypeID) {
+		t.Fatalf("expected ErrUnsupportedCompressionTypeID, got %v", err)
+	}
+}
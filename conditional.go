@@ -0,0 +1,72 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNotModifiedWithoutEntry is returned when a ConditionalLoadFunc reports
+// "not modified" although no cached entry was passed to it.
+var ErrNotModifiedWithoutEntry = errors.New("conditional loader reported not modified without a cached entry")
+
+// ConditionalLoadFunc loads a value given the cached entry being revalidated,
+// which is nil on a miss. It returns modified=false to keep prev.Value, for
+// example after an If-None-Match request answered with 304 Not Modified, in
+// which case the returned value is ignored.
+type ConditionalLoadFunc[V any] func(ctx context.Context, prev *CacheObject[V]) (value V, modified bool, err error)
+
+// conditionalLoad records whether the conditional loader of a call reported
+// the entry as not modified.
+type conditionalLoad struct {
+	notModified atomic.Bool
+}
+
+type conditionalLoadKey struct{}
+
+// GetOrLoadConditional returns a cached value or loads it with loader, which
+// receives the entry being revalidated so that it can perform a conditional
+// fetch. When loader reports the entry as not modified, the cached value is
+// kept and only its expiry is extended by ttl: codecs implementing
+// ExpiryHeaderCodec have just the stored header rewritten, other entries are
+// stored again. Followers joining the singleflight load share the leader's
+// entry.
+func (c *cacheImpl[V, S]) GetOrLoadConditional(ctx context.Context, key string, ttl time.Duration, loader ConditionalLoadFunc[V]) (V, error) {
+	state := &conditionalLoad{}
+	ctx = context.WithValue(ctx, conditionalLoadKey{}, state)
+
+	return c.getOrLoad(ctx, key, ttl, func(prev *CacheObject[V]) CacheLoadFunc[V] {
+		return func(ctx context.Context) (V, error) {
+			v, modified, err := loader(ctx, prev)
+			if err != nil || modified {
+				return v, err
+			}
+			if prev == nil {
+				var zero V
+
+				return zero, ErrNotModifiedWithoutEntry
+			}
+			state.notModified.Store(true)
+
+			return prev.Value, nil
+		}
+	})
+}
+
+// extendUnmodified moves the expiry of the stored entry for key to that of co
+// without rewriting its value when the conditional load of this call reported
+// it as not modified and the codec keeps expiries in a header. It reports
+// false when the entry has to be stored in full instead.
+func (c *cacheImpl[V, S]) extendUnmodified(ctx context.Context, key string, co CacheObject[V]) bool {
+	state, _ := ctx.Value(conditionalLoadKey{}).(*conditionalLoad)
+	if state == nil || !state.notModified.Load() {
+		return false
+	}
+	if _, ok := c.codec.(ExpiryHeaderCodec[S]); !ok {
+		return false
+	}
+	extended, err := c.rewriteExpiry(ctx, key, func(int64) int64 { return co.ExpireAtMillis })
+
+	return extended && err == nil
+}
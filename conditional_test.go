@@ -0,0 +1,110 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_GetOrLoadConditionalNotModified(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	now := time.UnixMilli(1_000_000)
+	provider.items["key"] = CacheObject[string]{Value: "etag-1", ExpireAtMillis: now.Add(time.Second).UnixMilli()}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{})
+	impl := cache.(*cacheImpl[string, CacheObject[string]])
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(0)
+
+	var got *CacheObject[string]
+	value, err := cache.GetOrLoadConditional(context.Background(), "key", time.Minute, func(_ context.Context, prev *CacheObject[string]) (string, bool, error) {
+		got = prev
+
+		return "", false, nil
+	})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got == nil || got.Value != "etag-1" {
+		t.Fatalf("expected loader to receive the cached entry, got %+v", got)
+	}
+	if value != "etag-1" {
+		t.Fatalf("expected cached value, got %q", value)
+	}
+	if stored := provider.items["key"]; stored.Value != "etag-1" || stored.ExpireAtMillis != now.Add(time.Minute).UnixMilli() {
+		t.Fatalf("expected extended entry, got %+v", stored)
+	}
+}
+
+func TestCache_GetOrLoadConditionalModified(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{})
+
+	value, err := cache.GetOrLoadConditional(context.Background(), "key", time.Minute, func(_ context.Context, prev *CacheObject[string]) (string, bool, error) {
+		if prev != nil {
+			t.Errorf("expected nil entry on miss, got %+v", prev)
+		}
+
+		return "fresh", true, nil
+	})
+	if err != nil || value != "fresh" {
+		t.Fatalf("unexpected result %q, %v", value, err)
+	}
+
+	_, err = cache.GetOrLoadConditional(context.Background(), "other", time.Minute, func(context.Context, *CacheObject[string]) (string, bool, error) {
+		return "", false, nil
+	})
+	if !errors.Is(err, ErrNotModifiedWithoutEntry) {
+		t.Fatalf("expected ErrNotModifiedWithoutEntry, got %v", err)
+	}
+}
+
+type countingRawCodec struct {
+	RawByteCodec
+	encodes *atomic.Int64
+}
+
+func (c countingRawCodec) Encode(value CacheObject[[]byte]) ([]byte, error) {
+	c.encodes.Add(1)
+
+	return c.RawByteCodec.Encode(value)
+}
+
+func TestCache_GetOrLoadConditionalNotModifiedRewritesExpiryOnly(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	encodes := &atomic.Int64{}
+	cache := NewCache[[]byte, []byte](provider, countingRawCodec{encodes: encodes})
+	impl := cache.(*cacheImpl[[]byte, []byte])
+	now := time.Now()
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(0)
+	ctx := context.Background()
+	if err := cache.Set(ctx, "key", CacheObject[[]byte]{Value: []byte("body"), ExpireAtMillis: now.Add(time.Second).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	value, err := cache.GetOrLoadConditional(ctx, "key", time.Minute, func(context.Context, *CacheObject[[]byte]) ([]byte, bool, error) {
+		return nil, false, nil
+	})
+	if err != nil || string(value) != "body" {
+		t.Fatalf("expected the cached value, got %q, %v", value, err)
+	}
+	if got := encodes.Load(); got != 1 {
+		t.Fatalf("expected only the initial write to encode, got %d encodes", got)
+	}
+	raw, ok, _ := provider.Get(ctx, "key")
+	if !ok {
+		t.Fatal("expected the entry to remain")
+	}
+	co, err := RawByteCodec{}.Decode(raw)
+	if err != nil || string(co.Value) != "body" || co.ExpireAtMillis != now.Add(time.Minute).UnixMilli() {
+		t.Fatalf("expected the expiry header to be extended, got %+v, %v", co, err)
+	}
+}
@@ -0,0 +1,165 @@
+package crema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that decodes from either a Go duration string
+// such as "1.5s" or an integer number of nanoseconds, for use in
+// configuration files.
+type Duration time.Duration
+
+// MarshalText encodes the duration as a Go duration string.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText decodes a Go duration string.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+
+	return nil
+}
+
+// UnmarshalJSON decodes a Go duration string or an integer number of nanoseconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var nanos int64
+	if err := json.Unmarshal(data, &nanos); err == nil {
+		*d = Duration(nanos)
+
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string or an integer: %w", err)
+	}
+
+	return d.UnmarshalText([]byte(text))
+}
+
+// Config describes a cache as plain data, as an alternative to composing
+// options in code. Provider and Codec must be set programmatically; the other
+// fields can be decoded from JSON or YAML. Zero values keep the defaults of
+// NewCache, which also allows a nil Codec when S is CacheObject[V].
+type Config[V any, S any] struct {
+	// Provider stores encoded entries.
+	Provider CacheProvider[S] `json:"-" yaml:"-"`
+	// Codec encodes entries for Provider. It may be nil when S is
+	// CacheObject[V], storing objects as they are.
+	Codec CacheStorageCodec[V, S] `json:"-" yaml:"-"`
+	// DefaultTTL replaces a non-positive ttl passed to GetOrLoad or
+	// GetOrBatchLoad when positive.
+	DefaultTTL Duration `json:"defaultTTL,omitempty" yaml:"defaultTTL,omitempty"`
+	// FailOpen overrides whether GetOrLoad calls the loader when reading from
+	// the provider fails. It defaults to true.
+	FailOpen *bool `json:"failOpen,omitempty" yaml:"failOpen,omitempty"`
+	// RevalidationWindow overrides the revalidation window when set. Zero
+	// disables probabilistic early revalidation.
+	RevalidationWindow *Duration `json:"revalidationWindow,omitempty" yaml:"revalidationWindow,omitempty"`
+	// MaxLoadTimeout caps singleflight loader execution when positive.
+	MaxLoadTimeout Duration `json:"maxLoadTimeout,omitempty" yaml:"maxLoadTimeout,omitempty"`
+	// DirectLoader disables singleflight.
+	DirectLoader bool `json:"directLoader,omitempty" yaml:"directLoader,omitempty"`
+	// MaxConcurrentLoads caps concurrently running loaders when positive.
+	MaxConcurrentLoads int `json:"maxConcurrentLoads,omitempty" yaml:"maxConcurrentLoads,omitempty"`
+	// CompressThresholdBytes wraps Codec with NewBinaryCompressionCodec when
+	// set. It requires S to be []byte.
+	CompressThresholdBytes *int `json:"compressThresholdBytes,omitempty" yaml:"compressThresholdBytes,omitempty"`
+}
+
+// ErrInvalidConfig wraps every error reported by Config.Validate.
+var ErrInvalidConfig = errors.New("invalid cache config")
+
+// Validate reports every problem with the configuration at once. Each error
+// wraps ErrInvalidConfig.
+func (cfg Config[V, S]) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidConfig}, args...)...))
+	}
+
+	if cfg.Provider == nil {
+		invalid("provider is required")
+	}
+	var zero S
+	_, objectStorage := any(zero).(CacheObject[V])
+	if cfg.Codec == nil && !objectStorage {
+		invalid("codec is required unless storing CacheObject values")
+	}
+	if cfg.DefaultTTL < 0 {
+		invalid("defaultTTL must not be negative, got %s", time.Duration(cfg.DefaultTTL))
+	}
+	if cfg.RevalidationWindow != nil && *cfg.RevalidationWindow < 0 {
+		invalid("revalidationWindow must not be negative, got %s", time.Duration(*cfg.RevalidationWindow))
+	}
+	if cfg.MaxLoadTimeout < 0 {
+		invalid("maxLoadTimeout must not be negative, got %s", time.Duration(cfg.MaxLoadTimeout))
+	}
+	if cfg.DirectLoader && cfg.MaxLoadTimeout > 0 {
+		invalid("maxLoadTimeout has no effect with directLoader")
+	}
+	if cfg.MaxConcurrentLoads < 0 {
+		invalid("maxConcurrentLoads must not be negative, got %d", cfg.MaxConcurrentLoads)
+	}
+	if cfg.CompressThresholdBytes != nil && (cfg.Codec != nil || objectStorage) {
+		if _, ok := any(cfg.Codec).(CacheStorageCodec[V, []byte]); !ok {
+			invalid("compressThresholdBytes requires a []byte storage codec")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// options converts the configuration into cache options.
+func (cfg Config[V, S]) options() []CacheOption[V, S] {
+	var opts []CacheOption[V, S]
+	if cfg.DirectLoader {
+		opts = append(opts, WithDirectLoader[V, S]())
+	}
+	if cfg.RevalidationWindow != nil {
+		opts = append(opts, WithRevalidationWindow[V, S](time.Duration(*cfg.RevalidationWindow)))
+	}
+	if cfg.MaxLoadTimeout > 0 {
+		opts = append(opts, WithMaxLoadTimeout[V, S](time.Duration(cfg.MaxLoadTimeout)))
+	}
+	if cfg.MaxConcurrentLoads > 0 {
+		opts = append(opts, WithMaxConcurrentLoads[V, S](cfg.MaxConcurrentLoads))
+	}
+	if cfg.DefaultTTL > 0 || cfg.FailOpen != nil {
+		opts = append(opts, func(c *cacheImpl[V, S]) {
+			c.updateSettings(func(s *Settings) {
+				if cfg.DefaultTTL > 0 {
+					s.DefaultTTL = time.Duration(cfg.DefaultTTL)
+				}
+				if cfg.FailOpen != nil {
+					s.FailOpen = *cfg.FailOpen
+				}
+			})
+		})
+	}
+
+	return opts
+}
+
+// NewCacheFromConfig validates cfg and constructs a Cache from it. opts are
+// applied after the configuration, so they can set what Config cannot express.
+func NewCacheFromConfig[V any, S any](cfg Config[V, S], opts ...CacheOption[V, S]) (Cache[V, S], error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	codec := cfg.Codec
+	if cfg.CompressThresholdBytes != nil {
+		inner := any(cfg.Codec).(CacheStorageCodec[V, []byte])
+		codec = any(NewBinaryCompressionCodec(inner, *cfg.CompressThresholdBytes)).(CacheStorageCodec[V, S])
+	}
+
+	return NewCache(cfg.Provider, codec, append(cfg.options(), opts...)...), nil
+}
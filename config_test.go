@@ -0,0 +1,130 @@
+package crema
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var parsed struct {
+		Text  Duration `json:"text"`
+		Nanos Duration `json:"nanos"`
+	}
+	if err := json.Unmarshal([]byte(`{"text":"1.5s","nanos":2000}`), &parsed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if time.Duration(parsed.Text) != 1500*time.Millisecond {
+		t.Fatalf("unexpected text duration %v", time.Duration(parsed.Text))
+	}
+	if time.Duration(parsed.Nanos) != 2*time.Microsecond {
+		t.Fatalf("unexpected numeric duration %v", time.Duration(parsed.Nanos))
+	}
+	if err := json.Unmarshal([]byte(`{"text":"soon"}`), &parsed); err == nil {
+		t.Fatal("expected invalid duration to fail")
+	}
+	text, err := Duration(time.Minute).MarshalText()
+	if err != nil || string(text) != "1m0s" {
+		t.Fatalf("unexpected marshal result %q, %v", text, err)
+	}
+}
+
+func TestConfig_ValidateAggregatesErrors(t *testing.T) {
+	t.Parallel()
+
+	window := Duration(-time.Second)
+	threshold := 10
+	cfg := Config[int, CacheObject[int]]{
+		Codec:                  NoopCacheStorageCodec[int]{},
+		DefaultTTL:             Duration(-time.Second),
+		RevalidationWindow:     &window,
+		MaxLoadTimeout:         Duration(time.Second),
+		DirectLoader:           true,
+		MaxConcurrentLoads:     -1,
+		CompressThresholdBytes: &threshold,
+	}
+
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	for _, want := range []string{
+		"provider is required",
+		"defaultTTL must not be negative",
+		"revalidationWindow must not be negative",
+		"maxLoadTimeout has no effect with directLoader",
+		"maxConcurrentLoads must not be negative",
+		"compressThresholdBytes requires a []byte storage codec",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to contain %q, got %v", want, err)
+		}
+	}
+}
+
+func TestNewCacheFromConfig_AppliesSettings(t *testing.T) {
+	t.Parallel()
+
+	var cfg Config[string, []byte]
+	raw := `{"defaultTTL":"1m","failOpen":false,"revalidationWindow":"2s","maxLoadTimeout":"150ms","maxConcurrentLoads":4,"compressThresholdBytes":0}`
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	provider := &byteProvider{items: make(map[string][]byte)}
+	cfg.Provider = provider
+	cfg.Codec = JSONByteStringCodec[string]{}
+
+	cache, err := NewCacheFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+	impl := cache.(*cacheImpl[string, []byte])
+	_, window := calculateSteepnessAndRevalidationWindow(2000)
+	if got := impl.settings.Load().revalidationWindowMilliseconds; got != window {
+		t.Fatalf("expected revalidation window %d, got %d", window, got)
+	}
+	if settings := impl.Settings(); settings.DefaultTTL != time.Minute || settings.FailOpen {
+		t.Fatalf("expected default ttl 1m and fail-closed, got %+v", settings)
+	}
+	if impl.maxLoadTimeout != 150*time.Millisecond {
+		t.Fatalf("expected max load timeout 150ms, got %v", impl.maxLoadTimeout)
+	}
+	if impl.loadLimiter.limit != 4 {
+		t.Fatal("expected load limiter with limit 4")
+	}
+	if _, ok := impl.codec.(*binaryCompressionCodec[string]); !ok {
+		t.Fatalf("expected compression codec, got %T", impl.codec)
+	}
+}
+
+func TestNewCacheFromConfig_RejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewCacheFromConfig(Config[int, CacheObject[int]]{}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestNewCacheFromConfig_AllowsNilObjectCodec(t *testing.T) {
+	t.Parallel()
+
+	cache, err := NewCacheFromConfig(Config[int, CacheObject[int]]{Provider: NewMemoryCacheProvider[CacheObject[int]]()})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !cache.(*cacheImpl[int, CacheObject[int]]).objectStorage {
+		t.Fatal("expected object storage")
+	}
+	if !cache.Settings().FailOpen {
+		t.Fatal("expected fail-open by default")
+	}
+
+	err = Config[string, []byte]{Provider: &byteProvider{items: make(map[string][]byte)}}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "codec is required") {
+		t.Fatalf("expected codec error, got %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const degradationBuckets = 10
+
+// DegradationPolicy configures automatic degradation on loader errors.
+type DegradationPolicy struct {
+	// Window is the sliding window over which the loader error rate is
+	// measured.
+	Window time.Duration
+	// ErrorRateThreshold is the error rate in (0, 1] at or above which the
+	// cache degrades.
+	ErrorRateThreshold float64
+	// MinLoads is the number of loads in the window required before the
+	// error rate is evaluated, so a single failure cannot trigger degradation.
+	MinLoads int
+	// TTLFactor multiplies the ttl of values loaded while degraded. Values
+	// below 1 are treated as 1.
+	TTLFactor float64
+}
+
+// degradationController tracks leader load outcomes in time buckets and
+// toggles the degraded state when the error rate crosses the threshold.
+type degradationController struct {
+	_        noCopy
+	policy   DegradationPolicy
+	mu       sync.Mutex
+	buckets  [degradationBuckets]degradationBucket
+	degraded bool
+}
+
+type degradationBucket struct {
+	start  int64
+	loads  int
+	errors int
+}
+
+// WithDegradation makes the cache degrade while the loader error rate over
+// policy.Window is at or above policy.ErrorRateThreshold. While degraded, a
+// failed load returns the cached value when one exists (stale-if-error), and
+// loaded values are stored with their ttl multiplied by policy.TTLFactor. The
+// cache recovers once the error rate drops below the threshold. State changes
+// are reported through MetricsProvider.RecordDegraded, logged as warnings, and
+// visible in Stats.
+func WithDegradation[V any, S any](policy DegradationPolicy) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if policy.Window <= 0 || policy.ErrorRateThreshold <= 0 {
+			c.degradation = nil
+
+			return
+		}
+		policy.TTLFactor = max(policy.TTLFactor, 1)
+		c.degradation = &degradationController{policy: policy}
+	}
+}
+
+// record adds a load outcome at nowMillis and reports whether the degraded
+// state changed along with the new state.
+func (d *degradationController) record(nowMillis int64, failed bool) (bool, bool) {
+	bucketMillis := max(d.policy.Window.Milliseconds()/degradationBuckets, 1)
+	start := nowMillis - nowMillis%bucketMillis
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	bucket := &d.buckets[(start/bucketMillis)%degradationBuckets]
+	if bucket.start != start {
+		*bucket = degradationBucket{start: start}
+	}
+	bucket.loads++
+	if failed {
+		bucket.errors++
+	}
+
+	loads, errs := 0, 0
+	oldest := start - bucketMillis*(degradationBuckets-1)
+	for _, b := range d.buckets {
+		if b.start >= oldest {
+			loads += b.loads
+			errs += b.errors
+		}
+	}
+	degraded := d.degraded
+	if loads >= d.policy.MinLoads {
+		degraded = float64(errs)/float64(loads) >= d.policy.ErrorRateThreshold
+	}
+	changed := degraded != d.degraded
+	d.degraded = degraded
+
+	return changed, degraded
+}
+
+func (d *degradationController) isDegraded() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.degraded
+}
+
+// recordLoadOutcome feeds a leader load result to the degradation controller.
+// Loads canceled by their callers are not counted.
+func (c *cacheImpl[V, S]) recordLoadOutcome(ctx context.Context, err error) {
+	if c.degradation == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrLoadCanceled) {
+		return
+	}
+	changed, degraded := c.degradation.record(c.now().UnixMilli(), err != nil)
+	if !changed {
+		return
+	}
+	c.metrics.RecordDegraded(ctx, degraded)
+	if degraded {
+		c.logger.Warn("cache degraded by loader errors")
+	} else {
+		c.logger.Warn("cache recovered from degradation")
+	}
+}
+
+// degraded reports whether the cache is currently degraded.
+func (c *cacheImpl[V, S]) degraded() bool {
+	return c.degradation != nil && c.degradation.isDegraded()
+}
+
+// degradedTTL lengthens ttl by the degradation factor while degraded.
+func (c *cacheImpl[V, S]) degradedTTL(ttl time.Duration) time.Duration {
+	if !c.degraded() {
+		return ttl
+	}
+
+	return time.Duration(float64(ttl) * c.degradation.policy.TTLFactor)
+}
+
+// serveStale returns the cached value in place of a load error while degraded
+// or when the entry was soft-deleted.
+func (c *cacheImpl[V, S]) serveStale(ctx context.Context, key string, found bool, cached CacheObject[V], err error) (V, bool) {
+	if !found || (!c.degraded() && !c.tombstoned(ctx, key)) {
+		var zero V
+
+		return zero, false
+	}
+	c.logger.Warn("serving stale value after load failure", slog.String("key", key), slog.String("error", err.Error()))
+
+	return cached.Value, true
+}
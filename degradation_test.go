@@ -0,0 +1,100 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type degradationMetricsProvider struct {
+	BaseMetricsProvider
+	entered   atomic.Int32
+	recovered atomic.Int32
+}
+
+func (m *degradationMetricsProvider) RecordDegraded(_ context.Context, degraded bool) {
+	if degraded {
+		m.entered.Add(1)
+	} else {
+		m.recovered.Add(1)
+	}
+}
+
+func TestWithDegradation_ServesStaleAndLengthensTTL(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"stale": {Value: 1, ExpireAtMillis: 500},
+	}}
+	metrics := &degradationMetricsProvider{}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithDirectLoader[int, CacheObject[int]](),
+		WithDegradation[int, CacheObject[int]](DegradationPolicy{
+			Window:             time.Minute,
+			ErrorRateThreshold: 0.5,
+			MinLoads:           2,
+			TTLFactor:          3,
+		}),
+	)
+	now := time.UnixMilli(1000)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return now }
+	ctx := context.Background()
+	errBackend := errors.New("backend down")
+	failing := func(context.Context) (int, error) { return 0, errBackend }
+
+	if _, err := cache.GetOrLoad(ctx, "stale", time.Second, failing); !errors.Is(err, errBackend) {
+		t.Fatalf("expected load error before reaching min loads, got %v", err)
+	}
+	v, err := cache.GetOrLoad(ctx, "stale", time.Second, failing)
+	if err != nil || v != 1 {
+		t.Fatalf("expected stale value while degraded, got %d, %v", v, err)
+	}
+	if !cache.Stats().Degraded {
+		t.Fatal("expected stats to report degradation")
+	}
+	if _, err := cache.GetOrLoad(ctx, "missing", time.Second, failing); !errors.Is(err, errBackend) {
+		t.Fatalf("expected load error without a cached value, got %v", err)
+	}
+
+	if _, err := cache.GetOrLoad(ctx, "fresh", time.Second, func(context.Context) (int, error) {
+		return 2, nil
+	}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := provider.items["fresh"].ExpireAtMillis; got != now.Add(3*time.Second).UnixMilli() {
+		t.Fatalf("expected lengthened expiry, got %d", got)
+	}
+	if got := metrics.entered.Load(); got != 1 {
+		t.Fatalf("expected one degradation, got %d", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	for i := range 2 {
+		if _, err := cache.GetOrLoad(ctx, "recovered", time.Second, func(context.Context) (int, error) {
+			return i + 10, nil
+		}); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		delete(provider.items, "recovered")
+	}
+	if cache.Stats().Degraded {
+		t.Fatal("expected recovery after successful loads")
+	}
+	if got := metrics.recovered.Load(); got != 1 {
+		t.Fatalf("expected one recovery, got %d", got)
+	}
+}
+
+func TestWithDegradation_InvalidPolicyDisables(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithDegradation[int, CacheObject[int]](DegradationPolicy{}),
+	)
+	if cache.(*cacheImpl[int, CacheObject[int]]).degradation != nil {
+		t.Fatal("expected degradation to be disabled")
+	}
+}
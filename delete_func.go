@@ -0,0 +1,88 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DeleteFuncProvider is an optional CacheProvider extension that removes
+// every entry whose key matches a predicate in one pass, for in-memory
+// providers that can enumerate their keys.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type DeleteFuncProvider[S any] interface {
+	CacheProvider[S]
+	// DeleteFunc removes the entries whose key fn reports true for and
+	// returns how many were removed. fn must not call back into the provider.
+	DeleteFunc(ctx context.Context, fn func(key string) bool) (int, error)
+}
+
+// ErrDeleteFuncNotSupported is returned by DeleteFunc when the provider
+// implements neither DeleteFuncProvider nor AdminProvider.
+var ErrDeleteFuncNotSupported = errors.New("cache provider does not support deleting by predicate")
+
+// DeleteFunc removes every entry whose key fn reports true for, such as all
+// keys containing a user ID, and returns how many were removed. It uses
+// DeleteFuncProvider when the provider implements it and otherwise scans an
+// AdminProvider and deletes matching keys one by one. It is meant for local
+// invalidation in development and small deployments; shared backends should
+// invalidate by known keys instead.
+func (c *cacheImpl[V, S]) DeleteFunc(ctx context.Context, fn func(key string) bool) (int, error) {
+	ctx = c.metricsContext(ctx)
+
+	started := time.Now()
+	deleted, err := c.deleteFunc(ctx, fn)
+	for _, key := range deleted {
+		c.metrics.RecordCacheDelete(ctx)
+		c.scopeDelete(ctx, key)
+		c.cancelSetRetry(key)
+		c.forgetRecency(key)
+		c.forgetTags(key)
+		c.replicateDelete(ctx, key)
+	}
+	if err != nil {
+		return len(deleted), c.observeError(ctx, ErrorPhaseProviderDelete, "", started, err)
+	}
+
+	return len(deleted), nil
+}
+
+// deleteFunc removes the entries matching fn and returns their keys.
+func (c *cacheImpl[V, S]) deleteFunc(ctx context.Context, fn func(key string) bool) ([]string, error) {
+	var deleted []string
+	if deleter, ok := providerAs[DeleteFuncProvider[S]](c.provider); ok {
+		_, err := deleter.DeleteFunc(ctx, func(key string) bool {
+			if !fn(key) {
+				return false
+			}
+			deleted = append(deleted, key)
+
+			return true
+		})
+
+		return deleted, err
+	}
+	admin, ok := providerAs[AdminProvider[S]](c.provider)
+	if !ok {
+		return nil, ErrDeleteFuncNotSupported
+	}
+
+	var keys []string
+	if err := admin.Scan(ctx, "", func(key string, _ S) bool {
+		if fn(key) {
+			keys = append(keys, key)
+		}
+
+		return true
+	}); err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if err := c.provider.Delete(ctx, key); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, key)
+	}
+
+	return deleted, nil
+}
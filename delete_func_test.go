@@ -0,0 +1,73 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeleteFunc_MemoryProvider(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[CacheObject[int]]()
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	ctx := context.Background()
+	expireAt := time.Now().Add(time.Minute).UnixMilli()
+	for _, key := range []string{"user:42:profile", "user:42:feed", "user:7:profile"} {
+		if err := cache.Set(ctx, key, CacheObject[int]{Value: 1, ExpireAtMillis: expireAt}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	n, err := cache.DeleteFunc(ctx, func(key string) bool { return strings.Contains(key, ":42:") })
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 deleted entries, got %d", n)
+	}
+	if provider.Len() != 1 {
+		t.Fatalf("expected 1 remaining entry, got %d", provider.Len())
+	}
+	if _, found, _ := cache.Get(ctx, "user:7:profile"); !found {
+		t.Fatal("expected unmatched entry to remain")
+	}
+}
+
+// adminOnlyProvider hides every extension of the wrapped provider but Scan.
+type adminOnlyProvider struct {
+	AdminProvider[CacheObject[int]]
+}
+
+func TestDeleteFunc_AdminProviderFallback(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[CacheObject[int]]()
+	cache := NewCache[int, CacheObject[int]](adminOnlyProvider{provider}, NoopCacheStorageCodec[int]{})
+	ctx := context.Background()
+	expireAt := time.Now().Add(time.Minute).UnixMilli()
+	for _, key := range []string{"a", "b", "c"} {
+		if err := cache.Set(ctx, key, CacheObject[int]{Value: 1, ExpireAtMillis: expireAt}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	n, err := cache.DeleteFunc(ctx, func(key string) bool { return key != "b" })
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 deleted entries, got %d, %v", n, err)
+	}
+	if provider.Len() != 1 {
+		t.Fatalf("expected 1 remaining entry, got %d", provider.Len())
+	}
+}
+
+func TestDeleteFunc_NotSupported(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache[int, CacheObject[int]](&errorProvider[CacheObject[int]]{}, NoopCacheStorageCodec[int]{})
+	if _, err := cache.DeleteFunc(context.Background(), func(string) bool { return true }); !errors.Is(err, ErrDeleteFuncNotSupported) {
+		t.Fatalf("expected ErrDeleteFuncNotSupported, got %v", err)
+	}
+}
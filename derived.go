@@ -0,0 +1,127 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DerivedCache caches a representation derived from the entries of a parent
+// cache, such as rendered HTML of a cached model, so the transform runs once
+// per parent entry. Derived entries live under their own namespace, expire
+// together with the parent entry they were derived from, and are dropped
+// when the parent key is written or invalidated through the DerivedCache.
+type DerivedCache[V any, W any, S any, D any] struct {
+	parent    Cache[V, S]
+	derived   Cache[W, D]
+	namespace string
+	transform func(V) (W, error)
+	now       func() time.Time
+}
+
+// NewDerivedCache wraps parent and stores values produced by transform in
+// derived under keys prefixed with namespace. transform must be pure: its
+// result may only depend on the parent value.
+func NewDerivedCache[V any, W any, S any, D any](parent Cache[V, S], derived Cache[W, D], namespace string, transform func(V) (W, error)) *DerivedCache[V, W, S, D] {
+	return &DerivedCache[V, W, S, D]{
+		parent:    parent,
+		derived:   derived,
+		namespace: namespace,
+		transform: transform,
+		now:       time.Now,
+	}
+}
+
+// Key returns the derived cache key for a parent key.
+func (d *DerivedCache[V, W, S, D]) Key(key string) string {
+	return d.namespace + ":" + key
+}
+
+// Get returns the derived value for key, deriving and storing it when only
+// the parent entry is cached. An expired parent entry is reported as a miss,
+// since deriving from it would outlive it.
+func (d *DerivedCache[V, W, S, D]) Get(ctx context.Context, key string) (W, bool, error) {
+	if w, ok := d.cached(ctx, key); ok {
+		return w, true, nil
+	}
+
+	co, ok, err := d.parent.Get(ctx, key)
+	if err != nil || !ok || co.ExpireAtMillis <= d.now().UnixMilli() {
+		var zero W
+
+		return zero, false, err
+	}
+	w, err := d.derive(ctx, key, co)
+	if err != nil {
+		var zero W
+
+		return zero, false, err
+	}
+
+	return w, true, nil
+}
+
+// GetOrLoad returns the derived value for key, loading the parent value with
+// loader through the parent cache when the parent entry is missing or
+// expired as well.
+func (d *DerivedCache[V, W, S, D]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader CacheLoadFunc[V]) (W, error) {
+	w, ok, err := d.Get(ctx, key)
+	if err == nil && ok {
+		return w, nil
+	}
+
+	v, err := d.parent.GetOrLoad(ctx, key, ttl, loader)
+	if err != nil {
+		var zero W
+
+		return zero, err
+	}
+	co := CacheObject[V]{Value: v, ExpireAtMillis: d.now().Add(ttl).UnixMilli()}
+	if stored, ok, err := d.parent.Get(ctx, key); err == nil && ok && stored.ExpireAtMillis > d.now().UnixMilli() {
+		co.ExpireAtMillis = stored.ExpireAtMillis
+	}
+
+	return d.derive(ctx, key, co)
+}
+
+// Set stores value in the parent cache and drops the derived entry.
+func (d *DerivedCache[V, W, S, D]) Set(ctx context.Context, key string, value CacheObject[V]) error {
+	if err := d.parent.Set(ctx, key, value); err != nil {
+		return err
+	}
+
+	return d.derived.Delete(ctx, d.Key(key))
+}
+
+// Invalidate removes both the parent and the derived entry for key.
+func (d *DerivedCache[V, W, S, D]) Invalidate(ctx context.Context, key string) error {
+	return errors.Join(d.parent.Delete(ctx, key), d.derived.Delete(ctx, d.Key(key)))
+}
+
+// cached returns the unexpired derived entry for key. Read errors are treated
+// as misses so that the value is derived again.
+func (d *DerivedCache[V, W, S, D]) cached(ctx context.Context, key string) (W, bool) {
+	co, ok, err := d.derived.Get(ctx, d.Key(key))
+	if err != nil || !ok || co.ExpireAtMillis <= d.now().UnixMilli() {
+		var zero W
+
+		return zero, false
+	}
+
+	return co.Value, true
+}
+
+// derive transforms a parent entry and stores the result with the parent's
+// expiry. A failed write only costs a later re-derivation, so it is left to
+// the derived cache's error observer instead of failing the read.
+func (d *DerivedCache[V, W, S, D]) derive(ctx context.Context, key string, parent CacheObject[V]) (W, error) {
+	w, err := d.transform(parent.Value)
+	if err != nil {
+		var zero W
+
+		return zero, err
+	}
+	_ = d.derived.Set(ctx, d.Key(key), CacheObject[W]{Value: w, ExpireAtMillis: parent.ExpireAtMillis})
+
+	return w, nil
+}
@@ -0,0 +1,98 @@
+package crema
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDerivedCache_DerivesOncePerParentEntry(t *testing.T) {
+	t.Parallel()
+
+	parent := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	derivedProvider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	derived := NewCache(derivedProvider, NoopCacheStorageCodec[string]{})
+	var transforms int
+	view := NewDerivedCache[int, string, CacheObject[int], CacheObject[string]](parent, derived, "html", func(v int) (string, error) {
+		transforms++
+
+		return "<p>" + strconv.Itoa(v) + "</p>", nil
+	})
+	ctx := context.Background()
+
+	for range 3 {
+		w, err := view.GetOrLoad(ctx, "model:1", time.Minute, func(context.Context) (int, error) {
+			return 1, nil
+		})
+		if err != nil {
+			t.Fatalf("get or load: %v", err)
+		}
+		if w != "<p>1</p>" {
+			t.Fatalf("unexpected derived value %q", w)
+		}
+	}
+	if transforms != 1 {
+		t.Fatalf("expected 1 transform, got %d", transforms)
+	}
+
+	parentEntry, _, _ := parent.Get(ctx, "model:1")
+	derivedEntry, ok := derivedProvider.items["html:model:1"]
+	if !ok {
+		t.Fatal("expected derived entry under its namespace")
+	}
+	if derivedEntry.ExpireAtMillis != parentEntry.ExpireAtMillis {
+		t.Fatalf("expected derived expiry %d to match parent %d", derivedEntry.ExpireAtMillis, parentEntry.ExpireAtMillis)
+	}
+
+	if err := view.Set(ctx, "model:1", CacheObject[int]{Value: 2, ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	w, ok, err := view.Get(ctx, "model:1")
+	if err != nil || !ok || w != "<p>2</p>" {
+		t.Fatalf("expected re-derived value after parent write, got %q, %v, %v", w, ok, err)
+	}
+
+	if err := view.Invalidate(ctx, "model:1"); err != nil {
+		t.Fatalf("invalidate: %v", err)
+	}
+	if _, ok, _ := view.Get(ctx, "model:1"); ok {
+		t.Fatal("expected invalidated key to miss")
+	}
+	if len(derivedProvider.items) != 0 {
+		t.Fatalf("expected derived entries to be removed, got %d", len(derivedProvider.items))
+	}
+}
+
+func TestDerivedCache_ReloadsExpiredParent(t *testing.T) {
+	t.Parallel()
+
+	parentProvider := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"model:1": {Value: 1, ExpireAtMillis: time.Now().Add(-time.Second).UnixMilli()},
+	}}
+	parent := NewCache(parentProvider, NoopCacheStorageCodec[int]{})
+	derived := NewCache(&testMemoryProvider[string]{items: make(map[string]CacheObject[string])}, NoopCacheStorageCodec[string]{})
+	view := NewDerivedCache[int, string, CacheObject[int], CacheObject[string]](parent, derived, "html", func(v int) (string, error) {
+		return "<p>" + strconv.Itoa(v) + "</p>", nil
+	})
+	ctx := context.Background()
+
+	if w, ok, err := view.Get(ctx, "model:1"); err != nil || ok {
+		t.Fatalf("expected an expired parent to miss, got %q, %v, %v", w, ok, err)
+	}
+	loads := 0
+	w, err := view.GetOrLoad(ctx, "model:1", time.Minute, func(context.Context) (int, error) {
+		loads++
+
+		return 2, nil
+	})
+	if err != nil || w != "<p>2</p>" {
+		t.Fatalf("expected the value derived from the reloaded parent, got %q, %v", w, err)
+	}
+	if loads != 1 {
+		t.Fatalf("expected the parent to be reloaded once, got %d", loads)
+	}
+	if got := parentProvider.items["model:1"]; got.Value != 2 || got.ExpireAtMillis <= time.Now().UnixMilli() {
+		t.Fatalf("expected the parent entry to be refreshed, got %+v", got)
+	}
+}
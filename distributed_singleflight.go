@@ -0,0 +1,378 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// markerKeyPrefix starts the suffixes of the sibling keys the cache
+	// stores next to entries. It only uses bytes memcache accepts in keys,
+	// which excludes spaces and control characters.
+	markerKeyPrefix = "|crema|"
+	// loadLockSuffix is appended to a key to form the sibling key electing
+	// the process that loads it.
+	loadLockSuffix = markerKeyPrefix + "loading"
+
+	defaultPollingWaitInterval    = 50 * time.Millisecond
+	defaultPollingWaitMaxInterval = time.Second
+	defaultNotifyFallbackInterval = time.Second
+)
+
+// FollowerWaitStrategy decides how a process that lost the distributed
+// singleflight election for a key waits for the leading process to store it.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type FollowerWaitStrategy interface {
+	// Wait blocks until ready reports true or ctx is done. ready reads the
+	// provider and reports whether the leader stored the key.
+	Wait(ctx context.Context, key string, ready func(ctx context.Context) (bool, error)) error
+	// Loaded is called by the leader once it stored key or gave up loading
+	// it.
+	Loaded(ctx context.Context, key string) error
+}
+
+// LoadNotifier delivers "key was loaded" notifications between processes,
+// for example over Redis pub/sub.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type LoadNotifier interface {
+	// Subscribe returns a channel receiving a value after key is notified,
+	// and a function ending the subscription. Notifications sent while the
+	// channel holds an undelivered value may be dropped.
+	Subscribe(ctx context.Context, key string) (<-chan struct{}, func(), error)
+	// Notify wakes the subscribers of key.
+	Notify(ctx context.Context, key string) error
+}
+
+// PollingWaitStrategy makes followers read the provider with exponential
+// backoff, for providers without a notification primitive such as memcache
+// or DynamoDB.
+type PollingWaitStrategy struct {
+	// Interval is the delay before the second read. Defaults to 50ms.
+	Interval time.Duration
+	// MaxInterval caps the delay between reads. Defaults to one second.
+	MaxInterval time.Duration
+}
+
+var _ FollowerWaitStrategy = PollingWaitStrategy{}
+
+// Wait polls ready with exponential backoff until it reports true or ctx is done.
+func (s PollingWaitStrategy) Wait(ctx context.Context, _ string, ready func(ctx context.Context) (bool, error)) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultPollingWaitInterval
+	}
+	maxInterval := s.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultPollingWaitMaxInterval
+	}
+	for {
+		if ok, err := ready(ctx); err != nil || ok {
+			return err
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(interval*2, max(maxInterval, interval))
+	}
+}
+
+// Loaded does nothing; polling followers notice the stored key on their own.
+func (PollingWaitStrategy) Loaded(context.Context, string) error {
+	return nil
+}
+
+// NotifyWaitStrategy makes followers block on notifications the leader sends
+// through Notifier, for providers with a pub/sub primitive.
+type NotifyWaitStrategy struct {
+	// Notifier carries the notifications. It is required.
+	Notifier LoadNotifier
+	// FallbackInterval is how often followers still read the provider in
+	// case a notification is lost. Defaults to one second.
+	FallbackInterval time.Duration
+}
+
+var _ FollowerWaitStrategy = NotifyWaitStrategy{}
+
+// Wait subscribes to key and checks ready on every notification until it
+// reports true or ctx is done.
+func (s NotifyWaitStrategy) Wait(ctx context.Context, key string, ready func(ctx context.Context) (bool, error)) error {
+	notified, unsubscribe, err := s.Notifier.Subscribe(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+	interval := s.FallbackInterval
+	if interval <= 0 {
+		interval = defaultNotifyFallbackInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		// checked after subscribing, so a notification cannot be missed
+		if ok, err := ready(ctx); err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notified:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Loaded notifies the followers of key.
+func (s NotifyWaitStrategy) Loaded(ctx context.Context, key string) error {
+	return s.Notifier.Notify(ctx, key)
+}
+
+// LocalLoadNotifier is an in-process LoadNotifier, for caches in one process
+// sharing a provider and for tests.
+type LocalLoadNotifier struct {
+	_           noCopy
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+var _ LoadNotifier = (*LocalLoadNotifier)(nil)
+
+// NewLocalLoadNotifier constructs a LocalLoadNotifier.
+func NewLocalLoadNotifier() *LocalLoadNotifier {
+	return &LocalLoadNotifier{subscribers: make(map[string]map[chan struct{}]struct{})}
+}
+
+// Subscribe registers a subscriber of key.
+func (n *LocalLoadNotifier) Subscribe(_ context.Context, key string) (<-chan struct{}, func(), error) {
+	ch := make(chan struct{}, 1)
+	n.mu.Lock()
+	subscribers, ok := n.subscribers[key]
+	if !ok {
+		subscribers = make(map[chan struct{}]struct{})
+		n.subscribers[key] = subscribers
+	}
+	subscribers[ch] = struct{}{}
+	n.mu.Unlock()
+	unsubscribe := func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(subscribers, ch)
+		if current, ok := n.subscribers[key]; ok && len(current) == 0 {
+			delete(n.subscribers, key)
+		}
+	}
+
+	return ch, unsubscribe, nil
+}
+
+// Notify wakes every subscriber of key without blocking.
+func (n *LocalLoadNotifier) Notify(_ context.Context, key string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for ch := range n.subscribers[key] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// distributedSingleflight configures WithDistributedSingleflight.
+type distributedSingleflight struct {
+	lease time.Duration
+	wait  FollowerWaitStrategy
+}
+
+// remoteLoad tracks the distributed singleflight election of one load.
+type remoteLoad struct {
+	key string
+	// afterMillis is the expiry of the entry being replaced; followers only
+	// accept entries expiring later.
+	afterMillis int64
+	// held is set by the loader, which may outlive a canceled caller.
+	held atomic.Bool
+}
+
+// WithDistributedSingleflight extends singleflight across processes sharing
+// a provider. Before loading a key, the process-local singleflight leader
+// stores a lock under a sibling key for lease; the process that stores it
+// loads, while the others wait with wait until an entry newer than the one
+// they found is stored and return it. The leader removes the lock and calls
+// wait.Loaded once it stored the value or its load failed. Followers that
+// find the lock released without a new entry, still find nothing after
+// lease, or whose wait fails load the key themselves, so a crashed leader
+// delays them by at most lease.
+//
+// Use PollingWaitStrategy for providers without notifications, such as
+// memcache or DynamoDB, and NotifyWaitStrategy with a LoadNotifier where
+// pub/sub is available. Locks are stored with GetOrSetProvider when the
+// provider implements it, and with a best-effort Get and Set otherwise. They
+// are encoded with the cache codec as an entry holding the zero value.
+// Followers store the value they received again, which is a no-op for
+// missing keys on a GetOrSetProvider. A non-positive lease or nil wait
+// disables it.
+func WithDistributedSingleflight[V any, S any](lease time.Duration, wait FollowerWaitStrategy) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if lease <= 0 || wait == nil {
+			c.distributedSingleflight = nil
+
+			return
+		}
+		c.distributedSingleflight = &distributedSingleflight{lease: lease, wait: wait}
+	}
+}
+
+// newRemoteLoad starts the distributed singleflight election of a load of
+// key replacing an entry expiring at afterMillis, or returns nil when it is
+// disabled.
+func (c *cacheImpl[V, S]) newRemoteLoad(key string, found bool, afterMillis int64) *remoteLoad {
+	if c.distributedSingleflight == nil || !c.experimentEnabled(ExperimentDistributedSingleflight, key) {
+		return nil
+	}
+	if !found {
+		afterMillis = 0
+	}
+
+	return &remoteLoad{key: key, afterMillis: afterMillis}
+}
+
+// coordinateLoad runs loader only when this process wins the election for
+// rl, and otherwise returns the value stored by the winner.
+func (c *cacheImpl[V, S]) coordinateLoad(rl *remoteLoad, loader CacheLoadFunc[V]) CacheLoadFunc[V] {
+	if rl == nil {
+		return loader
+	}
+
+	return func(ctx context.Context) (V, error) {
+		if c.acquireLoadLock(ctx, rl.key) {
+			rl.held.Store(true)
+
+			return loader(ctx)
+		}
+		if co, ok := c.awaitRemoteLoad(ctx, rl); ok {
+			return co.Value, nil
+		}
+
+		return loader(ctx)
+	}
+}
+
+// acquireLoadLock reports whether this process stored the load lock of key.
+// Provider and codec errors let the load proceed.
+func (c *cacheImpl[V, S]) acquireLoadLock(ctx context.Context, key string) bool {
+	d := c.distributedSingleflight
+	lockKey := key + loadLockSuffix
+	nowMillis := c.now().UnixMilli()
+	encoded, err := c.encode(lockKey, CacheObject[V]{ExpireAtMillis: nowMillis + d.lease.Milliseconds()})
+	if err != nil {
+		return true
+	}
+
+	if setter, ok := providerAs[GetOrSetProvider[S]](c.provider); ok {
+		actual, loaded, err := setter.GetOrSet(ctx, lockKey, encoded, d.lease)
+		if err != nil || !loaded || !c.loadLockHeld(lockKey, actual, nowMillis) {
+			return true
+		}
+		c.logger.Debug("waiting for load by another process", slog.String("key", key))
+
+		return false
+	}
+	if rv, exists, err := c.provider.Get(ctx, lockKey); err == nil && exists && c.loadLockHeld(lockKey, rv, nowMillis) {
+		c.logger.Debug("waiting for load by another process", slog.String("key", key))
+
+		return false
+	}
+	if err := c.provider.Set(ctx, lockKey, encoded, d.lease); err != nil {
+		c.logger.Debug("failed to store load lock", slog.String("key", key), slog.String("error", err.Error()))
+	}
+
+	return true
+}
+
+// loadLockHeld decodes a stored lock and reports whether it is unexpired.
+// Locks that fail to decode are not held.
+func (c *cacheImpl[V, S]) loadLockHeld(lockKey string, stored S, nowMillis int64) bool {
+	lock, err := c.decode(lockKey, stored)
+
+	return err == nil && lock.ExpireAtMillis > nowMillis
+}
+
+// awaitRemoteLoad waits up to the lease for another process to store a
+// fresh entry for rl.key newer than the one being replaced. It stops waiting
+// early when the lock is released without such an entry, as after a failed
+// load.
+func (c *cacheImpl[V, S]) awaitRemoteLoad(ctx context.Context, rl *remoteLoad) (CacheObject[V], bool) {
+	d := c.distributedSingleflight
+	waitCtx, cancel := context.WithTimeout(ctx, d.lease)
+	defer cancel()
+	var (
+		stored CacheObject[V]
+		found  bool
+	)
+	ready := func(ctx context.Context) (bool, error) {
+		var err error
+		if stored, found, err = c.remoteLoaded(ctx, rl); err != nil || found {
+			return found, err
+		}
+		lockKey := rl.key + loadLockSuffix
+		rv, exists, err := c.provider.Get(ctx, lockKey)
+		if err != nil || (exists && c.loadLockHeld(lockKey, rv, c.now().UnixMilli())) {
+			return false, err
+		}
+		// the leader may have stored the entry right before releasing the lock
+		stored, found, err = c.remoteLoaded(ctx, rl)
+
+		return true, err
+	}
+	if err := d.wait.Wait(waitCtx, rl.key, ready); err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			c.logger.Debug("failed to wait for load by another process", slog.String("key", rl.key), slog.String("error", err.Error()))
+		}
+
+		return CacheObject[V]{}, false
+	}
+
+	return stored, found
+}
+
+// remoteLoaded returns the entry stored for rl.key when it is fresh and
+// newer than the one being replaced.
+func (c *cacheImpl[V, S]) remoteLoaded(ctx context.Context, rl *remoteLoad) (CacheObject[V], bool, error) {
+	rv, exists, err := c.provider.Get(ctx, rl.key)
+	if err != nil || !exists {
+		return CacheObject[V]{}, false, err
+	}
+	co, err := c.decode(rl.key, rv)
+	if err != nil {
+		return CacheObject[V]{}, false, err
+	}
+	if co.ExpireAtMillis <= rl.afterMillis || co.ExpireAtMillis <= c.now().UnixMilli() {
+		return CacheObject[V]{}, false, nil
+	}
+
+	return co, true, nil
+}
+
+// releaseLoadLock removes the load lock rl holds and wakes the followers.
+func (c *cacheImpl[V, S]) releaseLoadLock(ctx context.Context, rl *remoteLoad) {
+	if rl == nil || !rl.held.Load() {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	if err := c.provider.Delete(ctx, rl.key+loadLockSuffix); err != nil {
+		c.logger.Debug("failed to remove load lock", slog.String("key", rl.key), slog.String("error", err.Error()))
+	}
+	if err := c.distributedSingleflight.wait.Loaded(ctx, rl.key); err != nil {
+		c.logger.Debug("failed to notify load", slog.String("key", rl.key), slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,143 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithDistributedSingleflight_FollowersReceiveLeaderValue(t *testing.T) {
+	t.Parallel()
+
+	strategies := map[string]func() FollowerWaitStrategy{
+		"notify": func() FollowerWaitStrategy {
+			return NotifyWaitStrategy{Notifier: NewLocalLoadNotifier(), FallbackInterval: time.Minute}
+		},
+		"polling": func() FollowerWaitStrategy {
+			return PollingWaitStrategy{Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond}
+		},
+	}
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := NewMemoryCacheProvider[CacheObject[int]]()
+			wait := strategy()
+			leader := NewCache(provider, NoopCacheStorageCodec[int]{},
+				WithDistributedSingleflight[int, CacheObject[int]](time.Minute, wait))
+			follower := NewCache(provider, NoopCacheStorageCodec[int]{},
+				WithDistributedSingleflight[int, CacheObject[int]](time.Minute, wait))
+			ctx := context.Background()
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			leaderDone := make(chan error, 1)
+			go func() {
+				_, err := leader.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+					close(started)
+					<-release
+
+					return 1, nil
+				})
+				leaderDone <- err
+			}()
+			<-started
+
+			var followerLoads atomic.Int32
+			followerDone := make(chan int, 1)
+			go func() {
+				v, err := follower.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+					followerLoads.Add(1)
+
+					return 2, nil
+				})
+				if err != nil {
+					t.Errorf("follower: %v", err)
+				}
+				followerDone <- v
+			}()
+			time.Sleep(20 * time.Millisecond)
+			close(release)
+
+			if err := <-leaderDone; err != nil {
+				t.Fatalf("leader: %v", err)
+			}
+			if got := <-followerDone; got != 1 {
+				t.Fatalf("expected the leader value 1, got %d", got)
+			}
+			if got := followerLoads.Load(); got != 0 {
+				t.Fatalf("expected no follower loads, got %d", got)
+			}
+			if _, exists, _ := provider.Get(ctx, "key"+loadLockSuffix); exists {
+				t.Fatal("expected the load lock to be released")
+			}
+		})
+	}
+}
+
+func TestWithDistributedSingleflight_FollowerLoadsAfterLeaderFailure(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[CacheObject[int]]()
+	wait := NotifyWaitStrategy{Notifier: NewLocalLoadNotifier(), FallbackInterval: time.Minute}
+	leader := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithDistributedSingleflight[int, CacheObject[int]](time.Minute, wait))
+	follower := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithDistributedSingleflight[int, CacheObject[int]](time.Minute, wait))
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		_, _ = leader.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 0, errors.New("boom")
+		})
+	}()
+	<-started
+
+	followerDone := make(chan int, 1)
+	go func() {
+		v, err := follower.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+			return 2, nil
+		})
+		if err != nil {
+			t.Errorf("follower: %v", err)
+		}
+		followerDone <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	select {
+	case got := <-followerDone:
+		if got != 2 {
+			t.Fatalf("expected the follower to load 2, got %d", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the follower to stop waiting once the lock was released")
+	}
+}
+
+func TestPollingWaitStrategy_StopsAtDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	calls := 0
+	err := PollingWaitStrategy{Interval: time.Millisecond}.Wait(ctx, "key", func(context.Context) (bool, error) {
+		calls++
+
+		return false, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if calls < 2 {
+		t.Fatalf("expected repeated polls, got %d", calls)
+	}
+}
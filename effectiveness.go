@@ -0,0 +1,276 @@
+package crema
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultEffectivenessSampleRate = 0.01
+	defaultEffectivenessInterval   = time.Minute
+	defaultEffectivenessMaxGroups  = 1000
+	effectivenessMaxLoadTimes      = 1024
+	// EffectivenessOtherGroup collects the outcomes of groups beyond
+	// EffectivenessReportPolicy.MaxGroups.
+	EffectivenessOtherGroup = "_other"
+)
+
+// EffectivenessReportPolicy configures WithEffectivenessReport.
+type EffectivenessReportPolicy[V any] struct {
+	// OnReport receives each report. It is called from a background
+	// goroutine and must not block for long.
+	OnReport func(report EffectivenessReport)
+	// SampleRate is the fraction of GetOrLoad calls recorded, in (0, 1].
+	// Defaults to 0.01.
+	SampleRate float64
+	// Interval is how long each report covers. Defaults to one minute.
+	Interval time.Duration
+	// GroupFunc maps keys to the groups outcomes are aggregated by. Defaults
+	// to the key up to its first ':'.
+	GroupFunc func(key string) string
+	// MaxGroups bounds the number of groups per report; outcomes of further
+	// groups are aggregated under EffectivenessOtherGroup. Defaults to 1000.
+	MaxGroups int
+	// Hash fingerprints values to detect refreshes that reloaded an
+	// unchanged value. Nil hashes the JSON encoding of values.
+	Hash func(value V) (uint64, error)
+}
+
+// EffectivenessReport summarizes sampled GetOrLoad outcomes over an interval.
+type EffectivenessReport struct {
+	// Start and End bound the interval the report covers.
+	Start, End time.Time
+	// SampleRate is the fraction of calls the counts were sampled from.
+	SampleRate float64
+	// Groups holds the outcomes per key group, sorted by group.
+	Groups []GroupEffectiveness
+}
+
+// GroupEffectiveness is the sampled outcome of GetOrLoad calls for one key
+// group.
+type GroupEffectiveness struct {
+	// Group is the key group.
+	Group string
+	// Calls is the number of sampled calls.
+	Calls int
+	// Hits is the number of calls served from the cache.
+	Hits int
+	// Loads is the number of calls that loaded the value.
+	Loads int
+	// Refreshes is the number of loads that replaced a cached entry, early or
+	// after it expired.
+	Refreshes int
+	// WastedRefreshes is the number of refreshes that reloaded an unchanged
+	// value, a sign the ttl could be longer.
+	WastedRefreshes int
+	// StaleServes is the number of calls served a stale value after a
+	// failed load.
+	StaleServes int
+	// Errors is the number of calls that failed.
+	Errors int
+	// P99LoadTime is the 99th percentile of the time calls spent loading.
+	P99LoadTime time.Duration
+}
+
+// HitRatio returns Hits divided by Calls, or zero without calls.
+func (g GroupEffectiveness) HitRatio() float64 {
+	if g.Calls == 0 {
+		return 0
+	}
+
+	return float64(g.Hits) / float64(g.Calls)
+}
+
+// effectivenessAnalyzer aggregates sampled outcomes into reports.
+type effectivenessAnalyzer[V any] struct {
+	policy EffectivenessReportPolicy[V]
+	mu     sync.Mutex
+	start  time.Time
+	groups map[string]*effectivenessGroup
+}
+
+type effectivenessGroup struct {
+	GroupEffectiveness
+	loadTimes []time.Duration
+	loadSeen  int
+}
+
+// effectivenessSample is the outcome of one sampled GetOrLoad call.
+type effectivenessSample struct {
+	key      string
+	started  time.Time
+	outer    *loadOutcome
+	outcome  loadOutcome
+	refresh  bool
+	unchange bool
+}
+
+// WithEffectivenessReport samples GetOrLoad outcomes and delivers a report
+// to policy.OnReport for every policy.Interval: per key group, the hit
+// ratio, refreshes that reloaded unchanged values, stale serves, errors and
+// the p99 load time, so ttls can be tuned with data. A report is delivered
+// once the first sampled call after its interval ends; intervals without
+// sampled calls produce no report. A nil OnReport disables it.
+func WithEffectivenessReport[V any, S any](policy EffectivenessReportPolicy[V]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if policy.OnReport == nil {
+			c.effectiveness = nil
+
+			return
+		}
+		if policy.SampleRate <= 0 || policy.SampleRate > 1 {
+			policy.SampleRate = defaultEffectivenessSampleRate
+		}
+		if policy.Interval <= 0 {
+			policy.Interval = defaultEffectivenessInterval
+		}
+		if policy.GroupFunc == nil {
+			policy.GroupFunc = func(key string) string {
+				group, _, _ := strings.Cut(key, ":")
+
+				return group
+			}
+		}
+		if policy.MaxGroups <= 0 {
+			policy.MaxGroups = defaultEffectivenessMaxGroups
+		}
+		if policy.Hash == nil {
+			policy.Hash = hashJSON[V]
+		}
+		c.effectiveness = &effectivenessAnalyzer[V]{policy: policy, groups: make(map[string]*effectivenessGroup)}
+	}
+}
+
+// sampleEffectiveness starts recording a sampled GetOrLoad call for key
+// reporting to out, or returns nil when the call is not sampled.
+func (c *cacheImpl[V, S]) sampleEffectiveness(key string, out *loadOutcome) *effectivenessSample {
+	if c.effectiveness == nil || !c.sampled(c.effectiveness.policy.SampleRate) {
+		return nil
+	}
+
+	return &effectivenessSample{key: key, started: time.Now(), outer: out, outcome: loadOutcome{status: BatchError}}
+}
+
+// observeRefresh records whether a sampled load replaced prev with an
+// unchanged value.
+func (c *cacheImpl[V, S]) observeRefresh(sample *effectivenessSample, prev *CacheObject[V], value V) {
+	if sample == nil || prev == nil {
+		return
+	}
+	sample.refresh = true
+	prevHash, err := c.effectiveness.policy.Hash(prev.Value)
+	if err != nil {
+		return
+	}
+	hash, err := c.effectiveness.policy.Hash(value)
+	sample.unchange = err == nil && prevHash == hash
+}
+
+// finishEffectiveness forwards the sampled call's outcome to its caller and
+// records the outcome, delivering the report of a finished interval.
+func (c *cacheImpl[V, S]) finishEffectiveness(sample *effectivenessSample) {
+	if sample.outer != nil && sample.outcome.status != BatchError {
+		*sample.outer = sample.outcome
+	}
+	report, ok := c.effectiveness.record(c.now(), sample, c.random)
+	if !ok {
+		return
+	}
+	onReport := c.effectiveness.policy.OnReport
+	c.runner.goAsync(func(context.Context) error {
+		onReport(report)
+
+		return nil
+	})
+}
+
+// record adds sample at now, returning the report of the previous interval
+// when now is past its end.
+func (a *effectivenessAnalyzer[V]) record(now time.Time, sample *effectivenessSample, random func() float64) (EffectivenessReport, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var report EffectivenessReport
+	rolled := false
+	switch end := a.start.Add(a.policy.Interval); {
+	case a.start.IsZero():
+		a.start = now
+	case !now.Before(end):
+		report, rolled = a.reportLocked(end), len(a.groups) > 0
+		a.groups = make(map[string]*effectivenessGroup, len(a.groups))
+		a.start = end
+		if !now.Before(end.Add(a.policy.Interval)) {
+			a.start = now
+		}
+	}
+
+	name := a.policy.GroupFunc(sample.key)
+	group, ok := a.groups[name]
+	if !ok {
+		if len(a.groups) >= a.policy.MaxGroups {
+			name = EffectivenessOtherGroup
+			group = a.groups[name]
+		}
+		if group == nil {
+			group = &effectivenessGroup{GroupEffectiveness: GroupEffectiveness{Group: name}}
+			a.groups[name] = group
+		}
+	}
+	group.Calls++
+	switch sample.outcome.status {
+	case BatchHit:
+		group.Hits++
+	case BatchStale:
+		group.StaleServes++
+	case BatchLoaded:
+		group.Loads++
+		group.addLoadTime(time.Since(sample.started), random)
+	default:
+		group.Errors++
+	}
+	if sample.refresh {
+		group.Refreshes++
+		if sample.unchange {
+			group.WastedRefreshes++
+		}
+	}
+
+	return report, rolled
+}
+
+// addLoadTime keeps a uniform reservoir of load times.
+func (g *effectivenessGroup) addLoadTime(d time.Duration, random func() float64) {
+	g.loadSeen++
+	if len(g.loadTimes) < effectivenessMaxLoadTimes {
+		g.loadTimes = append(g.loadTimes, d)
+
+		return
+	}
+	if i := int(random() * float64(g.loadSeen)); i < len(g.loadTimes) {
+		g.loadTimes[i] = d
+	}
+}
+
+// reportLocked builds the report of the interval ending at end.
+func (a *effectivenessAnalyzer[V]) reportLocked(end time.Time) EffectivenessReport {
+	report := EffectivenessReport{
+		Start:      a.start,
+		End:        end,
+		SampleRate: a.policy.SampleRate,
+		Groups:     make([]GroupEffectiveness, 0, len(a.groups)),
+	}
+	for _, group := range a.groups {
+		if n := len(group.loadTimes); n > 0 {
+			slices.Sort(group.loadTimes)
+			group.P99LoadTime = group.loadTimes[min(n-1, n*99/100)]
+		}
+		report.Groups = append(report.Groups, group.GroupEffectiveness)
+	}
+	slices.SortFunc(report.Groups, func(a, b GroupEffectiveness) int {
+		return strings.Compare(a.Group, b.Group)
+	})
+
+	return report
+}
@@ -0,0 +1,120 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithEffectivenessReport(t *testing.T) {
+	t.Parallel()
+
+	reports := make(chan EffectivenessReport, 1)
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithEffectivenessReport[int, CacheObject[int]](EffectivenessReportPolicy[int]{
+			SampleRate: 1,
+			Interval:   time.Minute,
+			OnReport: func(report EffectivenessReport) {
+				reports <- report
+			},
+		}),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	start := time.UnixMilli(1_000_000)
+	now := start
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(1)
+	ctx := context.Background()
+
+	loader := func(context.Context) (int, error) {
+		return 1, nil
+	}
+	failing := func(context.Context) (int, error) {
+		return 0, errors.New("boom")
+	}
+	for i, step := range []struct {
+		key     string
+		advance time.Duration
+		loader  CacheLoadFunc[int]
+	}{
+		{key: "user:1", loader: loader},
+		{key: "user:1", loader: loader},
+		{key: "user:1", advance: 11 * time.Second, loader: loader},
+		{key: "item:1", loader: failing},
+	} {
+		now = now.Add(step.advance)
+		_, _ = cache.GetOrLoad(ctx, step.key, 10*time.Second, step.loader)
+		select {
+		case report := <-reports:
+			t.Fatalf("step %d: unexpected report %+v", i, report)
+		default:
+		}
+	}
+
+	now = start.Add(time.Minute)
+	if _, err := cache.GetOrLoad(ctx, "user:1", 10*time.Second, loader); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var report EffectivenessReport
+	select {
+	case report = <-reports:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a report")
+	}
+	if !report.Start.Equal(start) || !report.End.Equal(start.Add(time.Minute)) {
+		t.Fatalf("expected interval [%v, %v), got [%v, %v)", start, start.Add(time.Minute), report.Start, report.End)
+	}
+	if len(report.Groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", report.Groups)
+	}
+	item, user := report.Groups[0], report.Groups[1]
+	if item.Group != "item" || item.Calls != 1 || item.Errors != 1 {
+		t.Fatalf("expected one failed item call, got %+v", item)
+	}
+	if user.Group != "user" || user.Calls != 3 || user.Hits != 1 || user.Loads != 2 {
+		t.Fatalf("expected 3 user calls with 1 hit and 2 loads, got %+v", user)
+	}
+	if user.Refreshes != 1 || user.WastedRefreshes != 1 {
+		t.Fatalf("expected 1 wasted refresh, got %d of %d", user.WastedRefreshes, user.Refreshes)
+	}
+	if got := user.HitRatio(); got != 1.0/3 {
+		t.Fatalf("expected hit ratio 1/3, got %v", got)
+	}
+}
+
+func TestEffectivenessAnalyzer_Groups(t *testing.T) {
+	t.Parallel()
+
+	a := &effectivenessAnalyzer[int]{
+		policy: EffectivenessReportPolicy[int]{
+			Interval:  time.Minute,
+			GroupFunc: func(key string) string { return key },
+			MaxGroups: 2,
+		},
+		groups: make(map[string]*effectivenessGroup),
+	}
+	now := time.UnixMilli(1_000_000)
+	for _, key := range []string{"a", "b", "c", "d", "a"} {
+		a.record(now, &effectivenessSample{key: key, outcome: loadOutcome{status: BatchStale}}, fakeRandom(0))
+	}
+	report, ok := a.record(now.Add(3*time.Minute), &effectivenessSample{key: "a", outcome: loadOutcome{status: BatchHit}}, fakeRandom(0))
+	if !ok {
+		t.Fatalf("expected a report")
+	}
+	if len(report.Groups) != 3 {
+		t.Fatalf("expected 3 groups, got %+v", report.Groups)
+	}
+	for i, want := range []struct {
+		group string
+		calls int
+	}{{"_other", 2}, {"a", 2}, {"b", 1}} {
+		if got := report.Groups[i]; got.Group != want.group || got.Calls != want.calls || got.StaleServes != want.calls {
+			t.Fatalf("expected group %q with %d stale calls, got %+v", want.group, want.calls, got)
+		}
+	}
+	if !a.start.Equal(now.Add(3 * time.Minute)) {
+		t.Fatalf("expected an idle analyzer to restart its interval, got %v", a.start)
+	}
+}
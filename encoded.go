@@ -0,0 +1,31 @@
+package crema
+
+// EncodedValue is a storage value encoded once per write and shared by every
+// provider and hook the write fans out to, such as the cache provider and a
+// ReplicationHook. Consumers share the underlying bytes and must treat Value
+// as read-only; one that needs to modify the payload, or to keep it where
+// others could modify it, takes a private copy with Clone first.
+type EncodedValue[S any] struct {
+	value S
+}
+
+// NewEncodedValue wraps an encoded storage value for fan-out.
+func NewEncodedValue[S any](value S) EncodedValue[S] {
+	return EncodedValue[S]{value: value}
+}
+
+// Value returns the shared, read-only storage value.
+func (e EncodedValue[S]) Value() S {
+	return e.value
+}
+
+// Clone returns a copy of the storage value that the caller owns. []byte
+// values are copied; other storage types are returned as is and must be
+// immutable to be shared.
+func (e EncodedValue[S]) Clone() S {
+	if b, ok := any(e.value).([]byte); ok && b != nil {
+		return any(append([]byte(nil), b...)).(S)
+	}
+
+	return e.value
+}
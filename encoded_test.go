@@ -0,0 +1,83 @@
+package crema
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingEncodeCodec struct {
+	JSONByteStringCodec[string]
+	encodes atomic.Int32
+}
+
+func (c *countingEncodeCodec) Encode(value CacheObject[string]) ([]byte, error) {
+	c.encodes.Add(1)
+
+	return c.JSONByteStringCodec.Encode(value)
+}
+
+type recordingReplicationHook struct {
+	mu    sync.Mutex
+	value []byte
+}
+
+func (h *recordingReplicationHook) AfterSet(_ context.Context, _ string, value []byte, _ time.Duration) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.value = value
+
+	return nil
+}
+
+func (h *recordingReplicationHook) AfterDelete(context.Context, string) error {
+	return nil
+}
+
+func TestEncodedValue_EncodesOnceAcrossFanOut(t *testing.T) {
+	t.Parallel()
+
+	provider := &byteProvider{items: make(map[string][]byte)}
+	codec := &countingEncodeCodec{}
+	hook := &recordingReplicationHook{}
+	cache := NewCache[string, []byte](provider, codec, WithReplicationHook[string, []byte](hook))
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "key", CacheObject[string]{Value: "v", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := codec.encodes.Load(); got != 1 {
+		t.Fatalf("expected 1 encode, got %d", got)
+	}
+	stored, _, _ := provider.Get(ctx, "key")
+	hook.mu.Lock()
+	replicated := hook.value
+	hook.mu.Unlock()
+	if len(stored) == 0 || len(replicated) != len(stored) || &stored[0] != &replicated[0] {
+		t.Fatalf("expected provider and hook to share the encoded bytes")
+	}
+}
+
+func TestEncodedValue_CloneIsIndependent(t *testing.T) {
+	t.Parallel()
+
+	shared := []byte("payload")
+	encoded := NewEncodedValue(shared)
+	owned := encoded.Clone()
+	owned[0] = 'P'
+
+	if got := string(encoded.Value()); got != "payload" {
+		t.Fatalf("expected shared value to be unchanged, got %q", got)
+	}
+	if got := string(owned); got != "Payload" {
+		t.Fatalf("expected clone to be modified, got %q", got)
+	}
+	if got := NewEncodedValue("text").Clone(); got != "text" {
+		t.Fatalf("expected text, got %q", got)
+	}
+}
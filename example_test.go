@@ -24,3 +24,8 @@ func ExampleCache() {
 	fmt.Println(value)
 	// Output: 42
 }
+
+func ExampleHashedKey() {
+	fmt.Println(NewKeyHasher(WithKeyHashLength(0)).Key("user", 42, "profile"))
+	// Output: s4:useri2:42s7:profile
+}
@@ -0,0 +1,116 @@
+package crema
+
+import (
+	"hash/fnv"
+	"maps"
+	"math"
+)
+
+// Experiment names a new behavior that can ship dark and be rolled out
+// gradually with WithExperimental and Settings.Experiments.
+type Experiment string
+
+const (
+	// ExperimentAdaptiveTTL gates the ttl adjustments of WithAdaptiveTTL.
+	ExperimentAdaptiveTTL Experiment = "adaptive_ttl"
+	// ExperimentDistributedSingleflight gates the load election of
+	// WithDistributedSingleflight.
+	ExperimentDistributedSingleflight Experiment = "distributed_singleflight"
+	// ExperimentReencodeOnUpgrade gates the rewrites of WithReencodeOnUpgrade.
+	ExperimentReencodeOnUpgrade Experiment = "reencode_on_upgrade"
+)
+
+// ExperimentOption sets the rollout of an experiment for WithExperimental.
+type ExperimentOption func(experiments map[Experiment]float64)
+
+// EnableExperiment enables e for every key.
+func EnableExperiment(e Experiment) ExperimentOption {
+	return RolloutExperiment(e, 1)
+}
+
+// DisableExperiment disables e, keeping its behavior configured but dark.
+func DisableExperiment(e Experiment) ExperimentOption {
+	return RolloutExperiment(e, 0)
+}
+
+// RolloutExperiment enables e for a fraction in [0, 1] of keys. Keys are
+// selected by a stable hash, so every process sharing a provider agrees on
+// them and raising the fraction only adds keys.
+func RolloutExperiment(e Experiment, fraction float64) ExperimentOption {
+	return func(experiments map[Experiment]float64) {
+		experiments[e] = fraction
+	}
+}
+
+// WithExperimental sets the initial rollout of experimental behaviors. An
+// experiment only takes effect for a cache that also configures its
+// behavior, such as WithAdaptiveTTL for ExperimentAdaptiveTTL; configured
+// behaviors without a rollout are fully enabled, as before they were gated.
+// Rollouts can be raised or lowered at runtime through
+// Settings.Experiments, and are reported in Stats.
+func WithExperimental[V any, S any](opts ...ExperimentOption) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.updateSettings(func(settings *Settings) {
+			experiments := maps.Clone(settings.Experiments)
+			if experiments == nil {
+				experiments = make(map[Experiment]float64, len(opts))
+			}
+			for _, opt := range opts {
+				if opt != nil {
+					opt(experiments)
+				}
+			}
+			settings.Experiments = experiments
+		})
+	}
+}
+
+// experimentRollout returns the fraction of keys e is enabled for.
+func (s *cacheSettings) experimentRollout(e Experiment) float64 {
+	fraction, ok := s.Experiments[e]
+	if !ok {
+		return 1
+	}
+
+	return min(max(fraction, 0), 1)
+}
+
+// experimentEnabled reports whether e is enabled for key.
+func (c *cacheImpl[V, S]) experimentEnabled(e Experiment, key string) bool {
+	switch fraction := c.settings.Load().experimentRollout(e); fraction {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(e))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(key))
+
+		return float64(h.Sum64()) < fraction*math.MaxUint64
+	}
+}
+
+// experiments returns the rollout of each experiment whose behavior is
+// configured.
+func (c *cacheImpl[V, S]) experiments() map[Experiment]float64 {
+	configured := map[Experiment]bool{
+		ExperimentAdaptiveTTL:             c.adaptiveTTL != nil,
+		ExperimentDistributedSingleflight: c.distributedSingleflight != nil,
+		ExperimentReencodeOnUpgrade:       c.reencode != nil,
+	}
+	settings := c.settings.Load()
+	var experiments map[Experiment]float64
+	for e, ok := range configured {
+		if !ok {
+			continue
+		}
+		if experiments == nil {
+			experiments = make(map[Experiment]float64, len(configured))
+		}
+		experiments[e] = settings.experimentRollout(e)
+	}
+
+	return experiments
+}
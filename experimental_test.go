@@ -0,0 +1,132 @@
+package crema
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWithExperimental_GatesAdaptiveTTL(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithAdaptiveTTL[int, CacheObject[int]](AdaptiveTTLPolicy[int]{MinTTL: 5 * time.Second, MaxTTL: 40 * time.Second}),
+		WithExperimental[int, CacheObject[int]](DisableExperiment(ExperimentAdaptiveTTL)),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(1)
+	ctx := context.Background()
+	loader := func(context.Context) (int, error) { return 1, nil }
+	storedTTL := func() time.Duration {
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+
+		return time.UnixMilli(provider.items["key"].ExpireAtMillis).Sub(now)
+	}
+
+	for i := range 3 {
+		if _, err := cache.GetOrLoad(ctx, "key", 10*time.Second, loader); err != nil {
+			t.Fatalf("load %d: %v", i, err)
+		}
+		if got := storedTTL(); got != 10*time.Second {
+			t.Fatalf("expected requested ttl while dark, got %v", got)
+		}
+		now = now.Add(11 * time.Second)
+	}
+	if got := cache.Stats().Experiments; len(got) != 1 || got[ExperimentAdaptiveTTL] != 0 {
+		t.Fatalf("expected dark adaptive ttl in stats, got %v", got)
+	}
+
+	settings := cache.Settings()
+	settings.Experiments[ExperimentAdaptiveTTL] = 1
+	cache.UpdateSettings(settings)
+	if _, err := cache.GetOrLoad(ctx, "key", 10*time.Second, loader); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := storedTTL(); got == 10*time.Second {
+		t.Fatal("expected adapted ttl once enabled")
+	}
+	if got := cache.Stats().Experiments[ExperimentAdaptiveTTL]; got != 1 {
+		t.Fatalf("expected enabled adaptive ttl in stats, got %v", got)
+	}
+}
+
+func TestWithExperimental_DefaultsAndStats(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithAdaptiveTTL[int, CacheObject[int]](AdaptiveTTLPolicy[int]{MaxTTL: time.Minute}),
+		WithExperimental[int, CacheObject[int]](RolloutExperiment(ExperimentDistributedSingleflight, 0.5)),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	if !impl.experimentEnabled(ExperimentAdaptiveTTL, "key") {
+		t.Fatal("expected configured behavior without a rollout to be enabled")
+	}
+	got := cache.Stats().Experiments
+	if len(got) != 1 || got[ExperimentAdaptiveTTL] != 1 {
+		t.Fatalf("expected only configured experiments in stats, got %v", got)
+	}
+
+	settings := cache.Settings()
+	settings.Experiments[ExperimentAdaptiveTTL] = 0
+	if !impl.experimentEnabled(ExperimentAdaptiveTTL, "key") {
+		t.Fatal("expected Settings to return a copy of the experiments")
+	}
+}
+
+func TestUpdateSettings_NilExperimentsKeepRollouts(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithAdaptiveTTL[int, CacheObject[int]](AdaptiveTTLPolicy[int]{MaxTTL: time.Minute}),
+		WithExperimental[int, CacheObject[int]](DisableExperiment(ExperimentAdaptiveTTL)),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+
+	cache.UpdateSettings(Settings{FailOpen: true, MaxConcurrentLoads: 8})
+	if impl.experimentEnabled(ExperimentAdaptiveTTL, "key") {
+		t.Fatal("expected the dark experiment to stay disabled")
+	}
+	if got := cache.Stats().Experiments[ExperimentAdaptiveTTL]; got != 0 {
+		t.Fatalf("expected rollout 0, got %v", got)
+	}
+
+	cache.UpdateSettings(Settings{FailOpen: true, Experiments: map[Experiment]float64{ExperimentAdaptiveTTL: 1}})
+	if !impl.experimentEnabled(ExperimentAdaptiveTTL, "key") {
+		t.Fatal("expected an explicit rollout to apply")
+	}
+}
+
+func TestExperimentEnabled_RolloutFraction(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithExperimental[int, CacheObject[int]](RolloutExperiment(ExperimentAdaptiveTTL, 0.25)),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	const keys = 10000
+	enabled := make(map[string]bool)
+	for i := range keys {
+		key := fmt.Sprintf("key:%d", i)
+		if impl.experimentEnabled(ExperimentAdaptiveTTL, key) {
+			enabled[key] = true
+		}
+	}
+	if ratio := float64(len(enabled)) / keys; math.Abs(ratio-0.25) > 0.03 {
+		t.Fatalf("expected about a quarter of keys enabled, got %v", ratio)
+	}
+
+	settings := impl.Settings()
+	settings.Experiments[ExperimentAdaptiveTTL] = 0.5
+	impl.UpdateSettings(settings)
+	for key := range enabled {
+		if !impl.experimentEnabled(ExperimentAdaptiveTTL, key) {
+			t.Fatalf("expected %q to stay enabled when the rollout grows", key)
+		}
+	}
+}
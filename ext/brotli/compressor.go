@@ -0,0 +1,88 @@
+package brotli
+
+import (
+	"bytes"
+
+	"github.com/abema/crema"
+	abrotli "github.com/andybalholm/brotli"
+)
+
+// TypeID is the compression type ID Register uses for brotli payloads. It is
+// persisted in cached payloads and must not change.
+const TypeID byte = 0x02
+
+// Option customizes a Compressor.
+type Option func(*Compressor)
+
+// Compressor compresses BinaryCompressionCodec payloads with brotli.
+type Compressor struct {
+	quality int
+	lgwin   int
+}
+
+var _ crema.Compressor = (*Compressor)(nil)
+
+// NewCompressor constructs a Compressor using brotli's default quality and
+// window size unless overridden by opts.
+func NewCompressor(opts ...Option) *Compressor {
+	c := &Compressor{quality: abrotli.DefaultCompression}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(c)
+	}
+
+	return c
+}
+
+// WithQuality sets the quality used when a CompressionBand has no level,
+// from abrotli.BestSpeed (0) to abrotli.BestCompression (11). Out-of-range
+// values are clamped.
+func WithQuality(quality int) Option {
+	return func(c *Compressor) {
+		c.quality = min(max(quality, abrotli.BestSpeed), abrotli.BestCompression)
+	}
+}
+
+// WithWindowBits sets the base 2 logarithm of the sliding window size, from
+// 10 to 24. Zero selects brotli's default based on quality.
+func WithWindowBits(lgwin int) Option {
+	return func(c *Compressor) {
+		c.lgwin = lgwin
+	}
+}
+
+// Register makes brotli available to every BinaryCompressionCodec under
+// TypeID. Call it once, from an init function or before any codec encodes
+// or decodes brotli payloads.
+func Register(opts ...Option) error {
+	return crema.RegisterCompression(TypeID, NewCompressor(opts...))
+}
+
+// Name returns "brotli".
+func (c *Compressor) Name() string {
+	return "brotli"
+}
+
+// Compress appends the brotli encoding of src to dst. A non-zero level is
+// used as the quality instead of the configured one.
+func (c *Compressor) Compress(dst *bytes.Buffer, src []byte, level int) error {
+	quality := c.quality
+	if level != 0 {
+		quality = min(max(level, abrotli.BestSpeed), abrotli.BestCompression)
+	}
+	w := abrotli.NewWriterOptions(dst, abrotli.WriterOptions{Quality: quality, LGWin: c.lgwin})
+	if _, err := w.Write(src); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
+
+// Decompress appends the decoding of the brotli stream src to dst.
+func (c *Compressor) Decompress(dst *bytes.Buffer, src []byte) error {
+	_, err := dst.ReadFrom(abrotli.NewReader(bytes.NewReader(src)))
+
+	return err
+}
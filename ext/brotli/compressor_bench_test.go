@@ -0,0 +1,133 @@
+package brotli
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/abema/crema"
+	"github.com/klauspost/compress/zstd"
+)
+
+const zstdTypeID byte = 0xd2
+
+// zstdCompressor compresses with klauspost/compress for comparison only.
+type zstdCompressor struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+func (zstdCompressor) Name() string { return "zstd-bench" }
+
+func (z zstdCompressor) Compress(dst *bytes.Buffer, src []byte, _ int) error {
+	dst.Write(z.encoder.EncodeAll(src, nil))
+
+	return nil
+}
+
+func (z zstdCompressor) Decompress(dst *bytes.Buffer, src []byte) error {
+	out, err := z.decoder.DecodeAll(src, nil)
+	if err != nil {
+		return err
+	}
+	dst.Write(out)
+
+	return nil
+}
+
+var registerBenchZstd = sync.OnceValue(func() error {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return err
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return err
+	}
+
+	return crema.RegisterCompression(zstdTypeID, zstdCompressor{encoder: encoder, decoder: decoder})
+})
+
+type benchItem struct {
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Score       float64           `json:"score"`
+	Attributes  map[string]string `json:"attributes"`
+}
+
+// benchItems returns n items shaped like a typical API response.
+func benchItems(n int) []benchItem {
+	items := make([]benchItem, n)
+	for i := range items {
+		items[i] = benchItem{
+			ID:          fmt.Sprintf("item-%06d", i),
+			Title:       fmt.Sprintf("Episode %d of the weekly show", i),
+			Description: "A recorded program available for a limited time after broadcast.",
+			Tags:        []string{"anime", "drama", fmt.Sprintf("season-%d", i%4)},
+			Score:       float64(i%100) / 10,
+			Attributes:  map[string]string{"genre": "animation", "rating": "G", "lang": "ja"},
+		}
+	}
+
+	return items
+}
+
+// BenchmarkCompression compares brotli with zlib and zstd on JSON payloads,
+// reporting the encoded size next to encode and decode times.
+func BenchmarkCompression(b *testing.B) {
+	if err := registerOnce(); err != nil {
+		b.Fatalf("register brotli: %v", err)
+	}
+	if err := registerBenchZstd(); err != nil {
+		b.Fatalf("register zstd: %v", err)
+	}
+	bands := []struct {
+		name string
+		band crema.CompressionBand
+	}{
+		{name: "zlib", band: crema.CompressionBand{TypeID: crema.CompressionTypeIDZlib, Level: zlib.DefaultCompression}},
+		{name: "zstd", band: crema.CompressionBand{TypeID: zstdTypeID}},
+		{name: "brotli-q5", band: crema.CompressionBand{TypeID: TypeID}},
+		{name: "brotli-q11", band: crema.CompressionBand{TypeID: TypeID, Level: 11}},
+	}
+	for _, items := range []int{10, 300} {
+		value := benchItems(items)
+		for _, bc := range bands {
+			codec := crema.NewBinaryCompressionCodec[[]benchItem](
+				crema.JSONByteStringCodec[[]benchItem]{},
+				0,
+				crema.WithAutoCompression[[]benchItem](bc.band),
+			)
+			input := crema.CacheObject[[]benchItem]{Value: value, ExpireAtMillis: 1}
+			encoded, err := codec.Encode(input)
+			if err != nil {
+				b.Fatalf("encode: %v", err)
+			}
+			raw, _ := json.Marshal(input)
+
+			b.Run(fmt.Sprintf("items_%d/%s/encode", items, bc.name), func(b *testing.B) {
+				b.ReportAllocs()
+				b.ReportMetric(float64(len(encoded)), "bytes")
+				b.ReportMetric(float64(len(encoded))/float64(len(raw)), "ratio")
+				for b.Loop() {
+					if _, err := codec.Encode(input); err != nil {
+						b.Fatalf("encode: %v", err)
+					}
+				}
+			})
+			b.Run(fmt.Sprintf("items_%d/%s/decode", items, bc.name), func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, err := codec.Decode(encoded); err != nil {
+						b.Fatalf("decode: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package brotli
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abema/crema"
+)
+
+var registerOnce = sync.OnceValue(func() error {
+	return Register(WithQuality(5))
+})
+
+func TestCompressor_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := []byte(strings.Repeat(`{"id":"item","tags":["a","b","c"]}`, 100))
+	for _, level := range []int{0, 1, 11} {
+		var compressed bytes.Buffer
+		if err := NewCompressor().Compress(&compressed, src, level); err != nil {
+			t.Fatalf("compress level %d: %v", level, err)
+		}
+		if compressed.Len() >= len(src) {
+			t.Fatalf("expected compression at level %d, got %d bytes from %d", level, compressed.Len(), len(src))
+		}
+		var decompressed bytes.Buffer
+		if err := NewCompressor().Decompress(&decompressed, compressed.Bytes()); err != nil {
+			t.Fatalf("decompress level %d: %v", level, err)
+		}
+		if !bytes.Equal(decompressed.Bytes(), src) {
+			t.Fatalf("expected round trip at level %d to match", level)
+		}
+	}
+}
+
+func TestRegister_BinaryCompressionCodec(t *testing.T) {
+	t.Parallel()
+
+	if err := registerOnce(); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	codec := crema.NewBinaryCompressionCodec[string](
+		crema.JSONByteStringCodec[string]{},
+		0,
+		crema.WithAutoCompression[string](crema.CompressionBand{TypeID: TypeID}),
+	)
+	value := strings.Repeat("brotli ", 200)
+	encoded, err := codec.Encode(crema.CacheObject[string]{Value: value, ExpireAtMillis: 1})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if typ, err := crema.CompressionTypeOf(encoded); err != nil || typ.String() != "brotli" {
+		t.Fatalf("expected brotli payload, got %v, %v", typ, err)
+	}
+	decoded, err := codec.Decode(encoded)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.Value != value {
+		t.Fatalf("expected value to round trip, got %d bytes", len(decoded.Value))
+	}
+}
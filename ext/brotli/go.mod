@@ -0,0 +1,10 @@
+module github.com/abema/crema/ext/brotli
+
+go 1.25.0
+
+require github.com/abema/crema v1.0.2
+
+require (
+	github.com/andybalholm/brotli v1.2.0
+	github.com/klauspost/compress v1.18.0
+)
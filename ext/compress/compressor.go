@@ -0,0 +1,158 @@
+package compress
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/abema/crema"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// SnappyTypeID is the compression type ID Register uses for snappy
+	// payloads. It is persisted in cached payloads and must not change.
+	SnappyTypeID = crema.CompressionTypeIDSnappy
+	// ZstdTypeID is the compression type ID Register uses for zstd payloads.
+	// It is persisted in cached payloads and must not change.
+	ZstdTypeID = crema.CompressionTypeIDZstd
+)
+
+// Option customizes a ZstdCompressor.
+type Option func(*ZstdCompressor)
+
+// SnappyCompressor compresses BinaryCompressionCodec payloads with the snappy
+// block format. Snappy has no levels, so CompressionBand levels are ignored.
+type SnappyCompressor struct{}
+
+var _ crema.Compressor = SnappyCompressor{}
+
+// Name returns "snappy".
+func (SnappyCompressor) Name() string {
+	return "snappy"
+}
+
+// Compress appends the snappy encoding of src to dst.
+func (SnappyCompressor) Compress(dst *bytes.Buffer, src []byte, _ int) error {
+	_, err := dst.Write(snappy.Encode(nil, src))
+
+	return err
+}
+
+// Decompress appends the decoding of the snappy block src to dst.
+func (SnappyCompressor) Decompress(dst *bytes.Buffer, src []byte) error {
+	out, err := snappy.Decode(nil, src)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(out)
+
+	return err
+}
+
+// ZstdCompressor compresses BinaryCompressionCodec payloads with zstd. It
+// keeps one encoder per level, built on first use, and a shared decoder.
+type ZstdCompressor struct {
+	level    zstd.EncoderLevel
+	mu       sync.Mutex
+	encoders map[zstd.EncoderLevel]*zstd.Encoder
+	decoder  *zstd.Decoder
+}
+
+var _ crema.Compressor = (*ZstdCompressor)(nil)
+
+// NewZstdCompressor constructs a ZstdCompressor using zstd's default level
+// unless overridden by opts.
+func NewZstdCompressor(opts ...Option) (*ZstdCompressor, error) {
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	if err != nil {
+		return nil, err
+	}
+	c := &ZstdCompressor{
+		level:    zstd.SpeedDefault,
+		encoders: make(map[zstd.EncoderLevel]*zstd.Encoder),
+		decoder:  decoder,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// WithZstdLevel sets the level used when a CompressionBand has no level, as
+// a zstd level from 1 to 22 mapped to the closest supported encoder level.
+func WithZstdLevel(level int) Option {
+	return func(c *ZstdCompressor) {
+		c.level = zstd.EncoderLevelFromZstd(level)
+	}
+}
+
+// Register makes snappy and zstd available to every BinaryCompressionCodec
+// under SnappyTypeID and ZstdTypeID, which also switches
+// crema.DefaultCompressionBands to them. Call it once, from an init function
+// or before any codec encodes or decodes snappy or zstd payloads.
+func Register(opts ...Option) error {
+	zstdCompressor, err := NewZstdCompressor(opts...)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(
+		crema.RegisterCompression(SnappyTypeID, SnappyCompressor{}),
+		crema.RegisterCompression(ZstdTypeID, zstdCompressor),
+	)
+}
+
+// Name returns "zstd".
+func (c *ZstdCompressor) Name() string {
+	return "zstd"
+}
+
+// Compress appends the zstd encoding of src to dst. A non-zero level is used
+// as the zstd level instead of the configured one.
+func (c *ZstdCompressor) Compress(dst *bytes.Buffer, src []byte, level int) error {
+	encoderLevel := c.level
+	if level != 0 {
+		encoderLevel = zstd.EncoderLevelFromZstd(level)
+	}
+	encoder, err := c.encoder(encoderLevel)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(encoder.EncodeAll(src, nil))
+
+	return err
+}
+
+// Decompress appends the decoding of the zstd frame src to dst.
+func (c *ZstdCompressor) Decompress(dst *bytes.Buffer, src []byte) error {
+	out, err := c.decoder.DecodeAll(src, nil)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(out)
+
+	return err
+}
+
+// encoder returns the encoder for level, building it on first use.
+func (c *ZstdCompressor) encoder(level zstd.EncoderLevel) (*zstd.Encoder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if encoder, ok := c.encoders[level]; ok {
+		return encoder, nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, err
+	}
+	c.encoders[level] = encoder
+
+	return encoder, nil
+}
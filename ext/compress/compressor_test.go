@@ -0,0 +1,76 @@
+package compress
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abema/crema"
+)
+
+var registerOnce = sync.OnceValue(func() error {
+	return Register(WithZstdLevel(3))
+})
+
+func TestCompressors_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	zstdCompressor, err := NewZstdCompressor()
+	if err != nil {
+		t.Fatalf("new zstd compressor: %v", err)
+	}
+	src := []byte(strings.Repeat(`{"id":"item","tags":["a","b","c"]}`, 100))
+	for _, compressor := range []crema.Compressor{SnappyCompressor{}, zstdCompressor} {
+		for _, level := range []int{0, 1, 19} {
+			var compressed bytes.Buffer
+			if err := compressor.Compress(&compressed, src, level); err != nil {
+				t.Fatalf("%s: compress level %d: %v", compressor.Name(), level, err)
+			}
+			if compressed.Len() >= len(src) {
+				t.Fatalf("%s: expected compression at level %d, got %d bytes from %d", compressor.Name(), level, compressed.Len(), len(src))
+			}
+			var decompressed bytes.Buffer
+			if err := compressor.Decompress(&decompressed, compressed.Bytes()); err != nil {
+				t.Fatalf("%s: decompress level %d: %v", compressor.Name(), level, err)
+			}
+			if !bytes.Equal(decompressed.Bytes(), src) {
+				t.Fatalf("%s: expected round trip at level %d to match", compressor.Name(), level)
+			}
+		}
+	}
+}
+
+func TestRegister_DefaultCompressionBands(t *testing.T) {
+	t.Parallel()
+
+	if err := registerOnce(); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	codec := crema.NewBinaryCompressionCodec[string](
+		crema.JSONByteStringCodec[string]{},
+		0,
+		crema.WithAutoCompression[string](),
+	)
+	for size, expected := range map[int]string{
+		100:       "none",
+		4 * 1024:  "snappy",
+		64 * 1024: "zstd",
+	} {
+		value := strings.Repeat("a", size)
+		encoded, err := codec.Encode(crema.CacheObject[string]{Value: value, ExpireAtMillis: 1})
+		if err != nil {
+			t.Fatalf("size %d: encode: %v", size, err)
+		}
+		if typ, err := crema.CompressionTypeOf(encoded); err != nil || typ.String() != expected {
+			t.Fatalf("size %d: expected %s payload, got %v, %v", size, expected, typ, err)
+		}
+		decoded, err := codec.Decode(encoded)
+		if err != nil {
+			t.Fatalf("size %d: decode: %v", size, err)
+		}
+		if decoded.Value != value {
+			t.Fatalf("size %d: expected value to round trip, got %d bytes", size, len(decoded.Value))
+		}
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/abema/crema/ext/compress
+
+go 1.25.0
+
+require github.com/abema/crema v1.0.2
+
+require github.com/klauspost/compress v1.18.0
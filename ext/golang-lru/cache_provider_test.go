@@ -2,8 +2,11 @@ package golanglru
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/abema/crema"
 )
 
 func TestCacheProvider_GetSetDelete(t *testing.T) {
@@ -71,3 +74,132 @@ func TestCacheProvider_DefaultTTL(t *testing.T) {
 
 	t.Fatal("expected value to expire")
 }
+
+func TestCacheProvider_Scan(t *testing.T) {
+	t.Parallel()
+
+	provider := NewCacheProvider[string](3, time.Minute)
+	ctx := context.Background()
+	for _, key := range []string{"user:1", "item:1", "user:2"} {
+		if err := provider.Set(ctx, key, key+"-value", 0); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	var keys []string
+	err := provider.Scan(ctx, "user:*", func(key string, value string) bool {
+		if value != key+"-value" {
+			t.Errorf("unexpected value for %s: %q", key, value)
+		}
+		keys = append(keys, key)
+
+		return true
+	})
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "user:1" || keys[1] != "user:2" {
+		t.Fatalf("unexpected scanned keys: %v", keys)
+	}
+
+	var visited int
+	if err := provider.Scan(ctx, "", func(string, string) bool {
+		visited++
+
+		return false
+	}); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if visited != 1 {
+		t.Fatalf("expected scan to stop after first entry, got %d", visited)
+	}
+}
+
+func TestCacheProvider_SizeAccounting(t *testing.T) {
+	t.Parallel()
+
+	provider := NewCacheProvider[[]byte](2, time.Minute)
+	ctx := context.Background()
+
+	_ = provider.Set(ctx, "a", []byte("12345"), 0)
+	_ = provider.Set(ctx, "b", []byte("123"), 0)
+	if provider.Len() != 2 || provider.SizeBytes() != 10 {
+		t.Fatalf("unexpected size: %d entries, %d bytes", provider.Len(), provider.SizeBytes())
+	}
+	_ = provider.Set(ctx, "a", []byte("1"), 0)
+	if provider.SizeBytes() != 6 {
+		t.Fatalf("expected overwrite to replace size, got %d bytes", provider.SizeBytes())
+	}
+	// evicts "b" as the least recently used entry
+	_ = provider.Set(ctx, "c", []byte("12"), 0)
+	if provider.Len() != 2 || provider.SizeBytes() != 5 {
+		t.Fatalf("unexpected size after eviction: %d entries, %d bytes", provider.Len(), provider.SizeBytes())
+	}
+	_ = provider.Delete(ctx, "a")
+	if provider.Len() != 1 || provider.SizeBytes() != 3 {
+		t.Fatalf("unexpected size after delete: %d entries, %d bytes", provider.Len(), provider.SizeBytes())
+	}
+
+	custom := NewCacheProvider(2, time.Minute, WithSizeFunc(func(int) int64 { return 100 }))
+	_ = custom.Set(ctx, "k", 1, 0)
+	if custom.SizeBytes() != 101 {
+		t.Fatalf("expected custom size func to be used, got %d", custom.SizeBytes())
+	}
+}
+
+func TestCacheProvider_SizeAccountingOverwritesExpired(t *testing.T) {
+	t.Parallel()
+
+	provider := NewCacheProvider[[]byte](2, 50*time.Millisecond)
+	ctx := context.Background()
+
+	_ = provider.Set(ctx, "a", []byte("12345"), 0)
+	// overwrite once the entry has expired, usually before it is purged
+	time.Sleep(55 * time.Millisecond)
+	_ = provider.Set(ctx, "a", []byte("1"), 0)
+	if provider.SizeBytes() != 2 {
+		t.Fatalf("expected expired entry's size to be replaced, got %d bytes", provider.SizeBytes())
+	}
+}
+
+func TestCacheProvider_DeleteFunc(t *testing.T) {
+	t.Parallel()
+
+	provider := NewCacheProvider[[]byte](4, time.Minute)
+	ctx := context.Background()
+	for _, key := range []string{"user:1:a", "user:1:b", "user:2:a"} {
+		if err := provider.Set(ctx, key, []byte("v"), 0); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	n, err := provider.DeleteFunc(ctx, func(key string) bool { return strings.HasPrefix(key, "user:1:") })
+	if err != nil {
+		t.Fatalf("delete func: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 deleted entries, got %d", n)
+	}
+	if provider.Len() != 1 || provider.SizeBytes() != 9 {
+		t.Fatalf("unexpected size after delete: %d entries, %d bytes", provider.Len(), provider.SizeBytes())
+	}
+	if _, ok, _ := provider.Get(ctx, "user:2:a"); !ok {
+		t.Fatal("expected unmatched entry to remain")
+	}
+}
+
+func TestObjectCacheProvider_NewObjectCache(t *testing.T) {
+	t.Parallel()
+
+	provider := NewObjectCacheProvider[*string](2, time.Minute)
+	cache := crema.NewObjectCache[*string](provider)
+	ctx := context.Background()
+	value := "v"
+	if _, err := cache.GetOrLoad(ctx, "k", time.Minute, func(context.Context) (*string, error) { return &value, nil }); err != nil {
+		t.Fatalf("get or load: %v", err)
+	}
+	stored, ok, err := provider.Get(ctx, "k")
+	if err != nil || !ok || stored.Value != &value {
+		t.Fatalf("expected the object stored as is, got %+v, %v, %v", stored, ok, err)
+	}
+}
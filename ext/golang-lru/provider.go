@@ -2,23 +2,58 @@ package golanglru
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/abema/crema"
 	"github.com/hashicorp/golang-lru/v2/expirable"
 )
 
+// CacheProviderOption customizes the CacheProvider.
+type CacheProviderOption[S any] func(*CacheProvider[S])
+
 // CacheProvider stores cache entries in a hashicorp/golang-lru cache.
 type CacheProvider[S any] struct {
-	cache *expirable.LRU[string, S]
+	cache    *expirable.LRU[string, S]
+	sizeFunc crema.SizeFunc[S]
+	mu       sync.Mutex
+	bytes    atomic.Int64
 }
 
-var _ crema.CacheProvider[any] = (*CacheProvider[any])(nil)
+var (
+	_ crema.AdminProvider[any]      = (*CacheProvider[any])(nil)
+	_ crema.SizedProvider           = (*CacheProvider[any])(nil)
+	_ crema.DeleteFuncProvider[any] = (*CacheProvider[any])(nil)
+)
 
 // NewCacheProvider constructs a CacheProvider with the given max size and default TTL.
-func NewCacheProvider[S any](size int, defaultTTL time.Duration) *CacheProvider[S] {
-	return &CacheProvider[S]{
-		cache: expirable.NewLRU[string, S](size, nil, defaultTTL),
+func NewCacheProvider[S any](size int, defaultTTL time.Duration, opts ...CacheProviderOption[S]) *CacheProvider[S] {
+	provider := &CacheProvider[S]{sizeFunc: defaultSizeFunc[S]}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(provider)
+	}
+	provider.cache = expirable.NewLRU[string, S](size, provider.onEvict, defaultTTL)
+
+	return provider
+}
+
+// NewObjectCacheProvider constructs a CacheProvider that keeps cache objects
+// as they are. Use it with crema.NewObjectCache to skip the codec entirely.
+func NewObjectCacheProvider[V any](size int, defaultTTL time.Duration, opts ...CacheProviderOption[crema.CacheObject[V]]) *CacheProvider[crema.CacheObject[V]] {
+	return NewCacheProvider[crema.CacheObject[V]](size, defaultTTL, opts...)
+}
+
+// WithSizeFunc overrides how the approximate size of stored values is measured.
+// By default only []byte and string values are measured, by length.
+func WithSizeFunc[S any](sizeFunc crema.SizeFunc[S]) CacheProviderOption[S] {
+	return func(provider *CacheProvider[S]) {
+		if sizeFunc != nil {
+			provider.sizeFunc = sizeFunc
+		}
 	}
 }
 
@@ -36,14 +71,85 @@ func (c *CacheProvider[S]) Get(_ context.Context, key string) (S, bool, error) {
 
 // Set stores a value in the cache with the specified key.
 func (c *CacheProvider[S]) Set(_ context.Context, key string, value S, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// Remove drops the previous entry even when it has expired but is not
+	// purged yet, so onEvict subtracts its size; Add would replace it silently.
+	c.cache.Remove(key)
 	c.cache.Add(key, value)
+	c.bytes.Add(c.entrySize(key, value))
 
 	return nil
 }
 
 // Delete removes a value from the cache by key.
 func (c *CacheProvider[S]) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.cache.Remove(key)
 
 	return nil
 }
+
+// DeleteFunc removes the entries whose key fn reports true for and returns
+// how many were removed.
+func (c *CacheProvider[S]) DeleteFunc(_ context.Context, fn func(key string) bool) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, key := range c.cache.Keys() {
+		if fn(key) && c.cache.Remove(key) {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// Scan calls fn for each unexpired entry whose key matches pattern, from the
+// oldest to the newest entry.
+func (c *CacheProvider[S]) Scan(_ context.Context, pattern string, fn func(key string, value S) bool) error {
+	for _, key := range c.cache.Keys() {
+		if !crema.MatchKeyPattern(pattern, key) {
+			continue
+		}
+		value, ok := c.cache.Peek(key)
+		if !ok {
+			continue
+		}
+		if !fn(key, value) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of stored entries.
+func (c *CacheProvider[S]) Len() int {
+	return c.cache.Len()
+}
+
+// SizeBytes returns the approximate number of bytes held by keys and values.
+func (c *CacheProvider[S]) SizeBytes() int64 {
+	return c.bytes.Load()
+}
+
+func (c *CacheProvider[S]) onEvict(key string, value S) {
+	c.bytes.Add(-c.entrySize(key, value))
+}
+
+func (c *CacheProvider[S]) entrySize(key string, value S) int64 {
+	return c.sizeFunc(value) + int64(len(key))
+}
+
+func defaultSizeFunc[S any](value S) int64 {
+	switch v := any(value).(type) {
+	case []byte:
+		return int64(len(v))
+	case string:
+		return int64(len(v))
+	default:
+		return 0
+	}
+}
@@ -2,6 +2,8 @@ package gomemcache
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
 	"time"
 
@@ -9,16 +11,66 @@ import (
 	"github.com/bradfitz/gomemcache/memcache"
 )
 
+// FlagCompressed is the item flag bit that PHP's memcache extension and
+// similar clients set on zlib-compressed values.
+const FlagCompressed uint32 = 1 << 1
+
+// ErrUnmappedFlags is returned by Get when compression flags are enabled and
+// an item carries flags that match no configured compression type.
+var ErrUnmappedFlags = errors.New("gomemcache: item flags match no compression type")
+
 // MemcachedCacheProvider stores cache entries in Memcached.
 type MemcachedCacheProvider struct {
-	client memcacheClient
+	client           memcacheClient
+	compressionFlags map[byte]uint32
 }
 
 var _ crema.CacheProvider[[]byte] = (*MemcachedCacheProvider)(nil)
 
+// MemcachedCacheProviderOption customizes a MemcachedCacheProvider.
+type MemcachedCacheProviderOption func(*MemcachedCacheProvider)
+
 // NewMemcachedCacheProvider builds a Memcached-backed cache provider.
-func NewMemcachedCacheProvider(client memcacheClient) *MemcachedCacheProvider {
-	return &MemcachedCacheProvider{client: client}
+func NewMemcachedCacheProvider(client memcacheClient, opts ...MemcachedCacheProviderOption) *MemcachedCacheProvider {
+	provider := &MemcachedCacheProvider{client: client}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(provider)
+	}
+
+	return provider
+}
+
+// DefaultCompressionFlags maps crema's compression types to the flags used
+// by PHP's memcache extension: no flags for uncompressed values and
+// FlagCompressed for zlib.
+func DefaultCompressionFlags() map[byte]uint32 {
+	return map[byte]uint32{
+		crema.CompressionTypeIDNone: 0,
+		crema.CompressionTypeIDZlib: FlagCompressed,
+	}
+}
+
+// WithCompressionFlags moves the compression type byte written by
+// crema.NewBinaryCompressionCodec into the memcached item flags, so the stored
+// value is the bare, possibly compressed payload that clients relying on
+// flag-based compression can read. Get restores the type byte from the flags.
+// Without flags, DefaultCompressionFlags is used. Other clients see the inner
+// codec's encoding, including crema's expiry, after decompression.
+func WithCompressionFlags(flags map[byte]uint32) MemcachedCacheProviderOption {
+	if len(flags) == 0 {
+		flags = DefaultCompressionFlags()
+	}
+	mapped := make(map[byte]uint32, len(flags))
+	for typeID, flag := range flags {
+		mapped[typeID] = flag
+	}
+
+	return func(p *MemcachedCacheProvider) {
+		p.compressionFlags = mapped
+	}
 }
 
 // Get retrieves a cached value from Memcached.
@@ -34,6 +86,14 @@ func (p *MemcachedCacheProvider) Get(_ context.Context, key string) ([]byte, boo
 	if item == nil {
 		return nil, false, nil
 	}
+	if p.compressionFlags != nil {
+		value, err := p.unflag(key, item)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return value, true, nil
+	}
 
 	return item.Value, true, nil
 }
@@ -41,6 +101,11 @@ func (p *MemcachedCacheProvider) Get(_ context.Context, key string) ([]byte, boo
 // Set stores a cache entry in Memcached with the given TTL.
 func (p *MemcachedCacheProvider) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
 	item := &memcache.Item{Key: key, Value: value}
+	if p.compressionFlags != nil {
+		if err := p.flag(item); err != nil {
+			return err
+		}
+	}
 	if ttl > 0 {
 		item.Expiration = ttlSeconds(ttl)
 	}
@@ -57,6 +122,36 @@ func (p *MemcachedCacheProvider) Delete(_ context.Context, key string) error {
 	return nil
 }
 
+// flag moves the compression type byte of item.Value into item.Flags.
+func (p *MemcachedCacheProvider) flag(item *memcache.Item) error {
+	if len(item.Value) == 0 {
+		return crema.ErrDecompressZeroLengthData
+	}
+	flags, ok := p.compressionFlags[item.Value[0]]
+	if !ok {
+		return fmt.Errorf("%w: %d", crema.ErrUnsupportedCompressionTypeID, item.Value[0])
+	}
+	item.Flags = flags
+	item.Value = item.Value[1:]
+
+	return nil
+}
+
+// unflag restores the compression type byte of item from its flags.
+func (p *MemcachedCacheProvider) unflag(key string, item *memcache.Item) ([]byte, error) {
+	for typeID, flags := range p.compressionFlags {
+		if flags == item.Flags {
+			value := make([]byte, 1+len(item.Value))
+			value[0] = typeID
+			copy(value[1:], item.Value)
+
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q has flags %d", ErrUnmappedFlags, key, item.Flags)
+}
+
 type memcacheClient interface {
 	Get(key string) (*memcache.Item, error)
 	Set(item *memcache.Item) error
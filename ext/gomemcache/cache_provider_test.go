@@ -1,10 +1,16 @@
 package gomemcache
 
 import (
+	"bytes"
+	"compress/zlib"
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
+
+	"github.com/abema/crema"
+	"github.com/bradfitz/gomemcache/memcache"
 )
 
 func TestMemcachedCacheProvider_GetSetDelete(t *testing.T) {
@@ -116,6 +122,81 @@ func TestMemcachedCacheProvider_DeleteError(t *testing.T) {
 	}
 }
 
+func TestMemcachedCacheProvider_CompressionFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		threshold int
+		wantFlags uint32
+	}{
+		{name: "uncompressed", threshold: -1, wantFlags: 0},
+		{name: "zlib", threshold: 0, wantFlags: FlagCompressed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestMemcacheClient()
+			provider := NewMemcachedCacheProvider(client, WithCompressionFlags(nil))
+			codec := crema.NewBinaryCompressionCodec[string](crema.JSONByteStringCodec[string]{}, tt.threshold)
+			ctx := context.Background()
+
+			encoded, err := codec.Encode(crema.CacheObject[string]{Value: "value", ExpireAtMillis: 1})
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if err := provider.Set(ctx, "key", encoded, time.Minute); err != nil {
+				t.Fatalf("set: %v", err)
+			}
+
+			item, err := client.Get("key")
+			if err != nil {
+				t.Fatalf("raw get: %v", err)
+			}
+			if item.Flags != tt.wantFlags {
+				t.Fatalf("expected flags %d, got %d", tt.wantFlags, item.Flags)
+			}
+			payload := item.Value
+			if item.Flags&FlagCompressed != 0 {
+				reader, err := zlib.NewReader(bytes.NewReader(item.Value))
+				if err != nil {
+					t.Fatalf("zlib reader: %v", err)
+				}
+				if payload, err = io.ReadAll(reader); err != nil {
+					t.Fatalf("decompress: %v", err)
+				}
+			}
+			if !bytes.Contains(payload, []byte(`"value"`)) {
+				t.Fatalf("expected stored payload to hold the value, got %q", payload)
+			}
+
+			value, ok, err := provider.Get(ctx, "key")
+			if err != nil || !ok {
+				t.Fatalf("get: %v, %v", ok, err)
+			}
+			if !bytes.Equal(value, encoded) {
+				t.Fatalf("expected %q, got %q", encoded, value)
+			}
+		})
+	}
+}
+
+func TestMemcachedCacheProvider_CompressionFlagsUnmapped(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemcachedCacheProvider(&testMemcacheClient{
+		getItem: &memcache.Item{Key: "key", Value: []byte("value"), Flags: 1 << 8},
+	}, WithCompressionFlags(nil))
+
+	if _, _, err := provider.Get(context.Background(), "key"); !errors.Is(err, ErrUnmappedFlags) {
+		t.Fatalf("expected ErrUnmappedFlags, got %v", err)
+	}
+	if err := provider.Set(context.Background(), "key", []byte{0x7f, 'v'}, time.Minute); !errors.Is(err, crema.ErrUnsupportedCompressionTypeID) {
+		t.Fatalf("expected ErrUnsupportedCompressionTypeID, got %v", err)
+	}
+}
+
 func TestTTLSeconds_RoundsUpAndClamps(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -0,0 +1,135 @@
+package gomemcache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// fakeMemcached speaks enough of the memcached text protocol for a real
+// memcache.Client, so tests go through the client's own key validation.
+type fakeMemcached struct {
+	mu    sync.Mutex
+	items map[string]fakeMemcachedItem
+}
+
+type fakeMemcachedItem struct {
+	value     []byte
+	flags     uint32
+	expiresAt time.Time
+}
+
+// newFakeMemcachedClient starts a fake server and returns a client for it.
+func newFakeMemcachedClient(t *testing.T) *memcache.Client {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	server := &fakeMemcached{items: make(map[string]fakeMemcachedItem)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+
+	return memcache.New(listener.Addr().String())
+}
+
+func (s *fakeMemcached) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	for {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			fmt.Fprint(rw, "ERROR\r\n")
+		} else {
+			switch fields[0] {
+			case "gets", "get":
+				for _, key := range fields[1:] {
+					if item, ok := s.get(key); ok {
+						fmt.Fprintf(rw, "VALUE %s %d %d 0\r\n%s\r\n", key, item.flags, len(item.value), item.value)
+					}
+				}
+				fmt.Fprint(rw, "END\r\n")
+			case "set":
+				if len(fields) < 5 {
+					return
+				}
+				flags, _ := strconv.ParseUint(fields[2], 10, 32)
+				exptime, _ := strconv.Atoi(fields[3])
+				size, _ := strconv.Atoi(fields[4])
+				value := make([]byte, size+2)
+				if _, err := io.ReadFull(rw, value); err != nil {
+					return
+				}
+				s.set(fields[1], value[:size], uint32(flags), exptime)
+				fmt.Fprint(rw, "STORED\r\n")
+			case "delete":
+				if s.delete(fields[1]) {
+					fmt.Fprint(rw, "DELETED\r\n")
+				} else {
+					fmt.Fprint(rw, "NOT_FOUND\r\n")
+				}
+			default:
+				fmt.Fprint(rw, "ERROR\r\n")
+			}
+		}
+		if err := rw.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeMemcached) get(key string) (fakeMemcachedItem, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	if ok && !item.expiresAt.IsZero() && !time.Now().Before(item.expiresAt) {
+		delete(s.items, key)
+
+		return fakeMemcachedItem{}, false
+	}
+
+	return item, ok
+}
+
+func (s *fakeMemcached) set(key string, value []byte, flags uint32, exptime int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := fakeMemcachedItem{value: append([]byte(nil), value...), flags: flags}
+	if exptime > 0 {
+		item.expiresAt = time.Now().Add(time.Duration(exptime) * time.Second)
+	}
+	s.items[key] = item
+}
+
+func (s *fakeMemcached) delete(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.items[key]
+	delete(s.items, key)
+
+	return ok
+}
@@ -0,0 +1,86 @@
+package gomemcache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abema/crema"
+)
+
+func TestMemcachedCacheProvider_DistributedSingleflight(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemcachedCacheProvider(newFakeMemcachedClient(t))
+	wait := crema.PollingWaitStrategy{Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond}
+	leader := crema.NewCache(provider, crema.JSONByteStringCodec[int]{},
+		crema.WithDistributedSingleflight[int, []byte](time.Minute, wait))
+	follower := crema.NewCache(provider, crema.JSONByteStringCodec[int]{},
+		crema.WithDistributedSingleflight[int, []byte](time.Minute, wait))
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := leader.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 1, nil
+		})
+		leaderDone <- err
+	}()
+	<-started
+
+	var followerLoads atomic.Int32
+	followerDone := make(chan int, 1)
+	go func() {
+		v, err := follower.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+			followerLoads.Add(1)
+
+			return 2, nil
+		})
+		if err != nil {
+			t.Errorf("follower: %v", err)
+		}
+		followerDone <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if err := <-leaderDone; err != nil {
+		t.Fatalf("leader: %v", err)
+	}
+	if got := <-followerDone; got != 1 {
+		t.Fatalf("expected the leader value 1, got %d", got)
+	}
+	if got := followerLoads.Load(); got != 0 {
+		t.Fatalf("expected no follower loads, got %d", got)
+	}
+}
+
+func TestMemcachedCacheProvider_SoftDelete(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemcachedCacheProvider(newFakeMemcachedClient(t))
+	cache := crema.NewCache(provider, crema.JSONByteStringCodec[int]{},
+		crema.WithSoftDeleteFallback[int, []byte]())
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "key", crema.CacheObject[int]{Value: 1, ExpireAtMillis: time.Now().Add(time.Hour).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	ok, err := cache.SoftDelete(ctx, "key", 0)
+	if err != nil || !ok {
+		t.Fatalf("expected key to be soft-deleted, got %v, %v", ok, err)
+	}
+	got, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+		return 0, errors.New("boom")
+	})
+	if err != nil || got != 1 {
+		t.Fatalf("expected the previous value 1 on load failure, got %d, %v", got, err)
+	}
+}
@@ -17,6 +17,7 @@ type testMemcacheClient struct {
 
 type testMemcacheItem struct {
 	value     []byte
+	flags     uint32
 	expiresAt time.Time
 }
 
@@ -45,7 +46,7 @@ func (t *testMemcacheClient) Get(key string) (*memcache.Item, error) {
 		return nil, memcache.ErrCacheMiss
 	}
 
-	return &memcache.Item{Key: key, Value: append([]byte(nil), item.value...)}, nil
+	return &memcache.Item{Key: key, Value: append([]byte(nil), item.value...), Flags: item.flags}, nil
 }
 
 func (t *testMemcacheClient) Set(item *memcache.Item) error {
@@ -54,6 +55,7 @@ func (t *testMemcacheClient) Set(item *memcache.Item) error {
 
 	stored := testMemcacheItem{
 		value: append([]byte(nil), item.Value...),
+		flags: item.Flags,
 	}
 	if item.Expiration > 0 {
 		stored.expiresAt = time.Now().Add(time.Duration(item.Expiration) * time.Second)
@@ -0,0 +1,138 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/abema/crema"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Stater is implemented by crema.Cache and anything else exposing a
+// crema.CacheStats snapshot.
+type Stater interface {
+	Stats() crema.CacheStats
+}
+
+// StatsSource names a cache whose Stats snapshots are exported.
+type StatsSource struct {
+	// Name is reported as the cache label and must be unique per collector.
+	Name string
+	// Cache provides the snapshots.
+	Cache Stater
+}
+
+// NewStatsSource pairs a cache with the name used as its cache label.
+func NewStatsSource(name string, cache Stater) StatsSource {
+	return StatsSource{Name: name, Cache: cache}
+}
+
+// ErrDuplicateSource is returned when two sources share a name.
+var ErrDuplicateSource = errors.New("duplicate cache stats source")
+
+var (
+	entriesDesc = prometheus.NewDesc(
+		"crema_cache_entries",
+		"Number of entries held by the cache provider.",
+		[]string{"cache"}, nil,
+	)
+	bytesDesc = prometheus.NewDesc(
+		"crema_cache_bytes",
+		"Approximate number of bytes held by the cache provider.",
+		[]string{"cache"}, nil,
+	)
+	degradedDesc = prometheus.NewDesc(
+		"crema_cache_degraded",
+		"Whether the cache is degraded (1) or not (0).",
+		[]string{"cache"}, nil,
+	)
+	circuitStateDesc = prometheus.NewDesc(
+		"crema_cache_circuit_state",
+		"Provider circuit breaker state, 1 for the current state and 0 otherwise.",
+		[]string{"cache", "circuit", "state"}, nil,
+	)
+	experimentRolloutDesc = prometheus.NewDesc(
+		"crema_cache_experiment_rollout",
+		"Rollout fraction of each configured experimental behavior.",
+		[]string{"cache", "experiment"}, nil,
+	)
+)
+
+var circuitStates = []crema.CircuitState{crema.CircuitClosed, crema.CircuitOpen, crema.CircuitHalfOpen}
+
+// StatsCollector is a prometheus.Collector that takes a Stats snapshot of
+// every source on each scrape. Entry and byte gauges are skipped for sources
+// whose provider does not implement crema.SizedProvider; the degradation,
+// circuit breaker and experiment gauges are exported for every source.
+type StatsCollector struct {
+	sources []StatsSource
+}
+
+var _ prometheus.Collector = (*StatsCollector)(nil)
+
+// NewStatsCollector builds a collector for the given sources.
+func NewStatsCollector(sources ...StatsSource) (*StatsCollector, error) {
+	seen := make(map[string]struct{}, len(sources))
+	for _, source := range sources {
+		if source.Cache == nil {
+			return nil, fmt.Errorf("stats source %q has no cache", source.Name)
+		}
+		if _, ok := seen[source.Name]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateSource, source.Name)
+		}
+		seen[source.Name] = struct{}{}
+	}
+
+	return &StatsCollector{sources: sources}, nil
+}
+
+// Describe sends the descriptors of the exported metrics.
+func (c *StatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- entriesDesc
+	ch <- bytesDesc
+	ch <- degradedDesc
+	ch <- circuitStateDesc
+	ch <- experimentRolloutDesc
+}
+
+// Collect sends the current snapshot of every source.
+func (c *StatsCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, source := range c.sources {
+		stats := source.Cache.Stats()
+		if stats.Sized {
+			ch <- prometheus.MustNewConstMetric(entriesDesc, prometheus.GaugeValue, float64(stats.Entries), source.Name)
+			ch <- prometheus.MustNewConstMetric(bytesDesc, prometheus.GaugeValue, float64(stats.Bytes), source.Name)
+		}
+		ch <- prometheus.MustNewConstMetric(degradedDesc, prometheus.GaugeValue, boolValue(stats.Degraded), source.Name)
+		collectCircuit(ch, source.Name, "read", stats.ReadCircuit)
+		collectCircuit(ch, source.Name, "write", stats.WriteCircuit)
+		for experiment, rollout := range stats.Experiments {
+			ch <- prometheus.MustNewConstMetric(experimentRolloutDesc, prometheus.GaugeValue, rollout, source.Name, string(experiment))
+		}
+	}
+}
+
+// collectCircuit sends one state gauge per circuit breaker state.
+func collectCircuit(ch chan<- prometheus.Metric, name string, circuit string, current crema.CircuitState) {
+	for _, state := range circuitStates {
+		ch <- prometheus.MustNewConstMetric(circuitStateDesc, prometheus.GaugeValue, boolValue(state == current), name, circuit, state.String())
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// RegisterCollectors registers one StatsCollector for all caches with reg.
+func RegisterCollectors(reg prometheus.Registerer, caches ...StatsSource) error {
+	collector, err := NewStatsCollector(caches...)
+	if err != nil {
+		return err
+	}
+
+	return reg.Register(collector)
+}
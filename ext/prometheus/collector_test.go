@@ -0,0 +1,125 @@
+package prometheus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/abema/crema"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type staticStats crema.CacheStats
+
+func (s staticStats) Stats() crema.CacheStats {
+	return crema.CacheStats(s)
+}
+
+func TestRegisterCollectors_ExportsSizedSources(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewPedanticRegistry()
+	err := RegisterCollectors(reg,
+		NewStatsSource("users", staticStats{Sized: true, Entries: 3, Bytes: 120}),
+		NewStatsSource("items", staticStats{Sized: true, Entries: 5, Bytes: 400}),
+		NewStatsSource("remote", staticStats{}),
+	)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	got := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "crema_cache_entries" && family.GetName() != "crema_cache_bytes" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			got[family.GetName()+"/"+metric.GetLabel()[0].GetValue()] = metric.GetGauge().GetValue()
+		}
+	}
+	want := map[string]float64{
+		"crema_cache_entries/users": 3,
+		"crema_cache_bytes/users":   120,
+		"crema_cache_entries/items": 5,
+		"crema_cache_bytes/items":   400,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d samples, got %v", len(want), got)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Fatalf("expected %s=%v, got %v", key, value, got[key])
+		}
+	}
+}
+
+func TestRegisterCollectors_ExportsStateGauges(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewPedanticRegistry()
+	err := RegisterCollectors(reg, NewStatsSource("users", staticStats{
+		Degraded:     true,
+		ReadCircuit:  crema.CircuitOpen,
+		WriteCircuit: crema.CircuitClosed,
+		Experiments:  map[crema.Experiment]float64{crema.ExperimentAdaptiveTTL: 0.25},
+	}))
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	got := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := family.GetName()
+			for _, label := range metric.GetLabel() {
+				key += "/" + label.GetValue()
+			}
+			got[key] = metric.GetGauge().GetValue()
+		}
+	}
+	want := map[string]float64{
+		"crema_cache_degraded/users":                        1,
+		"crema_cache_circuit_state/users/read/closed":       0,
+		"crema_cache_circuit_state/users/read/open":         1,
+		"crema_cache_circuit_state/users/read/half-open":    0,
+		"crema_cache_circuit_state/users/write/closed":      1,
+		"crema_cache_circuit_state/users/write/open":        0,
+		"crema_cache_circuit_state/users/write/half-open":   0,
+		"crema_cache_experiment_rollout/users/adaptive_ttl": 0.25,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d samples, got %v", len(want), got)
+	}
+	for key, value := range want {
+		if v, ok := got[key]; !ok || v != value {
+			t.Fatalf("expected %s=%v, got %v", key, value, got)
+		}
+	}
+}
+
+func TestRegisterCollectors_RejectsDuplicateNames(t *testing.T) {
+	t.Parallel()
+
+	err := RegisterCollectors(prometheus.NewRegistry(),
+		NewStatsSource("users", staticStats{}),
+		NewStatsSource("users", staticStats{}),
+	)
+	if !errors.Is(err, ErrDuplicateSource) {
+		t.Fatalf("expected ErrDuplicateSource, got %v", err)
+	}
+}
+
+func TestNewStatsCollector_RejectsNilCache(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewStatsCollector(StatsSource{Name: "users"}); err == nil {
+		t.Fatal("expected error for nil cache")
+	}
+}
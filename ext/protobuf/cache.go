@@ -19,7 +19,8 @@ var ErrNilPrototype = errors.New("protobuf codec requires Prototype to construct
 
 // ProtobufCodec encodes/decodes crema.CacheObject values using protobuf.
 type ProtobufCodec[V proto.Message] struct {
-	Prototype V
+	Prototype  V
+	encryption *fieldEncryption
 }
 
 var (
@@ -35,17 +36,33 @@ var (
 // NewProtobufCodec creates a codec with a non-nil prototype message.
 // Pass a zero-value instance of the concrete protobuf message you will cache,
 // e.g. &mypb.MyMessage{}; it is used only for allocating new messages on decode.
-func NewProtobufCodec[V proto.Message](prototype V) (ProtobufCodec[V], error) {
+func NewProtobufCodec[V proto.Message](prototype V, opts ...CodecOption) (ProtobufCodec[V], error) {
 	if isNilPrototype(prototype) {
 		return ProtobufCodec[V]{}, ErrNilPrototype
 	}
+	var options codecOptions
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(&options)
+	}
 
-	return ProtobufCodec[V]{Prototype: prototype}, nil
+	return ProtobufCodec[V]{Prototype: prototype, encryption: options.encryption}, nil
 }
 
 // Encode marshals a cache object into the protobuf envelope format.
 func (p ProtobufCodec[V]) Encode(value crema.CacheObject[V]) ([]byte, error) {
-	serializedValue, err := proto.Marshal(value.Value)
+	var (
+		serializedValue []byte
+		encrypted       *encryptedFields
+		err             error
+	)
+	if p.encryption != nil {
+		serializedValue, encrypted, err = p.encryption.encrypt(value.Value)
+	} else {
+		serializedValue, err = proto.Marshal(value.Value)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +74,9 @@ func (p ProtobufCodec[V]) Encode(value crema.CacheObject[V]) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if encrypted != nil {
+		encoded = appendEncryptedFields(encoded, encrypted)
+	}
 
 	return encoded, nil
 }
@@ -72,6 +92,20 @@ func (p ProtobufCodec[V]) Decode(data []byte) (crema.CacheObject[V], error) {
 	if err := unmarshalOptions.Unmarshal(envelope.GetSerializedValue(), msg); err != nil {
 		return crema.CacheObject[V]{}, err
 	}
+	if unknown := envelope.ProtoReflect().GetUnknown(); len(unknown) > 0 {
+		encrypted, err := parseEncryptedFields(unknown)
+		if err != nil {
+			return crema.CacheObject[V]{}, err
+		}
+		if encrypted != nil {
+			if p.encryption == nil {
+				return crema.CacheObject[V]{}, ErrFieldEncryptionNotConfigured
+			}
+			if err := p.encryption.decrypt(msg, envelope.GetSerializedValue(), encrypted); err != nil {
+				return crema.CacheObject[V]{}, err
+			}
+		}
+	}
 
 	return crema.CacheObject[V]{
 		Value:          msg,
@@ -95,3 +129,10 @@ func isNilPrototype[V proto.Message](prototype V) bool {
 		return false
 	}
 }
+
+// MatchEnvelope reports whether data starts like an envelope written by
+// ProtobufCodec, for use as the crema.CodecFormat matcher of a
+// crema.MultiFormatCodec. The envelope starts with its version field.
+func MatchEnvelope(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x08 && data[1] == protoCacheEnvelopeVersion
+}
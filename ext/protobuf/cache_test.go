@@ -219,3 +219,24 @@ func TestIsNilPrototype_NonNilValue(t *testing.T) {
 		t.Fatal("isNilPrototype() = true, want false")
 	}
 }
+
+func TestMatchEnvelope(t *testing.T) {
+	t.Parallel()
+
+	codec, err := NewProtobufCodec(&testproto.ProtoTestObject{})
+	if err != nil {
+		t.Fatalf("new codec: %v", err)
+	}
+	value := &testproto.ProtoTestObject{}
+	value.SetValue(42)
+	encoded, err := codec.Encode(crema.CacheObject[*testproto.ProtoTestObject]{Value: value, ExpireAtMillis: 1})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if !MatchEnvelope(encoded) {
+		t.Fatalf("expected envelope to match, got %x", encoded)
+	}
+	if MatchEnvelope([]byte(`{"value":42}`)) {
+		t.Fatal("expected JSON not to match")
+	}
+}
@@ -0,0 +1,368 @@
+package protobuf
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Envelope fields carrying the encrypted sensitive fields. They are written
+// as raw fields next to the generated ProtoCacheObject fields, so envelopes
+// without encrypted fields keep their format.
+const (
+	encryptedFieldsNumber protowire.Number = 4
+	keyIDNumber           protowire.Number = 5
+	wrappedKeyNumber      protowire.Number = 6
+)
+
+var (
+	// ErrFieldEncryptionNotConfigured is returned by Decode for an envelope
+	// with encrypted fields when the codec has no KeyProvider.
+	ErrFieldEncryptionNotConfigured = errors.New("protobuf envelope has encrypted fields but the codec has no key provider")
+	// ErrInvalidDataKey is returned when a KeyProvider returns a key that is
+	// not 16, 24 or 32 bytes long.
+	ErrInvalidDataKey = errors.New("invalid protobuf field encryption data key")
+	// ErrMalformedEncryptedFields is returned by Decode when the encrypted
+	// fields of an envelope cannot be read.
+	ErrMalformedEncryptedFields = errors.New("malformed protobuf encrypted fields")
+)
+
+// DataKey is a symmetric key sensitive fields are encrypted with, in the
+// style of KMS envelope encryption.
+type DataKey struct {
+	// ID identifies the key and is stored in the envelope.
+	ID string
+	// Plaintext is the AES-128, AES-192 or AES-256 key used for AES-GCM.
+	// It is never stored.
+	Plaintext []byte
+	// Wrapped is the key encrypted by a key encryption key, stored in the
+	// envelope and passed back to UnwrapKey. It may be empty when keys are
+	// looked up by ID alone.
+	Wrapped []byte
+}
+
+// KeyProvider manages the data keys of field-level encryption, typically
+// backed by a KMS. Codecs call it without a request context and cache
+// unwrapped keys by ID, so CurrentKey should return a cached key and only
+// rotate it occasionally.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type KeyProvider interface {
+	// CurrentKey returns the data key new payloads are encrypted with.
+	CurrentKey(ctx context.Context) (DataKey, error)
+	// UnwrapKey returns the plaintext of the data key with id and wrapped
+	// form, as returned earlier by CurrentKey.
+	UnwrapKey(ctx context.Context, id string, wrapped []byte) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider holding plaintext keys in memory, for
+// tests and deployments that load keys from a secret store at startup.
+// Payloads are encrypted with the key CurrentID names; keeping retired keys
+// in Keys lets entries written with them still be decoded.
+type StaticKeyProvider struct {
+	CurrentID string
+	Keys      map[string][]byte
+}
+
+var _ KeyProvider = StaticKeyProvider{}
+
+// CurrentKey returns the key CurrentID names.
+func (s StaticKeyProvider) CurrentKey(context.Context) (DataKey, error) {
+	key, ok := s.Keys[s.CurrentID]
+	if !ok {
+		return DataKey{}, fmt.Errorf("%w: unknown key %q", ErrInvalidDataKey, s.CurrentID)
+	}
+
+	return DataKey{ID: s.CurrentID, Plaintext: key}, nil
+}
+
+// UnwrapKey returns the key named id.
+func (s StaticKeyProvider) UnwrapKey(_ context.Context, id string, _ []byte) ([]byte, error) {
+	key, ok := s.Keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidDataKey, id)
+	}
+
+	return key, nil
+}
+
+// CodecOption customizes a ProtobufCodec.
+type CodecOption func(*codecOptions)
+
+type codecOptions struct {
+	encryption *fieldEncryption
+}
+
+// WithFieldEncryption encrypts the sensitive fields of cached messages with
+// data keys from keys, while the rest of the message stays plain protobuf
+// that can be inspected in the backend for debugging. Fields are sensitive
+// when they are annotated with the standard [debug_redact = true] option or
+// named in fields by full name, such as "mypkg.User.email". Sensitive
+// fields may be nested in singular message fields; a repeated or map field
+// whose messages contain sensitive fields is encrypted as a whole.
+//
+// The sensitive fields are encrypted together with AES-GCM, authenticated
+// against the plain part of the message, and stored in the envelope with
+// the key ID and wrapped key. Messages without sensitive fields set are
+// stored unencrypted.
+func WithFieldEncryption(keys KeyProvider, fields ...protoreflect.FullName) CodecOption {
+	return func(o *codecOptions) {
+		if keys == nil {
+			o.encryption = nil
+
+			return
+		}
+		named := make(map[protoreflect.FullName]bool, len(fields))
+		for _, field := range fields {
+			named[field] = true
+		}
+		o.encryption = &fieldEncryption{keys: keys, named: named}
+	}
+}
+
+// fieldEncryption splits and encrypts the sensitive fields of messages.
+type fieldEncryption struct {
+	keys  KeyProvider
+	named map[protoreflect.FullName]bool
+	// aeads caches cipher.AEAD by data key ID.
+	aeads sync.Map
+	// containsSensitive caches whether a message type has sensitive fields,
+	// by full name.
+	containsSensitive sync.Map
+}
+
+// encryptedFields is the encrypted part of an envelope.
+type encryptedFields struct {
+	ciphertext []byte
+	keyID      string
+	wrappedKey []byte
+}
+
+func (e *fieldEncryption) sensitive(fd protoreflect.FieldDescriptor) bool {
+	if e.named[fd.FullName()] {
+		return true
+	}
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+
+	return ok && opts.GetDebugRedact()
+}
+
+// hasSensitive reports whether md has sensitive fields at any depth.
+func (e *fieldEncryption) hasSensitive(md protoreflect.MessageDescriptor) bool {
+	if cached, ok := e.containsSensitive.Load(md.FullName()); ok {
+		return cached.(bool)
+	}
+	found := e.findSensitive(md, make(map[protoreflect.FullName]bool))
+	e.containsSensitive.Store(md.FullName(), found)
+
+	return found
+}
+
+// findSensitive implements hasSensitive, skipping message types in visiting
+// to stop at recursive types.
+func (e *fieldEncryption) findSensitive(md protoreflect.MessageDescriptor, visiting map[protoreflect.FullName]bool) bool {
+	if visiting[md.FullName()] {
+		return false
+	}
+	visiting[md.FullName()] = true
+	fields := md.Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if e.sensitive(fd) || (fd.Message() != nil && e.findSensitive(fd.Message(), visiting)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// split clears the sensitive fields of public and everything but them of
+// secret, two copies of the same message. It reports whether secret holds
+// any field.
+func (e *fieldEncryption) split(public, secret protoreflect.Message) bool {
+	var fields []protoreflect.FieldDescriptor
+	public.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+
+		return true
+	})
+	hasSecret := false
+	for _, fd := range fields {
+		switch {
+		case e.sensitive(fd):
+			public.Clear(fd)
+			hasSecret = true
+		case fd.Message() == nil || !e.hasSensitive(fd.Message()):
+			secret.Clear(fd)
+		case fd.IsList() || fd.IsMap():
+			// merging on decode appends to lists, so they move as a whole
+			public.Clear(fd)
+			hasSecret = true
+		case e.split(public.Mutable(fd).Message(), secret.Mutable(fd).Message()):
+			hasSecret = true
+		default:
+			secret.Clear(fd)
+		}
+	}
+
+	return hasSecret
+}
+
+// encrypt splits msg into its marshaled plain part and the encrypted
+// sensitive fields, or nil ones when msg has none set.
+func (e *fieldEncryption) encrypt(msg proto.Message) ([]byte, *encryptedFields, error) {
+	if !e.hasSensitive(msg.ProtoReflect().Descriptor()) {
+		plain, err := proto.Marshal(msg)
+
+		return plain, nil, err
+	}
+	public := proto.Clone(msg)
+	secret := proto.Clone(msg)
+	if !e.split(public.ProtoReflect(), secret.ProtoReflect()) {
+		plain, err := proto.Marshal(msg)
+
+		return plain, nil, err
+	}
+	plain, err := proto.Marshal(public)
+	if err != nil {
+		return nil, nil, err
+	}
+	sensitive, err := proto.Marshal(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, err := e.keys.CurrentKey(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := e.aead(key.ID, key.Plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(sensitive)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+
+	return plain, &encryptedFields{
+		ciphertext: aead.Seal(nonce, nonce, sensitive, plain),
+		keyID:      key.ID,
+		wrappedKey: key.Wrapped,
+	}, nil
+}
+
+// decrypt merges the sensitive fields in enc into msg, which was
+// unmarshaled from plain.
+func (e *fieldEncryption) decrypt(msg proto.Message, plain []byte, enc *encryptedFields) error {
+	aead, err := e.cachedAEAD(enc.keyID, enc.wrappedKey)
+	if err != nil {
+		return err
+	}
+	if len(enc.ciphertext) < aead.NonceSize() {
+		return ErrMalformedEncryptedFields
+	}
+	nonce, ciphertext := enc.ciphertext[:aead.NonceSize()], enc.ciphertext[aead.NonceSize():]
+	sensitive, err := aead.Open(nil, nonce, ciphertext, plain)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMalformedEncryptedFields, err)
+	}
+
+	return proto.UnmarshalOptions{Merge: true}.Unmarshal(sensitive, msg)
+}
+
+// cachedAEAD returns the AEAD of the data key id, unwrapping it on first use.
+func (e *fieldEncryption) cachedAEAD(id string, wrapped []byte) (cipher.AEAD, error) {
+	if cached, ok := e.aeads.Load(id); ok {
+		return cached.(cipher.AEAD), nil
+	}
+	plaintext, err := e.keys.UnwrapKey(context.Background(), id, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.aead(id, plaintext)
+}
+
+// aead returns the AEAD of the data key id, caching it.
+func (e *fieldEncryption) aead(id string, plaintext []byte) (cipher.AEAD, error) {
+	if cached, ok := e.aeads.Load(id); ok {
+		return cached.(cipher.AEAD), nil
+	}
+	block, err := aes.NewCipher(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDataKey, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDataKey, err)
+	}
+	e.aeads.Store(id, aead)
+
+	return aead, nil
+}
+
+// appendEncryptedFields appends the raw envelope fields of enc to b.
+func appendEncryptedFields(b []byte, enc *encryptedFields) []byte {
+	b = protowire.AppendTag(b, encryptedFieldsNumber, protowire.BytesType)
+	b = protowire.AppendBytes(b, enc.ciphertext)
+	b = protowire.AppendTag(b, keyIDNumber, protowire.BytesType)
+	b = protowire.AppendString(b, enc.keyID)
+	if len(enc.wrappedKey) > 0 {
+		b = protowire.AppendTag(b, wrappedKeyNumber, protowire.BytesType)
+		b = protowire.AppendBytes(b, enc.wrappedKey)
+	}
+
+	return b
+}
+
+// parseEncryptedFields reads the encrypted fields from the unknown fields
+// of an envelope, returning nil when there are none.
+func parseEncryptedFields(unknown []byte) (*encryptedFields, error) {
+	var enc encryptedFields
+	found := false
+	for len(unknown) > 0 {
+		num, typ, n := protowire.ConsumeTag(unknown)
+		if n < 0 {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedEncryptedFields, protowire.ParseError(n))
+		}
+		unknown = unknown[n:]
+		if typ != protowire.BytesType || num < encryptedFieldsNumber || num > wrappedKeyNumber {
+			n = protowire.ConsumeFieldValue(num, typ, unknown)
+			if n < 0 {
+				return nil, fmt.Errorf("%w: %w", ErrMalformedEncryptedFields, protowire.ParseError(n))
+			}
+			unknown = unknown[n:]
+
+			continue
+		}
+		v, n := protowire.ConsumeBytes(unknown)
+		if n < 0 {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedEncryptedFields, protowire.ParseError(n))
+		}
+		unknown = unknown[n:]
+		switch num {
+		case encryptedFieldsNumber:
+			enc.ciphertext = bytes.Clone(v)
+			found = true
+		case keyIDNumber:
+			enc.keyID = string(v)
+		case wrappedKeyNumber:
+			enc.wrappedKey = bytes.Clone(v)
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return &enc, nil
+}
@@ -0,0 +1,132 @@
+package protobuf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/abema/crema"
+	internalproto "github.com/abema/crema/ext/protobuf/internal/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func testKeys() StaticKeyProvider {
+	return StaticKeyProvider{
+		CurrentID: "k1",
+		Keys:      map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)},
+	}
+}
+
+func sensitiveField() *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:         proto.String("visible"),
+		JsonName:     proto.String("secret-json-name"),
+		DefaultValue: proto.String("secret-default"),
+		Options:      &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)},
+	}
+}
+
+func TestProtobufCodec_FieldEncryptionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	codec, err := NewProtobufCodec(&descriptorpb.FieldDescriptorProto{}, WithFieldEncryption(testKeys(),
+		"google.protobuf.FieldDescriptorProto.json_name",
+		"google.protobuf.FieldDescriptorProto.default_value",
+		"google.protobuf.FieldOptions.deprecated",
+	))
+	if err != nil {
+		t.Fatalf("NewProtobufCodec() error = %v", err)
+	}
+	in := sensitiveField()
+
+	encoded, err := codec.Encode(crema.CacheObject[*descriptorpb.FieldDescriptorProto]{Value: in, ExpireAtMillis: 456})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if bytes.Contains(encoded, []byte("secret")) {
+		t.Fatalf("encoded envelope leaks sensitive fields: %q", encoded)
+	}
+	var envelope internalproto.ProtoCacheObject
+	if err := proto.Unmarshal(encoded, &envelope); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	var plain descriptorpb.FieldDescriptorProto
+	if err := proto.Unmarshal(envelope.GetSerializedValue(), &plain); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if plain.GetName() != "visible" || plain.JsonName != nil || plain.GetOptions().Deprecated != nil {
+		t.Fatalf("plain part = %v, want only non-sensitive fields", &plain)
+	}
+
+	out, err := codec.Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !proto.Equal(out.Value, in) {
+		t.Fatalf("decoded value = %v, want %v", out.Value, in)
+	}
+	if out.ExpireAtMillis != 456 {
+		t.Fatalf("decoded expiration = %d, want %d", out.ExpireAtMillis, 456)
+	}
+}
+
+func TestProtobufCodec_FieldEncryptionRequiresKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	encrypting, err := NewProtobufCodec(&descriptorpb.FieldDescriptorProto{}, WithFieldEncryption(testKeys(),
+		"google.protobuf.FieldDescriptorProto.json_name",
+	))
+	if err != nil {
+		t.Fatalf("NewProtobufCodec() error = %v", err)
+	}
+	encoded, err := encrypting.Encode(crema.CacheObject[*descriptorpb.FieldDescriptorProto]{Value: sensitiveField()})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	plain, err := NewProtobufCodec(&descriptorpb.FieldDescriptorProto{})
+	if err != nil {
+		t.Fatalf("NewProtobufCodec() error = %v", err)
+	}
+	if _, err := plain.Decode(encoded); !errors.Is(err, ErrFieldEncryptionNotConfigured) {
+		t.Fatalf("Decode() error = %v, want %v", err, ErrFieldEncryptionNotConfigured)
+	}
+
+	wrongKey, err := NewProtobufCodec(&descriptorpb.FieldDescriptorProto{}, WithFieldEncryption(StaticKeyProvider{
+		CurrentID: "k1",
+		Keys:      map[string][]byte{"k1": bytes.Repeat([]byte{2}, 32)},
+	}))
+	if err != nil {
+		t.Fatalf("NewProtobufCodec() error = %v", err)
+	}
+	if _, err := wrongKey.Decode(encoded); !errors.Is(err, ErrMalformedEncryptedFields) {
+		t.Fatalf("Decode() error = %v, want %v", err, ErrMalformedEncryptedFields)
+	}
+}
+
+func TestProtobufCodec_FieldEncryptionSkipsMessagesWithoutSensitiveFields(t *testing.T) {
+	t.Parallel()
+
+	codec, err := NewProtobufCodec(&internalproto.ProtoTestObject{}, WithFieldEncryption(StaticKeyProvider{}))
+	if err != nil {
+		t.Fatalf("NewProtobufCodec() error = %v", err)
+	}
+	value := &internalproto.ProtoTestObject{}
+	value.SetValue(123)
+
+	encoded, err := codec.Encode(crema.CacheObject[*internalproto.ProtoTestObject]{Value: value})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !MatchEnvelope(encoded) {
+		t.Fatal("MatchEnvelope() = false, want true")
+	}
+	out, err := codec.Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got := out.Value.GetValue(); got != 123 {
+		t.Fatalf("decoded value = %d, want %d", got, 123)
+	}
+}
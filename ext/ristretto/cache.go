@@ -3,6 +3,7 @@ package ristretto
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/abema/crema"
@@ -19,14 +20,24 @@ type CacheProviderOption[S any] func(*RistrettoCacheProvider[S])
 type RistrettoCacheProvider[S any] struct {
 	cache    *dgraphristretto.Cache
 	costFunc CostFunc[S]
+	keys     *sync.Map
 }
 
-// ErrUnexpectedCacheValueType indicates a non-matching value type stored in ristretto.
-var ErrUnexpectedCacheValueType = errors.New("ristretto cache returned unexpected value type")
+var (
+	// ErrUnexpectedCacheValueType indicates a non-matching value type stored in ristretto.
+	ErrUnexpectedCacheValueType = errors.New("ristretto cache returned unexpected value type")
+	// ErrKeyIndexDisabled is returned by DeleteFunc when the provider was
+	// built without WithKeyIndex.
+	ErrKeyIndexDisabled = errors.New("ristretto key index is disabled")
+)
 
 const defaultCost = int64(1)
 
-var _ crema.CacheProvider[any] = (*RistrettoCacheProvider[any])(nil)
+var (
+	_ crema.CacheProvider[any]      = (*RistrettoCacheProvider[any])(nil)
+	_ crema.SizedProvider           = (*RistrettoCacheProvider[any])(nil)
+	_ crema.DeleteFuncProvider[any] = (*RistrettoCacheProvider[any])(nil)
+)
 
 // NewRistrettoCacheProvider wraps an existing ristretto cache.
 func NewRistrettoCacheProvider[S any](cache *dgraphristretto.Cache, opts ...CacheProviderOption[S]) (*RistrettoCacheProvider[S], error) {
@@ -58,6 +69,16 @@ func WithCostFunc[S any](costFunc CostFunc[S]) CacheProviderOption[S] {
 	}
 }
 
+// WithKeyIndex keeps the set of written keys next to ristretto, which only
+// stores key hashes, so that DeleteFunc can enumerate them. Keys evicted by
+// ristretto stay in the index until they are deleted, so the index suits
+// bounded key sets such as development and small deployments.
+func WithKeyIndex[S any]() CacheProviderOption[S] {
+	return func(provider *RistrettoCacheProvider[S]) {
+		provider.keys = &sync.Map{}
+	}
+}
+
 // Get retrieves a value from the cache by key.
 func (r *RistrettoCacheProvider[S]) Get(_ context.Context, key string) (S, bool, error) {
 	value, ok := r.cache.Get(key)
@@ -82,6 +103,9 @@ func (r *RistrettoCacheProvider[S]) Set(_ context.Context, key string, value S,
 	if cost <= 0 {
 		cost = defaultCost
 	}
+	if r.keys != nil {
+		r.keys.Store(key, struct{}{})
+	}
 	if ok := r.cache.SetWithTTL(key, value, cost, ttl); !ok {
 		// rejected by TinyLFU algorithm, but not an error
 		return nil
@@ -93,6 +117,66 @@ func (r *RistrettoCacheProvider[S]) Set(_ context.Context, key string, value S,
 // Delete removes a value from the cache by key.
 func (r *RistrettoCacheProvider[S]) Delete(_ context.Context, key string) error {
 	r.cache.Del(key)
+	if r.keys != nil {
+		r.keys.Delete(key)
+	}
 
 	return nil
 }
+
+// DeleteFunc removes the entries whose key fn reports true for and returns
+// how many of them were still stored. It requires WithKeyIndex and otherwise
+// returns ErrKeyIndexDisabled.
+func (r *RistrettoCacheProvider[S]) DeleteFunc(_ context.Context, fn func(key string) bool) (int, error) {
+	if r.keys == nil {
+		return 0, ErrKeyIndexDisabled
+	}
+	n := 0
+	r.keys.Range(func(k, _ any) bool {
+		key := k.(string)
+		if !fn(key) {
+			return true
+		}
+		if _, ok := r.cache.Get(key); ok {
+			n++
+		}
+		r.cache.Del(key)
+		r.keys.Delete(key)
+
+		return true
+	})
+
+	return n, nil
+}
+
+// Len returns the approximate number of stored entries from ristretto metrics.
+// It returns 0 unless the cache was created with Config.Metrics enabled.
+func (r *RistrettoCacheProvider[S]) Len() int {
+	metrics := r.cache.Metrics
+	if metrics == nil {
+		return 0
+	}
+	added, evicted := metrics.KeysAdded(), metrics.KeysEvicted()
+	if evicted >= added {
+		return 0
+	}
+
+	return int(added - evicted)
+}
+
+// SizeBytes returns the total cost of stored entries from ristretto metrics,
+// which approximates bytes when the cost function measures bytes (see
+// WithCostFunc) and Config.IgnoreInternalCost is set. It returns 0 unless
+// Config.Metrics is enabled.
+func (r *RistrettoCacheProvider[S]) SizeBytes() int64 {
+	metrics := r.cache.Metrics
+	if metrics == nil {
+		return 0
+	}
+	added, evicted := metrics.CostAdded(), metrics.CostEvicted()
+	if evicted >= added {
+		return 0
+	}
+
+	return int64(added - evicted)
+}
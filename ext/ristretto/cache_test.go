@@ -2,6 +2,8 @@ package ristretto
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -205,3 +207,91 @@ func TestRistrettoCacheProvider_SetRejected(t *testing.T) {
 		t.Fatal("expected value to be rejected")
 	}
 }
+
+func TestRistrettoCacheProvider_Size(t *testing.T) {
+	t.Parallel()
+
+	cache, err := dgraphristretto.NewCache(&dgraphristretto.Config{
+		NumCounters:        1e4,
+		MaxCost:            1 << 20,
+		BufferItems:        64,
+		Metrics:            true,
+		IgnoreInternalCost: true,
+	})
+	if err != nil {
+		t.Fatalf("create cache: %v", err)
+	}
+	provider, err := NewRistrettoCacheProvider(cache, WithCostFunc(func(value []byte) int64 {
+		return int64(len(value))
+	}))
+	if err != nil {
+		t.Fatalf("create provider: %v", err)
+	}
+
+	ctx := context.Background()
+	_ = provider.Set(ctx, "a", []byte("12345"), 0)
+	_ = provider.Set(ctx, "b", []byte("123"), 0)
+	cache.Wait()
+
+	if provider.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", provider.Len())
+	}
+	if provider.SizeBytes() != 8 {
+		t.Fatalf("expected 8 bytes, got %d", provider.SizeBytes())
+	}
+}
+
+func TestRistrettoCacheProvider_SizeWithoutMetrics(t *testing.T) {
+	t.Parallel()
+
+	provider, err := NewRistrettoCacheProvider[[]byte](newTestCache(t))
+	if err != nil {
+		t.Fatalf("create provider: %v", err)
+	}
+	if provider.Len() != 0 || provider.SizeBytes() != 0 {
+		t.Fatalf("expected zero size without metrics, got %d entries, %d bytes", provider.Len(), provider.SizeBytes())
+	}
+}
+
+func TestRistrettoCacheProvider_DeleteFunc(t *testing.T) {
+	t.Parallel()
+
+	cache := newTestCache(t)
+	provider, err := NewRistrettoCacheProvider[[]byte](cache, WithKeyIndex[[]byte]())
+	if err != nil {
+		t.Fatalf("create provider: %v", err)
+	}
+	ctx := context.Background()
+	for _, key := range []string{"user:1:a", "user:1:b", "user:2:a"} {
+		if err := provider.Set(ctx, key, []byte("v"), 0); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+	cache.Wait()
+
+	n, err := provider.DeleteFunc(ctx, func(key string) bool { return strings.HasPrefix(key, "user:1:") })
+	if err != nil {
+		t.Fatalf("delete func: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 deleted entries, got %d", n)
+	}
+	if _, ok, _ := provider.Get(ctx, "user:1:a"); ok {
+		t.Fatal("expected matched entry to be deleted")
+	}
+	if _, ok, _ := provider.Get(ctx, "user:2:a"); !ok {
+		t.Fatal("expected unmatched entry to remain")
+	}
+}
+
+func TestRistrettoCacheProvider_DeleteFuncWithoutKeyIndex(t *testing.T) {
+	t.Parallel()
+
+	provider, err := NewRistrettoCacheProvider[[]byte](newTestCache(t))
+	if err != nil {
+		t.Fatalf("create provider: %v", err)
+	}
+	if _, err := provider.DeleteFunc(context.Background(), func(string) bool { return true }); !errors.Is(err, ErrKeyIndexDisabled) {
+		t.Fatalf("expected ErrKeyIndexDisabled, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package rueidis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/abema/crema"
@@ -11,14 +12,146 @@ import (
 
 // RedisCacheProvider stores cache entries in Redis using rueidis.
 type RedisCacheProvider struct {
-	client rueidis.Client
+	client            rueidis.Client
+	serverCompression ServerCompressionCommand
+	replication       writeReplication
 }
 
-var _ crema.CacheProvider[[]byte] = (*RedisCacheProvider)(nil)
+const defaultWriteReplicationTimeout = time.Second
+
+// ErrWriteNotReplicated is returned when fewer replicas than required
+// acknowledged a write before the WAIT timeout. The write itself was applied
+// on the primary and may still be lost on failover.
+var ErrWriteNotReplicated = errors.New("rueidis: write not acknowledged by enough replicas")
+
+// writeReplication is the number of replicas a write waits for.
+type writeReplication struct {
+	numReplicas int
+	timeout     time.Duration
+}
+
+type writeReplicationKey struct{}
+
+// RedisCacheProviderOption customizes the RedisCacheProvider.
+type RedisCacheProviderOption func(*RedisCacheProvider)
+
+// ServerCompressionCommand builds the command that writes a value tagged
+// crema.CompressionProviderManaged through a server-side compression module.
+// ttl is zero for entries without expiry. When ifAbsent is true the command
+// is issued by GetOrSet and must behave like SET NX GET: store value only when
+// key is absent and reply with the previously stored value, or nil.
+type ServerCompressionCommand func(b rueidis.Builder, key string, value []byte, ttl time.Duration, ifAbsent bool) rueidis.Completed
+
+var (
+	_ crema.TakeProvider[[]byte]     = (*RedisCacheProvider)(nil)
+	_ crema.GetOrSetProvider[[]byte] = (*RedisCacheProvider)(nil)
+)
 
 // NewRedisCacheProvider builds a Redis-backed cache provider.
-func NewRedisCacheProvider(client rueidis.Client) *RedisCacheProvider {
-	return &RedisCacheProvider{client: client}
+func NewRedisCacheProvider(client rueidis.Client, opts ...RedisCacheProviderOption) *RedisCacheProvider {
+	provider := &RedisCacheProvider{client: client}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(provider)
+	}
+
+	return provider
+}
+
+// WithServerSideCompression writes values tagged
+// crema.CompressionProviderManaged, as produced by a BinaryCompressionCodec
+// with crema.WithProviderManagedCompression, with the command built by cmd
+// instead of SET, so that a compression module deployed on the server
+// compresses them. The module must return the original payload, tag
+// included, on GET. Untagged values keep using SET.
+func WithServerSideCompression(cmd ServerCompressionCommand) RedisCacheProviderOption {
+	return func(provider *RedisCacheProvider) {
+		provider.serverCompression = cmd
+	}
+}
+
+// WithReplicatedWrites makes every write wait, with the WAIT command on the
+// same connection, until numReplicas replicas acknowledged it or timeout
+// passed, so entries whose loss on failover would cause incorrect results,
+// such as negative-cache tombstones, survive a primary failure. Writes
+// acknowledged by fewer replicas fail with ErrWriteNotReplicated. Each write
+// then takes a dedicated connection and a replication round trip, so prefer
+// WithWriteReplication to require it only for the calls that need it. A
+// non-positive timeout uses one second, since WAIT 0 blocks forever.
+func WithReplicatedWrites(numReplicas int, timeout time.Duration) RedisCacheProviderOption {
+	return func(provider *RedisCacheProvider) {
+		provider.replication = newWriteReplication(numReplicas, timeout)
+	}
+}
+
+// WithWriteReplication returns a context whose writes through a
+// RedisCacheProvider wait for numReplicas replicas as WithReplicatedWrites
+// describes, overriding the provider option for calls made with it. A
+// non-positive numReplicas turns waiting off for those calls.
+func WithWriteReplication(ctx context.Context, numReplicas int, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, writeReplicationKey{}, newWriteReplication(numReplicas, timeout))
+}
+
+func newWriteReplication(numReplicas int, timeout time.Duration) writeReplication {
+	if numReplicas <= 0 {
+		return writeReplication{}
+	}
+	if timeout <= 0 {
+		timeout = defaultWriteReplicationTimeout
+	}
+
+	return writeReplication{numReplicas: numReplicas, timeout: timeout}
+}
+
+// writeReplicationFor returns the replication required for writes made with
+// ctx.
+func (p *RedisCacheProvider) writeReplicationFor(ctx context.Context) writeReplication {
+	if replication, ok := ctx.Value(writeReplicationKey{}).(writeReplication); ok {
+		return replication
+	}
+
+	return p.replication
+}
+
+// write runs cmd and, when ctx requires replication, WAITs for it on the
+// same connection.
+func (p *RedisCacheProvider) write(ctx context.Context, cmd rueidis.Completed) (rueidis.RedisMessage, error) {
+	replication := p.writeReplicationFor(ctx)
+	if replication.numReplicas == 0 {
+		return p.client.Do(ctx, cmd).ToMessage()
+	}
+	var (
+		msg rueidis.RedisMessage
+		err error
+	)
+	dedicatedErr := p.client.Dedicated(func(c rueidis.DedicatedClient) error {
+		wait := c.B().Wait().Numreplicas(int64(replication.numReplicas)).Timeout(replication.timeout.Milliseconds()).Build()
+		results := c.DoMulti(ctx, cmd, wait)
+		msg, err = results[0].ToMessage()
+		if err != nil && !rueidis.IsRedisNil(err) {
+			return nil
+		}
+		acked, waitErr := results[1].AsInt64()
+		if waitErr != nil {
+			msg = rueidis.RedisMessage{}
+			err = fmt.Errorf("rueidis: wait for replicas: %w", waitErr)
+
+			return nil
+		}
+		if acked < int64(replication.numReplicas) {
+			msg = rueidis.RedisMessage{}
+			err = fmt.Errorf("%w: %d of %d replicas", ErrWriteNotReplicated, acked, replication.numReplicas)
+		}
+
+		return nil
+	})
+	if dedicatedErr != nil {
+		return rueidis.RedisMessage{}, dedicatedErr
+	}
+
+	return msg, err
 }
 
 // Get retrieves a cached value from Redis.
@@ -31,17 +164,59 @@ func (p *RedisCacheProvider) Get(ctx context.Context, key string) ([]byte, bool,
 
 // Set stores a cache entry in Redis with the given TTL.
 func (p *RedisCacheProvider) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := p.write(ctx, p.setCommand(key, value, ttl, false))
+
+	return err
+}
+
+// setCommand builds the command Set, or GetOrSet when ifAbsent is true,
+// writes value with.
+func (p *RedisCacheProvider) setCommand(key string, value []byte, ttl time.Duration, ifAbsent bool) rueidis.Completed {
+	if p.serverCompression != nil && len(value) > 0 && value[0] == crema.CompressionTypeIDProviderManaged {
+		return p.serverCompression(p.client.B(), key, value, max(ttl, 0), ifAbsent)
+	}
 	builder := p.client.B().Set().Key(key).Value(rueidis.BinaryString(value))
+	if ifAbsent {
+		nx := builder.Nx().Get()
+		if ttl > 0 {
+			return nx.Px(ttl).Build()
+		}
+
+		return nx.Build()
+	}
 	if ttl > 0 {
-		return p.client.Do(ctx, builder.Px(ttl).Build()).Error()
+		return builder.Px(ttl).Build()
 	}
 
-	return p.client.Do(ctx, builder.Build()).Error()
+	return builder.Build()
+}
+
+// GetOrSet stores value unless key exists, using SET NX GET, which requires
+// Redis 7.0 or newer. Values tagged crema.CompressionProviderManaged go through
+// the WithServerSideCompression command like in Set.
+func (p *RedisCacheProvider) GetOrSet(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	actual, loaded, err := parseRedisGetMessage(p.write(ctx, p.setCommand(key, value, ttl, true)))
+	if err != nil {
+		return nil, false, err
+	}
+	if !loaded {
+		return value, false, nil
+	}
+
+	return actual, true, nil
+}
+
+// Take retrieves and removes a cached value with GETDEL, which requires
+// Redis 6.2 or newer.
+func (p *RedisCacheProvider) Take(ctx context.Context, key string) ([]byte, bool, error) {
+	return parseRedisGetMessage(p.write(ctx, p.client.B().Getdel().Key(key).Build()))
 }
 
 // Delete removes a cached value from Redis.
 func (p *RedisCacheProvider) Delete(ctx context.Context, key string) error {
-	return p.client.Do(ctx, p.client.B().Del().Key(key).Build()).Error()
+	_, err := p.write(ctx, p.client.B().Del().Key(key).Build())
+
+	return err
 }
 
 func parseRedisGetMessage(msg rueidis.RedisMessage, err error) ([]byte, bool, error) {
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/abema/crema"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/rueidis"
 )
@@ -86,6 +87,56 @@ func TestRedisCacheProvider_GetWrongType(t *testing.T) {
 	}
 }
 
+func TestRedisCacheProvider_Take(t *testing.T) {
+	t.Parallel()
+
+	_, _, provider := newTestRedisProvider(t)
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	value, ok, err := provider.Take(ctx, "key")
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if !ok || string(value) != "value" {
+		t.Fatalf("unexpected take result: %q, %v", value, ok)
+	}
+
+	_, ok, err = provider.Take(ctx, "key")
+	if err != nil {
+		t.Fatalf("second take: %v", err)
+	}
+	if ok {
+		t.Fatal("expected value to be consumed")
+	}
+}
+
+func TestRedisCacheProvider_GetOrSet(t *testing.T) {
+	t.Parallel()
+
+	_, _, provider := newTestRedisProvider(t)
+	ctx := context.Background()
+
+	actual, loaded, err := provider.GetOrSet(ctx, "key", []byte("first"), time.Minute)
+	if err != nil {
+		t.Fatalf("first get or set: %v", err)
+	}
+	if loaded || string(actual) != "first" {
+		t.Fatalf("unexpected first result: %q, %v", actual, loaded)
+	}
+
+	actual, loaded, err = provider.GetOrSet(ctx, "key", []byte("second"), time.Minute)
+	if err != nil {
+		t.Fatalf("second get or set: %v", err)
+	}
+	if !loaded || string(actual) != "first" {
+		t.Fatalf("unexpected second result: %q, %v", actual, loaded)
+	}
+}
+
 func newTestRedisProvider(t *testing.T) (*miniredis.Miniredis, rueidis.Client, *RedisCacheProvider) {
 	t.Helper()
 
@@ -101,3 +152,85 @@ func newTestRedisProvider(t *testing.T) (*miniredis.Miniredis, rueidis.Client, *
 
 	return server, client, NewRedisCacheProvider(client)
 }
+
+func TestRedisCacheProvider_ServerSideCompression(t *testing.T) {
+	t.Parallel()
+
+	server, client, _ := newTestRedisProvider(t)
+	var tagged []string
+	provider := NewRedisCacheProvider(client, WithServerSideCompression(func(b rueidis.Builder, key string, value []byte, ttl time.Duration, ifAbsent bool) rueidis.Completed {
+		tagged = append(tagged, key)
+		if ifAbsent {
+			return b.Set().Key(key + ":compressed").Value(rueidis.BinaryString(value)).Nx().Get().Px(ttl).Build()
+		}
+
+		return b.Set().Key(key + ":compressed").Value(rueidis.BinaryString(value)).Px(ttl).Build()
+	}))
+	ctx := context.Background()
+
+	managed := append([]byte{crema.CompressionTypeIDProviderManaged}, "payload"...)
+	if err := provider.Set(ctx, "managed", managed, time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := provider.Set(ctx, "plain", append([]byte{crema.CompressionTypeIDNone}, "payload"...), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if len(tagged) != 1 || tagged[0] != "managed" {
+		t.Fatalf("expected only the tagged value to use the module command, got %v", tagged)
+	}
+	if got, err := server.Get("managed:compressed"); err != nil || got != string(managed) {
+		t.Fatalf("expected module command to store the value, got %q, %v", got, err)
+	}
+	if _, err := server.Get("plain"); err != nil {
+		t.Fatalf("expected untagged value to be stored with SET, got %v", err)
+	}
+
+	actual, loaded, err := provider.GetOrSet(ctx, "filled", managed, time.Minute)
+	if err != nil || loaded || string(actual) != string(managed) {
+		t.Fatalf("expected the tagged value to be stored, got %q, %v, %v", actual, loaded, err)
+	}
+	if got, err := server.Get("filled:compressed"); err != nil || got != string(managed) {
+		t.Fatalf("expected GetOrSet to use the module command, got %q, %v", got, err)
+	}
+	actual, loaded, err = provider.GetOrSet(ctx, "filled", append([]byte{crema.CompressionTypeIDProviderManaged}, "other"...), time.Minute)
+	if err != nil || !loaded || string(actual) != string(managed) {
+		t.Fatalf("expected the stored value to be kept, got %q, %v, %v", actual, loaded, err)
+	}
+	if len(tagged) != 3 {
+		t.Fatalf("expected GetOrSet calls to use the module command, got %v", tagged)
+	}
+}
+
+func TestRedisCacheProvider_WriteReplicationFor(t *testing.T) {
+	t.Parallel()
+
+	provider := NewRedisCacheProvider(nil, WithReplicatedWrites(2, 0))
+	ctx := context.Background()
+	if got := provider.writeReplicationFor(ctx); got.numReplicas != 2 || got.timeout != time.Second {
+		t.Fatalf("expected the provider default with a one second timeout, got %+v", got)
+	}
+	if got := provider.writeReplicationFor(WithWriteReplication(ctx, 1, 50*time.Millisecond)); got.numReplicas != 1 || got.timeout != 50*time.Millisecond {
+		t.Fatalf("expected the per-call replication, got %+v", got)
+	}
+	if got := provider.writeReplicationFor(WithWriteReplication(ctx, 0, time.Second)); got.numReplicas != 0 {
+		t.Fatalf("expected replication turned off for the call, got %+v", got)
+	}
+	if got := NewRedisCacheProvider(nil).writeReplicationFor(ctx); got.numReplicas != 0 {
+		t.Fatalf("expected no replication by default, got %+v", got)
+	}
+}
+
+func TestRedisCacheProvider_ReplicatedWriteWithoutReplicas(t *testing.T) {
+	t.Parallel()
+
+	_, _, provider := newTestRedisProvider(t)
+	ctx := context.Background()
+
+	if err := provider.Set(WithWriteReplication(ctx, 1, 10*time.Millisecond), "key", []byte("value"), time.Minute); err == nil {
+		t.Fatal("expected a write without replicas to fail")
+	}
+	value, ok, err := provider.Get(ctx, "key")
+	if err != nil || !ok || string(value) != "value" {
+		t.Fatalf("expected the write applied on the primary, got %q, %v, %v", value, ok, err)
+	}
+}
@@ -0,0 +1,136 @@
+package rueidis
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/abema/crema"
+	"github.com/redis/rueidis"
+)
+
+// RedisHashCacheProviderOption customizes the RedisHashCacheProvider.
+type RedisHashCacheProviderOption func(*RedisHashCacheProvider)
+
+// RedisHashCacheProvider stores cache entries as fields of a single Redis hash
+// per namespace, so that a feature's entries can be inspected or dropped together.
+// Field TTLs are applied with HPEXPIRE, which requires Redis 7.4 or newer,
+// once the server is known to support it.
+type RedisHashCacheProvider struct {
+	client          rueidis.Client
+	namespace       string
+	fieldExpiration atomic.Int32
+}
+
+// Field expiration support states.
+const (
+	fieldExpirationUnknown int32 = iota
+	fieldExpirationSupported
+	fieldExpirationUnsupported
+)
+
+var _ crema.CacheProvider[[]byte] = (*RedisHashCacheProvider)(nil)
+
+// NewRedisHashCacheProvider builds a Redis hash-backed cache provider that stores
+// entries in the hash named by namespace.
+func NewRedisHashCacheProvider(client rueidis.Client, namespace string, opts ...RedisHashCacheProviderOption) *RedisHashCacheProvider {
+	provider := &RedisHashCacheProvider{
+		client:    client,
+		namespace: namespace,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(provider)
+	}
+
+	return provider
+}
+
+// WithFieldExpiration applies field TTLs with HPEXPIRE without first probing
+// the server for support.
+func WithFieldExpiration() RedisHashCacheProviderOption {
+	return func(provider *RedisHashCacheProvider) {
+		provider.fieldExpiration.Store(fieldExpirationSupported)
+	}
+}
+
+// WithoutFieldExpiration disables HPEXPIRE for servers that do not support
+// per-field TTLs. Entries then stay in the hash until deleted, and expiry is
+// only enforced through CacheObject.ExpireAtMillis. Without either option,
+// the first Set with a ttl probes for HPTTL and field expiration stays off
+// when the server does not know it.
+func WithoutFieldExpiration() RedisHashCacheProviderOption {
+	return func(provider *RedisHashCacheProvider) {
+		provider.fieldExpiration.Store(fieldExpirationUnsupported)
+	}
+}
+
+// Namespace returns the Redis key of the hash holding the entries.
+func (p *RedisHashCacheProvider) Namespace() string {
+	return p.namespace
+}
+
+// Get retrieves a cached value from the namespace hash.
+func (p *RedisHashCacheProvider) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	result := p.client.Do(ctx, p.client.B().Hget().Key(p.namespace).Field(key).Build())
+	msg, err := result.ToMessage()
+
+	return parseRedisGetMessage(msg, err)
+}
+
+// Set stores a cache entry in the namespace hash with the given TTL.
+func (p *RedisHashCacheProvider) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	hset := p.client.B().Hset().Key(p.namespace).FieldValue().FieldValue(key, rueidis.BinaryString(value)).Build()
+	if ttl <= 0 {
+		return p.client.Do(ctx, hset).Error()
+	}
+	supported, err := p.supportsFieldExpiration(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return p.client.Do(ctx, hset).Error()
+	}
+	hpexpire := p.client.B().Hpexpire().Key(p.namespace).Milliseconds(max(ttl.Milliseconds(), 1)).
+		Fields().Numfields(1).Field(key).Build()
+	for _, result := range p.client.DoMulti(ctx, hset, hpexpire) {
+		if err := result.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// supportsFieldExpiration reports whether the server supports per-field TTLs,
+// probing it with a read-only HPTTL for key the first time. Only a definite
+// answer is remembered; a failed probe is returned and retried on the next
+// Set.
+func (p *RedisHashCacheProvider) supportsFieldExpiration(ctx context.Context, key string) (bool, error) {
+	switch p.fieldExpiration.Load() {
+	case fieldExpirationSupported:
+		return true, nil
+	case fieldExpirationUnsupported:
+		return false, nil
+	}
+	err := p.client.Do(ctx, p.client.B().Hpttl().Key(p.namespace).Fields().Numfields(1).Field(key).Build()).Error()
+	if redisErr, ok := rueidis.IsRedisErr(err); ok && strings.Contains(strings.ToLower(redisErr.Error()), "unknown command") {
+		p.fieldExpiration.Store(fieldExpirationUnsupported)
+
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	p.fieldExpiration.Store(fieldExpirationSupported)
+
+	return true, nil
+}
+
+// Delete removes a cached value from the namespace hash.
+func (p *RedisHashCacheProvider) Delete(ctx context.Context, key string) error {
+	return p.client.Do(ctx, p.client.B().Hdel().Key(p.namespace).Field(key).Build()).Error()
+}
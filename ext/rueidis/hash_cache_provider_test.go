@@ -0,0 +1,221 @@
+package rueidis
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/redis/rueidis"
+)
+
+func TestRedisHashCacheProvider_GetSetDelete(t *testing.T) {
+	t.Parallel()
+
+	server, _, provider := newTestRedisHashProvider(t, "feature")
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", []byte("value"), 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got := server.HGet("feature", "key"); got != "value" {
+		t.Fatalf("expected field in namespace hash, got %q", got)
+	}
+
+	value, ok, err := provider.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected value to exist")
+	}
+	if string(value) != "value" {
+		t.Fatalf("unexpected value: %q", value)
+	}
+
+	if err := provider.Delete(ctx, "key"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	_, ok, err = provider.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("get after delete: %v", err)
+	}
+	if ok {
+		t.Fatal("expected value to be deleted")
+	}
+}
+
+func TestRedisHashCacheProvider_FieldExpiration(t *testing.T) {
+	t.Parallel()
+
+	mini, client, _ := newTestRedisHashProvider(t, "feature")
+	expires := registerTestHpexpire(t, mini)
+	provider := NewRedisHashCacheProvider(client, "feature")
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", []byte("value"), 1500*time.Millisecond); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := provider.Set(ctx, "forever", []byte("value"), 0); err != nil {
+		t.Fatalf("set without ttl: %v", err)
+	}
+	if got := expires(); len(got) != 1 || got[0] != "feature key 1500" {
+		t.Fatalf("expected one HPEXPIRE for the field with a ttl, got %v", got)
+	}
+
+	mini.FastForward(3 * time.Second)
+	if _, ok, err := provider.Get(ctx, "key"); err != nil || ok {
+		t.Fatalf("expected the field to expire, got ok=%v err=%v", ok, err)
+	}
+	if _, ok, err := provider.Get(ctx, "forever"); err != nil || !ok {
+		t.Fatalf("expected the field without ttl to remain, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRedisHashCacheProvider_FieldExpirationUnsupported(t *testing.T) {
+	t.Parallel()
+
+	mini, client, _ := newTestRedisHashProvider(t, "feature")
+	provider := NewRedisHashCacheProvider(client, "feature")
+	ctx := context.Background()
+
+	for _, key := range []string{"first", "second"} {
+		if err := provider.Set(ctx, key, []byte("value"), time.Minute); err != nil {
+			t.Fatalf("expected set without HPEXPIRE support to succeed, got %v", err)
+		}
+		if got := mini.HGet("feature", key); got != "value" {
+			t.Fatalf("expected field in namespace hash, got %q", got)
+		}
+	}
+}
+
+func TestRedisHashCacheProvider_Namespaces(t *testing.T) {
+	t.Parallel()
+
+	server, client, first := newTestRedisHashProvider(t, "first")
+	second := NewRedisHashCacheProvider(client, "second", WithoutFieldExpiration())
+	ctx := context.Background()
+
+	if err := first.Set(ctx, "key", []byte("one"), 0); err != nil {
+		t.Fatalf("set first: %v", err)
+	}
+	if _, ok, err := second.Get(ctx, "key"); err != nil || ok {
+		t.Fatalf("expected miss in second namespace, got ok=%v err=%v", ok, err)
+	}
+	if second.Namespace() != "second" {
+		t.Fatalf("unexpected namespace: %q", second.Namespace())
+	}
+	if keys := server.Keys(); len(keys) != 1 || keys[0] != "first" {
+		t.Fatalf("expected only first hash to exist, got %v", keys)
+	}
+}
+
+func TestRedisHashCacheProvider_GetWrongType(t *testing.T) {
+	t.Parallel()
+
+	_, client, provider := newTestRedisHashProvider(t, "feature")
+	ctx := context.Background()
+	if err := client.Do(ctx, client.B().Set().Key("feature").Value("value").Build()).Error(); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	_, ok, err := provider.Get(ctx, "key")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if ok {
+		t.Fatal("expected ok to be false")
+	}
+}
+
+func newTestRedisHashProvider(t *testing.T, namespace string) (*miniredis.Miniredis, rueidis.Client, *RedisHashCacheProvider) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{server.Addr()},
+		DisableCache: true,
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return server, client, NewRedisHashCacheProvider(client, namespace, WithoutFieldExpiration())
+}
+
+// registerTestHpexpire adds HPEXPIRE and the HPTTL support probe, which
+// miniredis lacks, to mini. It records each HPEXPIRE as "key field
+// milliseconds" and applies the ttl rounded up to seconds with HEXPIRE through
+// a separate connection.
+func registerTestHpexpire(t *testing.T, mini *miniredis.Miniredis) func() []string {
+	t.Helper()
+
+	forward, err := rueidis.NewClient(rueidis.ClientOption{
+		InitAddress:  []string{mini.Addr()},
+		DisableCache: true,
+	})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+	t.Cleanup(func() { forward.Close() })
+
+	var (
+		mu      sync.Mutex
+		expires []string
+	)
+	err = mini.Server().Register("HPEXPIRE", func(c *server.Peer, _ string, args []string) {
+		// HPEXPIRE key milliseconds FIELDS numfields field
+		if len(args) != 5 {
+			c.WriteError("ERR wrong number of arguments for 'hpexpire' command")
+
+			return
+		}
+		millis, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			c.WriteError(err.Error())
+
+			return
+		}
+		mu.Lock()
+		expires = append(expires, args[0]+" "+args[4]+" "+args[1])
+		mu.Unlock()
+		seconds := strconv.FormatInt((millis+999)/1000, 10)
+		cmd := forward.B().Arbitrary("HEXPIRE").Keys(args[0]).Args(seconds, "FIELDS", "1", args[4]).Build()
+		replies, err := forward.Do(context.Background(), cmd).AsIntSlice()
+		if err != nil {
+			c.WriteError(err.Error())
+
+			return
+		}
+		c.WriteLen(len(replies))
+		for _, reply := range replies {
+			c.WriteInt(int(reply))
+		}
+	})
+	if err != nil {
+		t.Fatalf("register HPEXPIRE: %v", err)
+	}
+	err = mini.Server().Register("HPTTL", func(c *server.Peer, _ string, args []string) {
+		// HPTTL key FIELDS numfields field; report every field as missing
+		c.WriteLen(len(args) - 3)
+		for range args[3:] {
+			c.WriteInt(-2)
+		}
+	})
+	if err != nil {
+		t.Fatalf("register HPTTL: %v", err)
+	}
+
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return slices.Clone(expires)
+	}
+}
@@ -0,0 +1,252 @@
+package rueidis
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+const (
+	// IAMServiceElastiCache signs tokens for ElastiCache.
+	IAMServiceElastiCache = "elasticache"
+	// IAMServiceMemoryDB signs tokens for MemoryDB.
+	IAMServiceMemoryDB = "memorydb"
+
+	// maxIAMTokenTTL is the longest validity ElastiCache and MemoryDB accept.
+	maxIAMTokenTTL              = 15 * time.Minute
+	defaultIAMTokenRefreshAhead = time.Minute
+	defaultIAMTokenTimeout      = 10 * time.Second
+
+	awsSigningAlgorithm = "AWS4-HMAC-SHA256"
+	// emptyPayloadHash is the SHA-256 of an empty body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+var (
+	// ErrInvalidIAMAuthConfig is returned when an IAMAuthConfig misses a
+	// required field.
+	ErrInvalidIAMAuthConfig = errors.New("rueidis: invalid IAM auth config")
+	// ErrIAMAuthRequiresTLS is returned when IAM auth is configured without
+	// TLS, which ElastiCache and MemoryDB require for it.
+	ErrIAMAuthRequiresTLS = errors.New("rueidis: IAM auth requires TLS")
+)
+
+// AWSCredentials are the AWS credentials IAM auth tokens are signed with.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary credentials, such as those of an
+	// assumed role.
+	SessionToken string
+}
+
+// AWSCredentialsProvider returns the current AWS credentials. Wrap the
+// Retrieve method of an aws.CredentialsProvider from the AWS SDK to reuse
+// its credential chain and caching.
+type AWSCredentialsProvider func(ctx context.Context) (AWSCredentials, error)
+
+// IAMAuthConfig configures IAM authentication with ElastiCache or MemoryDB.
+type IAMAuthConfig struct {
+	// UserID is the ID of the IAM-enabled user, also used as the AUTH user
+	// name. Required.
+	UserID string
+	// CacheName is the replication group, serverless cache or MemoryDB
+	// cluster name, in lower case. Required.
+	CacheName string
+	// Region is the AWS region of the cache, such as "ap-northeast-1".
+	// Required.
+	Region string
+	// Service is IAMServiceElastiCache, the default, or IAMServiceMemoryDB.
+	Service string
+	// Serverless marks an ElastiCache serverless cache.
+	Serverless bool
+	// Credentials signs the tokens. Required.
+	Credentials AWSCredentialsProvider
+	// TokenTTL is how long a token stays valid, at most and by default 15
+	// minutes.
+	TokenTTL time.Duration
+	// RefreshAhead is how long before its expiry a token is replaced.
+	// Defaults to one minute.
+	RefreshAhead time.Duration
+}
+
+// IAMAuth generates IAM auth tokens for ElastiCache and MemoryDB and caches
+// them until shortly before they expire. Tokens are only checked when a
+// connection authenticates, so handing AuthCredentialsFn to rueidis lets
+// every new or reconnecting connection use a fresh token without recreating
+// the client. The zero value is not usable; construct it with NewIAMAuth.
+type IAMAuth struct {
+	cfg     IAMAuthConfig
+	now     func() time.Time
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewIAMAuth validates cfg and constructs an IAMAuth.
+func NewIAMAuth(cfg IAMAuthConfig) (*IAMAuth, error) {
+	switch {
+	case cfg.UserID == "":
+		return nil, fmt.Errorf("%w: UserID is required", ErrInvalidIAMAuthConfig)
+	case cfg.CacheName == "":
+		return nil, fmt.Errorf("%w: CacheName is required", ErrInvalidIAMAuthConfig)
+	case cfg.Region == "":
+		return nil, fmt.Errorf("%w: Region is required", ErrInvalidIAMAuthConfig)
+	case cfg.Credentials == nil:
+		return nil, fmt.Errorf("%w: Credentials is required", ErrInvalidIAMAuthConfig)
+	}
+	if cfg.Service == "" {
+		cfg.Service = IAMServiceElastiCache
+	}
+	if cfg.TokenTTL <= 0 || cfg.TokenTTL > maxIAMTokenTTL {
+		cfg.TokenTTL = maxIAMTokenTTL
+	}
+	if cfg.RefreshAhead <= 0 {
+		cfg.RefreshAhead = defaultIAMTokenRefreshAhead
+	}
+	cfg.RefreshAhead = min(cfg.RefreshAhead, cfg.TokenTTL/2)
+
+	return &IAMAuth{cfg: cfg, now: time.Now}, nil
+}
+
+// Token returns a cached token, generating a new one when none is cached or
+// the cached one expires within RefreshAhead.
+func (a *IAMAuth) Token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if a.token != "" && now.Before(a.expires.Add(-a.cfg.RefreshAhead)) {
+		return a.token, nil
+	}
+	creds, err := a.cfg.Credentials(ctx)
+	if err != nil {
+		return "", fmt.Errorf("rueidis: retrieve AWS credentials: %w", err)
+	}
+	a.token = a.presign(creds, now.UTC())
+	a.expires = now.Add(a.cfg.TokenTTL)
+
+	return a.token, nil
+}
+
+// AuthCredentialsFn returns a rueidis.ClientOption.AuthCredentialsFn
+// authenticating as UserID with a current token.
+func (a *IAMAuth) AuthCredentialsFn() func(rueidis.AuthCredentialsContext) (rueidis.AuthCredentials, error) {
+	return func(rueidis.AuthCredentialsContext) (rueidis.AuthCredentials, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultIAMTokenTimeout)
+		defer cancel()
+		token, err := a.Token(ctx)
+		if err != nil {
+			return rueidis.AuthCredentials{}, err
+		}
+
+		return rueidis.AuthCredentials{Username: a.cfg.UserID, Password: token}, nil
+	}
+}
+
+// presign builds a token: a SigV4 presigned "connect" request for the cache
+// without its scheme.
+func (a *IAMAuth) presign(creds AWSCredentials, now time.Time) string {
+	date := now.Format("20060102")
+	amzDate := now.Format("20060102T150405Z")
+	scope := date + "/" + a.cfg.Region + "/" + a.cfg.Service + "/aws4_request"
+	query := map[string]string{
+		"Action":              "connect",
+		"User":                a.cfg.UserID,
+		"X-Amz-Algorithm":     awsSigningAlgorithm,
+		"X-Amz-Credential":    creds.AccessKeyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(a.cfg.TokenTTL / time.Second)),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if a.cfg.Serverless {
+		query["ResourceType"] = "ServerlessCache"
+	}
+	if creds.SessionToken != "" {
+		query["X-Amz-Security-Token"] = creds.SessionToken
+	}
+	canonicalQuery := canonicalAWSQuery(query)
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		"/",
+		canonicalQuery,
+		"host:" + a.cfg.CacheName + "\n",
+		"host",
+		emptyPayloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{awsSigningAlgorithm, amzDate, scope, sha256Hex(canonicalRequest)}, "\n")
+	key := awsSigningKey(creds.SecretAccessKey, date, a.cfg.Region, a.cfg.Service)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return a.cfg.CacheName + "/?" + canonicalQuery + "&X-Amz-Signature=" + signature
+}
+
+// awsSigningKey derives the SigV4 signing key.
+func awsSigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+
+	return hmacSHA256(key, "aws4_request")
+}
+
+// canonicalAWSQuery encodes query sorted by name, as SigV4 requires.
+func canonicalAWSQuery(query map[string]string) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(awsURIEncode(name))
+		b.WriteByte('=')
+		b.WriteString(awsURIEncode(query[name]))
+	}
+
+	return b.String()
+}
+
+// awsURIEncode percent-encodes every byte except unreserved characters.
+func awsURIEncode(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&0xf])
+	}
+
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,77 @@
+package rueidis
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRedisRecencyTracker_Ordering(t *testing.T) {
+	t.Parallel()
+
+	_, client, _ := newTestRedisProvider(t)
+	tracker := NewRedisRecencyTracker(client, "recency")
+	ctx := context.Background()
+
+	for i, key := range []string{"a", "b", "c"} {
+		if err := tracker.Touch(ctx, key, time.UnixMilli(int64(1000+i))); err != nil {
+			t.Fatalf("touch %s: %v", key, err)
+		}
+	}
+	if err := tracker.Touch(ctx, "c", time.UnixMilli(1)); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+
+	lru, err := tracker.LeastRecentlyUsed(ctx, 2)
+	if err != nil {
+		t.Fatalf("lru: %v", err)
+	}
+	if !slices.Equal(lru, []string{"a", "b"}) {
+		t.Fatalf("unexpected lru keys: %v", lru)
+	}
+	mru, err := tracker.MostRecentlyUsed(ctx, 5)
+	if err != nil {
+		t.Fatalf("mru: %v", err)
+	}
+	if !slices.Equal(mru, []string{"c", "b", "a"}) {
+		t.Fatalf("unexpected mru keys: %v", mru)
+	}
+
+	if err := tracker.Remove(ctx, "b"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	mru, err = tracker.MostRecentlyUsed(ctx, 5)
+	if err != nil {
+		t.Fatalf("mru after remove: %v", err)
+	}
+	if !slices.Equal(mru, []string{"c", "a"}) {
+		t.Fatalf("unexpected mru keys after remove: %v", mru)
+	}
+}
+
+func TestRedisRecencyTracker_Trims(t *testing.T) {
+	t.Parallel()
+
+	_, client, _ := newTestRedisProvider(t)
+	tracker := NewRedisRecencyTracker(client, "recency", WithRecencyMaxKeys(3), WithRecencyRetention(time.Minute))
+	ctx := context.Background()
+
+	if err := tracker.Touch(ctx, "expired", time.UnixMilli(0)); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+	start := time.UnixMilli(0).Add(time.Hour)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		if err := tracker.Touch(ctx, key, start.Add(time.Duration(i)*time.Millisecond)); err != nil {
+			t.Fatalf("touch %s: %v", key, err)
+		}
+	}
+
+	mru, err := tracker.MostRecentlyUsed(ctx, 10)
+	if err != nil {
+		t.Fatalf("mru: %v", err)
+	}
+	if !slices.Equal(mru, []string{"d", "c", "b"}) {
+		t.Fatalf("expected expired and least recent keys trimmed, got %v", mru)
+	}
+}
@@ -0,0 +1,14 @@
+package rueidis
+
+import (
+	"github.com/abema/crema"
+	"github.com/redis/rueidis"
+)
+
+// NewRedisReplicationHook builds a crema.ReplicationHook that forwards cache
+// writes and deletes to the Redis server behind client, such as a cache in a
+// secondary region. Use it with crema.WithReplicationHook, which runs the hook
+// asynchronously after each write.
+func NewRedisReplicationHook(client rueidis.Client) *crema.ProviderReplicationHook[[]byte] {
+	return crema.NewProviderReplicationHook[[]byte](NewRedisCacheProvider(client))
+}
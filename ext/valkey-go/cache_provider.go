@@ -14,7 +14,10 @@ type ValkeyCacheProvider struct {
 	client valkey.Client
 }
 
-var _ crema.CacheProvider[[]byte] = (*ValkeyCacheProvider)(nil)
+var (
+	_ crema.TakeProvider[[]byte]     = (*ValkeyCacheProvider)(nil)
+	_ crema.GetOrSetProvider[[]byte] = (*ValkeyCacheProvider)(nil)
+)
 
 // NewValkeyCacheProvider builds a Valkey-backed cache provider.
 func NewValkeyCacheProvider(client valkey.Client) *ValkeyCacheProvider {
@@ -39,6 +42,36 @@ func (p *ValkeyCacheProvider) Set(ctx context.Context, key string, value []byte,
 	return p.client.Do(ctx, builder.Build()).Error()
 }
 
+// GetOrSet stores value unless key exists, using SET NX GET, which requires
+// Valkey 7.2 or newer.
+func (p *ValkeyCacheProvider) GetOrSet(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	builder := p.client.B().Set().Key(key).Value(valkey.BinaryString(value)).Nx().Get()
+	var cmd valkey.Completed
+	if ttl > 0 {
+		cmd = builder.Px(ttl).Build()
+	} else {
+		cmd = builder.Build()
+	}
+	msg, err := p.client.Do(ctx, cmd).ToMessage()
+	actual, loaded, err := parseValkeyGetMessage(msg, err)
+	if err != nil {
+		return nil, false, err
+	}
+	if !loaded {
+		return value, false, nil
+	}
+
+	return actual, true, nil
+}
+
+// Take retrieves and removes a cached value with GETDEL.
+func (p *ValkeyCacheProvider) Take(ctx context.Context, key string) ([]byte, bool, error) {
+	result := p.client.Do(ctx, p.client.B().Getdel().Key(key).Build())
+	msg, err := result.ToMessage()
+
+	return parseValkeyGetMessage(msg, err)
+}
+
 // Delete removes a cached value from Valkey.
 func (p *ValkeyCacheProvider) Delete(ctx context.Context, key string) error {
 	return p.client.Do(ctx, p.client.B().Del().Key(key).Build()).Error()
@@ -86,6 +86,56 @@ func TestValkeyCacheProvider_GetWrongType(t *testing.T) {
 	}
 }
 
+func TestValkeyCacheProvider_Take(t *testing.T) {
+	t.Parallel()
+
+	_, _, provider := newTestValkeyProvider(t)
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	value, ok, err := provider.Take(ctx, "key")
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if !ok || string(value) != "value" {
+		t.Fatalf("unexpected take result: %q, %v", value, ok)
+	}
+
+	_, ok, err = provider.Take(ctx, "key")
+	if err != nil {
+		t.Fatalf("second take: %v", err)
+	}
+	if ok {
+		t.Fatal("expected value to be consumed")
+	}
+}
+
+func TestValkeyCacheProvider_GetOrSet(t *testing.T) {
+	t.Parallel()
+
+	_, _, provider := newTestValkeyProvider(t)
+	ctx := context.Background()
+
+	actual, loaded, err := provider.GetOrSet(ctx, "key", []byte("first"), time.Minute)
+	if err != nil {
+		t.Fatalf("first get or set: %v", err)
+	}
+	if loaded || string(actual) != "first" {
+		t.Fatalf("unexpected first result: %q, %v", actual, loaded)
+	}
+
+	actual, loaded, err = provider.GetOrSet(ctx, "key", []byte("second"), time.Minute)
+	if err != nil {
+		t.Fatalf("second get or set: %v", err)
+	}
+	if !loaded || string(actual) != "first" {
+		t.Fatalf("unexpected second result: %q, %v", actual, loaded)
+	}
+}
+
 func newTestValkeyProvider(t *testing.T) (*miniredis.Miniredis, valkey.Client, *ValkeyCacheProvider) {
 	t.Helper()
 
@@ -0,0 +1,86 @@
+package valkeygo
+
+import (
+	"crypto/tls"
+	"errors"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+// ErrNoAddresses is returned when a Config has no server addresses.
+var ErrNoAddresses = errors.New("valkeygo: config requires at least one address")
+
+// Config holds the common connection settings of a Valkey client, so most
+// users never need to fill valkey.ClientOption themselves.
+type Config struct {
+	// Addresses are the host:port pairs to connect to. Several addresses
+	// connect to a cluster.
+	Addresses []string
+	// Username and Password authenticate with AUTH; both are optional.
+	Username string
+	Password string
+	// ClientName is sent with CLIENT SETNAME to identify the connection.
+	ClientName string
+	// DB selects the database number.
+	DB int
+	// TLS enables TLS with a default configuration requiring TLS 1.2 when
+	// TLSConfig is nil.
+	TLS bool
+	// TLSConfig overrides the TLS configuration and enables TLS.
+	TLSConfig *tls.Config
+	// DialTimeout bounds establishing a connection.
+	DialTimeout time.Duration
+	// WriteTimeout bounds writing a command and reading its reply.
+	WriteTimeout time.Duration
+	// DisableClientCache turns off server-assisted client-side caching, which
+	// some managed services and proxies do not support.
+	DisableClientCache bool
+}
+
+// ClientOption converts cfg into valkey-go client options.
+func (cfg Config) ClientOption() (valkey.ClientOption, error) {
+	if len(cfg.Addresses) == 0 {
+		return valkey.ClientOption{}, ErrNoAddresses
+	}
+	opt := valkey.ClientOption{
+		InitAddress:      append([]string(nil), cfg.Addresses...),
+		Username:         cfg.Username,
+		Password:         cfg.Password,
+		ClientName:       cfg.ClientName,
+		SelectDB:         cfg.DB,
+		ConnWriteTimeout: cfg.WriteTimeout,
+		DisableCache:     cfg.DisableClientCache,
+	}
+	opt.Dialer.Timeout = cfg.DialTimeout
+	switch {
+	case cfg.TLSConfig != nil:
+		opt.TLSConfig = cfg.TLSConfig.Clone()
+	case cfg.TLS:
+		opt.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	return opt, nil
+}
+
+// NewClient builds a valkey-go client from cfg.
+func (cfg Config) NewClient() (valkey.Client, error) {
+	opt, err := cfg.ClientOption()
+	if err != nil {
+		return nil, err
+	}
+
+	return valkey.NewClient(opt)
+}
+
+// NewValkeyCacheProviderFromConfig builds a client from cfg and a
+// ValkeyCacheProvider using it. The caller owns the returned client and must
+// close it when done.
+func NewValkeyCacheProviderFromConfig(cfg Config) (*ValkeyCacheProvider, valkey.Client, error) {
+	client, err := cfg.NewClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewValkeyCacheProvider(client), client, nil
+}
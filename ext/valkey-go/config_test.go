@@ -0,0 +1,71 @@
+package valkeygo
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestConfig_ClientOption(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		Addresses:    []string{"127.0.0.1:6379"},
+		Username:     "app",
+		Password:     "secret",
+		ClientName:   "crema",
+		DB:           2,
+		TLS:          true,
+		DialTimeout:  time.Second,
+		WriteTimeout: 2 * time.Second,
+	}
+	opt, err := cfg.ClientOption()
+	if err != nil {
+		t.Fatalf("client option: %v", err)
+	}
+	if opt.InitAddress[0] != "127.0.0.1:6379" || opt.Username != "app" || opt.Password != "secret" || opt.ClientName != "crema" || opt.SelectDB != 2 {
+		t.Fatalf("unexpected client option: %+v", opt)
+	}
+	if opt.TLSConfig == nil || opt.TLSConfig.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("expected default TLS config, got %+v", opt.TLSConfig)
+	}
+	if opt.Dialer.Timeout != time.Second || opt.ConnWriteTimeout != 2*time.Second {
+		t.Fatalf("unexpected timeouts: %v, %v", opt.Dialer.Timeout, opt.ConnWriteTimeout)
+	}
+
+	custom := &tls.Config{ServerName: "cache.internal"}
+	cfg.TLSConfig = custom
+	if opt, err = cfg.ClientOption(); err != nil || opt.TLSConfig.ServerName != "cache.internal" || opt.TLSConfig == custom {
+		t.Fatalf("expected a copy of the custom TLS config, got %+v, %v", opt.TLSConfig, err)
+	}
+
+	if _, err := (Config{}).ClientOption(); !errors.Is(err, ErrNoAddresses) {
+		t.Fatalf("expected ErrNoAddresses, got %v", err)
+	}
+}
+
+func TestNewValkeyCacheProviderFromConfig(t *testing.T) {
+	t.Parallel()
+
+	server := miniredis.RunT(t)
+	provider, client, err := NewValkeyCacheProviderFromConfig(Config{
+		Addresses:          []string{server.Addr()},
+		DisableClientCache: true,
+	})
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	ctx := context.Background()
+	if err := provider.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got, err := server.Get("key"); err != nil || got != "value" {
+		t.Fatalf("expected value in valkey, got %q, %v", got, err)
+	}
+}
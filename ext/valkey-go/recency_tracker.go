@@ -0,0 +1,102 @@
+package valkeygo
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/abema/crema"
+	"github.com/valkey-io/valkey-go"
+)
+
+// ValkeyRecencyTracker records key accesses in a Valkey sorted set scored by
+// access time in milliseconds, so recency is shared across processes. The set
+// is trimmed on every touch to a key cap and an optional retention period.
+type ValkeyRecencyTracker struct {
+	client    valkey.Client
+	key       string
+	maxKeys   int
+	retention time.Duration
+}
+
+var _ crema.RecencyTracker = (*ValkeyRecencyTracker)(nil)
+
+// ValkeyRecencyTrackerOption customizes a ValkeyRecencyTracker.
+type ValkeyRecencyTrackerOption func(*ValkeyRecencyTracker)
+
+// WithRecencyMaxKeys caps the keys the sorted set keeps, dropping the least
+// recently used ones beyond it. Non-positive values remove the cap.
+func WithRecencyMaxKeys(n int) ValkeyRecencyTrackerOption {
+	return func(r *ValkeyRecencyTracker) {
+		r.maxKeys = max(n, 0)
+	}
+}
+
+// WithRecencyRetention drops keys last touched more than retention before a
+// touch, such as keys whose entries have outlived the cache TTL.
+func WithRecencyRetention(retention time.Duration) ValkeyRecencyTrackerOption {
+	return func(r *ValkeyRecencyTracker) {
+		r.retention = max(retention, 0)
+	}
+}
+
+// NewValkeyRecencyTracker builds a recency tracker backed by the sorted set at
+// key, keeping up to crema.DefaultMaxRecencyKeys keys unless overridden by opts.
+func NewValkeyRecencyTracker(client valkey.Client, key string, opts ...ValkeyRecencyTrackerOption) *ValkeyRecencyTracker {
+	tracker := &ValkeyRecencyTracker{client: client, key: key, maxKeys: crema.DefaultMaxRecencyKeys}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(tracker)
+	}
+
+	return tracker
+}
+
+// Touch records an access to key, never moving it backwards in time, and
+// trims the set in the same round trip.
+func (r *ValkeyRecencyTracker) Touch(ctx context.Context, key string, at time.Time) error {
+	cmds := valkey.Commands{
+		r.client.B().Zadd().Key(r.key).Gt().ScoreMember().ScoreMember(float64(at.UnixMilli()), key).Build(),
+	}
+	if r.retention > 0 {
+		cutoff := strconv.FormatInt(at.Add(-r.retention).UnixMilli(), 10)
+		cmds = append(cmds, r.client.B().Zremrangebyscore().Key(r.key).Min("-inf").Max("("+cutoff).Build())
+	}
+	if r.maxKeys > 0 {
+		cmds = append(cmds, r.client.B().Zremrangebyrank().Key(r.key).Start(0).Stop(-int64(r.maxKeys)-1).Build())
+	}
+	for _, result := range r.client.DoMulti(ctx, cmds...) {
+		if err := result.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove forgets the access record for key.
+func (r *ValkeyRecencyTracker) Remove(ctx context.Context, key string) error {
+	return r.client.Do(ctx, r.client.B().Zrem().Key(r.key).Member(key).Build()).Error()
+}
+
+// LeastRecentlyUsed returns up to n keys ordered from the oldest access.
+func (r *ValkeyRecencyTracker) LeastRecentlyUsed(ctx context.Context, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	cmd := r.client.B().Zrange().Key(r.key).Min("0").Max(strconv.Itoa(n - 1)).Build()
+
+	return r.client.Do(ctx, cmd).AsStrSlice()
+}
+
+// MostRecentlyUsed returns up to n keys ordered from the newest access.
+func (r *ValkeyRecencyTracker) MostRecentlyUsed(ctx context.Context, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	cmd := r.client.B().Zrange().Key(r.key).Min("0").Max(strconv.Itoa(n - 1)).Rev().Build()
+
+	return r.client.Do(ctx, cmd).AsStrSlice()
+}
@@ -0,0 +1,155 @@
+package valkeygo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/valkey-io/valkey-go"
+)
+
+const defaultRefreshLockSuffix = ":refresh-lock"
+
+// getAndLockScript returns {found, value, locked} for KEYS[1] after trying to
+// take the refresh lock KEYS[2] with token ARGV[1] for ARGV[2] milliseconds.
+var getAndLockScript = valkey.NewLuaScript(`
+local value = redis.call('GET', KEYS[1])
+local locked = 0
+if redis.call('SET', KEYS[2], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	locked = 1
+end
+if value then
+	return {1, value, locked}
+end
+return {0, '', locked}
+`)
+
+// unlockScript deletes the refresh lock KEYS[1] only while it holds ARGV[1].
+var unlockScript = valkey.NewLuaScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// ErrUnexpectedScriptReply is returned when a refresh lock script reply
+// cannot be parsed.
+var ErrUnexpectedScriptReply = errors.New("unexpected refresh lock script reply")
+
+// ValkeyRefreshLockOption customizes the ValkeyRefreshLock.
+type ValkeyRefreshLockOption func(*ValkeyRefreshLock)
+
+// ValkeyRefreshLock coordinates stale refreshes across processes. GetAndLock
+// reads the current entry and tries to take the refresh lock for it in a
+// single round trip, so only one process reloads a stale entry while the
+// others keep serving it. It is a standalone helper for refreshes the
+// application drives itself: crema.WithDistributedSingleflight does not use
+// it, and its locks are plain tokens under their own keys rather than the
+// cache's load locks.
+type ValkeyRefreshLock struct {
+	client valkey.Client
+	suffix string
+}
+
+// NewValkeyRefreshLock builds a refresh lock on client. Lock keys are the
+// entry keys with a ":refresh-lock" suffix; on Valkey Cluster, entry keys must
+// carry a hash tag such as "{user:1}" so both keys share a slot.
+func NewValkeyRefreshLock(client valkey.Client, opts ...ValkeyRefreshLockOption) *ValkeyRefreshLock {
+	lock := &ValkeyRefreshLock{client: client, suffix: defaultRefreshLockSuffix}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(lock)
+	}
+
+	return lock
+}
+
+// WithRefreshLockSuffix overrides the suffix appended to entry keys to build
+// lock keys.
+func WithRefreshLockSuffix(suffix string) ValkeyRefreshLockOption {
+	return func(lock *ValkeyRefreshLock) {
+		lock.suffix = suffix
+	}
+}
+
+// RefreshLockResult is the outcome of GetAndLock.
+type RefreshLockResult struct {
+	// Value is the stored entry, valid when Found is true.
+	Value []byte
+	// Found reports whether the entry exists.
+	Found bool
+	// Locked reports whether the caller won the refresh lock.
+	Locked bool
+	// Token identifies the lock holder for Unlock, set when Locked is true.
+	Token string
+}
+
+// GetAndLock returns the entry stored at key and tries to take its refresh
+// lock for lockTTL, atomically in one round trip.
+func (l *ValkeyRefreshLock) GetAndLock(ctx context.Context, key string, lockTTL time.Duration) (RefreshLockResult, error) {
+	token, err := newRefreshLockToken()
+	if err != nil {
+		return RefreshLockResult{}, err
+	}
+	millis := strconv.FormatInt(max(lockTTL.Milliseconds(), 1), 10)
+	reply, err := getAndLockScript.Exec(ctx, l.client, []string{key, l.LockKey(key)}, []string{token, millis}).ToArray()
+	if err != nil {
+		return RefreshLockResult{}, err
+	}
+	if len(reply) != 3 {
+		return RefreshLockResult{}, fmt.Errorf("%w: %d elements", ErrUnexpectedScriptReply, len(reply))
+	}
+	found, err := reply[0].AsInt64()
+	if err != nil {
+		return RefreshLockResult{}, err
+	}
+	value, err := reply[1].AsBytes()
+	if err != nil {
+		return RefreshLockResult{}, err
+	}
+	locked, err := reply[2].AsInt64()
+	if err != nil {
+		return RefreshLockResult{}, err
+	}
+
+	result := RefreshLockResult{Found: found == 1, Locked: locked == 1}
+	if result.Found {
+		result.Value = value
+	}
+	if result.Locked {
+		result.Token = token
+	}
+
+	return result, nil
+}
+
+// Unlock releases the refresh lock for key if it is still held with token.
+// It reports whether the lock was released.
+func (l *ValkeyRefreshLock) Unlock(ctx context.Context, key string, token string) (bool, error) {
+	deleted, err := unlockScript.Exec(ctx, l.client, []string{l.LockKey(key)}, []string{token}).AsInt64()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted == 1, nil
+}
+
+// LockKey returns the Valkey key of the refresh lock for key.
+func (l *ValkeyRefreshLock) LockKey(key string) string {
+	return key + l.suffix
+}
+
+func newRefreshLockToken() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf[:]), nil
+}
@@ -0,0 +1,70 @@
+package valkeygo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestValkeyRefreshLock_GetAndLock(t *testing.T) {
+	t.Parallel()
+
+	server, client, provider := newTestValkeyProvider(t)
+	lock := NewValkeyRefreshLock(client)
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "{key}", []byte("stale"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	first, err := lock.GetAndLock(ctx, "{key}", time.Second)
+	if err != nil {
+		t.Fatalf("get and lock: %v", err)
+	}
+	if !first.Found || string(first.Value) != "stale" || !first.Locked || first.Token == "" {
+		t.Fatalf("expected value and lock for first caller, got %+v", first)
+	}
+
+	second, err := lock.GetAndLock(ctx, "{key}", time.Second)
+	if err != nil {
+		t.Fatalf("get and lock: %v", err)
+	}
+	if !second.Found || string(second.Value) != "stale" || second.Locked || second.Token != "" {
+		t.Fatalf("expected value without lock for second caller, got %+v", second)
+	}
+
+	if released, err := lock.Unlock(ctx, "{key}", "other-token"); err != nil || released {
+		t.Fatalf("expected foreign token not to release the lock, got %v, %v", released, err)
+	}
+	if released, err := lock.Unlock(ctx, "{key}", first.Token); err != nil || !released {
+		t.Fatalf("expected holder to release the lock, got %v, %v", released, err)
+	}
+	if server.Exists(lock.LockKey("{key}")) {
+		t.Fatal("expected lock key to be deleted")
+	}
+}
+
+func TestValkeyRefreshLock_MissingEntryAndExpiry(t *testing.T) {
+	t.Parallel()
+
+	server, client, _ := newTestValkeyProvider(t)
+	lock := NewValkeyRefreshLock(client, WithRefreshLockSuffix(":lock"))
+	ctx := context.Background()
+
+	result, err := lock.GetAndLock(ctx, "{missing}", 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("get and lock: %v", err)
+	}
+	if result.Found || result.Value != nil || !result.Locked {
+		t.Fatalf("expected lock without value, got %+v", result)
+	}
+	if !server.Exists("{missing}:lock") {
+		t.Fatal("expected lock key with custom suffix")
+	}
+
+	server.FastForward(60 * time.Millisecond)
+	result, err = lock.GetAndLock(ctx, "{missing}", time.Second)
+	if err != nil || !result.Locked {
+		t.Fatalf("expected lock to be available after expiry, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,14 @@
+package valkeygo
+
+import (
+	"github.com/abema/crema"
+	"github.com/valkey-io/valkey-go"
+)
+
+// NewValkeyReplicationHook builds a crema.ReplicationHook that forwards cache
+// writes and deletes to the Valkey server behind client, such as a cache in a
+// secondary region. Use it with crema.WithReplicationHook, which runs the hook
+// asynchronously after each write.
+func NewValkeyReplicationHook(client valkey.Client) *crema.ProviderReplicationHook[[]byte] {
+	return crema.NewProviderReplicationHook[[]byte](NewValkeyCacheProvider(client))
+}
@@ -0,0 +1,30 @@
+package valkeygo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestValkeyReplicationHook_ReplicatesWrites(t *testing.T) {
+	t.Parallel()
+
+	_, client, secondary := newTestValkeyProvider(t)
+	hook := NewValkeyReplicationHook(client)
+	ctx := context.Background()
+
+	if err := hook.AfterSet(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("after set: %v", err)
+	}
+	value, ok, err := secondary.Get(ctx, "key")
+	if err != nil || !ok || string(value) != "value" {
+		t.Fatalf("unexpected replicated value: %q, %v, %v", value, ok, err)
+	}
+
+	if err := hook.AfterDelete(ctx, "key"); err != nil {
+		t.Fatalf("after delete: %v", err)
+	}
+	if _, ok, _ := secondary.Get(ctx, "key"); ok {
+		t.Fatal("expected replicated delete")
+	}
+}
@@ -0,0 +1,145 @@
+package crema
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+)
+
+// ExpiryHeaderCodec is an optional CacheStorageCodec extension for formats
+// that keep the expiry in a fixed header, letting ExtendTTL rewrite it
+// without decoding and re-encoding the value.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type ExpiryHeaderCodec[S any] interface {
+	// ExpiryOf returns the expiry stored in data.
+	ExpiryOf(data S) (int64, error)
+	// WithExpiry returns a copy of data with its expiry replaced; data must
+	// not be modified.
+	WithExpiry(data S, expireAtMillis int64) (S, error)
+}
+
+var (
+	_ ExpiryHeaderCodec[[]byte] = RawByteCodec{}
+	_ ExpiryHeaderCodec[[]byte] = StringCodec{}
+)
+
+// ExpiryOf returns the expiry header of data.
+func (RawByteCodec) ExpiryOf(data []byte) (int64, error) {
+	return rawExpiry(data)
+}
+
+// WithExpiry returns a copy of data with a new expiry header.
+func (RawByteCodec) WithExpiry(data []byte, expireAtMillis int64) ([]byte, error) {
+	return reframeRaw(data, expireAtMillis)
+}
+
+// ExpiryOf returns the expiry header of data.
+func (StringCodec) ExpiryOf(data []byte) (int64, error) {
+	return rawExpiry(data)
+}
+
+// WithExpiry returns a copy of data with a new expiry header.
+func (StringCodec) WithExpiry(data []byte, expireAtMillis int64) ([]byte, error) {
+	return reframeRaw(data, expireAtMillis)
+}
+
+func rawExpiry(data []byte) (int64, error) {
+	if len(data) < rawByteHeaderSize {
+		return 0, ErrRawByteDataTooShort
+	}
+
+	return int64(binary.BigEndian.Uint64(data)), nil
+}
+
+func reframeRaw(data []byte, expireAtMillis int64) ([]byte, error) {
+	if len(data) < rawByteHeaderSize {
+		return nil, ErrRawByteDataTooShort
+	}
+
+	return frameRaw(expireAtMillis, data[rawByteHeaderSize:]), nil
+}
+
+// ExtendTTL pushes the expiry of the entry stored for key d past its current
+// expiry, or d past now when it has already expired, without running a
+// loader. It is meant for times the application knows cached data is still
+// valid, such as an upstream maintenance window. Codecs implementing
+// ExpiryHeaderCodec only have the expiry header rewritten; other entries are
+// decoded and re-encoded. It reports false when key is not cached. The
+// read-modify-write is not atomic, so a concurrent write may be overwritten.
+func (c *cacheImpl[V, S]) ExtendTTL(ctx context.Context, key string, d time.Duration) (bool, error) {
+	ctx = c.metricsContext(ctx)
+	nowMillis := c.now().UnixMilli()
+
+	return c.rewriteExpiry(ctx, key, func(expireAtMillis int64) int64 {
+		return max(expireAtMillis, nowMillis) + d.Milliseconds()
+	})
+}
+
+// rewriteExpiry replaces the expiry of the entry stored for key with expiry
+// applied to the stored one, rewriting only the header of ExpiryHeaderCodec
+// formats. It reports false when key is not cached.
+func (c *cacheImpl[V, S]) rewriteExpiry(ctx context.Context, key string, expiry func(expireAtMillis int64) int64) (bool, error) {
+	c.metrics.RecordCacheGet(ctx)
+
+	started := time.Now()
+	rv, exists, err := c.provider.Get(ctx, key)
+	if err != nil {
+		return false, c.observeError(ctx, ErrorPhaseProviderGet, key, started, err)
+	}
+	if !exists {
+		return false, nil
+	}
+
+	started = time.Now()
+	raw, co, _, decoded, phase, err := c.reexpireEncoded(key, rv, expiry)
+	if err != nil {
+		return false, c.observeError(ctx, phase, key, started, err)
+	}
+	ttl := time.UnixMilli(co.ExpireAtMillis).Sub(c.now())
+	if ttl <= 0 {
+		return true, nil
+	}
+	c.metrics.RecordCacheSet(ctx)
+	encoded := NewEncodedValue(raw)
+
+	started = time.Now()
+	if err := c.provider.Set(ctx, key, encoded.Value(), ttl); err != nil {
+		return true, c.observeError(ctx, ErrorPhaseProviderSet, key, started, err)
+	}
+	c.cancelSetRetry(key)
+	if decoded {
+		c.scopeStore(ctx, key, co)
+	} else {
+		c.scopeDelete(ctx, key)
+	}
+	c.replicateSet(ctx, key, encoded, ttl)
+
+	return true, nil
+}
+
+// reexpireEncoded returns rv with its expiry replaced by expiry applied to
+// the stored one, the updated entry and the stored expiry. Only the expiry
+// of co is set unless decoded reports true. phase classifies a returned
+// error.
+func (c *cacheImpl[V, S]) reexpireEncoded(key string, rv S, expiry func(expireAtMillis int64) int64) (S, CacheObject[V], int64, bool, ErrorPhase, error) {
+	if header, ok := c.codec.(ExpiryHeaderCodec[S]); ok {
+		expireAtMillis, err := header.ExpiryOf(rv)
+		if err != nil {
+			return rv, CacheObject[V]{}, 0, false, ErrorPhaseDecode, err
+		}
+		co := CacheObject[V]{ExpireAtMillis: expiry(expireAtMillis)}
+		raw, err := header.WithExpiry(rv, co.ExpireAtMillis)
+
+		return raw, co, expireAtMillis, false, ErrorPhaseEncode, err
+	}
+
+	co, err := c.decode(key, rv)
+	if err != nil {
+		return rv, CacheObject[V]{}, 0, false, ErrorPhaseDecode, err
+	}
+	expireAtMillis := co.ExpireAtMillis
+	co.ExpireAtMillis = expiry(expireAtMillis)
+	raw, err := c.encode(key, co)
+
+	return raw, co, expireAtMillis, true, ErrorPhaseEncode, err
+}
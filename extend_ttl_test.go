@@ -0,0 +1,74 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCache_ExtendTTLRewritesExpiryHeader(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewRawByteCache(provider)
+	now := time.UnixMilli(1_000_000)
+	cache.(*cacheImpl[[]byte, []byte]).now = func() time.Time { return now }
+	ctx := context.Background()
+	expireAt := now.Add(time.Minute).UnixMilli()
+	if err := cache.Set(ctx, "key", CacheObject[[]byte]{Value: []byte("payload"), ExpireAtMillis: expireAt}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	ok, err := cache.ExtendTTL(ctx, "key", time.Hour)
+	if err != nil {
+		t.Fatalf("extend: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected key to be extended")
+	}
+	co, _, err := cache.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if want := expireAt + time.Hour.Milliseconds(); co.ExpireAtMillis != want {
+		t.Fatalf("expected expiry %d, got %d", want, co.ExpireAtMillis)
+	}
+	if string(co.Value) != "payload" {
+		t.Fatalf("expected payload, got %q", co.Value)
+	}
+}
+
+func TestCache_ExtendTTLReencodesAndRevivesExpired(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	provider.items["key"] = CacheObject[int]{Value: 7, ExpireAtMillis: 1}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	now := time.UnixMilli(1_000_000)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return now }
+
+	ok, err := cache.ExtendTTL(context.Background(), "key", time.Minute)
+	if err != nil {
+		t.Fatalf("extend: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected key to be extended")
+	}
+	co := provider.items["key"]
+	if want := now.Add(time.Minute).UnixMilli(); co.ExpireAtMillis != want || co.Value != 7 {
+		t.Fatalf("expected value 7 expiring at %d, got %+v", want, co)
+	}
+}
+
+func TestCache_ExtendTTLMissingKey(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(NewMemoryCacheProvider[[]byte](), JSONByteStringCodec[string]{})
+	ok, err := cache.ExtendTTL(context.Background(), "missing", time.Minute)
+	if err != nil {
+		t.Fatalf("extend: %v", err)
+	}
+	if ok {
+		t.Fatal("expected missing key to be reported")
+	}
+}
@@ -0,0 +1,57 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithFollowerTimeoutRetry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		retry     bool
+		wantValue int
+		wantErr   error
+	}{
+		{name: "retry", retry: true, wantValue: 5},
+		{name: "no retry", retry: false, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := []CacheOption[int, CacheObject[int]]{WithMaxLoadTimeout[int, CacheObject[int]](50 * time.Millisecond)}
+			if tt.retry {
+				opts = append(opts, WithFollowerTimeoutRetry[int, CacheObject[int]]())
+			}
+			cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{}, opts...)
+			ctx := context.Background()
+
+			started := make(chan struct{})
+			leaderErr := make(chan error, 1)
+			go func() {
+				_, err := cache.GetOrLoad(ctx, "key", time.Minute, func(ctx context.Context) (int, error) {
+					close(started)
+					<-ctx.Done()
+
+					return 0, ctx.Err()
+				})
+				leaderErr <- err
+			}()
+			<-started
+
+			v, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+				return 5, nil
+			})
+			if !errors.Is(err, tt.wantErr) || v != tt.wantValue {
+				t.Fatalf("expected %d, %v, got %d, %v", tt.wantValue, tt.wantErr, v, err)
+			}
+			if err := <-leaderErr; !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected leader to time out, got %v", err)
+			}
+		})
+	}
+}
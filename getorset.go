@@ -0,0 +1,76 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// GetOrSetProvider is an optional CacheProvider extension that stores a value
+// only when the key is absent and otherwise returns the stored value in one
+// atomic step, such as Redis SET NX GET.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type GetOrSetProvider[S any] interface {
+	CacheProvider[S]
+	// GetOrSet stores value for key when absent and returns it with
+	// loaded=false, or returns the already stored value with loaded=true.
+	GetOrSet(ctx context.Context, key string, value S, ttl time.Duration) (actual S, loaded bool, err error)
+}
+
+// WithAtomicMissFill stores values loaded for a key read as absent only if no
+// other writer stored the key first, returning the stored value otherwise, so
+// instances racing on a cold key agree on one value instead of the last write
+// winning. It requires a provider implementing GetOrSetProvider; the Redis and
+// Valkey providers implement it with SET NX GET, which needs Redis or Valkey
+// 7.0 or later. Without it, and whenever the preceding read failed or could
+// not be decoded, loaded values are stored with a plain Set.
+func WithAtomicMissFill[V any, S any]() CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.atomicMissFill = true
+	}
+}
+
+// setIfAbsent stores co unless another writer stored key first, in which case
+// the stored entry is returned. It falls back to Set when the provider does
+// not implement GetOrSetProvider.
+func (c *cacheImpl[V, S]) setIfAbsent(ctx context.Context, key string, co CacheObject[V]) (CacheObject[V], error) {
+	setter, ok := providerAs[GetOrSetProvider[S]](c.provider)
+	if !ok {
+		return co, c.set(ctx, key, co)
+	}
+	c.metrics.RecordCacheSet(ctx)
+	c.mutationCheck.forget(key)
+
+	started := time.Now()
+	encoded, err := c.encode(key, co)
+	if err != nil {
+		return co, c.observeError(ctx, ErrorPhaseEncode, key, started, err)
+	}
+	ttl := time.UnixMilli(co.ExpireAtMillis).Sub(c.now())
+	if ttl <= 0 {
+		return co, nil
+	}
+
+	started = time.Now()
+	actual, loaded, err := setter.GetOrSet(ctx, key, encoded, ttl)
+	if err != nil {
+		return co, c.writeFailed(ctx, key, encoded, co.ExpireAtMillis, started, err)
+	}
+	// either write is newer than any queued retry of key
+	c.cancelSetRetry(key)
+	if !loaded {
+		c.written(ctx, key, co, NewEncodedValue(encoded), ttl)
+
+		return co, nil
+	}
+
+	started = time.Now()
+	existing, err := c.decode(key, actual)
+	if err != nil {
+		return co, c.observeError(ctx, ErrorPhaseDecode, key, started, err)
+	}
+	if existing.ExpireAtMillis <= c.now().UnixMilli() {
+		return co, nil
+	}
+
+	return existing, nil
+}
@@ -0,0 +1,150 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_GetOrLoadKeepsFirstWriterOnMiss(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache(provider, JSONByteStringCodec[string]{}, WithAtomicMissFill[string, []byte]())
+	ctx := context.Background()
+
+	value, err := cache.GetOrLoad(ctx, "key", time.Minute, func(ctx context.Context) (string, error) {
+		// another instance fills the key while this load runs
+		other := CacheObject[string]{Value: "other", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}
+		if err := cache.Set(ctx, "key", other); err != nil {
+			t.Errorf("set: %v", err)
+		}
+
+		return "mine", nil
+	})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if value != "other" {
+		t.Fatalf("expected first writer value %q, got %q", "other", value)
+	}
+	co, ok, err := cache.Get(ctx, "key")
+	if err != nil || !ok || co.Value != "other" {
+		t.Fatalf("unexpected stored entry: %+v, %v, %v", co, ok, err)
+	}
+}
+
+func TestCache_GetOrLoadOverwritesOnRevalidation(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache(provider, JSONByteStringCodec[string]{})
+	impl := cache.(*cacheImpl[string, []byte])
+	impl.random = fakeRandom(0)
+	ctx := context.Background()
+	stale := CacheObject[string]{Value: "stale", ExpireAtMillis: time.Now().Add(time.Second).UnixMilli()}
+	if err := cache.Set(ctx, "key", stale); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	value, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (string, error) {
+		return "fresh", nil
+	})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if value != "fresh" {
+		t.Fatalf("expected fresh, got %q", value)
+	}
+	co, _, _ := cache.Get(ctx, "key")
+	if co.Value != "fresh" {
+		t.Fatalf("expected revalidated entry to be overwritten, got %q", co.Value)
+	}
+}
+
+func TestCache_GetOrLoadLastWriterWinsByDefault(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache(provider, JSONByteStringCodec[string]{})
+	ctx := context.Background()
+
+	value, err := cache.GetOrLoad(ctx, "key", time.Minute, func(ctx context.Context) (string, error) {
+		other := CacheObject[string]{Value: "other", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}
+		if err := cache.Set(ctx, "key", other); err != nil {
+			t.Errorf("set: %v", err)
+		}
+
+		return "mine", nil
+	})
+	if err != nil || value != "mine" {
+		t.Fatalf("expected mine, got %q, %v", value, err)
+	}
+	co, _, _ := cache.Get(ctx, "key")
+	if co.Value != "mine" {
+		t.Fatalf("expected the loaded value to be stored, got %q", co.Value)
+	}
+}
+
+func TestCache_GetOrLoadOverwritesUndecodableEntry(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache(provider, JSONByteStringCodec[string]{}, WithAtomicMissFill[string, []byte]())
+	ctx := context.Background()
+	if err := provider.Set(ctx, "key", []byte("{corrupt"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	value, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (string, error) {
+		return "fresh", nil
+	})
+	if err != nil || value != "fresh" {
+		t.Fatalf("expected fresh, got %q, %v", value, err)
+	}
+	co, ok, err := cache.Get(ctx, "key")
+	if err != nil || !ok || co.Value != "fresh" {
+		t.Fatalf("expected the corrupt entry to be overwritten, got %+v, %v, %v", co, ok, err)
+	}
+}
+
+type failingGetOrSetProvider struct {
+	*MemoryCacheProvider[[]byte]
+	failures atomic.Int32
+}
+
+func (p *failingGetOrSetProvider) GetOrSet(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	if p.failures.Add(-1) >= 0 {
+		return nil, false, errors.New("failover in progress")
+	}
+
+	return p.MemoryCacheProvider.GetOrSet(ctx, key, value, ttl)
+}
+
+func TestCache_GetOrLoadRetriesFailedMissFill(t *testing.T) {
+	t.Parallel()
+
+	provider := &failingGetOrSetProvider{MemoryCacheProvider: NewMemoryCacheProvider[[]byte]()}
+	provider.failures.Store(1)
+	cache := NewCache(provider, JSONByteStringCodec[string]{},
+		WithAtomicMissFill[string, []byte](),
+		WithSetRetry[string, []byte](SetRetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+	)
+	ctx := context.Background()
+
+	value, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (string, error) {
+		return "loaded", nil
+	})
+	if err != nil || value != "loaded" {
+		t.Fatalf("expected loaded value, got %q, %v", value, err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	co, ok, err := cache.Get(ctx, "key")
+	if err != nil || !ok || co.Value != "loaded" {
+		t.Fatalf("expected the failed write to be retried, got %+v, %v, %v", co, ok, err)
+	}
+}
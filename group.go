@@ -0,0 +1,101 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// Group deduplicates concurrent calls for the same key, running one function
+// per key at a time and sharing its result with every caller that joined.
+// It is the sharded singleflight implementation used by Cache, exported for
+// work that is not cached. Functions run with a context detached from caller
+// cancellation, so a caller giving up does not abort the shared call.
+// The zero value is not usable; construct it with NewGroup.
+type Group[V any] struct {
+	_      noCopy
+	loader *singleflightLoader[V]
+}
+
+// GroupOption configures a Group.
+type GroupOption[V any] func(*Group[V])
+
+// GroupResult holds the outcome of a call delivered by DoChan.
+type GroupResult[V any] struct {
+	// Value is the result of the call.
+	Value V
+	// Err is the error returned by the call.
+	Err error
+	// Shared reports whether the caller joined a call started by another caller.
+	Shared bool
+}
+
+// NewGroup constructs a Group.
+func NewGroup[V any](opts ...GroupOption[V]) *Group[V] {
+	group := &Group[V]{loader: newSingleflightLoader[V](NoopMetricsProvider{}, 0)}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(group)
+	}
+
+	return group
+}
+
+// WithGroupMetricsProvider reports call events to metrics, as Cache does for
+// its loads.
+func WithGroupMetricsProvider[V any](metrics MetricsProvider) GroupOption[V] {
+	return func(g *Group[V]) {
+		if metrics == nil {
+			metrics = NoopMetricsProvider{}
+		}
+		g.loader.metrics = metrics
+	}
+}
+
+// WithGroupTimeout caps the execution time of each shared call. A
+// non-positive duration disables the timeout.
+func WithGroupTimeout[V any](timeout time.Duration) GroupOption[V] {
+	return func(g *Group[V]) {
+		g.loader.maxLoadTimeout = timeout
+	}
+}
+
+// Do runs fn for key unless a call for key is already in flight, in which
+// case it waits for that call instead. shared reports whether the result came
+// from a call started by another caller. Do returns ctx.Err() when ctx ends
+// before the result is available.
+func (g *Group[V]) Do(ctx context.Context, key string, fn func(ctx context.Context) (V, error)) (v V, shared bool, err error) {
+	v, leader, err := g.loader.load(ctx, key, fn)
+
+	return v, !leader, err
+}
+
+// DoChan is like Do but delivers the result on the returned channel, which
+// receives exactly one value.
+func (g *Group[V]) DoChan(ctx context.Context, key string, fn func(ctx context.Context) (V, error)) <-chan GroupResult[V] {
+	ch := make(chan GroupResult[V], 1)
+	go func() {
+		v, shared, err := g.Do(ctx, key, fn)
+		ch <- GroupResult[V]{Value: v, Err: err, Shared: shared}
+	}()
+
+	return ch
+}
+
+// Forget makes the next call for key start a new call instead of joining the
+// one in flight. Callers already waiting still receive the earlier result.
+func (g *Group[V]) Forget(key string) {
+	g.loader.forget(key)
+}
+
+// Cancel aborts the unfinished call for key: its context is cancelled and
+// waiting callers return ErrLoadCanceled. It reports whether a call was aborted.
+func (g *Group[V]) Cancel(key string) bool {
+	return g.loader.cancelLoad(key)
+}
+
+// LastCallInfo returns the most recent finished call for key.
+func (g *Group[V]) LastCallInfo(key string) (LoadInfo, bool) {
+	return g.loader.lastLoadInfo(key)
+}
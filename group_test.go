@@ -0,0 +1,163 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_DoDeduplicates(t *testing.T) {
+	t.Parallel()
+
+	group := NewGroup[int]()
+	release := make(chan struct{})
+	var calls atomic.Int32
+	fn := func(context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+
+		return 7, nil
+	}
+
+	const callers = 5
+	var (
+		wg     sync.WaitGroup
+		shared atomic.Int32
+	)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, isShared, err := group.Do(context.Background(), "key", fn)
+			if err != nil || v != 7 {
+				t.Errorf("unexpected result %d, %v", v, err)
+			}
+			if isShared {
+				shared.Add(1)
+			}
+		}()
+	}
+	deadline := time.After(time.Second)
+	for {
+		shard := group.loader.shardFor("key")
+		shard.mu.Lock()
+		refs := 0
+		if inf, ok := shard.inflight["key"]; ok {
+			refs = inf.refs
+		}
+		shard.mu.Unlock()
+		if refs == callers {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for callers to join")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 call, got %d", calls.Load())
+	}
+	if shared.Load() != callers-1 {
+		t.Fatalf("expected %d shared results, got %d", callers-1, shared.Load())
+	}
+	info, ok := group.LastCallInfo("key")
+	if !ok || info.Followers != callers-1 {
+		t.Fatalf("unexpected call info %+v, %v", info, ok)
+	}
+}
+
+func TestGroup_DoChan(t *testing.T) {
+	t.Parallel()
+
+	group := NewGroup[string]()
+	expectedErr := errors.New("boom")
+	res := <-group.DoChan(context.Background(), "key", func(context.Context) (string, error) {
+		return "", expectedErr
+	})
+	if !errors.Is(res.Err, expectedErr) {
+		t.Fatalf("expected %v, got %v", expectedErr, res.Err)
+	}
+	if res.Shared {
+		t.Fatal("expected unshared result")
+	}
+}
+
+func TestGroup_Forget(t *testing.T) {
+	t.Parallel()
+
+	group := NewGroup[int]()
+	release := make(chan struct{})
+	first := group.DoChan(context.Background(), "key", func(context.Context) (int, error) {
+		<-release
+
+		return 1, nil
+	})
+	deadline := time.After(time.Second)
+	for {
+		shard := group.loader.shardFor("key")
+		shard.mu.Lock()
+		_, ok := shard.inflight["key"]
+		shard.mu.Unlock()
+		if ok {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for the first call")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	group.Forget("key")
+	v, shared, err := group.Do(context.Background(), "key", func(context.Context) (int, error) {
+		return 2, nil
+	})
+	if err != nil || v != 2 || shared {
+		t.Fatalf("expected a fresh call, got %d, %v, %v", v, shared, err)
+	}
+	close(release)
+	if res := <-first; res.Value != 1 {
+		t.Fatalf("expected first caller to receive 1, got %d", res.Value)
+	}
+}
+
+func TestGroup_Cancel(t *testing.T) {
+	t.Parallel()
+
+	group := NewGroup[int]()
+	started := make(chan struct{})
+	res := group.DoChan(context.Background(), "key", func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	})
+	<-started
+	if !group.Cancel("key") {
+		t.Fatal("expected the call to be canceled")
+	}
+	if r := <-res; !errors.Is(r.Err, ErrLoadCanceled) {
+		t.Fatalf("expected ErrLoadCanceled, got %v", r.Err)
+	}
+}
+
+func TestGroup_Timeout(t *testing.T) {
+	t.Parallel()
+
+	group := NewGroup(WithGroupTimeout[int](10 * time.Millisecond))
+	_, _, err := group.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package crema
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"time"
+)
+
+// WithSkipIdenticalWrites reads the stored entry before each Set and skips
+// the write when the stored payload already holds an identical value whose
+// expiry is no more than tolerance earlier than the new one. This trades a
+// read for a write on refresh-heavy keys whose values rarely change, such as
+// configuration refreshed by many instances at once. Values are compared by
+// their encoded form, so the codec must encode equal values identically.
+func WithSkipIdenticalWrites[V any, S any](tolerance time.Duration) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.skipIdenticalWrites = true
+		c.identicalWriteTolerance = max(tolerance, 0)
+	}
+}
+
+// isIdenticalWrite reports whether value is already stored for key within
+// the configured expiry tolerance. Read failures report false so that the
+// write proceeds.
+func (c *cacheImpl[V, S]) isIdenticalWrite(ctx context.Context, key string, value CacheObject[V]) bool {
+	rv, exists, err := c.provider.Get(ctx, key)
+	if err != nil || !exists {
+		return false
+	}
+	stored, err := c.decode(key, rv)
+	if err != nil {
+		return false
+	}
+	if stored.ExpireAtMillis < value.ExpireAtMillis-c.identicalWriteTolerance.Milliseconds() {
+		return false
+	}
+	reencoded, err := c.encode(key, CacheObject[V]{Value: value.Value, ExpireAtMillis: stored.ExpireAtMillis})
+	if err != nil {
+		return false
+	}
+
+	return equalStorageValues(rv, reencoded)
+}
+
+// equalStorageValues compares two encoded entries, ignoring writer headers
+// since they hold the write time.
+func equalStorageValues[S any](a S, b S) bool {
+	if ab, ok := any(a).([]byte); ok {
+		return bytes.Equal(stripWriterHeader(ab), stripWriterHeader(any(b).([]byte)))
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,94 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type identicalWriteMetricsProvider struct {
+	BaseMetricsProvider
+	skipped atomic.Int32
+}
+
+func (m *identicalWriteMetricsProvider) RecordIdenticalWriteSkipped(context.Context) {
+	m.skipped.Add(1)
+}
+
+type countingSetProvider struct {
+	*byteProvider
+	sets atomic.Int32
+}
+
+func (p *countingSetProvider) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	p.sets.Add(1)
+
+	return p.byteProvider.Set(ctx, key, value, ttl)
+}
+
+func TestWithSkipIdenticalWrites(t *testing.T) {
+	t.Parallel()
+
+	metrics := &identicalWriteMetricsProvider{}
+	provider := &countingSetProvider{byteProvider: &byteProvider{items: make(map[string][]byte)}}
+	now := time.UnixMilli(1_000_000)
+	cache := NewCache[string, []byte](provider, JSONByteStringCodec[string]{},
+		WithSkipIdenticalWrites[string, []byte](time.Second),
+		WithMetricsProvider[string, []byte](metrics),
+	)
+	cache.(*cacheImpl[string, []byte]).now = func() time.Time { return now }
+	ctx := context.Background()
+	expireAt := now.Add(time.Minute).UnixMilli()
+
+	write := func(value string, expireAtMillis int64) {
+		t.Helper()
+		if err := cache.Set(ctx, "config", CacheObject[string]{Value: value, ExpireAtMillis: expireAtMillis}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+
+	write("v1", expireAt)
+	write("v1", expireAt+500)
+	if provider.sets.Load() != 1 || metrics.skipped.Load() != 1 {
+		t.Fatalf("expected identical write within tolerance to be skipped, got %d sets and %d skips", provider.sets.Load(), metrics.skipped.Load())
+	}
+
+	write("v1", expireAt+5000)
+	if provider.sets.Load() != 2 {
+		t.Fatalf("expected write extending expiry beyond tolerance, got %d sets", provider.sets.Load())
+	}
+
+	write("v2", expireAt+5000)
+	if provider.sets.Load() != 3 {
+		t.Fatalf("expected changed value to be written, got %d sets", provider.sets.Load())
+	}
+}
+
+func TestWithSkipIdenticalWrites_IgnoresWriterHeader(t *testing.T) {
+	t.Parallel()
+
+	metrics := &identicalWriteMetricsProvider{}
+	provider := &countingSetProvider{byteProvider: &byteProvider{items: make(map[string][]byte)}}
+	now := time.UnixMilli(1_000_000)
+	cache := NewCache[string, []byte](provider, JSONByteStringCodec[string]{},
+		WithSkipIdenticalWrites[string, []byte](time.Second),
+		WithWriterIdentity[string, []byte](WriterIdentity{Service: "api", Pod: "api-1"}),
+		WithMetricsProvider[string, []byte](metrics),
+	)
+	impl := cache.(*cacheImpl[string, []byte])
+	impl.now = func() time.Time { return now }
+	ctx := context.Background()
+	expireAt := now.Add(time.Minute).UnixMilli()
+
+	if err := cache.Set(ctx, "config", CacheObject[string]{Value: "v1", ExpireAtMillis: expireAt}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	now = now.Add(100 * time.Millisecond)
+	if err := cache.Set(ctx, "config", CacheObject[string]{Value: "v1", ExpireAtMillis: expireAt + 100}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if provider.sets.Load() != 1 || metrics.skipped.Load() != 1 {
+		t.Fatalf("expected the later identical write to be skipped, got %d sets and %d skips", provider.sets.Load(), metrics.skipped.Load())
+	}
+}
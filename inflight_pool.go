@@ -0,0 +1,76 @@
+package crema
+
+import "sync"
+
+// inflightPool recycles inflight records between singleflight loads. It uses
+// a sync.Pool by default, a bounded free list when capped, and allocates a
+// new record for every load when disabled.
+type inflightPool[V any] struct {
+	_        noCopy
+	disabled bool
+	pool     sync.Pool
+	free     chan *inflight[V]
+}
+
+// get returns a recycled record and true, or a new record and false.
+func (p *inflightPool[V]) get() (*inflight[V], bool) {
+	switch {
+	case p.disabled:
+	case p.free != nil:
+		select {
+		case inf := <-p.free:
+			return inf, true
+		default:
+		}
+	default:
+		if inf, ok := p.pool.Get().(*inflight[V]); ok {
+			return inf, true
+		}
+	}
+
+	return &inflight[V]{}, false
+}
+
+// put hands inf back for reuse. It is dropped when pooling is disabled or
+// the free list is full.
+func (p *inflightPool[V]) put(inf *inflight[V]) {
+	switch {
+	case p.disabled:
+	case p.free != nil:
+		select {
+		case p.free <- inf:
+		default:
+		}
+	default:
+		p.pool.Put(inf)
+	}
+}
+
+// WithoutInflightPool allocates a new inflight record for every singleflight
+// load instead of recycling them, to rule out pooled reuse when diagnosing
+// unusual cancellation patterns. It has no effect with WithDirectLoader.
+func WithoutInflightPool[V any, S any]() CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+			loader.inflightPool = &inflightPool[V]{disabled: true}
+		}
+	}
+}
+
+// WithInflightPoolCap keeps at most maxPooled idle inflight records for reuse
+// instead of an unbounded sync.Pool. A non-positive maxPooled disables
+// pooling. It has no effect with WithDirectLoader.
+func WithInflightPoolCap[V any, S any](maxPooled int) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		loader, ok := c.internalLoader.(*singleflightLoader[V])
+		if !ok {
+			return
+		}
+		if maxPooled <= 0 {
+			loader.inflightPool = &inflightPool[V]{disabled: true}
+
+			return
+		}
+		loader.inflightPool = &inflightPool[V]{free: make(chan *inflight[V], maxPooled)}
+	}
+}
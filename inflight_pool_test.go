@@ -0,0 +1,84 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type inflightPoolMetricsProvider struct {
+	BaseMetricsProvider
+	hits   atomic.Int32
+	misses atomic.Int32
+}
+
+func (m *inflightPoolMetricsProvider) RecordInflightPoolGet(_ context.Context, hit bool) {
+	if hit {
+		m.hits.Add(1)
+	} else {
+		m.misses.Add(1)
+	}
+}
+
+func loadSequentially(t *testing.T, cache Cache[int, CacheObject[int]], n int) {
+	t.Helper()
+
+	for i := range n {
+		if err := cache.Delete(context.Background(), "key"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if _, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+			return i, nil
+		}); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	}
+}
+
+func TestWithoutInflightPool_NeverReuses(t *testing.T) {
+	t.Parallel()
+
+	metrics := &inflightPoolMetricsProvider{}
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithoutInflightPool[int, CacheObject[int]](),
+	)
+
+	loadSequentially(t, cache, 5)
+	if got := metrics.hits.Load(); got != 0 {
+		t.Fatalf("expected no pool hits, got %d", got)
+	}
+	if got := metrics.misses.Load(); got != 5 {
+		t.Fatalf("expected 5 pool misses, got %d", got)
+	}
+}
+
+func TestWithInflightPoolCap_ReusesBoundedRecords(t *testing.T) {
+	t.Parallel()
+
+	metrics := &inflightPoolMetricsProvider{}
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithInflightPoolCap[int, CacheObject[int]](1),
+	)
+
+	loadSequentially(t, cache, 5)
+	if got := metrics.misses.Load(); got != 1 {
+		t.Fatalf("expected 1 pool miss, got %d", got)
+	}
+	if got := metrics.hits.Load(); got != 4 {
+		t.Fatalf("expected 4 pool hits, got %d", got)
+	}
+}
+
+func TestInflightPool_CapDropsExcessRecords(t *testing.T) {
+	t.Parallel()
+
+	pool := &inflightPool[int]{free: make(chan *inflight[int], 1)}
+	pool.put(&inflight[int]{})
+	pool.put(&inflight[int]{})
+	if got := len(pool.free); got != 1 {
+		t.Fatalf("expected 1 pooled record, got %d", got)
+	}
+}
@@ -0,0 +1,123 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ProviderOperation identifies a CacheProvider call recorded by an
+// instrumented provider.
+type ProviderOperation string
+
+const (
+	// ProviderOperationGet is a CacheProvider.Get.
+	ProviderOperationGet ProviderOperation = "get"
+	// ProviderOperationSet is a CacheProvider.Set.
+	ProviderOperationSet ProviderOperation = "set"
+	// ProviderOperationDelete is a CacheProvider.Delete.
+	ProviderOperationDelete ProviderOperation = "delete"
+)
+
+// ProviderErrorClass groups provider errors for error-rate metrics.
+type ProviderErrorClass string
+
+const (
+	// ProviderErrorNone means the operation succeeded.
+	ProviderErrorNone ProviderErrorClass = ""
+	// ProviderErrorCanceled means the operation context was canceled.
+	ProviderErrorCanceled ProviderErrorClass = "canceled"
+	// ProviderErrorTimeout means the operation context ran out of time.
+	ProviderErrorTimeout ProviderErrorClass = "timeout"
+	// ProviderErrorOther is any other failure.
+	ProviderErrorOther ProviderErrorClass = "other"
+)
+
+// ClassifyProviderError returns the class of a provider error.
+func ClassifyProviderError(err error) ProviderErrorClass {
+	switch {
+	case err == nil:
+		return ProviderErrorNone
+	case errors.Is(err, context.Canceled):
+		return ProviderErrorCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ProviderErrorTimeout
+	default:
+		return ProviderErrorOther
+	}
+}
+
+type instrumentedProvider[S any] struct {
+	inner   CacheProvider[S]
+	metrics MetricsProvider
+}
+
+var _ CacheProvider[any] = &instrumentedProvider[any]{}
+
+// NewInstrumentedProvider wraps inner so that every Get, Set and Delete is
+// reported to metrics through RecordProviderOperation with its latency, error
+// class and payload size. The size is the length of []byte and string values
+// read or written and zero otherwise. Optional provider interfaces such as
+// GetOrSetProvider remain reachable by the cache through Unwrap but are not
+// recorded.
+func NewInstrumentedProvider[S any](inner CacheProvider[S], metrics MetricsProvider) CacheProvider[S] {
+	if metrics == nil {
+		metrics = NoopMetricsProvider{}
+	}
+
+	return &instrumentedProvider[S]{inner: inner, metrics: metrics}
+}
+
+// WithoutProviderInstrumentation stops NewCache from wrapping the provider
+// with NewInstrumentedProvider when a metrics provider is configured.
+func WithoutProviderInstrumentation[V any, S any]() CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.skipProviderMetrics = true
+	}
+}
+
+func (p *instrumentedProvider[S]) Get(ctx context.Context, key string) (S, bool, error) {
+	started := time.Now()
+	value, ok, err := p.inner.Get(ctx, key)
+	p.metrics.RecordProviderOperation(ctx, ProviderOperationGet, time.Since(started), storageSize(value), ClassifyProviderError(err))
+
+	return value, ok, err
+}
+
+func (p *instrumentedProvider[S]) Set(ctx context.Context, key string, value S, ttl time.Duration) error {
+	started := time.Now()
+	err := p.inner.Set(ctx, key, value, ttl)
+	p.metrics.RecordProviderOperation(ctx, ProviderOperationSet, time.Since(started), storageSize(value), ClassifyProviderError(err))
+
+	return err
+}
+
+func (p *instrumentedProvider[S]) Delete(ctx context.Context, key string) error {
+	started := time.Now()
+	err := p.inner.Delete(ctx, key)
+	p.metrics.RecordProviderOperation(ctx, ProviderOperationDelete, time.Since(started), 0, ClassifyProviderError(err))
+
+	return err
+}
+
+// Unwrap returns the wrapped provider.
+func (p *instrumentedProvider[S]) Unwrap() CacheProvider[S] {
+	return p.inner
+}
+
+// providerAs returns the first provider in the Unwrap chain of provider that
+// implements T.
+func providerAs[T any, S any](provider CacheProvider[S]) (T, bool) {
+	for {
+		if target, ok := provider.(T); ok {
+			return target, true
+		}
+		wrapper, ok := provider.(interface{ Unwrap() CacheProvider[S] })
+		if !ok {
+			var zero T
+
+			return zero, false
+		}
+		provider = wrapper.Unwrap()
+	}
+}
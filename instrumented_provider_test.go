@@ -0,0 +1,126 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type providerOperationRecord struct {
+	op    ProviderOperation
+	size  int
+	class ProviderErrorClass
+}
+
+type providerOperationMetricsProvider struct {
+	BaseMetricsProvider
+	mu      sync.Mutex
+	records []providerOperationRecord
+}
+
+func (m *providerOperationMetricsProvider) RecordProviderOperation(_ context.Context, op ProviderOperation, _ time.Duration, size int, class ProviderErrorClass) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, providerOperationRecord{op: op, size: size, class: class})
+}
+
+func (m *providerOperationMetricsProvider) snapshot() []providerOperationRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]providerOperationRecord(nil), m.records...)
+}
+
+func TestNewInstrumentedProvider_RecordsOperations(t *testing.T) {
+	t.Parallel()
+
+	metrics := &providerOperationMetricsProvider{}
+	provider := NewInstrumentedProvider[[]byte](&byteProvider{items: make(map[string][]byte)}, metrics)
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", []byte("abc"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, _, err := provider.Get(ctx, "key"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if err := provider.Delete(ctx, "key"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	want := []providerOperationRecord{
+		{op: ProviderOperationSet, size: 3},
+		{op: ProviderOperationGet, size: 3},
+		{op: ProviderOperationDelete},
+	}
+	got := metrics.snapshot()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestClassifyProviderError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want ProviderErrorClass
+	}{
+		{err: nil, want: ProviderErrorNone},
+		{err: fmt.Errorf("get: %w", context.Canceled), want: ProviderErrorCanceled},
+		{err: context.DeadlineExceeded, want: ProviderErrorTimeout},
+		{err: errors.New("connection refused"), want: ProviderErrorOther},
+	}
+	for _, tt := range tests {
+		if got := ClassifyProviderError(tt.err); got != tt.want {
+			t.Fatalf("expected %q for %v, got %q", tt.want, tt.err, got)
+		}
+	}
+}
+
+func TestNewCache_InstrumentsProviderWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		useMetrics bool
+		optOut     bool
+		wantOps    int
+	}{
+		{name: "without metrics", wantOps: 0},
+		{name: "with metrics", useMetrics: true, wantOps: 2},
+		{name: "opted out", useMetrics: true, optOut: true, wantOps: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			metrics := &providerOperationMetricsProvider{}
+			var opts []CacheOption[int, CacheObject[int]]
+			if tt.useMetrics {
+				opts = append(opts, WithMetricsProvider[int, CacheObject[int]](metrics))
+			}
+			if tt.optOut {
+				opts = append(opts, WithoutProviderInstrumentation[int, CacheObject[int]]())
+			}
+			cache := NewCache(NewMemoryCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{}, opts...)
+			ctx := context.Background()
+
+			if err := cache.Set(ctx, "key", CacheObject[int]{Value: 1, ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+				t.Fatalf("set: %v", err)
+			}
+			if _, _, err := cache.Get(ctx, "key"); err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			if got := len(metrics.snapshot()); got != tt.wantOps {
+				t.Fatalf("expected %d recorded operations, got %d", tt.wantOps, got)
+			}
+			if stats := cache.Stats(); stats.Entries != 1 {
+				t.Fatalf("expected sized provider to stay reachable, got %+v", stats)
+			}
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package crema
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultInvalidationConcurrency      = 16
+	defaultInvalidationProgressInterval = time.Second
+)
+
+// KeyDeleter deletes keys. Cache and CacheProvider implement it.
+type KeyDeleter interface {
+	// Delete removes key.
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	_ KeyDeleter = (Cache[any, any])(nil)
+	_ KeyDeleter = (CacheProvider[any])(nil)
+)
+
+// KeySource streams keys to an InvalidationJob, calling yield for each key
+// in a stable order until yield returns false. It returns the error that
+// stopped the stream, if any.
+type KeySource func(ctx context.Context, yield func(key string) bool) error
+
+// InvalidationCheckpoint persists how far an InvalidationJob got, so a
+// restarted job resumes instead of starting over.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type InvalidationCheckpoint interface {
+	// Load returns the saved offset, or zero when none was saved.
+	Load(ctx context.Context) (int64, error)
+	// Save records that the first offset keys of the source were processed.
+	Save(ctx context.Context, offset int64) error
+}
+
+// InvalidationProgress is a snapshot of a running InvalidationJob.
+type InvalidationProgress struct {
+	// Offset is the number of keys from the start of the source that were
+	// processed, including skipped keys; a resumed run skips that many.
+	Offset int64
+	// Skipped is the number of keys skipped because a checkpoint covered them.
+	Skipped int64
+	// Deleted is the number of keys deleted.
+	Deleted int64
+	// Failed is the number of keys whose delete failed.
+	Failed int64
+	// Elapsed is the time since the run started.
+	Elapsed time.Duration
+}
+
+// InvalidationJob deletes large numbers of keys streamed from a KeySource,
+// such as after an incident left bad entries behind, with bounded
+// concurrency, an optional rate limit, periodic progress reports and
+// checkpoints to resume from. The zero value is not usable; construct it
+// with NewInvalidationJob.
+type InvalidationJob struct {
+	deleter          KeyDeleter
+	concurrency      int
+	interval         time.Duration
+	progressInterval time.Duration
+	onProgress       func(InvalidationProgress)
+	onError          func(key string, err error)
+	checkpoint       InvalidationCheckpoint
+}
+
+// InvalidationJobOption configures an InvalidationJob.
+type InvalidationJobOption func(*InvalidationJob)
+
+// NewInvalidationJob constructs a job deleting keys through deleter.
+// Deleting through a Cache also clears request scopes and replicates the
+// deletes; deleting through a CacheProvider only touches the provider.
+func NewInvalidationJob(deleter KeyDeleter, opts ...InvalidationJobOption) *InvalidationJob {
+	job := &InvalidationJob{
+		deleter:          deleter,
+		concurrency:      defaultInvalidationConcurrency,
+		progressInterval: defaultInvalidationProgressInterval,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(job)
+	}
+
+	return job
+}
+
+// WithInvalidationConcurrency sets how many deletes run at once, 16 by
+// default. Non-positive values are ignored.
+func WithInvalidationConcurrency(n int) InvalidationJobOption {
+	return func(job *InvalidationJob) {
+		if n > 0 {
+			job.concurrency = n
+		}
+	}
+}
+
+// WithInvalidationRate caps deletes at perSecond. A non-positive rate
+// removes the cap.
+func WithInvalidationRate(perSecond float64) InvalidationJobOption {
+	return func(job *InvalidationJob) {
+		job.interval = 0
+		if perSecond > 0 {
+			job.interval = time.Duration(float64(time.Second) / perSecond)
+		}
+	}
+}
+
+// WithInvalidationProgress calls fn with a progress snapshot every interval
+// and once when the run ends. fn is called from the job's goroutines, one
+// call at a time. A non-positive interval keeps the one second default.
+func WithInvalidationProgress(interval time.Duration, fn func(InvalidationProgress)) InvalidationJobOption {
+	return func(job *InvalidationJob) {
+		if interval > 0 {
+			job.progressInterval = interval
+		}
+		job.onProgress = fn
+	}
+}
+
+// WithInvalidationErrorHandler calls fn for every key whose delete failed,
+// for example to collect keys to retry. Runs continue past failed deletes,
+// and checkpoints cover them. fn may be called concurrently.
+func WithInvalidationErrorHandler(fn func(key string, err error)) InvalidationJobOption {
+	return func(job *InvalidationJob) {
+		job.onError = fn
+	}
+}
+
+// WithInvalidationCheckpoint resumes runs from the offset saved in
+// checkpoint and saves the offset at every progress interval and when the
+// run ends, including when it is canceled. Resuming skips keys by position,
+// so it requires a source yielding keys in the same order every time, such
+// as a file.
+func WithInvalidationCheckpoint(checkpoint InvalidationCheckpoint) InvalidationJobOption {
+	return func(job *InvalidationJob) {
+		job.checkpoint = checkpoint
+	}
+}
+
+// invalidationRun tracks the state of one Run.
+type invalidationRun struct {
+	started time.Time
+	skipped atomic.Int64
+	deleted atomic.Int64
+	failed  atomic.Int64
+	mu      sync.Mutex
+	// offset is the lowest source position not processed yet.
+	offset int64
+	// done holds processed positions above offset.
+	done map[int64]struct{}
+}
+
+// complete marks the key at position i as processed.
+func (r *invalidationRun) complete(i int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done[i] = struct{}{}
+	for {
+		if _, ok := r.done[r.offset]; !ok {
+			return
+		}
+		delete(r.done, r.offset)
+		r.offset++
+	}
+}
+
+func (r *invalidationRun) progress() InvalidationProgress {
+	r.mu.Lock()
+	offset := r.offset
+	r.mu.Unlock()
+
+	return InvalidationProgress{
+		Offset:  offset,
+		Skipped: r.skipped.Load(),
+		Deleted: r.deleted.Load(),
+		Failed:  r.failed.Load(),
+		Elapsed: time.Since(r.started),
+	}
+}
+
+// Run deletes every key source yields and returns the final progress. Failed
+// deletes are counted and reported to the error handler without stopping the
+// run. It returns the error of the source, the checkpoint, or ctx.
+func (j *InvalidationJob) Run(ctx context.Context, source KeySource) (InvalidationProgress, error) {
+	var resume int64
+	if j.checkpoint != nil {
+		offset, err := j.checkpoint.Load(ctx)
+		if err != nil {
+			return InvalidationProgress{}, fmt.Errorf("load invalidation checkpoint: %w", err)
+		}
+		resume = max(offset, 0)
+	}
+	run := &invalidationRun{started: time.Now(), offset: resume, done: make(map[int64]struct{})}
+
+	reportDone := make(chan struct{})
+	reporterExited := make(chan struct{})
+	var reportErr error
+	go func() {
+		defer close(reporterExited)
+		ticker := time.NewTicker(j.progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-reportDone:
+				return
+			case <-ticker.C:
+				if err := j.report(ctx, run); err != nil && reportErr == nil {
+					reportErr = err
+				}
+			}
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		position int64
+		next     time.Time
+	)
+	slots := make(chan struct{}, j.concurrency)
+	sourceErr := source(ctx, func(key string) bool {
+		i := position
+		position++
+		if i < resume {
+			run.skipped.Add(1)
+
+			return true
+		}
+		if j.interval > 0 {
+			now := time.Now()
+			if next.Before(now) {
+				next = now
+			}
+			if wait := next.Sub(now); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+
+					return false
+				case <-timer.C:
+				}
+			}
+			next = next.Add(j.interval)
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case slots <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			if err := j.deleter.Delete(ctx, key); err != nil {
+				run.failed.Add(1)
+				if j.onError != nil {
+					j.onError(key, err)
+				}
+			} else {
+				run.deleted.Add(1)
+			}
+			run.complete(i)
+		}()
+
+		return true
+	})
+	wg.Wait()
+	close(reportDone)
+	<-reporterExited
+
+	if err := j.report(context.WithoutCancel(ctx), run); err != nil && reportErr == nil {
+		reportErr = err
+	}
+	err := errors.Join(sourceErr, reportErr)
+	if err == nil {
+		err = ctx.Err()
+	}
+
+	return run.progress(), err
+}
+
+// report saves the checkpoint and reports progress.
+func (j *InvalidationJob) report(ctx context.Context, run *invalidationRun) error {
+	progress := run.progress()
+	var err error
+	if j.checkpoint != nil {
+		if saveErr := j.checkpoint.Save(ctx, progress.Offset); saveErr != nil {
+			err = fmt.Errorf("save invalidation checkpoint: %w", saveErr)
+		}
+	}
+	if j.onProgress != nil {
+		j.onProgress(progress)
+	}
+
+	return err
+}
+
+// KeysFromReader returns a KeySource reading one key per line from r,
+// ignoring blank lines.
+func KeysFromReader(r io.Reader) KeySource {
+	return func(ctx context.Context, yield func(key string) bool) error {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			key := strings.TrimRight(scanner.Text(), "\r")
+			if key == "" {
+				continue
+			}
+			if !yield(key) {
+				return nil
+			}
+		}
+
+		return scanner.Err()
+	}
+}
+
+// KeysFromChannel returns a KeySource yielding keys received from ch until
+// it is closed.
+func KeysFromChannel(ch <-chan string) KeySource {
+	return func(ctx context.Context, yield func(key string) bool) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case key, ok := <-ch:
+				if !ok || !yield(key) {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// KeysFromScan returns a KeySource yielding the keys provider stores that
+// match pattern, as AdminProvider.Scan does. Scans of most providers do not
+// guarantee a stable order, so do not combine it with checkpoints.
+func KeysFromScan[S any](provider AdminProvider[S], pattern string) KeySource {
+	return func(ctx context.Context, yield func(key string) bool) error {
+		return provider.Scan(ctx, pattern, func(key string, _ S) bool {
+			return yield(key)
+		})
+	}
+}
+
+// FileInvalidationCheckpoint stores the offset of an InvalidationJob in a
+// file, replaced atomically on every save.
+type FileInvalidationCheckpoint struct {
+	path string
+}
+
+var _ InvalidationCheckpoint = (*FileInvalidationCheckpoint)(nil)
+
+// NewFileInvalidationCheckpoint constructs a checkpoint stored at path.
+func NewFileInvalidationCheckpoint(path string) *FileInvalidationCheckpoint {
+	return &FileInvalidationCheckpoint{path: path}
+}
+
+// Load reads the saved offset, returning zero when the file does not exist.
+func (f *FileInvalidationCheckpoint) Load(context.Context) (int64, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// Save writes offset to a temporary file and renames it over the checkpoint.
+func (f *FileInvalidationCheckpoint) Save(_ context.Context, offset int64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(strconv.FormatInt(offset, 10) + "\n"); err != nil {
+		tmp.Close()
+
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
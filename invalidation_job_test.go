@@ -0,0 +1,156 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type recordingDeleter struct {
+	mu      sync.Mutex
+	deleted []string
+	fail    map[string]bool
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (d *recordingDeleter) Delete(_ context.Context, key string) error {
+	n := d.running.Add(1)
+	defer d.running.Add(-1)
+	for {
+		peak := d.peak.Load()
+		if n <= peak || d.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.fail[key] {
+		return errors.New("boom")
+	}
+	d.deleted = append(d.deleted, key)
+
+	return nil
+}
+
+func TestInvalidationJob_DeletesWithBoundedConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var keys []string
+	for i := range 100 {
+		keys = append(keys, fmt.Sprintf("key:%d", i))
+	}
+	deleter := &recordingDeleter{fail: map[string]bool{"key:7": true}}
+	var failed []string
+	var reports atomic.Int32
+	job := NewInvalidationJob(deleter,
+		WithInvalidationConcurrency(4),
+		WithInvalidationProgress(time.Hour, func(InvalidationProgress) { reports.Add(1) }),
+		WithInvalidationErrorHandler(func(key string, _ error) { failed = append(failed, key) }),
+	)
+
+	progress, err := job.Run(context.Background(), KeysFromReader(strings.NewReader(strings.Join(keys, "\n")+"\n\n")))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if progress.Deleted != 99 || progress.Failed != 1 || progress.Offset != 100 {
+		t.Fatalf("expected 99 deleted, 1 failed at offset 100, got %+v", progress)
+	}
+	if len(failed) != 1 || failed[0] != "key:7" {
+		t.Fatalf("expected key:7 to fail, got %v", failed)
+	}
+	if peak := deleter.peak.Load(); peak > 4 {
+		t.Fatalf("expected at most 4 concurrent deletes, got %d", peak)
+	}
+	if got := reports.Load(); got != 1 {
+		t.Fatalf("expected a final progress report, got %d", got)
+	}
+}
+
+func TestInvalidationJob_ResumesFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	checkpoint := NewFileInvalidationCheckpoint(filepath.Join(t.TempDir(), "checkpoint"))
+	ch := make(chan string)
+	ctx, cancel := context.WithCancel(context.Background())
+	deleter := &recordingDeleter{}
+	job := NewInvalidationJob(deleter, WithInvalidationConcurrency(1), WithInvalidationCheckpoint(checkpoint))
+	go func() {
+		for _, key := range []string{"a", "b", "c"} {
+			ch <- key
+		}
+		for len(deleter.snapshot()) < 3 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+	progress, err := job.Run(ctx, KeysFromChannel(ch))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled, got %v", err)
+	}
+	if progress.Offset != 3 {
+		t.Fatalf("expected offset 3, got %+v", progress)
+	}
+	if offset, err := checkpoint.Load(context.Background()); err != nil || offset != 3 {
+		t.Fatalf("expected saved offset 3, got %d, %v", offset, err)
+	}
+
+	resumed := &recordingDeleter{}
+	progress, err = NewInvalidationJob(resumed, WithInvalidationCheckpoint(checkpoint)).
+		Run(context.Background(), KeysFromReader(strings.NewReader("a\nb\nc\nd\ne\n")))
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if progress.Skipped != 3 || progress.Deleted != 2 || progress.Offset != 5 {
+		t.Fatalf("expected 3 skipped and 2 deleted, got %+v", progress)
+	}
+	if got := strings.Join(resumed.snapshot(), ","); got != "d,e" && got != "e,d" {
+		t.Fatalf("expected d and e deleted, got %s", got)
+	}
+}
+
+func TestInvalidationJob_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	job := NewInvalidationJob(&recordingDeleter{}, WithInvalidationRate(200))
+	started := time.Now()
+	if _, err := job.Run(context.Background(), KeysFromReader(strings.NewReader("a\nb\nc\nd\ne\nf\n"))); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < 25*time.Millisecond {
+		t.Fatalf("expected 6 deletes at 200/s to take at least 25ms, got %v", elapsed)
+	}
+}
+
+func TestInvalidationJob_KeysFromScan(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	ctx := context.Background()
+	for _, key := range []string{"user:1", "user:2", "item:1"} {
+		if err := provider.Set(ctx, key, []byte("v"), time.Minute); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	progress, err := NewInvalidationJob(provider).Run(ctx, KeysFromScan[[]byte](provider, "user:*"))
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if progress.Deleted != 2 || provider.Len() != 1 {
+		t.Fatalf("expected 2 user keys deleted, got %+v with %d left", progress, provider.Len())
+	}
+}
+
+func (d *recordingDeleter) snapshot() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]string(nil), d.deleted...)
+}
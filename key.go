@@ -0,0 +1,190 @@
+package crema
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultKeyHashLength = 8
+
+// KeyCollision describes two distinct key component lists whose short hashes match.
+type KeyCollision struct {
+	// Hash is the shared short hash.
+	Hash string
+	// First is the canonical encoding recorded first for Hash.
+	First string
+	// Second is the conflicting canonical encoding.
+	Second string
+}
+
+// KeyHasherOption customizes a KeyHasher.
+type KeyHasherOption func(*KeyHasher)
+
+// KeyHasher builds cache keys from components with a canonical encoding:
+// every component is type-tagged and length-prefixed, so ("a:b", "c") and
+// ("a", "b:c") never produce the same key. A short FNV-1a hash of the
+// encoding is appended after '#' unless disabled.
+type KeyHasher struct {
+	_          noCopy
+	hashLength int
+	debug      bool
+	mu         sync.Mutex
+	reverse    map[string][]any
+	hashes     map[string]string
+	collisions []KeyCollision
+}
+
+var defaultKeyHasher = NewKeyHasher()
+
+// NewKeyHasher constructs a KeyHasher with an 8 character hash suffix.
+func NewKeyHasher(opts ...KeyHasherOption) *KeyHasher {
+	h := &KeyHasher{hashLength: defaultKeyHashLength}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(h)
+	}
+	if h.debug {
+		h.reverse = make(map[string][]any)
+		h.hashes = make(map[string]string)
+	}
+
+	return h
+}
+
+// WithKeyHashLength sets the number of hex characters of the appended hash,
+// up to 16. Zero disables the hash suffix.
+func WithKeyHashLength(length int) KeyHasherOption {
+	return func(h *KeyHasher) {
+		h.hashLength = min(max(length, 0), 16)
+	}
+}
+
+// WithKeyDebug records every built key with its components for Lookup and
+// tracks short hash collisions. It retains all keys and is meant for tests
+// and debugging, not production traffic.
+func WithKeyDebug() KeyHasherOption {
+	return func(h *KeyHasher) {
+		h.debug = true
+	}
+}
+
+// HashedKey builds a key from parts with the default KeyHasher.
+func HashedKey(parts ...any) string {
+	return defaultKeyHasher.Key(parts...)
+}
+
+// Key builds a key from parts. Supported component types are strings, byte
+// slices, booleans, integers, floats, time.Time, time.Duration, fmt.Stringer
+// and nil; other values are encoded with their %v representation.
+func (h *KeyHasher) Key(parts ...any) string {
+	var b strings.Builder
+	for _, part := range parts {
+		tag, value := canonicalKeyPart(part)
+		b.WriteByte(tag)
+		b.WriteString(strconv.Itoa(len(value)))
+		b.WriteByte(':')
+		b.WriteString(value)
+	}
+	canonical := b.String()
+	key := canonical
+	var hash string
+	if h.hashLength > 0 {
+		sum := fnv.New64a()
+		_, _ = sum.Write([]byte(canonical))
+		hash = hex.EncodeToString(sum.Sum(nil))[:h.hashLength]
+		key = canonical + "#" + hash
+	}
+	if h.debug {
+		h.record(key, canonical, hash, parts)
+	}
+
+	return key
+}
+
+// Lookup returns the components a key was built from. It requires WithKeyDebug.
+func (h *KeyHasher) Lookup(key string) ([]any, bool) {
+	if !h.debug {
+		return nil, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	parts, ok := h.reverse[key]
+
+	return parts, ok
+}
+
+// Collisions returns the short hash collisions observed so far. It requires WithKeyDebug.
+func (h *KeyHasher) Collisions() []KeyCollision {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]KeyCollision(nil), h.collisions...)
+}
+
+func (h *KeyHasher) record(key string, canonical string, hash string, parts []any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.reverse[key]; !ok {
+		h.reverse[key] = append([]any(nil), parts...)
+	}
+	if hash == "" {
+		return
+	}
+	if first, ok := h.hashes[hash]; !ok {
+		h.hashes[hash] = canonical
+	} else if first != canonical {
+		h.collisions = append(h.collisions, KeyCollision{Hash: hash, First: first, Second: canonical})
+	}
+}
+
+func canonicalKeyPart(part any) (byte, string) {
+	switch v := part.(type) {
+	case nil:
+		return 'n', ""
+	case string:
+		return 's', v
+	case []byte:
+		return 'b', string(v)
+	case bool:
+		return 't', strconv.FormatBool(v)
+	case int:
+		return 'i', strconv.FormatInt(int64(v), 10)
+	case int8:
+		return 'i', strconv.FormatInt(int64(v), 10)
+	case int16:
+		return 'i', strconv.FormatInt(int64(v), 10)
+	case int32:
+		return 'i', strconv.FormatInt(int64(v), 10)
+	case int64:
+		return 'i', strconv.FormatInt(v, 10)
+	case uint:
+		return 'u', strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return 'u', strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return 'u', strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return 'u', strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return 'u', strconv.FormatUint(v, 10)
+	case float32:
+		return 'f', strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return 'f', strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return 'T', v.UTC().Format(time.RFC3339Nano)
+	case time.Duration:
+		return 'D', strconv.FormatInt(int64(v), 10)
+	case fmt.Stringer:
+		return 'S', v.String()
+	default:
+		return 'v', fmt.Sprintf("%v", v)
+	}
+}
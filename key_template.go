@@ -0,0 +1,243 @@
+package crema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidKeyTemplate is returned by NewKeyTemplate for malformed templates.
+var ErrInvalidKeyTemplate = errors.New("invalid key template")
+
+// KeyTemplate is a compiled cache key layout such as "user:{id}:profile".
+// Placeholders are written as {name} with names made of letters, digits and
+// '_', and are filled in order by Key. A KeyTemplate is immutable and safe
+// for concurrent use.
+type KeyTemplate struct {
+	template string
+	// literals has one more element than params: literals[i] precedes params[i].
+	literals []string
+	params   []string
+	size     int
+}
+
+// NewKeyTemplate compiles template, rejecting unbalanced braces, empty or
+// invalid placeholder names, duplicate names and adjacent placeholders, which
+// could not be told apart by Parse.
+func NewKeyTemplate(template string) (*KeyTemplate, error) {
+	t := &KeyTemplate{template: template}
+	rest := template
+	for {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			t.literals = append(t.literals, rest)
+
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("%w: unexpected '}' in %q", ErrInvalidKeyTemplate, template)
+		}
+		end := strings.IndexAny(rest[open+1:], "{}")
+		if end < 0 || rest[open+1+end] == '{' {
+			return nil, fmt.Errorf("%w: unclosed '{' in %q", ErrInvalidKeyTemplate, template)
+		}
+		name := rest[open+1 : open+1+end]
+		if !validKeyTemplateParam(name) {
+			return nil, fmt.Errorf("%w: invalid placeholder %q in %q", ErrInvalidKeyTemplate, name, template)
+		}
+		for _, param := range t.params {
+			if param == name {
+				return nil, fmt.Errorf("%w: duplicate placeholder %q in %q", ErrInvalidKeyTemplate, name, template)
+			}
+		}
+		if open == 0 && len(t.params) > 0 {
+			return nil, fmt.Errorf("%w: adjacent placeholders in %q", ErrInvalidKeyTemplate, template)
+		}
+		t.literals = append(t.literals, rest[:open])
+		t.params = append(t.params, name)
+		rest = rest[open+end+2:]
+	}
+	for _, literal := range t.literals {
+		t.size += len(literal)
+	}
+
+	return t, nil
+}
+
+// MustKeyTemplate is like NewKeyTemplate but panics on invalid templates. It
+// is meant for package-level variables, so mistakes fail at startup.
+func MustKeyTemplate(template string) *KeyTemplate {
+	t, err := NewKeyTemplate(template)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func validKeyTemplateParam(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the template source.
+func (t *KeyTemplate) String() string {
+	return t.template
+}
+
+// Params returns the placeholder names in order.
+func (t *KeyTemplate) Params() []string {
+	return append([]string(nil), t.params...)
+}
+
+// Key substitutes values for the placeholders in order. Values are formatted
+// like KeyHasher components, with strings, integers and other common types
+// converted without fmt. A value followed by a literal has '%' and the first
+// byte of that literal percent-encoded, so that values containing the
+// separator, such as "x:y" in "{a}:{b}", cannot collide with other values and
+// Parse returns them unchanged. It panics when the number of values does not
+// match the number of placeholders.
+func (t *KeyTemplate) Key(values ...any) string {
+	if len(values) != len(t.params) {
+		panic(fmt.Sprintf("crema: key template %q expects %d values, got %d", t.template, len(t.params), len(values)))
+	}
+	if len(values) == 0 {
+		return t.literals[0]
+	}
+
+	formatted := make([]string, len(values))
+	size := t.size
+	for i, value := range values {
+		_, formatted[i] = canonicalKeyPart(value)
+		if next := t.literals[i+1]; next != "" {
+			formatted[i] = escapeKeyTemplateValue(formatted[i], next[0])
+		}
+		size += len(formatted[i])
+	}
+	var b strings.Builder
+	b.Grow(size)
+	for i, value := range formatted {
+		b.WriteString(t.literals[i])
+		b.WriteString(value)
+	}
+	b.WriteString(t.literals[len(formatted)])
+
+	return b.String()
+}
+
+// Parse extracts the placeholder values from a key built by the template,
+// undoing the escaping applied by Key. Each value ends at the first
+// occurrence of the literal that follows it.
+func (t *KeyTemplate) Parse(key string) ([]string, bool) {
+	rest, ok := strings.CutPrefix(key, t.literals[0])
+	if !ok {
+		return nil, false
+	}
+	values := make([]string, 0, len(t.params))
+	for i := range t.params {
+		next := t.literals[i+1]
+		if next == "" {
+			values = append(values, rest)
+			rest = ""
+
+			continue
+		}
+		idx := strings.Index(rest, next)
+		if idx < 0 {
+			return nil, false
+		}
+		value, ok := unescapeKeyTemplateValue(rest[:idx])
+		if !ok {
+			return nil, false
+		}
+		values = append(values, value)
+		rest = rest[idx+len(next):]
+	}
+	if rest != "" {
+		return nil, false
+	}
+
+	return values, true
+}
+
+// escapeKeyTemplateValue percent-encodes '%' and stop in value.
+func escapeKeyTemplateValue(value string, stop byte) string {
+	if !strings.ContainsAny(value, "%"+string(stop)) {
+		return value
+	}
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	b.Grow(len(value) + 4)
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '%' || c == stop {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0x0f])
+
+			continue
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// unescapeKeyTemplateValue decodes the percent-encoding of
+// escapeKeyTemplateValue, reporting false for malformed escapes.
+func unescapeKeyTemplateValue(value string) (string, bool) {
+	if !strings.Contains(value, "%") {
+		return value, true
+	}
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 0; i < len(value); i++ {
+		if value[i] != '%' {
+			b.WriteByte(value[i])
+
+			continue
+		}
+		if i+2 >= len(value) {
+			return "", false
+		}
+		decoded, err := strconv.ParseUint(value[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", false
+		}
+		b.WriteByte(byte(decoded))
+		i += 2
+	}
+
+	return b.String(), true
+}
+
+// Pattern returns a glob pattern matching every key of the template, with
+// placeholders replaced by '*', for AdminProvider.Scan, PreloadFromProvider
+// and MatchKeyPattern.
+func (t *KeyTemplate) Pattern() string {
+	return strings.Join(t.literals, "*")
+}
+
+// Namespace returns a template whose keys are prefixed with namespace and a
+// ':' separator, matching the keys of a DerivedCache with that namespace.
+func (t *KeyTemplate) Namespace(namespace string) *KeyTemplate {
+	ns := &KeyTemplate{
+		template: namespace + ":" + t.template,
+		literals: append([]string(nil), t.literals...),
+		params:   t.params,
+		size:     t.size + len(namespace) + 1,
+	}
+	ns.literals[0] = namespace + ":" + ns.literals[0]
+
+	return ns
+}
@@ -0,0 +1,109 @@
+package crema
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNewKeyTemplate_Validation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		template string
+		valid    bool
+	}{
+		{template: "user:{id}:profile", valid: true},
+		{template: "static", valid: true},
+		{template: "{tenant}:user:{id}", valid: true},
+		{template: "user:{id", valid: false},
+		{template: "user:id}", valid: false},
+		{template: "user:{}", valid: false},
+		{template: "user:{a-b}", valid: false},
+		{template: "user:{id}:{id}", valid: false},
+		{template: "user:{a{b}}", valid: false},
+		{template: "user:{a}{b}", valid: false},
+	}
+	for _, tt := range tests {
+		_, err := NewKeyTemplate(tt.template)
+		if tt.valid && err != nil {
+			t.Fatalf("expected %q to be valid, got %v", tt.template, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidKeyTemplate) {
+			t.Fatalf("expected ErrInvalidKeyTemplate for %q, got %v", tt.template, err)
+		}
+	}
+}
+
+func TestKeyTemplate_KeyAndParse(t *testing.T) {
+	t.Parallel()
+
+	tmpl := MustKeyTemplate("{tenant}:user:{id}:profile")
+	key := tmpl.Key("acme", 42)
+	if key != "acme:user:42:profile" {
+		t.Fatalf("unexpected key %q", key)
+	}
+	values, ok := tmpl.Parse(key)
+	if !ok || !slices.Equal(values, []string{"acme", "42"}) {
+		t.Fatalf("expected [acme 42], got %v, %v", values, ok)
+	}
+	if _, ok := tmpl.Parse("acme:item:42:profile"); ok {
+		t.Fatal("expected foreign key not to parse")
+	}
+	if got := tmpl.Params(); !slices.Equal(got, []string{"tenant", "id"}) {
+		t.Fatalf("unexpected params %v", got)
+	}
+	if got := tmpl.Pattern(); got != "*:user:*:profile" || !MatchKeyPattern(got, key) {
+		t.Fatalf("unexpected pattern %q", got)
+	}
+	if got := MustKeyTemplate("static").Key(); got != "static" {
+		t.Fatalf("unexpected static key %q", got)
+	}
+}
+
+func TestKeyTemplate_KeyEscapesValues(t *testing.T) {
+	t.Parallel()
+
+	tmpl := MustKeyTemplate("{a}:{b}")
+	first := tmpl.Key("x:y", "z")
+	second := tmpl.Key("x", "y:z")
+	if first == second {
+		t.Fatalf("expected distinct keys, got %q for both", first)
+	}
+	for _, values := range [][]string{{"x:y", "z"}, {"x", "y:z"}, {"50%3A", "%"}} {
+		key := tmpl.Key(values[0], values[1])
+		got, ok := tmpl.Parse(key)
+		if !ok || !slices.Equal(got, values) {
+			t.Fatalf("expected %v from %q, got %v, %v", values, key, got, ok)
+		}
+	}
+	if _, ok := tmpl.Parse("x%3:z"); ok {
+		t.Fatal("expected malformed escape not to parse")
+	}
+}
+
+func TestKeyTemplate_Namespace(t *testing.T) {
+	t.Parallel()
+
+	tmpl := MustKeyTemplate("user:{id}").Namespace("html")
+	if got := tmpl.Key(1); got != "html:user:1" {
+		t.Fatalf("unexpected namespaced key %q", got)
+	}
+	if got := tmpl.String(); got != "html:user:{id}" {
+		t.Fatalf("unexpected template %q", got)
+	}
+	if values, ok := tmpl.Parse("html:user:1"); !ok || values[0] != "1" {
+		t.Fatalf("expected to parse namespaced key, got %v, %v", values, ok)
+	}
+}
+
+func TestKeyTemplate_KeyPanicsOnArity(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for missing values")
+		}
+	}()
+	MustKeyTemplate("user:{id}").Key()
+}
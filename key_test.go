@@ -0,0 +1,71 @@
+package crema
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHashedKey_CanonicalEncoding(t *testing.T) {
+	t.Parallel()
+
+	hasher := NewKeyHasher(WithKeyHashLength(0))
+	cases := []struct {
+		parts []any
+		want  string
+	}{
+		{[]any{"user", 42}, "s4:useri2:42"},
+		{[]any{"a:b", "c"}, "s3:a:bs1:c"},
+		{[]any{"a", "b:c"}, "s1:as3:b:c"},
+		{[]any{uint8(7), true, nil}, "u1:7t4:truen0:"},
+		{[]any{1.5, time.Second}, "f3:1.5D10:1000000000"},
+		{[]any{time.Date(2024, 6, 1, 10, 5, 0, 0, time.UTC)}, "T20:2024-06-01T10:05:00Z"},
+		{[]any{[]byte("raw"), struct{ A int }{1}}, "b3:rawv3:{1}"},
+	}
+	for _, tc := range cases {
+		if got := hasher.Key(tc.parts...); got != tc.want {
+			t.Errorf("Key(%v) = %q, want %q", tc.parts, got, tc.want)
+		}
+	}
+	if hasher.Key(42) == hasher.Key("42") {
+		t.Fatal("expected type tags to distinguish int and string components")
+	}
+}
+
+func TestHashedKey_AppendsShortHash(t *testing.T) {
+	t.Parallel()
+
+	key := HashedKey("user", 42)
+	if len(key) != len("s4:useri2:42")+1+defaultKeyHashLength || key[:13] != "s4:useri2:42#" {
+		t.Fatalf("unexpected hashed key %q", key)
+	}
+	if key != HashedKey("user", 42) {
+		t.Fatal("expected hashed key to be deterministic")
+	}
+}
+
+func TestKeyHasher_DebugLookupAndCollisions(t *testing.T) {
+	t.Parallel()
+
+	hasher := NewKeyHasher(WithKeyDebug(), WithKeyHashLength(1))
+	key := hasher.Key("user", 42)
+	parts, ok := hasher.Lookup(key)
+	if !ok || !slices.Equal(parts, []any{"user", 42}) {
+		t.Fatalf("expected reverse mapping, got %v, %v", parts, ok)
+	}
+
+	// a single hex character hash collides within 17 distinct keys
+	for i := range 17 {
+		hasher.Key("item", i)
+	}
+	collisions := hasher.Collisions()
+	if len(collisions) == 0 {
+		t.Fatal("expected short hash collisions to be recorded")
+	}
+	if collisions[0].First == collisions[0].Second {
+		t.Fatalf("expected distinct encodings in collision, got %+v", collisions[0])
+	}
+	if _, ok := NewKeyHasher().Lookup(key); ok {
+		t.Fatal("expected lookup to require debug mode")
+	}
+}
@@ -0,0 +1,154 @@
+package crema
+
+import (
+	"context"
+	"sync"
+)
+
+// LoadPriority classifies loads for the load concurrency limiter.
+type LoadPriority int
+
+const (
+	// LoadPriorityHigh is used for interactive loads and is the default.
+	LoadPriorityHigh LoadPriority = iota
+	// LoadPriorityLow is used for background work such as refreshes. Low
+	// priority loads only start when no high priority load is waiting.
+	LoadPriorityLow
+)
+
+type loadPriorityKey struct{}
+
+// WithLoadPriority returns a context whose loads are scheduled with priority
+// when a load concurrency limit is configured with WithMaxConcurrentLoads.
+func WithLoadPriority(ctx context.Context, priority LoadPriority) context.Context {
+	return context.WithValue(ctx, loadPriorityKey{}, priority)
+}
+
+// LoadPriorityFromContext returns the load priority carried by ctx, or
+// LoadPriorityHigh when none is set.
+func LoadPriorityFromContext(ctx context.Context) LoadPriority {
+	if priority, ok := ctx.Value(loadPriorityKey{}).(LoadPriority); ok {
+		return priority
+	}
+
+	return LoadPriorityHigh
+}
+
+// loadLimiter caps the number of concurrently running loaders. Waiting loads
+// are granted slots from the high priority queue before the low priority one,
+// in FIFO order within each queue. A non-positive limit admits every load.
+type loadLimiter struct {
+	_      noCopy
+	mu     sync.Mutex
+	limit  int
+	active int
+	queues [2][]chan struct{}
+}
+
+func newLoadLimiter(limit int) *loadLimiter {
+	return &loadLimiter{limit: limit}
+}
+
+// WithMaxConcurrentLoads caps the number of loaders running at once across all
+// keys. Loads beyond the cap wait for a slot, with high priority loads served
+// first (see WithLoadPriority). A non-positive limit disables the cap.
+func WithMaxConcurrentLoads[V any, S any](limit int) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.updateSettings(func(s *Settings) {
+			s.MaxConcurrentLoads = limit
+		})
+	}
+}
+
+func (l *loadLimiter) acquire(ctx context.Context, priority LoadPriority) error {
+	if priority != LoadPriorityLow {
+		priority = LoadPriorityHigh
+	}
+
+	l.mu.Lock()
+	if l.admitsLocked() && len(l.queues[LoadPriorityHigh]) == 0 &&
+		(priority == LoadPriorityHigh || len(l.queues[LoadPriorityLow]) == 0) {
+		l.active++
+		l.mu.Unlock()
+
+		return nil
+	}
+	ch := make(chan struct{})
+	l.queues[priority] = append(l.queues[priority], ch)
+	l.mu.Unlock()
+
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		select {
+		case <-ch:
+			// the slot was granted concurrently; hand it to the next waiter
+			l.releaseLocked()
+		default:
+			queue := l.queues[priority]
+			for i, waiter := range queue {
+				if waiter == ch {
+					l.queues[priority] = append(queue[:i], queue[i+1:]...)
+
+					break
+				}
+			}
+		}
+
+		return ctx.Err()
+	}
+}
+
+// setLimit changes the limit and grants slots to waiting loads the new limit
+// admits.
+func (l *loadLimiter) setLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	for l.admitsLocked() && (len(l.queues[LoadPriorityHigh]) > 0 || len(l.queues[LoadPriorityLow]) > 0) {
+		l.active++
+		l.releaseLocked()
+	}
+}
+
+func (l *loadLimiter) admitsLocked() bool {
+	return l.limit <= 0 || l.active < l.limit
+}
+
+func (l *loadLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.releaseLocked()
+}
+
+func (l *loadLimiter) releaseLocked() {
+	for priority := range l.queues {
+		if queue := l.queues[priority]; len(queue) > 0 {
+			l.queues[priority] = queue[1:]
+			close(queue[0])
+
+			return
+		}
+	}
+	l.active--
+}
+
+// limitLoader wraps loader so that it runs within the load concurrency limit
+// applying to key.
+func (c *cacheImpl[V, S]) limitLoader(key string, loader CacheLoadFunc[V]) CacheLoadFunc[V] {
+	limiter := c.loadLimiterFor(key)
+
+	return func(ctx context.Context) (V, error) {
+		if err := limiter.acquire(ctx, LoadPriorityFromContext(ctx)); err != nil {
+			var zero V
+
+			return zero, err
+		}
+		defer limiter.release()
+
+		return loader(ctx)
+	}
+}
@@ -0,0 +1,149 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLoadLimiter_HighPriorityFirst(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLoadLimiter(1)
+	ctx := context.Background()
+	if err := limiter.acquire(ctx, LoadPriorityHigh); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	var (
+		mu    sync.Mutex
+		order []LoadPriority
+		wg    sync.WaitGroup
+	)
+	start := func(priority LoadPriority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.acquire(ctx, priority); err != nil {
+				t.Errorf("acquire: %v", err)
+
+				return
+			}
+			mu.Lock()
+			order = append(order, priority)
+			mu.Unlock()
+			limiter.release()
+		}()
+	}
+	waitQueued := func(priority LoadPriority, n int) {
+		deadline := time.After(time.Second)
+		for {
+			limiter.mu.Lock()
+			queued := len(limiter.queues[priority])
+			limiter.mu.Unlock()
+			if queued == n {
+				return
+			}
+			select {
+			case <-deadline:
+				t.Fatalf("timed out waiting for %d queued loads", n)
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+
+	start(LoadPriorityLow)
+	waitQueued(LoadPriorityLow, 1)
+	start(LoadPriorityHigh)
+	waitQueued(LoadPriorityHigh, 1)
+
+	limiter.release()
+	wg.Wait()
+
+	if len(order) != 2 || order[0] != LoadPriorityHigh || order[1] != LoadPriorityLow {
+		t.Fatalf("expected high priority load to run first, got %v", order)
+	}
+	if limiter.active != 0 {
+		t.Fatalf("expected no active loads, got %d", limiter.active)
+	}
+}
+
+func TestLoadLimiter_CancelWhileWaiting(t *testing.T) {
+	t.Parallel()
+
+	limiter := newLoadLimiter(1)
+	if err := limiter.acquire(context.Background(), LoadPriorityHigh); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.acquire(ctx, LoadPriorityLow); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if len(limiter.queues[LoadPriorityLow]) != 0 {
+		t.Fatal("expected cancelled waiter to be removed")
+	}
+	limiter.release()
+	if limiter.active != 0 {
+		t.Fatalf("expected no active loads, got %d", limiter.active)
+	}
+}
+
+func TestWithMaxConcurrentLoads_LimitsLoaders(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithMaxConcurrentLoads[int, CacheObject[int]](2))
+
+	var (
+		mu      sync.Mutex
+		running int
+		peak    int
+		wg      sync.WaitGroup
+	)
+	loader := func(context.Context) (int, error) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return 1, nil
+	}
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := context.Background()
+			if i%2 == 0 {
+				ctx = WithLoadPriority(ctx, LoadPriorityLow)
+			}
+			if _, err := cache.GetOrLoad(ctx, string(rune('a'+i)), time.Minute, loader); err != nil {
+				t.Errorf("get or load: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Fatalf("expected at most 2 concurrent loads, got %d", peak)
+	}
+}
+
+func TestLoadPriorityFromContext_DefaultsToHigh(t *testing.T) {
+	t.Parallel()
+
+	if got := LoadPriorityFromContext(context.Background()); got != LoadPriorityHigh {
+		t.Fatalf("expected default high priority, got %v", got)
+	}
+	ctx := WithLoadPriority(context.Background(), LoadPriorityLow)
+	if got := LoadPriorityFromContext(context.WithoutCancel(ctx)); got != LoadPriorityLow {
+		t.Fatalf("expected low priority to survive detaching, got %v", got)
+	}
+}
@@ -0,0 +1,26 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// GetOrLoadWithArg is GetOrLoad with a loader that receives arg, so hot paths
+// can share one loader function instead of building a closure over their
+// arguments on every call. The call that invokes loader is only built when a
+// load actually runs, keeping cache hits free of closure allocations. Caches
+// not built by NewCache fall back to GetOrLoad.
+func GetOrLoadWithArg[V any, S any, A any](ctx context.Context, cache Cache[V, S], key string, ttl time.Duration, arg A, loader func(ctx context.Context, arg A) (V, error)) (V, error) {
+	c, ok := cache.(*cacheImpl[V, S])
+	if !ok {
+		return cache.GetOrLoad(ctx, key, ttl, func(ctx context.Context) (V, error) {
+			return loader(ctx, arg)
+		})
+	}
+
+	return c.getOrLoad(ctx, key, ttl, func(*CacheObject[V]) CacheLoadFunc[V] {
+		return func(ctx context.Context) (V, error) {
+			return loader(ctx, arg)
+		}
+	})
+}
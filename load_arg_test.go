@@ -0,0 +1,51 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type loadArg struct {
+	id     int
+	region string
+}
+
+func loadByArg(_ context.Context, arg loadArg) (int, error) {
+	return arg.id * 10, nil
+}
+
+func TestGetOrLoadWithArg_PassesArgument(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	v, err := GetOrLoadWithArg[int, CacheObject[int]](context.Background(), cache, "key", time.Hour, loadArg{id: 4, region: "jp"}, loadByArg)
+	if err != nil || v != 40 {
+		t.Fatalf("expected 40, got %d, %v", v, err)
+	}
+	v, err = GetOrLoadWithArg[int, CacheObject[int]](context.Background(), cache, "key", time.Hour, loadArg{id: 5, region: "jp"}, loadByArg)
+	if err != nil || v != 40 {
+		t.Fatalf("expected cached 40, got %d, %v", v, err)
+	}
+}
+
+func TestGetOrLoadWithArg_HitAllocations(t *testing.T) {
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	ctx := context.Background()
+	arg := loadArg{id: 1, region: "jp"}
+	if _, err := GetOrLoadWithArg[int, CacheObject[int]](ctx, cache, "key", time.Hour, arg, loadByArg); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	withArg := testing.AllocsPerRun(100, func() {
+		_, _ = GetOrLoadWithArg[int, CacheObject[int]](ctx, cache, "key", time.Hour, arg, loadByArg)
+	})
+	withClosure := testing.AllocsPerRun(100, func() {
+		_, _ = cache.GetOrLoad(ctx, "key", time.Hour, func(ctx context.Context) (int, error) {
+			return loadByArg(ctx, arg)
+		})
+	})
+	if withArg > withClosure {
+		t.Fatalf("expected no more allocations than a closure, got %v > %v", withArg, withClosure)
+	}
+}
@@ -0,0 +1,158 @@
+package crema
+
+import (
+	"slices"
+	"time"
+)
+
+// maxLastLoadsPerShard bounds the load records kept for LastLoadInfo.
+const maxLastLoadsPerShard = 128
+
+// LoadRole tells whether a caller ran a singleflight load or joined one.
+type LoadRole int
+
+const (
+	// LoadRoleLeader is the caller that started the load.
+	LoadRoleLeader LoadRole = iota
+	// LoadRoleFollower is a caller that joined a load already in flight.
+	LoadRoleFollower
+)
+
+// String returns the role name for metric labels.
+func (r LoadRole) String() string {
+	if r == LoadRoleFollower {
+		return "follower"
+	}
+
+	return "leader"
+}
+
+// LoadOutcome is how a caller's wait for a singleflight load ended.
+type LoadOutcome int
+
+const (
+	// LoadOutcomeSuccess means the caller received a loaded value.
+	LoadOutcomeSuccess LoadOutcome = iota
+	// LoadOutcomeError means the loader returned an error.
+	LoadOutcomeError
+	// LoadOutcomeCanceled means the caller context ended before the load finished.
+	LoadOutcomeCanceled
+)
+
+// String returns the outcome name for metric labels.
+func (o LoadOutcome) String() string {
+	switch o {
+	case LoadOutcomeError:
+		return "error"
+	case LoadOutcomeCanceled:
+		return "canceled"
+	default:
+		return "success"
+	}
+}
+
+// LoadInfo describes the most recent finished singleflight load of a key.
+type LoadInfo struct {
+	// Key is the loaded cache key.
+	Key string
+	// StartedAt is when the leader started the load.
+	StartedAt time.Time
+	// Duration is how long the loader ran.
+	Duration time.Duration
+	// Followers is the number of callers that joined the load.
+	Followers int
+	// CanceledFollowers is the number of followers that stopped waiting
+	// before the load finished.
+	CanceledFollowers int
+	// Err is the error returned by the loader, if any.
+	Err error
+}
+
+// ServedFollowers returns the number of followers that received the result.
+func (i LoadInfo) ServedFollowers() int {
+	return i.Followers - i.CanceledFollowers
+}
+
+// LastLoadInfo returns the most recent finished singleflight load of key.
+// Records are kept for a bounded number of recent keys, and none are kept
+// with WithDirectLoader.
+func (c *cacheImpl[V, S]) LastLoadInfo(key string) (LoadInfo, bool) {
+	loader, ok := c.internalLoader.(*singleflightLoader[V])
+	if !ok {
+		return LoadInfo{}, false
+	}
+
+	return loader.lastLoadInfo(c.loadKey(key))
+}
+
+func (l *singleflightLoader[V]) lastLoadInfo(key string) (LoadInfo, bool) {
+	shard := l.shardFor(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	info, ok := shard.lastLoads[key]
+
+	return info, ok
+}
+
+// recordLastLoadLocked stores info for key, evicting an arbitrary record when
+// the shard is full. The shard lock must be held.
+func (s *singleflightShard[V]) recordLastLoadLocked(info LoadInfo) {
+	if _, ok := s.lastLoads[info.Key]; !ok && len(s.lastLoads) >= maxLastLoadsPerShard {
+		for key := range s.lastLoads {
+			delete(s.lastLoads, key)
+
+			break
+		}
+	}
+	s.lastLoads[info.Key] = info
+}
+
+// InflightInfo describes a singleflight load that is still running.
+type InflightInfo struct {
+	// Key is the singleflight key being loaded.
+	Key string
+	// StartedAt is when the leader started the load.
+	StartedAt time.Time
+	// Age is how long the load has been running.
+	Age time.Duration
+	// Waiters is the number of callers still waiting, including the leader.
+	Waiters int
+}
+
+// InflightLoads returns the singleflight loads that are running right now,
+// oldest first, to find keys stuck in long loads. It returns nil with
+// WithDirectLoader.
+func (c *cacheImpl[V, S]) InflightLoads() []InflightInfo {
+	loader, ok := c.internalLoader.(*singleflightLoader[V])
+	if !ok {
+		return nil
+	}
+
+	return loader.inflightLoads()
+}
+
+func (l *singleflightLoader[V]) inflightLoads() []InflightInfo {
+	now := time.Now()
+	var infos []InflightInfo
+	for i := range l.shards {
+		shard := &l.shards[i]
+		shard.mu.Lock()
+		for key, inf := range shard.inflight {
+			if inf.done || inf.aborted {
+				continue
+			}
+			infos = append(infos, InflightInfo{
+				Key:       key,
+				StartedAt: inf.started,
+				Age:       now.Sub(inf.started),
+				Waiters:   inf.refs,
+			})
+		}
+		shard.mu.Unlock()
+	}
+	slices.SortFunc(infos, func(a, b InflightInfo) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+
+	return infos
+}
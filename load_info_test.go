@@ -0,0 +1,231 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type loadWaitMetricsProvider struct {
+	BaseMetricsProvider
+	mu        sync.Mutex
+	followers []int
+	waits     map[LoadRole][]LoadOutcome
+}
+
+func (m *loadWaitMetricsProvider) RecordLoadFollowers(_ context.Context, followers int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.followers = append(m.followers, followers)
+}
+
+func (m *loadWaitMetricsProvider) RecordLoadWait(_ context.Context, role LoadRole, outcome LoadOutcome, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.waits == nil {
+		m.waits = make(map[LoadRole][]LoadOutcome)
+	}
+	m.waits[role] = append(m.waits[role], outcome)
+}
+
+func TestCache_LastLoadInfoCountsFollowers(t *testing.T) {
+	t.Parallel()
+
+	metrics := &loadWaitMetricsProvider{}
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithMetricsProvider[int, CacheObject[int]](metrics))
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	loader := impl.internalLoader.(*singleflightLoader[int])
+
+	if _, ok := cache.LastLoadInfo("key"); ok {
+		t.Fatal("expected no load info before the first load")
+	}
+
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		select {
+		case <-release:
+			return 1, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+	waitRefs := func(n int) {
+		deadline := time.After(time.Second)
+		for {
+			shard := loader.shardFor("key")
+			shard.mu.Lock()
+			inf, ok := shard.inflight["key"]
+			refs := 0
+			if ok {
+				refs = inf.refs
+			}
+			shard.mu.Unlock()
+			if refs == n {
+				return
+			}
+			select {
+			case <-deadline:
+				t.Fatalf("timed out waiting for %d waiters", n)
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.GetOrLoad(context.Background(), "key", time.Minute, load); err != nil {
+				t.Errorf("load: %v", err)
+			}
+		}()
+		waitRefs(i + 1)
+	}
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	canceledDone := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad(canceledCtx, "key", time.Minute, load)
+		canceledDone <- err
+	}()
+	waitRefs(4)
+	cancel()
+	if err := <-canceledDone; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	info, ok := cache.LastLoadInfo("key")
+	if !ok {
+		t.Fatal("expected load info")
+	}
+	if info.Followers != 3 || info.CanceledFollowers != 1 || info.ServedFollowers() != 2 {
+		t.Fatalf("unexpected follower counts %+v", info)
+	}
+	if info.Err != nil {
+		t.Fatalf("expected no load error, got %v", info.Err)
+	}
+
+	// the follower count is recorded after waiters are released
+	deadline := time.After(time.Second)
+	for {
+		metrics.mu.Lock()
+		recorded := len(metrics.followers)
+		metrics.mu.Unlock()
+		if recorded > 0 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for follower metrics")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.followers) != 1 || metrics.followers[0] != 3 {
+		t.Fatalf("expected one follower record of 3, got %v", metrics.followers)
+	}
+	if got := metrics.waits[LoadRoleLeader]; len(got) != 1 || got[0] != LoadOutcomeSuccess {
+		t.Fatalf("unexpected leader outcomes %v", got)
+	}
+	var canceled, succeeded int
+	for _, outcome := range metrics.waits[LoadRoleFollower] {
+		switch outcome {
+		case LoadOutcomeCanceled:
+			canceled++
+		case LoadOutcomeSuccess:
+			succeeded++
+		}
+	}
+	if canceled != 1 || succeeded != 2 {
+		t.Fatalf("expected 1 canceled and 2 served followers, got %d and %d", canceled, succeeded)
+	}
+}
+
+func TestCache_LastLoadInfoDirectLoader(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithDirectLoader[int, CacheObject[int]]())
+	if _, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+		return 1, nil
+	}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, ok := cache.LastLoadInfo("key"); ok {
+		t.Fatal("expected no load info with direct loader")
+	}
+}
+
+func TestSingleflightShard_RecordLastLoadBounded(t *testing.T) {
+	t.Parallel()
+
+	shard := &singleflightShard[int]{lastLoads: make(map[string]LoadInfo)}
+	for i := range maxLastLoadsPerShard + 10 {
+		shard.recordLastLoadLocked(LoadInfo{Key: string(rune('a' + i))})
+	}
+	if len(shard.lastLoads) != maxLastLoadsPerShard {
+		t.Fatalf("expected %d records, got %d", maxLastLoadsPerShard, len(shard.lastLoads))
+	}
+}
+
+func TestCache_InflightLoads(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	release := make(chan struct{})
+	load := func(context.Context) (int, error) {
+		<-release
+
+		return 1, nil
+	}
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"slow", "slow", "other"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = cache.GetOrLoad(context.Background(), key, time.Minute, load)
+		}()
+	}
+
+	deadline := time.After(time.Second)
+	var infos []InflightInfo
+	for {
+		infos = cache.InflightLoads()
+		waiters := 0
+		for _, info := range infos {
+			waiters += info.Waiters
+		}
+		if len(infos) == 2 && waiters == 3 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for inflight loads, got %+v", infos)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	for _, info := range infos {
+		want := map[string]int{"slow": 2, "other": 1}[info.Key]
+		if info.Waiters != want || info.Age < 0 {
+			t.Fatalf("unexpected inflight info %+v", info)
+		}
+	}
+	if infos[0].StartedAt.After(infos[1].StartedAt) {
+		t.Fatalf("expected oldest load first, got %+v", infos)
+	}
+
+	close(release)
+	wg.Wait()
+	if infos := cache.InflightLoads(); len(infos) != 0 {
+		t.Fatalf("expected no inflight loads after completion, got %+v", infos)
+	}
+}
@@ -0,0 +1,77 @@
+package crema
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithLoadKeyFunc_SharesLoadsAcrossVariants(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithLoadKeyFunc[string, CacheObject[string]](func(key string) string {
+			base, _, _ := strings.Cut(key, "@")
+
+			return base
+		}),
+	)
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		loads.Add(1)
+		<-release
+
+		return "doc", nil
+	}
+
+	var wg sync.WaitGroup
+	keys := []string{"doc:1@alice", "doc:1@bob", "doc:1@carol"}
+	for _, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := cache.GetOrLoad(context.Background(), key, time.Minute, load); err != nil || v != "doc" {
+				t.Errorf("expected doc for %s, got %q, %v", key, v, err)
+			}
+		}()
+	}
+	loader := cache.(*cacheImpl[string, CacheObject[string]]).internalLoader.(*singleflightLoader[string])
+	deadline := time.After(time.Second)
+	for {
+		shard := loader.shardFor("doc:1")
+		shard.mu.Lock()
+		refs := 0
+		if inf, ok := shard.inflight["doc:1"]; ok {
+			refs = inf.refs
+		}
+		shard.mu.Unlock()
+		if refs == len(keys) {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timed out waiting for %d waiters", len(keys))
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	if got := loads.Load(); got != 1 {
+		t.Fatalf("expected 1 shared load, got %d", got)
+	}
+	for _, key := range keys {
+		if got := provider.items[key].Value; got != "doc" {
+			t.Fatalf("expected %s to be stored under its own key, got %q", key, got)
+		}
+	}
+	if info, ok := cache.LastLoadInfo("doc:1@alice"); !ok || info.Key != "doc:1" {
+		t.Fatalf("expected last load of the shared key, got %+v, %v", info, ok)
+	}
+}
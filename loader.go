@@ -2,8 +2,11 @@ package crema
 
 import (
 	"context"
+	"errors"
 	"hash/maphash"
 	"runtime"
+	"runtime/pprof"
+	"strings"
 	"sync"
 	"time"
 )
@@ -19,19 +22,34 @@ var (
 	shardCount  = max(min(runtime.GOMAXPROCS(0)*shardMultiplier, maxShardCount), minShardCount)
 )
 
+// loadFinishedFunc is called after every loader execution with the load
+// context, the load key, the finished load, its outcome and the number of
+// callers that received the result.
+type loadFinishedFunc func(ctx context.Context, key string, info LoadInfo, outcome LoadOutcome, served int)
+
 type internalLoader[V any] interface {
 	load(ctx context.Context, key string, loader CacheLoadFunc[V]) (V, bool, error)
 }
 
 type inflight[V any] struct {
-	ctx    context.Context
-	cancel context.CancelFunc
-	refs   int
-	val    V
-	err    error
-	doneCh chan struct{}
-	done   bool
-	pooled bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+	endTrace func()
+	started  time.Time
+	refs     int
+	joins    int
+	canceled int
+	val      V
+	err      error
+	doneCh   chan struct{}
+	abortCh  chan struct{}
+	// readyCh is closed once the leader has stored the traced load context,
+	// when a trace linker is configured.
+	readyCh  chan struct{}
+	loaderID string
+	done     bool
+	aborted  bool
+	pooled   bool
 }
 
 var _ internalLoader[any] = (*singleflightLoader[any])(nil)
@@ -39,15 +57,40 @@ var _ internalLoader[any] = (*singleflightLoader[any])(nil)
 type singleflightLoader[V any] struct {
 	_              noCopy
 	shards         []singleflightShard[V]
-	inflightPool   sync.Pool
+	inflightPool   *inflightPool[V]
 	metrics        MetricsProvider
 	maxLoadTimeout time.Duration
+	// maxLoadTimeoutFunc overrides maxLoadTimeout per key when set.
+	maxLoadTimeoutFunc func(key string) time.Duration
+	traceLinker        LoadTraceLinker
+	// synchronousLeader runs the loader on the leader's goroutine when no
+	// timeout applies.
+	synchronousLeader bool
+	// pprofLabels labels background loads with name and the key group.
+	pprofLabels bool
+	name        string
+	// followerTimeoutRetry lets followers retry once after the load they
+	// joined hit its max load timeout.
+	followerTimeoutRetry bool
+	// onFinish is called after each loader execution when set.
+	onFinish loadFinishedFunc
+	// detectLoaderMismatch reports followers whose loader identity differs
+	// from the leader's.
+	detectLoaderMismatch bool
+}
+
+// loadTarget holds the shard and pprof labels of a key, precomputed by a
+// BoundKey. Nil fields are computed on demand.
+type loadTarget[V any] struct {
+	shard  *singleflightShard[V]
+	labels *pprof.LabelSet
 }
 
 type singleflightShard[V any] struct {
-	_        noCopy
-	mu       sync.Mutex
-	inflight map[string]*inflight[V]
+	_         noCopy
+	mu        sync.Mutex
+	inflight  map[string]*inflight[V]
+	lastLoads map[string]LoadInfo
 }
 
 func (l *singleflightLoader[V]) shardFor(key string) *singleflightShard[V] {
@@ -58,13 +101,14 @@ func newSingleflightLoader[V any](metrics MetricsProvider, maxLoadTimeout time.D
 	shards := make([]singleflightShard[V], shardCount)
 	for i := range shards {
 		shards[i].inflight = make(map[string]*inflight[V])
+		shards[i].lastLoads = make(map[string]LoadInfo)
 	}
 
 	return &singleflightLoader[V]{
 		shards:         shards,
 		metrics:        metrics,
 		maxLoadTimeout: maxLoadTimeout,
-		inflightPool:   sync.Pool{New: func() any { return &inflight[V]{} }},
+		inflightPool:   &inflightPool[V]{},
 	}
 }
 
@@ -72,76 +116,175 @@ func hashKey(key string) uint64 {
 	return maphash.String(mapHashSeed, key)
 }
 
-func (l *singleflightLoader[V]) newInflight(ctx context.Context) *inflight[V] {
-	ctx = context.WithoutCancel(ctx)
+func (l *singleflightLoader[V]) loadTimeout(key string) time.Duration {
+	if l.maxLoadTimeoutFunc != nil {
+		return l.maxLoadTimeoutFunc(key)
+	}
+
+	return l.maxLoadTimeout
+}
+
+// newInflight prepares a load with the given timeout. It runs with the shard
+// mutex held, so it must not call into user code; the leader starts the trace
+// with startTrace once the mutex is released. hit reports whether the
+// inflight was reused from the pool.
+func (l *singleflightLoader[V]) newInflight(callerCtx context.Context, timeout time.Duration) (*inflight[V], bool) {
+	ctx := context.WithoutCancel(callerCtx)
 	var cancel context.CancelFunc
-	if l.maxLoadTimeout > 0 {
-		ctx, cancel = context.WithTimeout(ctx, l.maxLoadTimeout)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
 	} else {
 		ctx, cancel = context.WithCancel(ctx)
 	}
 
 	var val V
-	inf := l.inflightPool.Get().(*inflight[V])
+	inf, hit := l.inflightPool.get()
 	inf.ctx = ctx
 	inf.cancel = cancel
+	inf.endTrace = nil
+	inf.readyCh = nil
+	if l.traceLinker != nil {
+		inf.readyCh = make(chan struct{})
+	}
+	inf.started = time.Now()
 	inf.refs = 1
+	inf.joins = 0
+	inf.canceled = 0
 	inf.val = val
 	inf.err = nil
 	inf.doneCh = make(chan struct{})
+	inf.abortCh = make(chan struct{})
+	inf.loaderID = ""
+	if l.detectLoaderMismatch {
+		inf.loaderID, _ = LoaderIDFromContext(callerCtx)
+	}
 	inf.done = false
+	inf.aborted = false
 	inf.pooled = false
 
-	return inf
+	return inf, hit
 }
 
-func (l *singleflightLoader[V]) acquireInflight(ctx context.Context, key string) (*inflight[V], bool, *singleflightShard[V]) {
-	shard := l.shardFor(key)
-	shard.mu.Lock()
-	defer shard.mu.Unlock()
+// startTrace lets the trace linker wrap the load context of inf, which the
+// calling leader has just created, and releases the followers waiting to link
+// to it.
+func (l *singleflightLoader[V]) startTrace(callerCtx context.Context, key string, inf *inflight[V]) {
+	if l.traceLinker == nil {
+		return
+	}
+	inf.ctx, inf.endTrace = l.traceLinker.StartLoad(inf.ctx, callerCtx, key)
+	close(inf.readyCh)
+}
 
-	if inf, ok := shard.inflight[key]; ok {
+// acquireInflight joins or starts the inflight load for key in shard, or in
+// the shard of key when shard is nil. timeout is the max load timeout of key,
+// resolved by the caller outside the shard mutex.
+func (l *singleflightLoader[V]) acquireInflight(ctx context.Context, key string, timeout time.Duration, shard *singleflightShard[V]) (*inflight[V], bool, *singleflightShard[V]) {
+	if shard == nil {
+		shard = l.shardFor(key)
+	}
+	shard.mu.Lock()
+	inf, ok := shard.inflight[key]
+	if ok {
 		select {
 		case <-inf.doneCh:
-			newInf := l.newInflight(ctx)
-			shard.inflight[key] = newInf
-
-			return newInf, true, shard
+			ok = false
 		default:
 		}
+	}
+	if ok {
 		inf.refs++
+		inf.joins++
+		mismatch := false
+		if l.detectLoaderMismatch {
+			id, _ := LoaderIDFromContext(ctx)
+			mismatch = id != inf.loaderID
+		}
+		shard.mu.Unlock()
 
-		return inf, false, shard
-	} else {
-		newInf := l.newInflight(ctx)
-		shard.inflight[key] = newInf
+		if mismatch {
+			l.metrics.RecordLoaderMismatch(ctx)
+		}
 
-		return newInf, true, shard
+		return inf, false, shard
 	}
+	inf, hit := l.newInflight(ctx, timeout)
+	shard.inflight[key] = inf
+	shard.mu.Unlock()
+
+	l.metrics.RecordInflightPoolGet(ctx, hit)
+	l.startTrace(ctx, key, inf)
+
+	return inf, true, shard
 }
 
-func (l *singleflightLoader[V]) finishInflight(inf *inflight[V], shard *singleflightShard[V], v V, err error) {
-	var refs int
+func (l *singleflightLoader[V]) finishInflight(key string, inf *inflight[V], shard *singleflightShard[V], v V, err error) {
+	if l.onFinish != nil {
+		l.reportFinish(key, inf, shard, err)
+	}
+	var refs, joins int
 	var ctx context.Context
 	shard.mu.Lock()
 
 	refs = inf.refs
+	joins = inf.joins
 	ctx = inf.ctx
+	shard.recordLastLoadLocked(LoadInfo{
+		Key:               key,
+		StartedAt:         inf.started,
+		Duration:          time.Since(inf.started),
+		Followers:         inf.joins,
+		CanceledFollowers: inf.canceled,
+		Err:               err,
+	})
 	inf.val = v
 	inf.err = err
 	inf.done = true
 	close(inf.doneCh)
 	if inf.refs <= 0 && !inf.pooled {
 		inf.pooled = true
-		l.inflightPool.Put(inf)
+		l.inflightPool.put(inf)
 	}
 	shard.mu.Unlock()
 
 	l.metrics.RecordLoadConcurrency(ctx, refs)
+	l.metrics.RecordLoadFollowers(ctx, joins)
 }
 
-func (l *singleflightLoader[V]) releaseInflight(key string, inf *inflight[V], shard *singleflightShard[V]) {
+// reportFinish calls onFinish for the load of inf before its waiters are
+// released, so the record is visible once they return.
+func (l *singleflightLoader[V]) reportFinish(key string, inf *inflight[V], shard *singleflightShard[V], err error) {
 	shard.mu.Lock()
+	refs := inf.refs
+	aborted := inf.aborted
+	ctx := inf.ctx
+	info := LoadInfo{
+		Key:               key,
+		StartedAt:         inf.started,
+		Duration:          time.Since(inf.started),
+		Followers:         inf.joins,
+		CanceledFollowers: inf.canceled,
+		Err:               err,
+	}
+	shard.mu.Unlock()
+
+	switch {
+	case aborted:
+		l.onFinish(ctx, key, info, LoadOutcomeCanceled, 0)
+	case err != nil:
+		l.onFinish(ctx, key, info, LoadOutcomeError, refs)
+	default:
+		l.onFinish(ctx, key, info, LoadOutcomeSuccess, refs)
+	}
+}
+
+// releaseInflight drops a reference to inf. canceled marks a follower that
+// stopped waiting before the load finished.
+func (l *singleflightLoader[V]) releaseInflight(key string, inf *inflight[V], shard *singleflightShard[V], canceled bool) {
+	shard.mu.Lock()
+	if canceled && !inf.done {
+		inf.canceled++
+	}
 	inf.refs--
 	if inf.refs <= 0 {
 		if current, ok := shard.inflight[key]; ok && current == inf {
@@ -150,50 +293,180 @@ func (l *singleflightLoader[V]) releaseInflight(key string, inf *inflight[V], sh
 		inf.cancel()
 		if inf.done && !inf.pooled {
 			inf.pooled = true
-			l.inflightPool.Put(inf)
+			l.inflightPool.put(inf)
 		}
 	}
 	shard.mu.Unlock()
 }
 
 func (l *singleflightLoader[V]) load(ctx context.Context, key string, loader CacheLoadFunc[V]) (V, bool, error) {
-	inf, leader, shard := l.acquireInflight(ctx, key)
-	if leader {
-		go func() {
-			l.metrics.RecordLoad(ctx)
+	return l.loadTarget(ctx, key, loadTarget[V]{}, loader)
+}
 
-			v, err := loader(inf.ctx)
-			l.finishInflight(inf, shard, v, err)
-		}()
+// loadTarget is load with the parts of target precomputed by a BoundKey.
+func (l *singleflightLoader[V]) loadTarget(ctx context.Context, key string, target loadTarget[V], loader CacheLoadFunc[V]) (V, bool, error) {
+	v, leader, timedOut, err := l.attempt(ctx, key, target, loader)
+	if timedOut && !leader && l.followerTimeoutRetry && ctx.Err() == nil {
+		v, leader, _, err = l.attempt(ctx, key, target, loader)
 	}
 
-	select {
-	case <-ctx.Done():
-		l.releaseInflight(key, inf, shard)
-		var zero V
+	return v, leader, err
+}
 
-		return zero, leader, ctx.Err()
-	case <-inf.doneCh:
+// attempt joins or leads one singleflight load for key. timedOut reports
+// that the load failed because it hit its max load timeout.
+func (l *singleflightLoader[V]) attempt(ctx context.Context, key string, target loadTarget[V], loader CacheLoadFunc[V]) (V, bool, bool, error) {
+	waitStart := time.Now()
+	timeout := l.loadTimeout(key)
+	inf, leader, shard := l.acquireInflight(ctx, key, timeout, target.shard)
+	role := LoadRoleFollower
+	synchronous := false
+	if leader {
+		role = LoadRoleLeader
+		synchronous = l.synchronousLeader && timeout <= 0
+		if synchronous {
+			l.runLoad(ctx, inf.ctx, key, inf, shard, loader)
+		} else {
+			go l.runBackgroundLoad(ctx, key, inf, shard, target.labels, loader)
+		}
+	} else if l.traceLinker != nil {
+		select {
+		case <-inf.readyCh:
+			l.traceLinker.Link(inf.ctx, ctx)
+		case <-ctx.Done():
+		}
+	}
+
+	if synchronous {
+		// The load has finished unless CancelLoad aborted it first.
+		select {
+		case <-inf.abortCh:
+			l.releaseInflight(key, inf, shard, false)
+			l.metrics.RecordLoadWait(ctx, role, LoadOutcomeCanceled, time.Since(waitStart))
+			var zero V
+
+			return zero, leader, false, ErrLoadCanceled
+		default:
+		}
+	} else {
+		select {
+		case <-ctx.Done():
+			l.releaseInflight(key, inf, shard, !leader)
+			l.metrics.RecordLoadWait(ctx, role, LoadOutcomeCanceled, time.Since(waitStart))
+			var zero V
+
+			return zero, leader, false, ctx.Err()
+		case <-inf.abortCh:
+			l.releaseInflight(key, inf, shard, !leader)
+			l.metrics.RecordLoadWait(ctx, role, LoadOutcomeCanceled, time.Since(waitStart))
+			var zero V
+
+			return zero, leader, false, ErrLoadCanceled
+		case <-inf.doneCh:
+		}
 	}
 	v := inf.val
 	err := inf.err
-	l.releaseInflight(key, inf, shard)
+	timedOut := errors.Is(err, context.DeadlineExceeded) && errors.Is(inf.ctx.Err(), context.DeadlineExceeded)
+	l.releaseInflight(key, inf, shard, false)
 
 	if err != nil {
+		l.metrics.RecordLoadWait(ctx, role, LoadOutcomeError, time.Since(waitStart))
 		var zero V
 
-		return zero, leader, err
+		return zero, leader, timedOut, err
 	}
+	l.metrics.RecordLoadWait(ctx, role, LoadOutcomeSuccess, time.Since(waitStart))
 
-	return v, leader, nil
+	return v, leader, false, nil
 }
 
-type directLoader[V any] struct{}
+// runLoad executes loader with loadCtx, derived from the inflight context,
+// and publishes the result to inf.
+func (l *singleflightLoader[V]) runLoad(ctx context.Context, loadCtx context.Context, key string, inf *inflight[V], shard *singleflightShard[V], loader CacheLoadFunc[V]) {
+	l.metrics.RecordLoad(ctx)
+
+	v, err := loader(loadCtx)
+	if inf.endTrace != nil {
+		inf.endTrace()
+	}
+	l.finishInflight(key, inf, shard, v, err)
+}
+
+// runBackgroundLoad is runLoad on a spawned goroutine, labeled for CPU
+// profiles and goroutine dumps when pprof labels are enabled.
+func (l *singleflightLoader[V]) runBackgroundLoad(ctx context.Context, key string, inf *inflight[V], shard *singleflightShard[V], labels *pprof.LabelSet, loader CacheLoadFunc[V]) {
+	if !l.pprofLabels {
+		l.runLoad(ctx, inf.ctx, key, inf, shard, loader)
+
+		return
+	}
+	if labels == nil {
+		computed := l.loadLabels(key)
+		labels = &computed
+	}
+	pprof.Do(inf.ctx, *labels, func(loadCtx context.Context) {
+		l.runLoad(ctx, loadCtx, key, inf, shard, loader)
+	})
+}
+
+// loadLabels returns the pprof labels for a background load of key.
+func (l *singleflightLoader[V]) loadLabels(key string) pprof.LabelSet {
+	group, _, _ := strings.Cut(key, ":")
+
+	return pprof.Labels("crema_cache", l.name, "crema_key_group", group, "crema_op", "load")
+}
+
+// cancelLoad aborts the unfinished inflight load for key: its context is
+// cancelled, waiting callers return ErrLoadCanceled, and the next load starts
+// anew. It reports whether a load was aborted.
+func (l *singleflightLoader[V]) cancelLoad(key string) bool {
+	shard := l.shardFor(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	return l.abortLocked(shard, key)
+}
+
+// abortLocked implements cancelLoad with shard.mu held.
+func (l *singleflightLoader[V]) abortLocked(shard *singleflightShard[V], key string) bool {
+	inf, ok := shard.inflight[key]
+	if !ok || inf.done || inf.aborted {
+		return false
+	}
+	delete(shard.inflight, key)
+	inf.aborted = true
+	close(inf.abortCh)
+	inf.cancel()
+
+	return true
+}
+
+// forget detaches the inflight load for key so that the next load starts anew.
+func (l *singleflightLoader[V]) forget(key string) {
+	shard := l.shardFor(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	delete(shard.inflight, key)
+}
+
+type directLoader[V any] struct {
+	// onFinish is called after each loader execution when set.
+	onFinish loadFinishedFunc
+}
 
 var _ internalLoader[any] = directLoader[any]{}
 
-func (directLoader[V]) load(ctx context.Context, key string, loader CacheLoadFunc[V]) (V, bool, error) {
+func (d directLoader[V]) load(ctx context.Context, key string, loader CacheLoadFunc[V]) (V, bool, error) {
+	started := time.Now()
 	v, err := loader(ctx)
+	if d.onFinish != nil {
+		outcome := LoadOutcomeSuccess
+		if err != nil {
+			outcome = LoadOutcomeError
+		}
+		d.onFinish(ctx, key, LoadInfo{Key: key, StartedAt: started, Duration: time.Since(started), Err: err}, outcome, 1)
+	}
 	if err != nil {
 		var zero V
 
@@ -0,0 +1,76 @@
+package crema
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+)
+
+// LoaderIdentityMode selects how WithLoaderIdentity treats concurrent loads
+// of one key by different loaders.
+type LoaderIdentityMode int
+
+const (
+	// LoaderIdentityDetect keeps deduplicating loads by key and reports each
+	// caller joining a load started by a different loader through
+	// MetricsProvider.RecordLoaderMismatch.
+	LoaderIdentityDetect LoaderIdentityMode = iota + 1
+	// LoaderIdentitySeparate deduplicates loads by key and loader identity,
+	// so different loaders of one key never share results.
+	LoaderIdentitySeparate
+)
+
+type loaderIDKey struct{}
+
+// WithLoaderID returns a context whose GetOrLoad calls identify their loader
+// as id when WithLoaderIdentity is enabled. Without it, a loader is
+// identified by its function, so every closure created by one function
+// literal has the same identity.
+func WithLoaderID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, loaderIDKey{}, id)
+}
+
+// LoaderIDFromContext returns the loader identity set by WithLoaderID.
+func LoaderIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(loaderIDKey{}).(string)
+
+	return id, ok
+}
+
+// WithLoaderIdentity guards singleflight against two different loaders used
+// with the same key, which otherwise silently receive each other's results.
+// Loaders are identified by WithLoaderID or, without it, by their function;
+// loaders wrapped by helpers such as GetOrLoadWithArg share the helper's
+// identity and need WithLoaderID to be told apart. mode selects whether
+// mismatches are only reported or kept from sharing loads; CancelLoad and
+// LastLoadInfo do not see loads kept apart by LoaderIdentitySeparate. Other
+// values disable it.
+func WithLoaderIdentity[V any, S any](mode LoaderIdentityMode) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if mode != LoaderIdentityDetect && mode != LoaderIdentitySeparate {
+			mode = 0
+		}
+		c.loaderIdentity = mode
+		if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+			loader.detectLoaderMismatch = mode == LoaderIdentityDetect
+		}
+	}
+}
+
+// identifyLoader returns ctx carrying the identity of loader and the
+// singleflight key to load loadKey under.
+func (c *cacheImpl[V, S]) identifyLoader(ctx context.Context, loadKey string, loader CacheLoadFunc[V]) (context.Context, string) {
+	if c.loaderIdentity == 0 {
+		return ctx, loadKey
+	}
+	id, ok := LoaderIDFromContext(ctx)
+	if !ok {
+		id = "func:" + strconv.FormatUint(uint64(reflect.ValueOf(loader).Pointer()), 16)
+		ctx = WithLoaderID(ctx, id)
+	}
+	if c.loaderIdentity == LoaderIdentitySeparate {
+		loadKey += "\x00loader=" + id
+	}
+
+	return ctx, loadKey
+}
@@ -0,0 +1,143 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type loaderMismatchMetricsProvider struct {
+	BaseMetricsProvider
+	mismatches atomic.Int32
+}
+
+func (m *loaderMismatchMetricsProvider) RecordLoaderMismatch(context.Context) {
+	m.mismatches.Add(1)
+}
+
+// loadWithConcurrentCaller starts a load of key with leaderLoader, has a
+// second caller load it with followerLoader once the first load is running,
+// and returns the value the second caller received.
+func loadWithConcurrentCaller(t *testing.T, cache Cache[int, CacheObject[int]], followerCtx context.Context, leaderLoader, followerLoader CacheLoadFunc[int]) int {
+	t.Helper()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		_, _ = cache.GetOrLoad(context.Background(), "key", time.Minute, func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return leaderLoader(ctx)
+		})
+	}()
+	<-started
+	followerDone := make(chan int, 1)
+	go func() {
+		v, err := cache.GetOrLoad(followerCtx, "key", time.Minute, followerLoader)
+		if err != nil {
+			t.Errorf("follower: %v", err)
+		}
+		followerDone <- v
+	}()
+	// release the first load once the second caller joined it or finished
+	for {
+		waiters := 0
+		for _, info := range cache.InflightLoads() {
+			waiters += info.Waiters
+		}
+		if waiters >= 2 {
+			break
+		}
+		select {
+		case v := <-followerDone:
+			close(release)
+			<-leaderDone
+
+			return v
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(release)
+	<-leaderDone
+
+	return <-followerDone
+}
+
+func TestWithLoaderIdentity_DetectReportsMismatch(t *testing.T) {
+	t.Parallel()
+
+	metrics := &loaderMismatchMetricsProvider{}
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithLoaderIdentity[int, CacheObject[int]](LoaderIdentityDetect),
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+	)
+	got := loadWithConcurrentCaller(t, cache, WithLoaderID(context.Background(), "other"),
+		func(context.Context) (int, error) { return 1, nil },
+		func(context.Context) (int, error) { return 2, nil },
+	)
+	if got != 1 {
+		t.Fatalf("expected the shared value 1, got %d", got)
+	}
+	if n := metrics.mismatches.Load(); n != 1 {
+		t.Fatalf("expected 1 mismatch, got %d", n)
+	}
+}
+
+func TestWithLoaderIdentity_SeparateKeepsLoadersApart(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithLoaderIdentity[int, CacheObject[int]](LoaderIdentitySeparate),
+	)
+	got := loadWithConcurrentCaller(t, cache, context.Background(),
+		func(context.Context) (int, error) { return 1, nil },
+		func(context.Context) (int, error) { return 2, nil },
+	)
+	if got != 2 {
+		t.Fatalf("expected the follower's own value 2, got %d", got)
+	}
+}
+
+func TestWithLoaderIdentity_SameLoaderShares(t *testing.T) {
+	t.Parallel()
+
+	metrics := &loaderMismatchMetricsProvider{}
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithLoaderIdentity[int, CacheObject[int]](LoaderIdentityDetect),
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+	)
+	ctx := WithLoaderID(context.Background(), "users")
+	id, ok := LoaderIDFromContext(ctx)
+	if !ok || id != "users" {
+		t.Fatalf("expected loader id users, got %q", id)
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan int, 2)
+	for range 2 {
+		go func() {
+			v, _ := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+				close(started)
+				<-release
+
+				return 1, nil
+			})
+			done <- v
+		}()
+	}
+	<-started
+	for len(cache.InflightLoads()) == 0 || cache.InflightLoads()[0].Waiters < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	if a, b := <-done, <-done; a != 1 || b != 1 {
+		t.Fatalf("expected both callers to share 1, got %d and %d", a, b)
+	}
+	if n := metrics.mismatches.Load(); n != 0 {
+		t.Fatalf("expected no mismatches, got %d", n)
+	}
+}
@@ -286,15 +286,15 @@ func TestSingleflightLoader_AcquireAfterDoneReplacesInflight(t *testing.T) {
 
 	loaderImpl := newSingleflightLoader[int](NoopMetricsProvider{}, 0)
 	ctx := context.Background()
-	inf := loaderImpl.newInflight(ctx)
+	inf, _ := loaderImpl.newInflight(ctx, 0)
 	shard := loaderImpl.shardFor("key")
 	shard.mu.Lock()
 	shard.inflight["key"] = inf
 	shard.mu.Unlock()
 
-	loaderImpl.finishInflight(inf, shard, 10, nil)
+	loaderImpl.finishInflight("key", inf, shard, 10, nil)
 
-	newInf, leader, _ := loaderImpl.acquireInflight(ctx, "key")
+	newInf, leader, _ := loaderImpl.acquireInflight(ctx, "key", 0, nil)
 	if !leader {
 		t.Fatalf("expected leader=true, got false")
 	}
@@ -309,8 +309,8 @@ func TestSingleflightLoader_AcquireAfterDoneReplacesInflight(t *testing.T) {
 		t.Fatal("expected inflight map to be replaced with new instance")
 	}
 
-	loaderImpl.releaseInflight("key", newInf, shard)
-	loaderImpl.releaseInflight("key", inf, shard)
+	loaderImpl.releaseInflight("key", newInf, shard, false)
+	loaderImpl.releaseInflight("key", inf, shard, false)
 }
 
 func TestSingleflightLoader_PoolPutOnlyAfterDone(t *testing.T) {
@@ -319,13 +319,13 @@ func TestSingleflightLoader_PoolPutOnlyAfterDone(t *testing.T) {
 	loaderImpl := newSingleflightLoader[int](NoopMetricsProvider{}, 0)
 	ctx := context.Background()
 
-	inf := loaderImpl.newInflight(ctx)
+	inf, _ := loaderImpl.newInflight(ctx, 0)
 	shard := loaderImpl.shardFor("key")
 	shard.mu.Lock()
 	shard.inflight["key"] = inf
 	shard.mu.Unlock()
 
-	loaderImpl.releaseInflight("key", inf, shard)
+	loaderImpl.releaseInflight("key", inf, shard, false)
 	if inf.refs != 0 {
 		t.Fatalf("expected refs=0 after release, got %d", inf.refs)
 	}
@@ -336,7 +336,7 @@ func TestSingleflightLoader_PoolPutOnlyAfterDone(t *testing.T) {
 		t.Fatal("expected pooled=false before finish")
 	}
 
-	loaderImpl.finishInflight(inf, shard, 10, nil)
+	loaderImpl.finishInflight("key", inf, shard, 10, nil)
 	if !inf.done {
 		t.Fatal("expected done=true after finish")
 	}
@@ -344,17 +344,17 @@ func TestSingleflightLoader_PoolPutOnlyAfterDone(t *testing.T) {
 		t.Fatal("expected pooled=true after finish with refs=0")
 	}
 
-	loaderImpl.releaseInflight("key", inf, shard)
+	loaderImpl.releaseInflight("key", inf, shard, false)
 	if !inf.pooled {
 		t.Fatal("expected pooled to remain true after extra release")
 	}
 
-	inf2 := loaderImpl.newInflight(ctx)
+	inf2, _ := loaderImpl.newInflight(ctx, 0)
 	shard.mu.Lock()
 	shard.inflight["key2"] = inf2
 	shard.mu.Unlock()
 
-	loaderImpl.finishInflight(inf2, shard, 20, nil)
+	loaderImpl.finishInflight("key2", inf2, shard, 20, nil)
 	if !inf2.done {
 		t.Fatal("expected done=true after finish")
 	}
@@ -362,7 +362,7 @@ func TestSingleflightLoader_PoolPutOnlyAfterDone(t *testing.T) {
 		t.Fatal("expected pooled=false before final release")
 	}
 
-	loaderImpl.releaseInflight("key2", inf2, shard)
+	loaderImpl.releaseInflight("key2", inf2, shard, false)
 	if !inf2.pooled {
 		t.Fatal("expected pooled=true after release with done=true")
 	}
@@ -504,3 +504,84 @@ func TestDirectLoader_LoadUsesContext(t *testing.T) {
 		t.Fatalf("expected value \"ok\", got %q", got)
 	}
 }
+
+func TestSingleflightLoader_MaxLoadTimeoutFunc(t *testing.T) {
+	t.Parallel()
+
+	loaderImpl := newSingleflightLoader[int](NoopMetricsProvider{}, time.Hour)
+	loaderImpl.maxLoadTimeoutFunc = func(key string) time.Duration {
+		if key == "fast" {
+			return 10 * time.Millisecond
+		}
+
+		return 0
+	}
+	loader := func(ctx context.Context) (int, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return 1, nil
+		}
+		<-ctx.Done()
+
+		return 0, ctx.Err()
+	}
+
+	if _, _, err := loaderImpl.load(context.Background(), "fast", loader); err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded for fast key, got %v", err)
+	}
+	got, _, err := loaderImpl.load(context.Background(), "slow", loader)
+	if err != nil {
+		t.Fatalf("expected no error for key without timeout, got %v", err)
+	}
+	if got != 1 {
+		t.Fatalf("expected value 1, got %d", got)
+	}
+}
+
+type lockProbeTraceLinker struct {
+	shard  *singleflightShard[int]
+	locked *atomic.Bool
+}
+
+func (l lockProbeTraceLinker) StartLoad(loadCtx context.Context, _ context.Context, _ string) (context.Context, func()) {
+	l.probe()
+
+	return loadCtx, func() {}
+}
+
+func (l lockProbeTraceLinker) Link(context.Context, context.Context) {}
+
+func (l lockProbeTraceLinker) probe() {
+	if !l.shard.mu.TryLock() {
+		l.locked.Store(true)
+
+		return
+	}
+	l.shard.mu.Unlock()
+}
+
+func TestSingleflightLoader_CallbacksRunOutsideShardLock(t *testing.T) {
+	t.Parallel()
+
+	var locked atomic.Bool
+	loaderImpl := newSingleflightLoader[int](NoopMetricsProvider{}, 0)
+	linker := lockProbeTraceLinker{shard: loaderImpl.shardFor("key"), locked: &locked}
+	loaderImpl.traceLinker = linker
+	loaderImpl.maxLoadTimeoutFunc = func(string) time.Duration {
+		linker.probe()
+
+		return time.Second
+	}
+
+	got, _, err := loaderImpl.load(context.Background(), "key", func(context.Context) (int, error) {
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != 1 {
+		t.Fatalf("expected value 1, got %d", got)
+	}
+	if locked.Load() {
+		t.Fatal("expected callbacks to run without the shard lock held")
+	}
+}
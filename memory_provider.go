@@ -0,0 +1,244 @@
+package crema
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SizedProvider is an optional CacheProvider extension reporting how much a
+// provider currently holds, for per-cache capacity monitoring.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type SizedProvider interface {
+	// Len returns the number of stored entries.
+	Len() int
+	// SizeBytes returns the approximate number of bytes held by stored entries.
+	SizeBytes() int64
+}
+
+// SizeFunc returns the approximate size in bytes of a stored value.
+type SizeFunc[S any] func(value S) int64
+
+// MemoryCacheProviderOption customizes the MemoryCacheProvider.
+type MemoryCacheProviderOption[S any] func(*MemoryCacheProvider[S])
+
+type memoryEntry[S any] struct {
+	value    S
+	expireAt time.Time
+	size     int64
+}
+
+// MemoryCacheProvider stores cache entries in a process-local map with TTLs.
+// Expired entries are removed lazily on access. It has no capacity bound and
+// suits tests, development, and small bounded key sets.
+type MemoryCacheProvider[S any] struct {
+	_        noCopy
+	mu       sync.RWMutex
+	items    map[string]memoryEntry[S]
+	bytes    int64
+	sizeFunc SizeFunc[S]
+	now      func() time.Time
+}
+
+var (
+	_ AdminProvider[any]      = (*MemoryCacheProvider[any])(nil)
+	_ SizedProvider           = (*MemoryCacheProvider[any])(nil)
+	_ TakeProvider[any]       = (*MemoryCacheProvider[any])(nil)
+	_ GetOrSetProvider[any]   = (*MemoryCacheProvider[any])(nil)
+	_ DeleteFuncProvider[any] = (*MemoryCacheProvider[any])(nil)
+)
+
+// NewMemoryCacheProvider constructs an empty MemoryCacheProvider. Sizes of
+// []byte and string values are measured by length unless WithSizeFunc is set.
+func NewMemoryCacheProvider[S any](opts ...MemoryCacheProviderOption[S]) *MemoryCacheProvider[S] {
+	provider := &MemoryCacheProvider[S]{
+		items:    make(map[string]memoryEntry[S]),
+		sizeFunc: defaultSizeFunc[S],
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		opt(provider)
+	}
+
+	return provider
+}
+
+// WithSizeFunc overrides how the approximate size of stored values is measured.
+func WithSizeFunc[S any](sizeFunc SizeFunc[S]) MemoryCacheProviderOption[S] {
+	return func(provider *MemoryCacheProvider[S]) {
+		if sizeFunc != nil {
+			provider.sizeFunc = sizeFunc
+		}
+	}
+}
+
+// Get retrieves a value from the cache by key.
+func (m *MemoryCacheProvider[S]) Get(_ context.Context, key string) (S, bool, error) {
+	m.mu.RLock()
+	entry, ok := m.items[key]
+	m.mu.RUnlock()
+	if !ok {
+		var zero S
+
+		return zero, false, nil
+	}
+	if !entry.expireAt.IsZero() && !m.now().Before(entry.expireAt) {
+		m.mu.Lock()
+		if current, ok := m.items[key]; ok && current.expireAt.Equal(entry.expireAt) {
+			m.removeLocked(key, current)
+		}
+		m.mu.Unlock()
+		var zero S
+
+		return zero, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+// Set stores a value in the cache with the specified key. A non-positive ttl
+// keeps the entry until it is deleted.
+func (m *MemoryCacheProvider[S]) Set(_ context.Context, key string, value S, ttl time.Duration) error {
+	entry := memoryEntry[S]{value: value, size: m.sizeFunc(value) + int64(len(key))}
+	if ttl > 0 {
+		entry.expireAt = m.now().Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if prev, ok := m.items[key]; ok {
+		m.bytes -= prev.size
+	}
+	m.items[key] = entry
+	m.bytes += entry.size
+
+	return nil
+}
+
+// Delete removes a value from the cache by key.
+func (m *MemoryCacheProvider[S]) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if entry, ok := m.items[key]; ok {
+		m.removeLocked(key, entry)
+	}
+
+	return nil
+}
+
+// DeleteFunc removes the entries whose key fn reports true for, including
+// expired entries, and returns how many were removed.
+func (m *MemoryCacheProvider[S]) DeleteFunc(_ context.Context, fn func(key string) bool) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for key, entry := range m.items {
+		if fn(key) {
+			m.removeLocked(key, entry)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// GetOrSet stores value when key is absent or expired, and otherwise returns
+// the stored value.
+func (m *MemoryCacheProvider[S]) GetOrSet(_ context.Context, key string, value S, ttl time.Duration) (S, bool, error) {
+	now := m.now()
+	entry := memoryEntry[S]{value: value, size: m.sizeFunc(value) + int64(len(key))}
+	if ttl > 0 {
+		entry.expireAt = now.Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if prev, ok := m.items[key]; ok {
+		if prev.expireAt.IsZero() || now.Before(prev.expireAt) {
+			return prev.value, true, nil
+		}
+		m.bytes -= prev.size
+	}
+	m.items[key] = entry
+	m.bytes += entry.size
+
+	return value, false, nil
+}
+
+// Take retrieves and removes a value in one step.
+func (m *MemoryCacheProvider[S]) Take(_ context.Context, key string) (S, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.items[key]
+	if !ok {
+		var zero S
+
+		return zero, false, nil
+	}
+	m.removeLocked(key, entry)
+	if !entry.expireAt.IsZero() && !m.now().Before(entry.expireAt) {
+		var zero S
+
+		return zero, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+// Scan calls fn for each unexpired entry whose key matches pattern, in key order.
+func (m *MemoryCacheProvider[S]) Scan(_ context.Context, pattern string, fn func(key string, value S) bool) error {
+	m.mu.RLock()
+	items := maps.Clone(m.items)
+	m.mu.RUnlock()
+
+	now := m.now()
+	for _, key := range slices.Sorted(maps.Keys(items)) {
+		entry := items[key]
+		if !entry.expireAt.IsZero() && !now.Before(entry.expireAt) {
+			continue
+		}
+		if MatchKeyPattern(pattern, key) && !fn(key, entry.value) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Len returns the number of stored entries, including expired entries that
+// have not been accessed since they expired.
+func (m *MemoryCacheProvider[S]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.items)
+}
+
+// SizeBytes returns the approximate number of bytes held by keys and values.
+func (m *MemoryCacheProvider[S]) SizeBytes() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.bytes
+}
+
+func (m *MemoryCacheProvider[S]) removeLocked(key string, entry memoryEntry[S]) {
+	delete(m.items, key)
+	m.bytes -= entry.size
+}
+
+func defaultSizeFunc[S any](value S) int64 {
+	switch v := any(value).(type) {
+	case []byte:
+		return int64(len(v))
+	case string:
+		return int64(len(v))
+	default:
+		return 0
+	}
+}
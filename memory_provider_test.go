@@ -0,0 +1,135 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheProvider_GetSetDelete(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	value, ok, err := provider.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !ok || string(value) != "value" {
+		t.Fatalf("unexpected value: %q, %v", value, ok)
+	}
+
+	if err := provider.Delete(ctx, "key"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok, _ := provider.Get(ctx, "key"); ok {
+		t.Fatal("expected value to be deleted")
+	}
+}
+
+func TestMemoryCacheProvider_TTL(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[string]()
+	now := time.UnixMilli(1000)
+	provider.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := provider.Set(ctx, "key", "value", time.Second); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := provider.Set(ctx, "forever", "value", 0); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	now = now.Add(time.Second)
+
+	if _, ok, _ := provider.Get(ctx, "key"); ok {
+		t.Fatal("expected value to expire")
+	}
+	if _, ok, _ := provider.Get(ctx, "forever"); !ok {
+		t.Fatal("expected value without ttl to remain")
+	}
+	if provider.Len() != 1 {
+		t.Fatalf("expected expired entry to be removed, got %d entries", provider.Len())
+	}
+}
+
+func TestMemoryCacheProvider_SizeAccounting(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	ctx := context.Background()
+
+	_ = provider.Set(ctx, "a", []byte("12345"), 0)
+	_ = provider.Set(ctx, "b", []byte("123"), 0)
+	if provider.Len() != 2 || provider.SizeBytes() != 10 {
+		t.Fatalf("unexpected size: %d entries, %d bytes", provider.Len(), provider.SizeBytes())
+	}
+	_ = provider.Set(ctx, "a", []byte("1"), 0)
+	if provider.SizeBytes() != 6 {
+		t.Fatalf("expected overwrite to replace size, got %d bytes", provider.SizeBytes())
+	}
+	_ = provider.Delete(ctx, "b")
+	if provider.Len() != 1 || provider.SizeBytes() != 2 {
+		t.Fatalf("unexpected size after delete: %d entries, %d bytes", provider.Len(), provider.SizeBytes())
+	}
+
+	custom := NewMemoryCacheProvider(WithSizeFunc(func(CacheObject[int]) int64 { return 100 }))
+	_ = custom.Set(ctx, "k", CacheObject[int]{Value: 1}, 0)
+	if custom.SizeBytes() != 101 {
+		t.Fatalf("expected custom size func to be used, got %d", custom.SizeBytes())
+	}
+}
+
+func TestMemoryCacheProvider_Scan(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[string]()
+	now := time.UnixMilli(1000)
+	provider.now = func() time.Time { return now }
+	ctx := context.Background()
+	_ = provider.Set(ctx, "user:2", "b", 0)
+	_ = provider.Set(ctx, "user:1", "a", 0)
+	_ = provider.Set(ctx, "user:3", "c", time.Millisecond)
+	_ = provider.Set(ctx, "item:1", "x", 0)
+	now = now.Add(time.Second)
+
+	var keys []string
+	if err := provider.Scan(ctx, "user:*", func(key string, _ string) bool {
+		keys = append(keys, key)
+
+		return true
+	}); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "user:1" || keys[1] != "user:2" {
+		t.Fatalf("unexpected scanned keys: %v", keys)
+	}
+}
+
+func TestMemoryCacheProvider_GetOrSet(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[string]()
+	now := time.Unix(100, 0)
+	provider.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if actual, loaded, _ := provider.GetOrSet(ctx, "key", "a", time.Second); loaded || actual != "a" {
+		t.Fatalf("unexpected first result: %q, %v", actual, loaded)
+	}
+	if actual, loaded, _ := provider.GetOrSet(ctx, "key", "b", time.Second); !loaded || actual != "a" {
+		t.Fatalf("unexpected second result: %q, %v", actual, loaded)
+	}
+	now = now.Add(2 * time.Second)
+	if actual, loaded, _ := provider.GetOrSet(ctx, "key", "c", time.Second); loaded || actual != "c" {
+		t.Fatalf("expected expired entry to be replaced, got %q, %v", actual, loaded)
+	}
+	if provider.Len() != 1 {
+		t.Fatalf("expected 1 entry, got %d", provider.Len())
+	}
+}
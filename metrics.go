@@ -1,6 +1,9 @@
 package crema
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // MetricsProvider receives cache and loader events for instrumentation.
 // Implementations must be safe for concurrent use and should avoid blocking.
@@ -17,16 +20,103 @@ type MetricsProvider interface {
 	RecordLoad(ctx context.Context)
 	// RecordLoadConcurrency is called when a load finishes with the inflight count.
 	RecordLoadConcurrency(ctx context.Context, concurrency int)
+	// RecordLoadFollowers is called when a singleflight load finishes with the
+	// number of callers that joined it after the leader.
+	RecordLoadFollowers(ctx context.Context, followers int)
+	// RecordLoadWait is called when a singleflight caller stops waiting, with
+	// its role, the outcome and how long it waited.
+	RecordLoadWait(ctx context.Context, role LoadRole, outcome LoadOutcome, wait time.Duration)
+	// RecordInflightPoolGet is called when a singleflight load takes an
+	// inflight record, reporting whether a pooled record was reused.
+	RecordInflightPoolGet(ctx context.Context, hit bool)
+	// RecordDoubleReadMismatch is called when double-read verification finds a
+	// secondary entry that is missing or differs from the primary entry.
+	RecordDoubleReadMismatch(ctx context.Context)
+	// RecordTTLConflict is called when GetOrLoad is called for a key with a
+	// different ttl than before, with a TTL conflict policy configured.
+	RecordTTLConflict(ctx context.Context)
+	// RecordTTLClamped is called when WithTTLBounds clamps a ttl.
+	RecordTTLClamped(ctx context.Context)
+	// RecordIdenticalWriteSkipped is called when WithSkipIdenticalWrites
+	// skips a write because the stored value is identical.
+	RecordIdenticalWriteSkipped(ctx context.Context)
+	// RecordLoadSample is called when WithLoadSampling compares a cached value
+	// with a fresh load, reporting whether the values drifted apart.
+	RecordLoadSample(ctx context.Context, drifted bool)
+	// RecordDegraded is called when WithDegradation enters or leaves the
+	// degraded state.
+	RecordDegraded(ctx context.Context, degraded bool)
+	// RecordCodecEncode is called by codecs built with NewMetricsCodec after
+	// each encode, with the codec name, duration, encoded size and error.
+	RecordCodecEncode(ctx context.Context, codec string, duration time.Duration, size int, err error)
+	// RecordCodecDecode is called by codecs built with NewMetricsCodec after
+	// each decode, with the codec name, duration, encoded size and error.
+	RecordCodecDecode(ctx context.Context, codec string, duration time.Duration, size int, err error)
+	// RecordSetAbandoned is called when a loaded value is not stored because
+	// the context of the write was cancelled or ran out of time.
+	RecordSetAbandoned(ctx context.Context)
+	// RecordSetDetached is called when a loaded value is stored with the
+	// detached context of WithSetTimeout after the caller context was done.
+	RecordSetDetached(ctx context.Context)
+	// RecordSetRetry is called when a write queued by WithSetRetry is
+	// retried successfully or dropped.
+	RecordSetRetry(ctx context.Context, outcome SetRetryOutcome)
+	// RecordClockSkewTolerated is called when an entry whose expiry passed by
+	// the local clock is treated as unexpired within the tolerance configured
+	// by WithClockSkewTolerance.
+	RecordClockSkewTolerated(ctx context.Context)
+	// RecordAbsentKeyFiltered is called when GetOrLoad returns the zero value
+	// for a key held by the filter configured with WithAbsentKeyFilter.
+	RecordAbsentKeyFiltered(ctx context.Context)
+	// RecordLoaderMismatch is called when a GetOrLoad call joins a load of
+	// its key started with a different loader, as detected by
+	// WithLoaderIdentity.
+	RecordLoaderMismatch(ctx context.Context)
+	// RecordReencode is called when an entry read in an outdated format is
+	// rewritten, or left as is, by WithReencodeOnUpgrade.
+	RecordReencode(ctx context.Context, outcome ReencodeOutcome)
+	// RecordValueMutation is called when WithMutationCheck finds a cached
+	// value modified after it was returned.
+	RecordValueMutation(ctx context.Context)
+	// RecordProviderOperation is called by providers built with
+	// NewInstrumentedProvider after each operation, with its duration,
+	// payload size and error class.
+	RecordProviderOperation(ctx context.Context, op ProviderOperation, duration time.Duration, size int, class ProviderErrorClass)
+	// RecordCacheSize is called after a successful write when the provider
+	// implements SizedProvider, with its current entry count and approximate bytes.
+	RecordCacheSize(ctx context.Context, entries int, bytes int64)
 }
 
 type BaseMetricsProvider struct{}
 
-func (BaseMetricsProvider) RecordCacheHit(context.Context)             {}
-func (BaseMetricsProvider) RecordCacheGet(context.Context)             {}
-func (BaseMetricsProvider) RecordCacheSet(context.Context)             {}
-func (BaseMetricsProvider) RecordCacheDelete(context.Context)          {}
-func (BaseMetricsProvider) RecordLoad(context.Context)                 {}
-func (BaseMetricsProvider) RecordLoadConcurrency(context.Context, int) {}
+func (BaseMetricsProvider) RecordCacheHit(context.Context)                                       {}
+func (BaseMetricsProvider) RecordCacheGet(context.Context)                                       {}
+func (BaseMetricsProvider) RecordCacheSet(context.Context)                                       {}
+func (BaseMetricsProvider) RecordCacheDelete(context.Context)                                    {}
+func (BaseMetricsProvider) RecordLoad(context.Context)                                           {}
+func (BaseMetricsProvider) RecordLoadConcurrency(context.Context, int)                           {}
+func (BaseMetricsProvider) RecordLoadFollowers(context.Context, int)                             {}
+func (BaseMetricsProvider) RecordLoadWait(context.Context, LoadRole, LoadOutcome, time.Duration) {}
+func (BaseMetricsProvider) RecordInflightPoolGet(context.Context, bool)                          {}
+func (BaseMetricsProvider) RecordDoubleReadMismatch(context.Context)                             {}
+func (BaseMetricsProvider) RecordTTLConflict(context.Context)                                    {}
+func (BaseMetricsProvider) RecordTTLClamped(context.Context)                                     {}
+func (BaseMetricsProvider) RecordIdenticalWriteSkipped(context.Context)                          {}
+func (BaseMetricsProvider) RecordLoadSample(context.Context, bool)                               {}
+func (BaseMetricsProvider) RecordDegraded(context.Context, bool)                                 {}
+func (BaseMetricsProvider) RecordCodecEncode(context.Context, string, time.Duration, int, error) {}
+func (BaseMetricsProvider) RecordCodecDecode(context.Context, string, time.Duration, int, error) {}
+func (BaseMetricsProvider) RecordSetAbandoned(context.Context)                                   {}
+func (BaseMetricsProvider) RecordSetDetached(context.Context)                                    {}
+func (BaseMetricsProvider) RecordSetRetry(context.Context, SetRetryOutcome)                      {}
+func (BaseMetricsProvider) RecordClockSkewTolerated(context.Context)                             {}
+func (BaseMetricsProvider) RecordAbsentKeyFiltered(context.Context)                              {}
+func (BaseMetricsProvider) RecordLoaderMismatch(context.Context)                                 {}
+func (BaseMetricsProvider) RecordReencode(context.Context, ReencodeOutcome)                      {}
+func (BaseMetricsProvider) RecordValueMutation(context.Context)                                  {}
+func (BaseMetricsProvider) RecordCacheSize(context.Context, int, int64)                          {}
+func (BaseMetricsProvider) RecordProviderOperation(context.Context, ProviderOperation, time.Duration, int, ProviderErrorClass) {
+}
 
 type NoopMetricsProvider struct {
 	BaseMetricsProvider
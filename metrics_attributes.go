@@ -0,0 +1,159 @@
+package crema
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+)
+
+const (
+	defaultMaxMetricLabels      = 8
+	defaultMaxMetricLabelValues = 64
+	// MetricLabelOverflow replaces the values of a label beyond the limit set
+	// by WithMetricLabelLimits.
+	MetricLabelOverflow = "_other"
+)
+
+// MetricsAttribute is a request-scoped dimension, such as a region or the
+// calling service, made available to MetricsProvider implementations.
+type MetricsAttribute struct {
+	Key   string
+	Value string
+}
+
+type (
+	metricsAttributesKey struct{}
+	metricLabelsKey      struct{}
+)
+
+// metricsAttributes holds the attributes extracted for one call.
+type metricsAttributes struct {
+	attrs []MetricsAttribute
+	// labels are the WithMetricLabels labels attrs were built with.
+	labels []MetricsAttribute
+}
+
+// WithMetricsContextExtractor extracts request-scoped attributes from the
+// context once at the start of each cache call and attaches them to the
+// context passed to MetricsProvider, where MetricsAttributesFromContext
+// returns them. Metrics implementations can then slice dashboards by region
+// or caller without parsing the request context themselves. Nested calls,
+// such as the Get made by GetOrLoad, reuse the attributes already extracted.
+func WithMetricsContextExtractor[V any, S any](extract func(ctx context.Context) []MetricsAttribute) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.metricsExtractor = extract
+	}
+}
+
+// MetricsAttributesFromContext returns the attributes extracted by
+// WithMetricsContextExtractor for the call ctx belongs to, or nil. The
+// returned slice must not be modified.
+func MetricsAttributesFromContext(ctx context.Context) []MetricsAttribute {
+	if attrs, ok := ctx.Value(metricsAttributesKey{}).(*metricsAttributes); ok {
+		return attrs.attrs
+	}
+
+	return nil
+}
+
+// WithMetricLabels returns a context whose cache calls are tagged with
+// labels, so endpoints sharing one Cache can tell their traffic apart.
+// MetricsProvider implementations receive them, after the attributes of
+// WithMetricsContextExtractor, from MetricsAttributesFromContext, and so do
+// LoadTraceLinker implementations from the caller context. Labels are
+// sorted by name and bounded per cache as WithMetricLabelLimits describes.
+// labels is copied, so it may be reused.
+func WithMetricLabels(ctx context.Context, labels map[string]string) context.Context {
+	attrs := make([]MetricsAttribute, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		attrs = append(attrs, MetricsAttribute{Key: key, Value: labels[key]})
+	}
+
+	return context.WithValue(ctx, metricLabelsKey{}, attrs)
+}
+
+// WithMetricLabelLimits bounds the cardinality of WithMetricLabels per cache:
+// labels beyond maxLabels distinct names are dropped, and values beyond
+// maxValues distinct values of a name are replaced by MetricLabelOverflow.
+// The limits default to 8 names and 64 values per name; non-positive values
+// keep the defaults.
+func WithMetricLabelLimits[V any, S any](maxLabels, maxValues int) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if maxLabels > 0 {
+			c.metricLabels.maxLabels = maxLabels
+		}
+		if maxValues > 0 {
+			c.metricLabels.maxValues = maxValues
+		}
+	}
+}
+
+// metricLabelLimiter tracks the label names and values seen by a cache.
+type metricLabelLimiter struct {
+	maxLabels int
+	maxValues int
+	mu        sync.Mutex
+	values    map[string]map[string]struct{}
+}
+
+func newMetricLabelLimiter() *metricLabelLimiter {
+	return &metricLabelLimiter{
+		maxLabels: defaultMaxMetricLabels,
+		maxValues: defaultMaxMetricLabelValues,
+		values:    make(map[string]map[string]struct{}),
+	}
+}
+
+// bound appends labels to attrs within the cardinality limits.
+func (l *metricLabelLimiter) bound(attrs []MetricsAttribute, labels []MetricsAttribute) []MetricsAttribute {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, label := range labels {
+		values, ok := l.values[label.Key]
+		if !ok {
+			if len(l.values) >= l.maxLabels {
+				continue
+			}
+			values = make(map[string]struct{})
+			l.values[label.Key] = values
+		}
+		if _, ok := values[label.Value]; !ok {
+			if len(values) >= l.maxValues {
+				label.Value = MetricLabelOverflow
+			} else {
+				values[label.Value] = struct{}{}
+			}
+		}
+		attrs = append(attrs, label)
+	}
+
+	return attrs
+}
+
+// metricsContext returns ctx carrying the extracted metrics attributes and
+// the bounded labels of WithMetricLabels.
+func (c *cacheImpl[V, S]) metricsContext(ctx context.Context) context.Context {
+	labels, labeled := ctx.Value(metricLabelsKey{}).([]MetricsAttribute)
+	if c.metricsExtractor == nil && !labeled {
+		return ctx
+	}
+	if extracted, ok := ctx.Value(metricsAttributesKey{}).(*metricsAttributes); ok && sameLabels(extracted.labels, labels) {
+		return ctx
+	}
+	var attrs []MetricsAttribute
+	if c.metricsExtractor != nil {
+		attrs = c.metricsExtractor(ctx)
+	}
+	if len(labels) > 0 {
+		attrs = c.metricLabels.bound(slices.Clip(attrs), labels)
+	}
+
+	return context.WithValue(ctx, metricsAttributesKey{}, &metricsAttributes{attrs: attrs, labels: labels})
+}
+
+// sameLabels reports whether a and b were set by the same WithMetricLabels
+// call, so a nested call made with labels of its own is tagged with them.
+func sameLabels(a, b []MetricsAttribute) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
@@ -0,0 +1,167 @@
+package crema
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type regionKey struct{}
+
+type attributeMetricsProvider struct {
+	BaseMetricsProvider
+	mu      sync.Mutex
+	regions []string
+}
+
+func (m *attributeMetricsProvider) record(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, attr := range MetricsAttributesFromContext(ctx) {
+		if attr.Key == "region" {
+			m.regions = append(m.regions, attr.Value)
+		}
+	}
+}
+
+func (m *attributeMetricsProvider) RecordCacheGet(ctx context.Context) { m.record(ctx) }
+func (m *attributeMetricsProvider) RecordLoad(ctx context.Context)     { m.record(ctx) }
+func (m *attributeMetricsProvider) RecordCacheSet(ctx context.Context) { m.record(ctx) }
+
+func TestWithMetricsContextExtractor(t *testing.T) {
+	t.Parallel()
+
+	metrics := &attributeMetricsProvider{}
+	var extractions atomic.Int32
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithoutProviderInstrumentation[int, CacheObject[int]](),
+		WithMetricsContextExtractor[int, CacheObject[int]](func(ctx context.Context) []MetricsAttribute {
+			extractions.Add(1)
+			region, _ := ctx.Value(regionKey{}).(string)
+
+			return []MetricsAttribute{{Key: "region", Value: region}}
+		}),
+	)
+	ctx := context.WithValue(context.Background(), regionKey{}, "tokyo")
+	if _, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := extractions.Load(); got != 1 {
+		t.Fatalf("expected attributes to be extracted once, got %d", got)
+	}
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.regions) != 3 {
+		t.Fatalf("expected get, load and set to carry the region, got %v", metrics.regions)
+	}
+	for _, region := range metrics.regions {
+		if region != "tokyo" {
+			t.Fatalf("expected tokyo, got %q", region)
+		}
+	}
+}
+
+func TestMetricsAttributesFromContext_WithoutExtractor(t *testing.T) {
+	t.Parallel()
+
+	if attrs := MetricsAttributesFromContext(context.Background()); attrs != nil {
+		t.Fatalf("expected nil, got %v", attrs)
+	}
+}
+
+type labelMetricsProvider struct {
+	BaseMetricsProvider
+	mu     sync.Mutex
+	labels [][]MetricsAttribute
+}
+
+func (m *labelMetricsProvider) RecordCacheGet(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.labels = append(m.labels, MetricsAttributesFromContext(ctx))
+}
+
+func TestWithMetricLabels(t *testing.T) {
+	t.Parallel()
+
+	metrics := &labelMetricsProvider{}
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithoutProviderInstrumentation[int, CacheObject[int]](),
+		WithMetricsContextExtractor[int, CacheObject[int]](func(context.Context) []MetricsAttribute {
+			return []MetricsAttribute{{Key: "region", Value: "tokyo"}}
+		}),
+	)
+	labels := map[string]string{"endpoint": "/users", "tier": "free"}
+	ctx := WithMetricLabels(context.Background(), labels)
+	labels["endpoint"] = "/changed"
+	if _, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	want := []MetricsAttribute{{Key: "region", Value: "tokyo"}, {Key: "endpoint", Value: "/users"}, {Key: "tier", Value: "free"}}
+	if len(metrics.labels) != 1 || !slices.Equal(metrics.labels[0], want) {
+		t.Fatalf("expected %v, got %v", want, metrics.labels)
+	}
+}
+
+func TestWithMetricLabels_NestedCall(t *testing.T) {
+	t.Parallel()
+
+	metrics := &labelMetricsProvider{}
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithoutProviderInstrumentation[int, CacheObject[int]](),
+		WithDirectLoader[int, CacheObject[int]](),
+	)
+	ctx := WithMetricLabels(context.Background(), map[string]string{"endpoint": "outer"})
+	if _, err := cache.GetOrLoad(ctx, "outer", time.Minute, func(ctx context.Context) (int, error) {
+		_, _, err := cache.Get(WithMetricLabels(ctx, map[string]string{"endpoint": "inner"}), "inner")
+
+		return 1, err
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if len(metrics.labels) != 2 || metrics.labels[0][0].Value != "outer" || metrics.labels[1][0].Value != "inner" {
+		t.Fatalf("expected outer then inner labels, got %v", metrics.labels)
+	}
+}
+
+func TestWithMetricLabelLimits(t *testing.T) {
+	t.Parallel()
+
+	metrics := &labelMetricsProvider{}
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithoutProviderInstrumentation[int, CacheObject[int]](),
+		WithMetricLabelLimits[int, CacheObject[int]](1, 2),
+	)
+	for _, user := range []string{"a", "b", "c", "a"} {
+		ctx := WithMetricLabels(context.Background(), map[string]string{"user": user, "zone": "z"})
+		if _, _, err := cache.Get(ctx, "key"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	var got []string
+	for _, attrs := range metrics.labels {
+		if len(attrs) != 1 {
+			t.Fatalf("expected labels beyond the name limit dropped, got %v", attrs)
+		}
+		got = append(got, attrs[0].Value)
+	}
+	if want := []string{"a", "b", MetricLabelOverflow, "a"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,81 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+type metricsCodec[V any, S any] struct {
+	inner   CacheStorageCodec[V, S]
+	metrics MetricsProvider
+	name    string
+}
+
+var (
+	_ CacheStorageCodec[any, []byte]      = &metricsCodec[any, []byte]{}
+	_ KeyedCacheStorageCodec[any, []byte] = &metricsCodec[any, []byte]{}
+	_ BufferReleasePolicy                 = &metricsCodec[any, []byte]{}
+)
+
+// NewMetricsCodec wraps inner so that every encode and decode is reported to
+// metrics through RecordCodecEncode and RecordCodecDecode with name, its
+// duration, the encoded size and its error. The size is the length of []byte
+// and string storage values and zero otherwise. To see sizes before and after
+// compression, wrap both the codec passed to NewBinaryCompressionCodec and the
+// compression codec itself under different names.
+func NewMetricsCodec[V any, S any](inner CacheStorageCodec[V, S], metrics MetricsProvider, name string) CacheStorageCodec[V, S] {
+	if metrics == nil {
+		metrics = NoopMetricsProvider{}
+	}
+
+	return &metricsCodec[V, S]{inner: inner, metrics: metrics, name: name}
+}
+
+func (m *metricsCodec[V, S]) Encode(value CacheObject[V]) (S, error) {
+	return m.EncodeKeyed("", value)
+}
+
+// EncodeKeyed encodes value with the inner codec, forwarding key when the
+// inner codec is keyed, and records the encode.
+func (m *metricsCodec[V, S]) EncodeKeyed(key string, value CacheObject[V]) (S, error) {
+	started := time.Now()
+	data, err := encodeKeyed(m.inner, key, value)
+	m.metrics.RecordCodecEncode(context.Background(), m.name, time.Since(started), storageSize(data), err)
+
+	return data, err
+}
+
+func (m *metricsCodec[V, S]) Decode(data S) (CacheObject[V], error) {
+	return m.DecodeKeyed("", data)
+}
+
+// DecodeKeyed decodes data with the inner codec, forwarding key when the
+// inner codec is keyed, and records the decode.
+func (m *metricsCodec[V, S]) DecodeKeyed(key string, data S) (CacheObject[V], error) {
+	started := time.Now()
+	co, err := decodeKeyed(m.inner, key, data)
+	m.metrics.RecordCodecDecode(context.Background(), m.name, time.Since(started), storageSize(data), err)
+
+	return co, err
+}
+
+// CanReleaseBufferOnDecode forwards the buffer release policy of the inner codec.
+func (m *metricsCodec[V, S]) CanReleaseBufferOnDecode() bool {
+	if policy, ok := any(m.inner).(BufferReleasePolicy); ok {
+		return policy.CanReleaseBufferOnDecode()
+	}
+
+	return false
+}
+
+// storageSize returns the length of byte and string storage values.
+func storageSize[S any](data S) int {
+	switch v := any(data).(type) {
+	case []byte:
+		return len(v)
+	case string:
+		return len(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,86 @@
+package crema
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type codecEvent struct {
+	op    string
+	codec string
+	size  int
+	err   bool
+}
+
+type codecMetricsProvider struct {
+	BaseMetricsProvider
+	mu     sync.Mutex
+	events []codecEvent
+}
+
+func (m *codecMetricsProvider) RecordCodecEncode(_ context.Context, codec string, _ time.Duration, size int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, codecEvent{op: "encode", codec: codec, size: size, err: err != nil})
+}
+
+func (m *codecMetricsProvider) RecordCodecDecode(_ context.Context, codec string, _ time.Duration, size int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, codecEvent{op: "decode", codec: codec, size: size, err: err != nil})
+}
+
+func TestMetricsCodec_RecordsEncodeAndDecode(t *testing.T) {
+	t.Parallel()
+
+	metrics := &codecMetricsProvider{}
+	codec := NewMetricsCodec(JSONByteStringCodec[string]{}, metrics, "json")
+
+	data, err := codec.Encode(CacheObject[string]{Value: "hello", ExpireAtMillis: 1})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	co, err := codec.Decode(data)
+	if err != nil || co.Value != "hello" {
+		t.Fatalf("expected hello, got %q, %v", co.Value, err)
+	}
+	if _, err := codec.Decode([]byte("{")); err == nil {
+		t.Fatal("expected decode error")
+	}
+
+	want := []codecEvent{
+		{op: "encode", codec: "json", size: len(data)},
+		{op: "decode", codec: "json", size: len(data)},
+		{op: "decode", codec: "json", size: 1, err: true},
+	}
+	if len(metrics.events) != len(want) {
+		t.Fatalf("expected %d events, got %v", len(want), metrics.events)
+	}
+	for i, event := range want {
+		if metrics.events[i] != event {
+			t.Fatalf("expected event %d to be %+v, got %+v", i, event, metrics.events[i])
+		}
+	}
+}
+
+func TestMetricsCodec_ReportsSizesAroundCompression(t *testing.T) {
+	t.Parallel()
+
+	metrics := &codecMetricsProvider{}
+	inner := NewMetricsCodec(JSONByteStringCodec[string]{}, metrics, "json")
+	codec := NewMetricsCodec(NewBinaryCompressionCodec(inner, 0), metrics, "zlib")
+
+	value := CacheObject[string]{Value: string(make([]byte, 1024)), ExpireAtMillis: 1}
+	if _, err := encodeKeyed(codec, "key", value); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if len(metrics.events) != 2 {
+		t.Fatalf("expected inner and outer encode events, got %v", metrics.events)
+	}
+	before, after := metrics.events[0], metrics.events[1]
+	if before.codec != "json" || after.codec != "zlib" || after.size >= before.size {
+		t.Fatalf("expected compressed size below %d, got %+v", before.size, after)
+	}
+}
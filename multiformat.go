@@ -0,0 +1,89 @@
+package crema
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrUnknownFormat is returned by a MultiFormatCodec when no registered format
+// recognizes the payload.
+var ErrUnknownFormat = errors.New("payload matches no registered codec format")
+
+// FormatMatcher reports whether a stored payload was written in a format.
+type FormatMatcher func(data []byte) bool
+
+// CodecFormat registers a codec with a MultiFormatCodec.
+type CodecFormat[V any] struct {
+	// Codec encodes and decodes the format.
+	Codec CacheStorageCodec[V, []byte]
+	// Match recognizes payloads written by Codec, usually by their leading
+	// byte or magic prefix.
+	Match FormatMatcher
+}
+
+type multiFormatCodec[V any] struct {
+	current  CodecFormat[V]
+	previous []CodecFormat[V]
+}
+
+var (
+	_ CacheStorageCodec[any, []byte]      = &multiFormatCodec[any]{}
+	_ KeyedCacheStorageCodec[any, []byte] = &multiFormatCodec[any]{}
+)
+
+// NewMultiFormatCodec returns a codec that always encodes with current and
+// decodes each payload with the first of current and previous whose Match
+// accepts it. It lets the stored format migrate gradually: entries written in
+// an older format stay readable until they expire or are rewritten. Formats
+// must be distinguishable by their matchers; ErrUnknownFormat is returned for
+// payloads no format accepts.
+func NewMultiFormatCodec[V any](current CodecFormat[V], previous ...CodecFormat[V]) CacheStorageCodec[V, []byte] {
+	return &multiFormatCodec[V]{current: current, previous: previous}
+}
+
+// MatchJSON matches payloads whose first non-whitespace byte opens a JSON
+// object, as written by JSONByteStringCodec.
+func MatchJSON(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// MatchPrefix returns a matcher for payloads starting with prefix, such as a
+// magic number or a version byte.
+func MatchPrefix(prefix ...byte) FormatMatcher {
+	prefix = bytes.Clone(prefix)
+
+	return func(data []byte) bool {
+		return bytes.HasPrefix(data, prefix)
+	}
+}
+
+func (m *multiFormatCodec[V]) Encode(value CacheObject[V]) ([]byte, error) {
+	return m.EncodeKeyed("", value)
+}
+
+// EncodeKeyed encodes value with the current format, forwarding key when its
+// codec is keyed.
+func (m *multiFormatCodec[V]) EncodeKeyed(key string, value CacheObject[V]) ([]byte, error) {
+	return encodeKeyed(m.current.Codec, key, value)
+}
+
+func (m *multiFormatCodec[V]) Decode(data []byte) (CacheObject[V], error) {
+	return m.DecodeKeyed("", data)
+}
+
+// DecodeKeyed decodes data with the first format that matches it, forwarding
+// key when its codec is keyed.
+func (m *multiFormatCodec[V]) DecodeKeyed(key string, data []byte) (CacheObject[V], error) {
+	if m.current.Match(data) {
+		return decodeKeyed(m.current.Codec, key, data)
+	}
+	for _, format := range m.previous {
+		if format.Match(data) {
+			return decodeKeyed(format.Codec, key, data)
+		}
+	}
+
+	return CacheObject[V]{}, ErrUnknownFormat
+}
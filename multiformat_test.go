@@ -0,0 +1,60 @@
+package crema
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMultiFormatCodec_MigratesFormats(t *testing.T) {
+	t.Parallel()
+
+	legacy := JSONByteStringCodec[string]{}
+	compressed := NewBinaryCompressionCodec[string](legacy, 0)
+	codec := NewMultiFormatCodec(
+		CodecFormat[string]{Codec: compressed, Match: MatchPrefix(CompressionTypeIDZlib)},
+		CodecFormat[string]{Codec: legacy, Match: MatchJSON},
+	)
+	want := CacheObject[string]{Value: "v", ExpireAtMillis: 42}
+
+	old, err := legacy.Encode(want)
+	if err != nil {
+		t.Fatalf("encode legacy: %v", err)
+	}
+	if got, err := codec.Decode(old); err != nil || got != want {
+		t.Fatalf("expected legacy payload to decode to %+v, got %+v, %v", want, got, err)
+	}
+
+	encoded, err := codec.Encode(want)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if encoded[0] != CompressionTypeIDZlib {
+		t.Fatalf("expected encode with the current format, got leading byte %#x", encoded[0])
+	}
+	if got, err := codec.Decode(encoded); err != nil || got != want {
+		t.Fatalf("expected %+v, got %+v, %v", want, got, err)
+	}
+
+	if _, err := codec.Decode([]byte{0x7f}); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestMatchJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data []byte
+		want bool
+	}{
+		{data: []byte(`{"value":1}`), want: true},
+		{data: []byte(" \n{}"), want: true},
+		{data: []byte(`[1]`), want: false},
+		{data: nil, want: false},
+	}
+	for _, tt := range tests {
+		if got := MatchJSON(tt.data); got != tt.want {
+			t.Fatalf("expected %v for %q, got %v", tt.want, tt.data, got)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package crema
+
+// ObjectCache is a Cache whose provider stores cache objects as they are,
+// without a codec.
+type ObjectCache[V any] = Cache[V, CacheObject[V]]
+
+// NewObjectCache constructs an ObjectCache on an in-process provider such as
+// MemoryCacheProvider that keeps CacheObject values without serializing them.
+// Reads and writes skip the codec entirely.
+func NewObjectCache[V any](provider CacheProvider[CacheObject[V]], opts ...CacheOption[V, CacheObject[V]]) ObjectCache[V] {
+	return NewCache[V, CacheObject[V]](provider, nil, opts...)
+}
+
+// resolveObjectStorage switches the cache to the codec-less path when S is
+// CacheObject[V] and the codec is nil or NoopCacheStorageCodec, which would
+// only copy the object. A nil codec is replaced with NoopCacheStorageCodec so
+// code reading c.codec still finds one.
+func (c *cacheImpl[V, S]) resolveObjectStorage() {
+	var zero S
+	if _, ok := any(zero).(CacheObject[V]); !ok {
+		return
+	}
+	switch any(c.codec).(type) {
+	case nil:
+		c.codec = any(NoopCacheStorageCodec[V]{}).(CacheStorageCodec[V, S])
+	case NoopCacheStorageCodec[V]:
+	default:
+		return
+	}
+	c.objectStorage = true
+}
+
+func (c *cacheImpl[V, S]) encode(key string, value CacheObject[V]) (S, error) {
+	if c.objectStorage {
+		return any(value).(S), nil
+	}
+
+	return encodeKeyed(c.codec, key, value)
+}
+
+func (c *cacheImpl[V, S]) decode(key string, data S) (CacheObject[V], error) {
+	if c.objectStorage {
+		return any(data).(CacheObject[V]), nil
+	}
+
+	return decodeKeyed(c.codec, key, data)
+}
@@ -0,0 +1,71 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingObjectCodec[V any] struct {
+	NoopCacheStorageCodec[V]
+	encodes *atomic.Int64
+}
+
+func (c countingObjectCodec[V]) Encode(value CacheObject[V]) (CacheObject[V], error) {
+	c.encodes.Add(1)
+
+	return value, nil
+}
+
+func TestNewObjectCache_SkipsCodec(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[CacheObject[*int]]()
+	cache := NewObjectCache[*int](provider)
+	if !cache.(*cacheImpl[*int, CacheObject[*int]]).objectStorage {
+		t.Fatalf("expected the codec-less path")
+	}
+	ctx := context.Background()
+	value := 42
+	got, err := cache.GetOrLoad(ctx, "answer", time.Minute, func(context.Context) (*int, error) {
+		return &value, nil
+	})
+	if err != nil || got != &value {
+		t.Fatalf("expected the loaded pointer, got %v, %v", got, err)
+	}
+	stored, ok, err := provider.Get(ctx, "answer")
+	if err != nil || !ok || stored.Value != &value {
+		t.Fatalf("expected the object stored as is, got %+v, %v, %v", stored, ok, err)
+	}
+	co, ok, err := cache.Get(ctx, "answer")
+	if err != nil || !ok || co.Value != &value {
+		t.Fatalf("expected the stored pointer, got %+v, %v, %v", co, ok, err)
+	}
+}
+
+func TestNewCache_ObjectStorageDetection(t *testing.T) {
+	t.Parallel()
+
+	noop := NewCache(NewMemoryCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{})
+	if !noop.(*cacheImpl[int, CacheObject[int]]).objectStorage {
+		t.Fatalf("expected NoopCacheStorageCodec to use the codec-less path")
+	}
+
+	encodes := &atomic.Int64{}
+	custom := NewCache[int, CacheObject[int]](NewMemoryCacheProvider[CacheObject[int]](), countingObjectCodec[int]{encodes: encodes})
+	if custom.(*cacheImpl[int, CacheObject[int]]).objectStorage {
+		t.Fatalf("expected a custom codec to be kept")
+	}
+	if err := custom.Set(context.Background(), "k", CacheObject[int]{Value: 1, ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := encodes.Load(); got != 1 {
+		t.Fatalf("expected 1 encode, got %d", got)
+	}
+
+	bytesCache := NewCache(NewMemoryCacheProvider[[]byte](), JSONByteStringCodec[int]{})
+	if bytesCache.(*cacheImpl[int, []byte]).objectStorage {
+		t.Fatalf("expected []byte storage to use the codec")
+	}
+}
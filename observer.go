@@ -0,0 +1,63 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// ErrorPhase identifies the cache operation step that failed.
+type ErrorPhase string
+
+const (
+	// ErrorPhaseProviderGet is a failed CacheProvider.Get.
+	ErrorPhaseProviderGet ErrorPhase = "provider-get"
+	// ErrorPhaseDecode is a failed CacheStorageCodec.Decode.
+	ErrorPhaseDecode ErrorPhase = "decode"
+	// ErrorPhaseLoad is a failed CacheLoadFunc.
+	ErrorPhaseLoad ErrorPhase = "load"
+	// ErrorPhaseEncode is a failed CacheStorageCodec.Encode.
+	ErrorPhaseEncode ErrorPhase = "encode"
+	// ErrorPhaseProviderSet is a failed CacheProvider.Set.
+	ErrorPhaseProviderSet ErrorPhase = "provider-set"
+	// ErrorPhaseProviderDelete is a failed CacheProvider.Delete.
+	ErrorPhaseProviderDelete ErrorPhase = "provider-delete"
+	// ErrorPhaseProviderScan is a failed AdminProvider.Scan. Its Key is the
+	// scanned pattern.
+	ErrorPhaseProviderScan ErrorPhase = "provider-scan"
+)
+
+// ErrorEvent describes a failure observed by the cache.
+type ErrorEvent struct {
+	// Phase is the step that failed.
+	Phase ErrorPhase
+	// Key is the cache key being processed.
+	Key string
+	// Duration is how long the failed step ran.
+	Duration time.Duration
+	// Err is the failure.
+	Err error
+}
+
+// WithErrorObserver calls observer for every failure, including those that
+// GetOrLoad masks from the caller by falling back to the loader or by only
+// logging a failed write. The observer runs on the calling goroutine and must
+// not block.
+func WithErrorObserver[V any, S any](observer func(ctx context.Context, event ErrorEvent)) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.errorObserver = observer
+	}
+}
+
+// observeError reports err to the error observer, if any, and returns it.
+func (c *cacheImpl[V, S]) observeError(ctx context.Context, phase ErrorPhase, key string, started time.Time, err error) error {
+	if c.errorObserver != nil {
+		c.errorObserver(ctx, ErrorEvent{
+			Phase:    phase,
+			Key:      key,
+			Duration: time.Since(started),
+			Err:      err,
+		})
+	}
+
+	return err
+}
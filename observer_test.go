@@ -0,0 +1,101 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []ErrorEvent
+}
+
+func (r *recordingObserver) observe(_ context.Context, event ErrorEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingObserver) phases() []ErrorPhase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	phases := make([]ErrorPhase, 0, len(r.events))
+	for _, event := range r.events {
+		phases = append(phases, event.Phase)
+	}
+
+	return phases
+}
+
+func TestWithErrorObserver_ReportsMaskedFailures(t *testing.T) {
+	t.Parallel()
+
+	getErr := errors.New("get failed")
+	setErr := errors.New("set failed")
+	observer := &recordingObserver{}
+	cache := NewCache(
+		&errorProvider[CacheObject[int]]{getErr: getErr, setErr: setErr},
+		NoopCacheStorageCodec[int]{},
+		WithErrorObserver[int, CacheObject[int]](observer.observe),
+	)
+
+	value, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+		return 7, nil
+	})
+	if err != nil {
+		t.Fatalf("expected fail open load, got %v", err)
+	}
+	if value != 7 {
+		t.Fatalf("expected 7, got %d", value)
+	}
+
+	phases := observer.phases()
+	if len(phases) != 2 || phases[0] != ErrorPhaseProviderGet || phases[1] != ErrorPhaseProviderSet {
+		t.Fatalf("expected provider-get and provider-set events, got %v", phases)
+	}
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if observer.events[0].Key != "key" || !errors.Is(observer.events[0].Err, getErr) {
+		t.Fatalf("unexpected get event %+v", observer.events[0])
+	}
+	if !errors.Is(observer.events[1].Err, setErr) {
+		t.Fatalf("unexpected set event %+v", observer.events[1])
+	}
+}
+
+func TestWithErrorObserver_ReportsCodecAndLoadFailures(t *testing.T) {
+	t.Parallel()
+
+	observer := &recordingObserver{}
+	provider := &byteProvider{items: map[string][]byte{"bad": []byte("{")}}
+	cache := NewCache(provider, JSONByteStringCodec[func()]{},
+		WithErrorObserver[func(), []byte](observer.observe),
+	)
+
+	if _, _, err := cache.Get(context.Background(), "bad"); err == nil {
+		t.Fatal("expected decode error")
+	}
+	if err := cache.Set(context.Background(), "fn", CacheObject[func()]{Value: func() {}, ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err == nil {
+		t.Fatal("expected encode error")
+	}
+	loadErr := errors.New("load failed")
+	if _, err := cache.GetOrLoad(context.Background(), "missing", time.Minute, func(context.Context) (func(), error) {
+		return nil, loadErr
+	}); !errors.Is(err, loadErr) {
+		t.Fatalf("expected %v, got %v", loadErr, err)
+	}
+
+	phases := observer.phases()
+	expected := []ErrorPhase{ErrorPhaseDecode, ErrorPhaseEncode, ErrorPhaseLoad}
+	if len(phases) != len(expected) {
+		t.Fatalf("expected phases %v, got %v", expected, phases)
+	}
+	for i := range expected {
+		if phases[i] != expected[i] {
+			t.Fatalf("expected phases %v, got %v", expected, phases)
+		}
+	}
+}
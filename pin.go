@@ -0,0 +1,52 @@
+package crema
+
+import "sync"
+
+// pinSet holds keys exempt from probabilistic early revalidation.
+type pinSet struct {
+	mu   sync.RWMutex
+	keys map[string]struct{}
+}
+
+// Pin exempts key from probabilistic early revalidation, so its entry is only
+// reloaded once it has expired. Pins are process-local and meant for a
+// handful of must-stay-hot entries such as configuration.
+func (c *cacheImpl[V, S]) Pin(key string) {
+	c.pins.mu.Lock()
+	defer c.pins.mu.Unlock()
+	if c.pins.keys == nil {
+		c.pins.keys = make(map[string]struct{})
+	}
+	c.pins.keys[key] = struct{}{}
+}
+
+// Unpin makes key subject to probabilistic early revalidation again.
+func (c *cacheImpl[V, S]) Unpin(key string) {
+	c.pins.mu.Lock()
+	defer c.pins.mu.Unlock()
+	delete(c.pins.keys, key)
+}
+
+// Pinned reports whether key is pinned.
+func (c *cacheImpl[V, S]) Pinned(key string) bool {
+	c.pins.mu.RLock()
+	defer c.pins.mu.RUnlock()
+	_, ok := c.pins.keys[key]
+
+	return ok
+}
+
+// shouldRevalidateKey is shouldRevalidate with pinned keys, and keys within
+// their refresh debounce window, only reloaded after they expire.
+func (c *cacheImpl[V, S]) shouldRevalidateKey(key string, nowMillis int64, expireAtMillis int64) bool {
+	if expireAtMillis > nowMillis {
+		if c.Pinned(key) {
+			return false
+		}
+		if c.refreshDebounce != nil && c.refreshDebounce.debounced(key, nowMillis) {
+			return false
+		}
+	}
+
+	return c.shouldRevalidate(nowMillis, expireAtMillis)
+}
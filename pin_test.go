@@ -0,0 +1,54 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCache_PinSkipsEarlyRevalidation(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	now := time.UnixMilli(1_000_000)
+	provider.items["config"] = CacheObject[int]{Value: 1, ExpireAtMillis: now.Add(time.Second).UnixMilli()}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(0)
+
+	loads := 0
+	loader := func(context.Context) (int, error) {
+		loads++
+
+		return 2, nil
+	}
+
+	cache.Pin("config")
+	if !cache.Pinned("config") {
+		t.Fatal("expected key to be pinned")
+	}
+	if v, err := cache.GetOrLoad(context.Background(), "config", time.Minute, loader); err != nil || v != 1 {
+		t.Fatalf("expected pinned entry to be served, got %d, %v", v, err)
+	}
+	if loads != 0 {
+		t.Fatalf("expected no load for pinned key, got %d", loads)
+	}
+
+	now = now.Add(2 * time.Second)
+	if v, err := cache.GetOrLoad(context.Background(), "config", time.Minute, loader); err != nil || v != 2 {
+		t.Fatalf("expected expired pinned entry to reload, got %d, %v", v, err)
+	}
+
+	cache.Unpin("config")
+	if cache.Pinned("config") {
+		t.Fatal("expected key to be unpinned")
+	}
+	now = now.Add(time.Minute - time.Second)
+	if _, err := cache.GetOrLoad(context.Background(), "config", time.Minute, loader); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loads != 2 {
+		t.Fatalf("expected early revalidation after unpin, got %d loads", loads)
+	}
+}
@@ -0,0 +1,51 @@
+package crema
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+	"time"
+)
+
+func TestWithPprofLabels_LabelsBackgroundLoads(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		enabled bool
+		want    map[string]string
+	}{
+		{name: "enabled", enabled: true, want: map[string]string{"crema_cache": "users", "crema_key_group": "user", "crema_op": "load"}},
+		{name: "disabled", enabled: false, want: map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+				WithCacheName[int, CacheObject[int]]("users"),
+				WithPprofLabels[int, CacheObject[int]](tt.enabled),
+			)
+			got := make(map[string]string)
+			if _, err := cache.GetOrLoad(context.Background(), "user:1", time.Minute, func(ctx context.Context) (int, error) {
+				pprof.ForLabels(ctx, func(key, value string) bool {
+					got[key] = value
+
+					return true
+				})
+
+				return 1, nil
+			}); err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected labels %v, got %v", tt.want, got)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Fatalf("expected label %s=%s, got %v", key, value, got)
+				}
+			}
+		})
+	}
+}
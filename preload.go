@@ -0,0 +1,70 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPreloadSourceNotConfigured is returned by PreloadFromProvider when no
+// source was configured with WithPreloadSource.
+var ErrPreloadSourceNotConfigured = errors.New("preload source not configured")
+
+// WithPreloadSource sets the shared provider scanned by PreloadFromProvider.
+// The cache provider acts as the local tier being warmed, and both providers
+// must hold entries encoded by the cache codec.
+func WithPreloadSource[V any, S any](source AdminProvider[S]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.preloadSource = source
+	}
+}
+
+// PreloadFromProvider scans the preload source for keys matching pattern and
+// copies up to maxEntries unexpired entries into the cache provider with their
+// original expiry, so a new instance starts warm instead of sending its first
+// reads to the shared backend. A maxEntries of zero or less copies every
+// matching entry. Refresh markers, tombstones and load locks are skipped. It
+// returns the number of entries copied.
+func (c *cacheImpl[V, S]) PreloadFromProvider(ctx context.Context, pattern string, maxEntries int) (int, error) {
+	if c.preloadSource == nil {
+		return 0, ErrPreloadSourceNotConfigured
+	}
+
+	nowMillis := c.now().UnixMilli()
+	copied := 0
+	var preloadErr error
+	err := c.preloadSource.Scan(ctx, pattern, func(key string, value S) bool {
+		if err := ctx.Err(); err != nil {
+			preloadErr = err
+
+			return false
+		}
+		if isMarkerKey(key) {
+			return true
+		}
+		co, err := c.decode(key, value)
+		if err != nil {
+			preloadErr = fmt.Errorf("decode %q: %w", key, err)
+
+			return false
+		}
+		if co.ExpireAtMillis <= nowMillis {
+			return true
+		}
+		ttl := time.Duration(co.ExpireAtMillis-nowMillis) * time.Millisecond
+		if err := c.provider.Set(ctx, key, value, ttl); err != nil {
+			preloadErr = fmt.Errorf("set %q: %w", key, err)
+
+			return false
+		}
+		copied++
+
+		return maxEntries <= 0 || copied < maxEntries
+	})
+	if err != nil {
+		return copied, err
+	}
+
+	return copied, preloadErr
+}
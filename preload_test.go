@@ -0,0 +1,75 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache_PreloadFromProvider(t *testing.T) {
+	t.Parallel()
+
+	source := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"user:1":  {Value: 1, ExpireAtMillis: 5000},
+		"user:2":  {Value: 2, ExpireAtMillis: 6000},
+		"user:3":  {Value: 3, ExpireAtMillis: 500},
+		"item:1":  {Value: 10, ExpireAtMillis: 5000},
+		"user:10": {Value: 4, ExpireAtMillis: 7000},
+
+		"user:1" + refreshMarkerSuffix: {ExpireAtMillis: 5000},
+		"user:2" + tombstoneSuffix:     {ExpireAtMillis: 6000},
+		"user:10" + loadLockSuffix:     {ExpireAtMillis: 7000},
+	}}
+	local := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(local, NoopCacheStorageCodec[int]{},
+		WithPreloadSource[int, CacheObject[int]](source),
+	)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return time.UnixMilli(1000) }
+
+	copied, err := cache.PreloadFromProvider(context.Background(), "user:*", 0)
+	if err != nil {
+		t.Fatalf("preload: %v", err)
+	}
+	if copied != 3 || len(local.items) != 3 {
+		t.Fatalf("expected 3 unexpired user entries without markers, got %d: %v", copied, local.items)
+	}
+	if got := local.items["user:2"]; got.Value != 2 || got.ExpireAtMillis != 6000 {
+		t.Fatalf("unexpected preloaded entry: %+v", got)
+	}
+	if _, ok := local.items["item:1"]; ok {
+		t.Fatal("expected entries outside the pattern not to be preloaded")
+	}
+}
+
+func TestCache_PreloadFromProviderMaxEntries(t *testing.T) {
+	t.Parallel()
+
+	source := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"a": {Value: 1, ExpireAtMillis: 5000},
+		"b": {Value: 2, ExpireAtMillis: 5000},
+		"c": {Value: 3, ExpireAtMillis: 5000},
+	}}
+	local := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(local, NoopCacheStorageCodec[int]{},
+		WithPreloadSource[int, CacheObject[int]](source),
+	)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return time.UnixMilli(1000) }
+
+	copied, err := cache.PreloadFromProvider(context.Background(), "", 2)
+	if err != nil {
+		t.Fatalf("preload: %v", err)
+	}
+	if copied != 2 || len(local.items) != 2 {
+		t.Fatalf("expected 2 preloaded entries, got %d: %v", copied, local.items)
+	}
+}
+
+func TestCache_PreloadFromProviderRequiresSource(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	if _, err := cache.PreloadFromProvider(context.Background(), "", 0); !errors.Is(err, ErrPreloadSourceNotConfigured) {
+		t.Fatalf("expected ErrPreloadSourceNotConfigured, got %v", err)
+	}
+}
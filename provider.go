@@ -10,7 +10,9 @@ import (
 type CacheProvider[S any] interface {
 	// Get retrieves a value from the cache by key.
 	Get(ctx context.Context, key string) (S, bool, error)
-	// Set stores a value in the cache with the specified key.
+	// Set stores a value in the cache with the specified key. The value may
+	// be shared with other consumers of the same write and must not be
+	// modified; see EncodedValue.
 	Set(ctx context.Context, key string, value S, ttl time.Duration) error
 	// Delete removes a value from the cache by key.
 	Delete(ctx context.Context, key string) error
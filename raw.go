@@ -0,0 +1,94 @@
+package crema
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const rawByteHeaderSize = 8
+
+// ErrRawByteDataTooShort is returned when RawByteCodec or StringCodec decodes data without
+// a complete expiry header.
+var ErrRawByteDataTooShort = errors.New("raw byte data shorter than expiry header")
+
+// RawByteCodec stores []byte values as-is behind an 8-byte big-endian expiry
+// header, avoiding the base64 expansion of JSONByteStringCodec for payloads
+// that are already serialized. Decoded values alias the storage bytes.
+type RawByteCodec struct{}
+
+var (
+	_ CacheStorageCodec[[]byte, []byte] = RawByteCodec{}
+	_ BufferReleasePolicy               = RawByteCodec{}
+)
+
+// Encode prefixes the payload with the expiry header.
+func (RawByteCodec) Encode(value CacheObject[[]byte]) ([]byte, error) {
+	return frameRaw(value.ExpireAtMillis, value.Value), nil
+}
+
+// Decode splits data into the expiry header and the payload.
+func (RawByteCodec) Decode(data []byte) (CacheObject[[]byte], error) {
+	if len(data) < rawByteHeaderSize {
+		return CacheObject[[]byte]{}, ErrRawByteDataTooShort
+	}
+
+	return CacheObject[[]byte]{
+		Value:          data[rawByteHeaderSize:],
+		ExpireAtMillis: int64(binary.BigEndian.Uint64(data)),
+	}, nil
+}
+
+// CanReleaseBufferOnDecode reports false because decoded values alias data.
+func (RawByteCodec) CanReleaseBufferOnDecode() bool {
+	return false
+}
+
+// frameRaw returns payload prefixed with the expiry header.
+func frameRaw[T string | []byte](expireAtMillis int64, payload T) []byte {
+	buf := make([]byte, rawByteHeaderSize+len(payload))
+	binary.BigEndian.PutUint64(buf, uint64(expireAtMillis))
+	copy(buf[rawByteHeaderSize:], payload)
+
+	return buf
+}
+
+// RawByteCache is a Cache of already-serialized blobs stored as-is.
+type RawByteCache = Cache[[]byte, []byte]
+
+// NewRawByteCache constructs a RawByteCache on provider using RawByteCodec.
+func NewRawByteCache(provider CacheProvider[[]byte], opts ...CacheOption[[]byte, []byte]) RawByteCache {
+	return NewCache[[]byte, []byte](provider, RawByteCodec{}, opts...)
+}
+
+// StringCodec stores string values as their bytes behind the same 8-byte
+// big-endian expiry header as RawByteCodec, avoiding JSON quoting and
+// escaping for cached HTML or pre-rendered JSON. It works with any []byte
+// provider, such as the Redis, Valkey and memcached providers.
+type StringCodec struct{}
+
+var (
+	_ CacheStorageCodec[string, []byte] = StringCodec{}
+	_ BufferReleasePolicy               = StringCodec{}
+)
+
+// Encode prefixes the string bytes with the expiry header.
+func (StringCodec) Encode(value CacheObject[string]) ([]byte, error) {
+	return frameRaw(value.ExpireAtMillis, value.Value), nil
+}
+
+// Decode splits data into the expiry header and a copy of the string.
+func (StringCodec) Decode(data []byte) (CacheObject[string], error) {
+	if len(data) < rawByteHeaderSize {
+		return CacheObject[string]{}, ErrRawByteDataTooShort
+	}
+
+	return CacheObject[string]{
+		Value:          string(data[rawByteHeaderSize:]),
+		ExpireAtMillis: int64(binary.BigEndian.Uint64(data)),
+	}, nil
+}
+
+// CanReleaseBufferOnDecode reports true because decoded strings are copies.
+func (StringCodec) CanReleaseBufferOnDecode() bool {
+	return true
+}
@@ -0,0 +1,89 @@
+package crema
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRawByteCodec_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"already":"serialized"}`)
+	data, err := RawByteCodec{}.Encode(CacheObject[[]byte]{Value: payload, ExpireAtMillis: 1234})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if len(data) != rawByteHeaderSize+len(payload) || !bytes.Equal(data[rawByteHeaderSize:], payload) {
+		t.Fatalf("expected payload stored as-is, got %q", data)
+	}
+	co, err := RawByteCodec{}.Decode(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !bytes.Equal(co.Value, payload) || co.ExpireAtMillis != 1234 {
+		t.Fatalf("unexpected decoded object %+v", co)
+	}
+	if _, err := (RawByteCodec{}).Decode([]byte{1, 2}); !errors.Is(err, ErrRawByteDataTooShort) {
+		t.Fatalf("expected ErrRawByteDataTooShort, got %v", err)
+	}
+}
+
+func TestNewRawByteCache(t *testing.T) {
+	t.Parallel()
+
+	provider := &byteProvider{items: make(map[string][]byte)}
+	cache := NewRawByteCache(provider)
+	payload := bytes.Repeat([]byte{0xff}, 64)
+
+	v, err := cache.GetOrLoad(context.Background(), "blob", time.Minute, func(context.Context) ([]byte, error) {
+		return payload, nil
+	})
+	if err != nil || !bytes.Equal(v, payload) {
+		t.Fatalf("expected payload, got %v, %v", v, err)
+	}
+	if got := len(provider.items["blob"]); got != rawByteHeaderSize+len(payload) {
+		t.Fatalf("expected %d stored bytes, got %d", rawByteHeaderSize+len(payload), got)
+	}
+	co, ok, err := cache.Get(context.Background(), "blob")
+	if err != nil || !ok || !bytes.Equal(co.Value, payload) {
+		t.Fatalf("expected cached payload, got %v, %v, %v", co.Value, ok, err)
+	}
+}
+
+func TestStringCodec_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	html := `<p class="greeting">"hello" & welcome</p>`
+	data, err := StringCodec{}.Encode(CacheObject[string]{Value: html, ExpireAtMillis: 99})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if string(data[rawByteHeaderSize:]) != html {
+		t.Fatalf("expected string stored without escaping, got %q", data)
+	}
+	co, err := StringCodec{}.Decode(data)
+	if err != nil || co.Value != html || co.ExpireAtMillis != 99 {
+		t.Fatalf("unexpected decoded object %+v, %v", co, err)
+	}
+	if _, err := (StringCodec{}).Decode(nil); !errors.Is(err, ErrRawByteDataTooShort) {
+		t.Fatalf("expected ErrRawByteDataTooShort, got %v", err)
+	}
+}
+
+func TestStringCodec_WithCompression(t *testing.T) {
+	t.Parallel()
+
+	codec := NewBinaryCompressionCodec[string](StringCodec{}, 0)
+	value := CacheObject[string]{Value: string(bytes.Repeat([]byte("<li>item</li>"), 100)), ExpireAtMillis: 7}
+	data, err := codec.Encode(value)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	co, err := codec.Decode(data)
+	if err != nil || co != value {
+		t.Fatalf("expected round trip through compression, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package crema
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RecencyTracker records the last access time per key for capacity planning
+// and targeted warming.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type RecencyTracker interface {
+	// Touch records an access to key at the given time.
+	Touch(ctx context.Context, key string, at time.Time) error
+	// Remove forgets the access record for key.
+	Remove(ctx context.Context, key string) error
+	// LeastRecentlyUsed returns up to n keys ordered from the oldest access.
+	LeastRecentlyUsed(ctx context.Context, n int) ([]string, error)
+	// MostRecentlyUsed returns up to n keys ordered from the newest access.
+	MostRecentlyUsed(ctx context.Context, n int) ([]string, error)
+}
+
+// recencyQueueSize caps the distinct keys waiting to be touched; accesses to
+// further keys are dropped until the worker catches up.
+const recencyQueueSize = 4096
+
+// recencyQueue coalesces sampled accesses by key, keeping the newest, for a
+// single worker to record.
+type recencyQueue struct {
+	mu      sync.Mutex
+	pending map[string]time.Time
+	running bool
+}
+
+func newRecencyQueue() *recencyQueue {
+	return &recencyQueue{pending: make(map[string]time.Time)}
+}
+
+// add queues an access to key at at. It reports true when the caller must
+// start the worker.
+func (q *recencyQueue) add(key string, at time.Time) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if prev, ok := q.pending[key]; ok {
+		if prev.Before(at) {
+			q.pending[key] = at
+		}
+	} else if len(q.pending) < recencyQueueSize {
+		q.pending[key] = at
+	}
+	if q.running || len(q.pending) == 0 {
+		return false
+	}
+	q.running = true
+
+	return true
+}
+
+// take returns the queued accesses, or nil and stops the worker when there
+// are none.
+func (q *recencyQueue) take() map[string]time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		q.running = false
+
+		return nil
+	}
+	batch := q.pending
+	q.pending = make(map[string]time.Time, len(batch))
+
+	return batch
+}
+
+// stop discards the queue when the worker cannot be started.
+func (q *recencyQueue) stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	clear(q.pending)
+	q.running = false
+}
+
+// forget drops a queued access to key.
+func (q *recencyQueue) forget(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.pending, key)
+}
+
+// DefaultMaxRecencyKeys is the number of keys a MemoryRecencyTracker keeps
+// unless WithMaxRecencyKeys says otherwise.
+const DefaultMaxRecencyKeys = 100_000
+
+// recencySweepInterval is the number of touches between retention sweeps.
+const recencySweepInterval = 1024
+
+// MemoryRecencyTracker keeps access records in process memory, bounded by a
+// key cap and an optional retention period.
+type MemoryRecencyTracker struct {
+	_          noCopy
+	mu         sync.Mutex
+	accessed   map[string]int64
+	maxKeys    int
+	retention  time.Duration
+	sinceSweep int
+}
+
+var _ RecencyTracker = (*MemoryRecencyTracker)(nil)
+
+// MemoryRecencyTrackerOption customizes a MemoryRecencyTracker.
+type MemoryRecencyTrackerOption func(*MemoryRecencyTracker)
+
+// WithMaxRecencyKeys caps the keys a MemoryRecencyTracker keeps, dropping the
+// least recently used ones beyond it. Non-positive values remove the cap.
+func WithMaxRecencyKeys(n int) MemoryRecencyTrackerOption {
+	return func(m *MemoryRecencyTracker) {
+		m.maxKeys = max(n, 0)
+	}
+}
+
+// WithRecencyRetention drops records last touched more than retention before
+// the newest touch, such as keys whose entries have outlived the cache TTL.
+func WithRecencyRetention(retention time.Duration) MemoryRecencyTrackerOption {
+	return func(m *MemoryRecencyTracker) {
+		m.retention = max(retention, 0)
+	}
+}
+
+// NewMemoryRecencyTracker constructs an empty MemoryRecencyTracker keeping up
+// to DefaultMaxRecencyKeys keys unless overridden by opts.
+func NewMemoryRecencyTracker(opts ...MemoryRecencyTrackerOption) *MemoryRecencyTracker {
+	m := &MemoryRecencyTracker{accessed: make(map[string]int64), maxKeys: DefaultMaxRecencyKeys}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+
+	return m
+}
+
+// Touch records an access to key, keeping the newest timestamp.
+func (m *MemoryRecencyTracker) Touch(_ context.Context, key string, at time.Time) error {
+	nanos := at.UnixNano()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if prev, ok := m.accessed[key]; !ok || prev < nanos {
+		m.accessed[key] = nanos
+	}
+	m.sinceSweep++
+	if (m.maxKeys > 0 && len(m.accessed) > m.maxKeys) || (m.retention > 0 && m.sinceSweep >= recencySweepInterval) {
+		m.sweepLocked(nanos)
+	}
+
+	return nil
+}
+
+// sweepLocked drops records older than the retention before now and, when
+// over the cap, the oldest records down to nine tenths of it, so that sweeps
+// stay rare.
+func (m *MemoryRecencyTracker) sweepLocked(now int64) {
+	m.sinceSweep = 0
+	if m.retention > 0 {
+		cutoff := now - m.retention.Nanoseconds()
+		for key, at := range m.accessed {
+			if at < cutoff {
+				delete(m.accessed, key)
+			}
+		}
+	}
+	if m.maxKeys <= 0 || len(m.accessed) <= m.maxKeys {
+		return
+	}
+	keys := make([]string, 0, len(m.accessed))
+	for key := range m.accessed {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Compare(m.accessed[a], m.accessed[b])
+	})
+	for _, key := range keys[:len(keys)-max(m.maxKeys*9/10, 1)] {
+		delete(m.accessed, key)
+	}
+}
+
+// Remove forgets the access record for key.
+func (m *MemoryRecencyTracker) Remove(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.accessed, key)
+
+	return nil
+}
+
+// LeastRecentlyUsed returns up to n keys ordered from the oldest access.
+func (m *MemoryRecencyTracker) LeastRecentlyUsed(_ context.Context, n int) ([]string, error) {
+	return m.ordered(n, false), nil
+}
+
+// MostRecentlyUsed returns up to n keys ordered from the newest access.
+func (m *MemoryRecencyTracker) MostRecentlyUsed(_ context.Context, n int) ([]string, error) {
+	return m.ordered(n, true), nil
+}
+
+func (m *MemoryRecencyTracker) ordered(n int, newestFirst bool) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	type record struct {
+		key string
+		at  int64
+	}
+	m.mu.Lock()
+	records := make([]record, 0, len(m.accessed))
+	for key, at := range m.accessed {
+		records = append(records, record{key: key, at: at})
+	}
+	m.mu.Unlock()
+
+	slices.SortFunc(records, func(a, b record) int {
+		if a.at != b.at {
+			if (a.at < b.at) != newestFirst {
+				return -1
+			}
+
+			return 1
+		}
+		if a.key < b.key {
+			return -1
+		}
+
+		return 1
+	})
+	keys := make([]string, 0, min(n, len(records)))
+	for _, r := range records[:min(n, len(records))] {
+		keys = append(keys, r.key)
+	}
+
+	return keys
+}
@@ -0,0 +1,249 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryRecencyTracker_Ordering(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewMemoryRecencyTracker()
+	ctx := context.Background()
+	for i, key := range []string{"a", "b", "c"} {
+		if err := tracker.Touch(ctx, key, time.UnixMilli(int64(1000+i))); err != nil {
+			t.Fatalf("touch %s: %v", key, err)
+		}
+	}
+	// older touches must not move a key backwards
+	if err := tracker.Touch(ctx, "c", time.UnixMilli(1)); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+
+	lru, err := tracker.LeastRecentlyUsed(ctx, 2)
+	if err != nil {
+		t.Fatalf("lru: %v", err)
+	}
+	if !slices.Equal(lru, []string{"a", "b"}) {
+		t.Fatalf("unexpected lru keys: %v", lru)
+	}
+	mru, err := tracker.MostRecentlyUsed(ctx, 5)
+	if err != nil {
+		t.Fatalf("mru: %v", err)
+	}
+	if !slices.Equal(mru, []string{"c", "b", "a"}) {
+		t.Fatalf("unexpected mru keys: %v", mru)
+	}
+
+	if err := tracker.Remove(ctx, "b"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	mru, _ = tracker.MostRecentlyUsed(ctx, 5)
+	if !slices.Equal(mru, []string{"c", "a"}) {
+		t.Fatalf("unexpected mru keys after remove: %v", mru)
+	}
+	if keys, _ := tracker.LeastRecentlyUsed(ctx, 0); keys != nil {
+		t.Fatalf("expected nil for n=0, got %v", keys)
+	}
+}
+
+func TestCache_RecencyTracker(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	tracker := NewMemoryRecencyTracker()
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithRecencyTracker[int, CacheObject[int]](tracker, 1))
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1000)
+	impl.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "a", CacheObject[int]{Value: 1, ExpireAtMillis: 5000}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	now = time.UnixMilli(1001)
+	if err := cache.Set(ctx, "b", CacheObject[int]{Value: 2, ExpireAtMillis: 5000}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	now = time.UnixMilli(1002)
+	if _, _, err := cache.Get(ctx, "a"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	mru, _ := tracker.MostRecentlyUsed(ctx, 2)
+	if !slices.Equal(mru, []string{"a", "b"}) {
+		t.Fatalf("unexpected mru keys: %v", mru)
+	}
+
+	if err := cache.Delete(ctx, "a"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	mru, _ = tracker.MostRecentlyUsed(ctx, 2)
+	if !slices.Equal(mru, []string{"b"}) {
+		t.Fatalf("expected deleted key to be forgotten, got %v", mru)
+	}
+}
+
+func TestCache_RecencyTrackerSampling(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	tracker := NewMemoryRecencyTracker()
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithRecencyTracker[int, CacheObject[int]](tracker, 0.5))
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	impl.now = func() time.Time { return time.UnixMilli(1000) }
+	impl.random = fakeRandom(0.9)
+
+	if err := cache.Set(context.Background(), "a", CacheObject[int]{Value: 1, ExpireAtMillis: 5000}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := cache.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if keys, _ := tracker.MostRecentlyUsed(context.Background(), 1); len(keys) != 0 {
+		t.Fatalf("expected access to be sampled out, got %v", keys)
+	}
+}
+
+type failingRecencyTracker struct {
+	MemoryRecencyTracker
+	err error
+}
+
+func (f *failingRecencyTracker) Touch(context.Context, string, time.Time) error {
+	return f.err
+}
+
+func TestCache_RecencyTrackerErrorsAreReported(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	expectErr := errors.New("zadd failed")
+	tracker := &failingRecencyTracker{err: expectErr}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithRecencyTracker[int, CacheObject[int]](tracker, 1))
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return time.UnixMilli(1000) }
+
+	if err := cache.Set(context.Background(), "a", CacheObject[int]{Value: 1, ExpireAtMillis: 5000}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	select {
+	case err := <-cache.Errors():
+		if !errors.Is(err, expectErr) {
+			t.Fatalf("expected %v, got %v", expectErr, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for background error")
+	}
+}
+
+// blockingRecencyTracker counts Touch calls and concurrent Touch calls, and
+// blocks the first one until released.
+type blockingRecencyTracker struct {
+	MemoryRecencyTracker
+	started    chan struct{}
+	release    chan struct{}
+	once       sync.Once
+	touches    atomic.Int32
+	running    atomic.Int32
+	maxRunning atomic.Int32
+}
+
+func (b *blockingRecencyTracker) Touch(ctx context.Context, key string, at time.Time) error {
+	b.touches.Add(1)
+	running := b.running.Add(1)
+	defer b.running.Add(-1)
+	if running > b.maxRunning.Load() {
+		b.maxRunning.Store(running)
+	}
+	b.once.Do(func() {
+		close(b.started)
+		<-b.release
+	})
+
+	return nil
+}
+
+func TestCache_RecencyTrackerCoalescesTouches(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	tracker := &blockingRecencyTracker{started: make(chan struct{}), release: make(chan struct{})}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithRecencyTracker[int, CacheObject[int]](tracker, 1))
+	ctx := context.Background()
+	expireAt := time.Now().Add(time.Hour).UnixMilli()
+	for _, key := range []string{"blocked", "a", "b"} {
+		if err := cache.Set(ctx, key, CacheObject[int]{Value: 1, ExpireAtMillis: expireAt}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		if key == "blocked" {
+			<-tracker.started
+		}
+	}
+	for range 100 {
+		for _, key := range []string{"a", "b"} {
+			if _, _, err := cache.Get(ctx, key); err != nil {
+				t.Fatalf("get: %v", err)
+			}
+		}
+	}
+	close(tracker.release)
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	if got := tracker.touches.Load(); got != 3 {
+		t.Fatalf("expected one touch per key, got %d", got)
+	}
+	if got := tracker.maxRunning.Load(); got != 1 {
+		t.Fatalf("expected touches recorded one at a time, got %d", got)
+	}
+}
+
+func TestMemoryRecencyTracker_MaxKeys(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewMemoryRecencyTracker(WithMaxRecencyKeys(10))
+	ctx := context.Background()
+	for i := range 11 {
+		if err := tracker.Touch(ctx, fmt.Sprintf("key:%02d", i), time.UnixMilli(int64(1000+i))); err != nil {
+			t.Fatalf("touch: %v", err)
+		}
+	}
+
+	keys, _ := tracker.LeastRecentlyUsed(ctx, 20)
+	if len(keys) != 9 || keys[0] != "key:02" || keys[8] != "key:10" {
+		t.Fatalf("expected the oldest keys dropped down to 9, got %v", keys)
+	}
+}
+
+func TestMemoryRecencyTracker_Retention(t *testing.T) {
+	t.Parallel()
+
+	tracker := NewMemoryRecencyTracker(WithRecencyRetention(time.Minute))
+	ctx := context.Background()
+	start := time.UnixMilli(0)
+	if err := tracker.Touch(ctx, "expired", start); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+	for i := range recencySweepInterval {
+		if err := tracker.Touch(ctx, "fresh", start.Add(time.Hour+time.Duration(i))); err != nil {
+			t.Fatalf("touch: %v", err)
+		}
+	}
+
+	if keys, _ := tracker.LeastRecentlyUsed(ctx, 10); !slices.Equal(keys, []string{"fresh"}) {
+		t.Fatalf("expected records past the retention dropped, got %v", keys)
+	}
+}
@@ -0,0 +1,219 @@
+package crema
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultReencodeRate = 100
+
+// UpgradableCodec is an optional CacheStorageCodec extension for codecs that
+// can tell payloads written in an older format, such as a previous format of
+// a MultiFormatCodec, from those Encode produces. WithReencodeOnUpgrade uses
+// it to find entries worth rewriting.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type UpgradableCodec[S any] interface {
+	// Outdated reports whether data was written in a format other than the
+	// one Encode currently produces.
+	Outdated(data S) bool
+}
+
+var (
+	_ UpgradableCodec[[]byte] = &multiFormatCodec[any]{}
+	_ UpgradableCodec[[]byte] = &writerCodec[any]{}
+)
+
+// Outdated reports whether data was written in one of the previous formats.
+func (m *multiFormatCodec[V]) Outdated(data []byte) bool {
+	return !m.current.Match(data)
+}
+
+// Outdated reports whether data lacks a writer header or its payload is
+// outdated for the inner codec.
+func (w *writerCodec[V]) Outdated(data []byte) bool {
+	_, payload, ok, err := parseWriterHeader(data)
+	if err != nil {
+		return false
+	}
+	if !ok {
+		return true
+	}
+	if upgradable, isUpgradable := any(w.inner).(UpgradableCodec[[]byte]); isUpgradable {
+		return upgradable.Outdated(payload)
+	}
+
+	return false
+}
+
+// ReencodePolicy configures WithReencodeOnUpgrade.
+type ReencodePolicy[S any] struct {
+	// Rate caps rewrites per second across the cache, so a codec rollout
+	// does not double the write load of a busy keyspace. Outdated entries
+	// read while the budget is spent are left for a later read. Defaults to
+	// 100.
+	Rate float64
+	// Outdated reports whether the stored payload of key should be
+	// rewritten. Nil uses the codec's UpgradableCodec implementation.
+	Outdated func(key string, data S) bool
+}
+
+// ReencodeOutcome is how an attempt to rewrite an outdated entry ended.
+type ReencodeOutcome int
+
+const (
+	// ReencodeSucceeded means the entry was rewritten with the current codec.
+	ReencodeSucceeded ReencodeOutcome = iota
+	// ReencodeSkippedRate means the entry was left as is because the rewrite
+	// rate was exhausted.
+	ReencodeSkippedRate
+	// ReencodeFailed means encoding or storing the entry failed.
+	ReencodeFailed
+)
+
+func (o ReencodeOutcome) String() string {
+	switch o {
+	case ReencodeSucceeded:
+		return "succeeded"
+	case ReencodeSkippedRate:
+		return "skipped_rate"
+	case ReencodeFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// reencoder rate limits and deduplicates rewrites of outdated entries.
+type reencoder[S any] struct {
+	outdated func(key string, data S) bool
+	rate     float64
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	pending  map[string]struct{}
+}
+
+// WithReencodeOnUpgrade rewrites entries read in an outdated format with the
+// current codec in the background, so the keyspace converges to a new
+// schema or compression without a migration job. Entries keep their value
+// and expiry. Rewrites are capped at policy.Rate per second and reported
+// through RecordReencode. The rewrite is not atomic with the read, so a
+// write landing in between may be overwritten by the older value it
+// replaced. It is disabled when policy.Outdated is nil and the codec does
+// not implement UpgradableCodec.
+func WithReencodeOnUpgrade[V any, S any](policy ReencodePolicy[S]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if policy.Rate <= 0 {
+			policy.Rate = defaultReencodeRate
+		}
+		c.reencode = &reencoder[S]{
+			outdated: policy.Outdated,
+			rate:     policy.Rate,
+			tokens:   max(policy.Rate, 1),
+			pending:  make(map[string]struct{}),
+		}
+	}
+}
+
+// resolveReencode falls back to the codec's UpgradableCodec implementation
+// when no Outdated function was configured, or disables re-encoding.
+func (c *cacheImpl[V, S]) resolveReencode() {
+	if c.reencode == nil || c.reencode.outdated != nil {
+		return
+	}
+	upgradable, ok := any(c.codec).(UpgradableCodec[S])
+	if !ok {
+		c.reencode = nil
+
+		return
+	}
+	c.reencode.outdated = func(_ string, data S) bool {
+		return upgradable.Outdated(data)
+	}
+}
+
+// reencodeOutdated schedules a rewrite of co for key when its stored
+// payload data is outdated.
+func (c *cacheImpl[V, S]) reencodeOutdated(ctx context.Context, key string, data S, co CacheObject[V]) {
+	r := c.reencode
+	if r == nil || !c.experimentEnabled(ExperimentReencodeOnUpgrade, key) || !r.outdated(key, data) {
+		return
+	}
+	if !r.acquire(key) {
+		return
+	}
+	if !r.take(c.now()) {
+		r.release(key)
+		c.metrics.RecordReencode(ctx, ReencodeSkippedRate)
+
+		return
+	}
+	if !c.runner.goAsync(func(context.Context) error {
+		defer r.release(key)
+
+		return c.rewrite(context.WithoutCancel(ctx), key, co)
+	}) {
+		r.release(key)
+	}
+}
+
+// rewrite stores co for key encoded with the current codec.
+func (c *cacheImpl[V, S]) rewrite(ctx context.Context, key string, co CacheObject[V]) error {
+	ttl := time.UnixMilli(co.ExpireAtMillis).Sub(c.now())
+	if ttl <= 0 {
+		return nil
+	}
+	started := time.Now()
+	raw, err := c.encode(key, co)
+	if err != nil {
+		c.metrics.RecordReencode(ctx, ReencodeFailed)
+
+		return fmt.Errorf("re-encode %q: %w", key, c.observeError(ctx, ErrorPhaseEncode, key, started, err))
+	}
+	started = time.Now()
+	if err := c.provider.Set(ctx, key, raw, ttl); err != nil {
+		c.metrics.RecordReencode(ctx, ReencodeFailed)
+
+		return fmt.Errorf("re-encode %q: %w", key, c.observeError(ctx, ErrorPhaseProviderSet, key, started, err))
+	}
+	c.metrics.RecordReencode(ctx, ReencodeSucceeded)
+
+	return nil
+}
+
+// acquire claims key for a rewrite, reporting false when one is pending.
+func (r *reencoder[S]) acquire(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pending[key]; ok {
+		return false
+	}
+	r.pending[key] = struct{}{}
+
+	return true
+}
+
+func (r *reencoder[S]) release(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, key)
+}
+
+// take removes a token from the bucket refilled at rate per second, holding
+// at most one second of rewrites.
+func (r *reencoder[S]) take(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.last.IsZero() && now.After(r.last) {
+		r.tokens = min(r.tokens+now.Sub(r.last).Seconds()*r.rate, max(r.rate, 1))
+	}
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+
+	return true
+}
@@ -0,0 +1,153 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type reencodeMetricsProvider struct {
+	BaseMetricsProvider
+	succeeded atomic.Int64
+	skipped   atomic.Int64
+	failed    atomic.Int64
+}
+
+func (m *reencodeMetricsProvider) RecordReencode(_ context.Context, outcome ReencodeOutcome) {
+	switch outcome {
+	case ReencodeSucceeded:
+		m.succeeded.Add(1)
+	case ReencodeSkippedRate:
+		m.skipped.Add(1)
+	case ReencodeFailed:
+		m.failed.Add(1)
+	}
+}
+
+func newReencodeTestCache(t *testing.T, rate float64) (Cache[string, []byte], *MemoryCacheProvider[[]byte], *reencodeMetricsProvider, CacheStorageCodec[string, []byte]) {
+	t.Helper()
+
+	legacy := JSONByteStringCodec[string]{}
+	provider := NewMemoryCacheProvider[[]byte]()
+	metrics := &reencodeMetricsProvider{}
+	cache := NewCache(provider, NewMultiFormatCodec(
+		CodecFormat[string]{Codec: NewBinaryCompressionCodec[string](legacy, 0), Match: MatchPrefix(CompressionTypeIDZlib)},
+		CodecFormat[string]{Codec: legacy, Match: MatchJSON},
+	),
+		WithMetricsProvider[string, []byte](metrics),
+		WithReencodeOnUpgrade[string, []byte](ReencodePolicy[[]byte]{Rate: rate}),
+	)
+
+	return cache, provider, metrics, legacy
+}
+
+func TestWithReencodeOnUpgrade_RewritesOutdatedEntries(t *testing.T) {
+	t.Parallel()
+
+	cache, provider, metrics, legacy := newReencodeTestCache(t, 100)
+	ctx := context.Background()
+	want := CacheObject[string]{Value: "v", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}
+	old, err := legacy.Encode(want)
+	if err != nil {
+		t.Fatalf("encode legacy: %v", err)
+	}
+	if err := provider.Set(ctx, "key", old, time.Minute); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	if got, ok, err := cache.Get(ctx, "key"); err != nil || !ok || got != want {
+		t.Fatalf("expected %+v, got %+v, %v, %v", want, got, ok, err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	stored, _, _ := provider.Get(ctx, "key")
+	if len(stored) == 0 || stored[0] != CompressionTypeIDZlib {
+		t.Fatalf("expected entry rewritten in the current format, got %q", stored)
+	}
+	if got := metrics.succeeded.Load(); got != 1 {
+		t.Fatalf("expected one rewrite, got %d", got)
+	}
+
+	if got, ok, err := cache.Get(ctx, "key"); err != nil || !ok || got != want {
+		t.Fatalf("expected %+v after rewrite, got %+v, %v, %v", want, got, ok, err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := metrics.succeeded.Load(); got != 1 {
+		t.Fatalf("expected current entries not to be rewritten, got %d rewrites", got)
+	}
+}
+
+func TestWithReencodeOnUpgrade_RateLimits(t *testing.T) {
+	t.Parallel()
+
+	cache, provider, metrics, legacy := newReencodeTestCache(t, 1)
+	impl := cache.(*cacheImpl[string, []byte])
+	now := time.Now()
+	impl.now = func() time.Time { return now }
+	ctx := context.Background()
+	for _, key := range []string{"a", "b"} {
+		old, err := legacy.Encode(CacheObject[string]{Value: key, ExpireAtMillis: now.Add(time.Minute).UnixMilli()})
+		if err != nil {
+			t.Fatalf("encode legacy: %v", err)
+		}
+		if err := provider.Set(ctx, key, old, time.Minute); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+		if _, _, err := cache.Get(ctx, key); err != nil {
+			t.Fatalf("get: %v", err)
+		}
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if metrics.succeeded.Load() != 1 || metrics.skipped.Load() != 1 {
+		t.Fatalf("expected one rewrite and one skip, got %d and %d", metrics.succeeded.Load(), metrics.skipped.Load())
+	}
+
+	now = now.Add(time.Second)
+	if _, _, err := cache.Get(ctx, "b"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := metrics.succeeded.Load(); got != 2 {
+		t.Fatalf("expected the skipped entry rewritten once the budget refilled, got %d rewrites", got)
+	}
+}
+
+func TestWithReencodeOnUpgrade_DisabledWithoutUpgradableCodec(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(NewMemoryCacheProvider[[]byte](), JSONByteStringCodec[string]{},
+		WithReencodeOnUpgrade[string, []byte](ReencodePolicy[[]byte]{}),
+	)
+	if cache.(*cacheImpl[string, []byte]).reencode != nil {
+		t.Fatal("expected re-encoding disabled for a codec without formats")
+	}
+}
+
+func TestWriterCodec_Outdated(t *testing.T) {
+	t.Parallel()
+
+	legacy := JSONByteStringCodec[string]{}
+	codec := withWriterHeader[string, []byte](legacy, WriterIdentity{Service: "svc"}, time.Now).(UpgradableCodec[[]byte])
+	old, err := legacy.Encode(CacheObject[string]{Value: "v"})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if !codec.Outdated(old) {
+		t.Fatal("expected payload without writer header to be outdated")
+	}
+	current, err := codec.(CacheStorageCodec[string, []byte]).Encode(CacheObject[string]{Value: "v"})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if codec.Outdated(current) {
+		t.Fatal("expected payload with writer header to be current")
+	}
+}
@@ -0,0 +1,63 @@
+package crema
+
+import (
+	"sync"
+	"time"
+)
+
+// refreshDebouncer remembers when each key was last loaded.
+type refreshDebouncer struct {
+	window      time.Duration
+	mu          sync.Mutex
+	refreshedAt map[string]int64
+	sweptAt     int64
+}
+
+// WithRefreshDebounce keeps a key loaded within the last window from being
+// revalidated early again in that window, whatever the revalidation
+// probability says. It caps the origin load caused by pathological access
+// patterns on near-expiry entries. Expired entries are still reloaded, and
+// refreshes are tracked per process. A non-positive window disables it.
+func WithRefreshDebounce[V any, S any](window time.Duration) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if window <= 0 {
+			c.refreshDebounce = nil
+
+			return
+		}
+		c.refreshDebounce = &refreshDebouncer{window: window, refreshedAt: make(map[string]int64)}
+	}
+}
+
+// markRefreshed records that key was loaded at nowMillis and forgets keys
+// whose window has passed, at most once per window.
+func (d *refreshDebouncer) markRefreshed(key string, nowMillis int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.refreshedAt[key] = nowMillis
+	windowMillis := d.window.Milliseconds()
+	if nowMillis-d.sweptAt < windowMillis {
+		return
+	}
+	for k, at := range d.refreshedAt {
+		if nowMillis-at >= windowMillis {
+			delete(d.refreshedAt, k)
+		}
+	}
+	d.sweptAt = nowMillis
+}
+
+// debounced reports whether key was loaded within the window before nowMillis.
+func (d *refreshDebouncer) debounced(key string, nowMillis int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	at, ok := d.refreshedAt[key]
+
+	return ok && nowMillis-at < d.window.Milliseconds()
+}
+
+func (c *cacheImpl[V, S]) markRefreshed(key string) {
+	if c.refreshDebounce != nil {
+		c.refreshDebounce.markRefreshed(key, c.now().UnixMilli())
+	}
+}
@@ -0,0 +1,64 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithRefreshDebounce_SkipsEarlyRevalidationWithinWindow(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithRevalidationWindow[int, CacheObject[int]](time.Minute),
+		WithRefreshDebounce[int, CacheObject[int]](10*time.Second),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(0)
+
+	loads := 0
+	loader := func(context.Context) (int, error) {
+		loads++
+
+		return loads, nil
+	}
+	ctx := context.Background()
+	steps := []struct {
+		advance   time.Duration
+		wantLoads int
+	}{
+		{advance: 0, wantLoads: 1},
+		{advance: 5 * time.Second, wantLoads: 1},
+		{advance: 10 * time.Second, wantLoads: 2},
+		{advance: time.Second, wantLoads: 2},
+		{advance: 35 * time.Second, wantLoads: 3},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if _, err := cache.GetOrLoad(ctx, "key", 30*time.Second, loader); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		if loads != step.wantLoads {
+			t.Fatalf("step %d: expected %d loads, got %d", i, step.wantLoads, loads)
+		}
+	}
+}
+
+func TestRefreshDebouncer_ForgetsPassedWindows(t *testing.T) {
+	t.Parallel()
+
+	d := &refreshDebouncer{window: time.Second, refreshedAt: make(map[string]int64)}
+	d.markRefreshed("a", 0)
+	if !d.debounced("a", 999) {
+		t.Fatal("expected key to be debounced within the window")
+	}
+	if d.debounced("a", 1_000) {
+		t.Fatal("expected key not to be debounced after the window")
+	}
+	d.markRefreshed("b", 2_000)
+	if _, ok := d.refreshedAt["a"]; ok {
+		t.Fatal("expected passed window to be forgotten")
+	}
+}
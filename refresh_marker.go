@@ -0,0 +1,95 @@
+package crema
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// refreshMarkerSuffix is appended to a key to form the sibling key holding
+// its refresh marker.
+const refreshMarkerSuffix = markerKeyPrefix + "refreshing"
+
+// refreshMarker coordinates early revalidation across processes.
+type refreshMarker struct {
+	lease   time.Duration
+	maxSkew time.Duration
+}
+
+// WithRefreshMarker coordinates early revalidation across processes sharing
+// a provider. Before reloading an entry that has not expired yet, the cache
+// stores a "refresh in progress until now+lease" marker under a sibling key;
+// other processes that find an unexpired marker keep serving the cached value
+// instead of starting the same refresh. Expired entries are still reloaded by
+// every process that needs them, since there is nothing to serve meanwhile.
+//
+// Markers are written with GetOrSetProvider when the provider implements it,
+// and with a best-effort Get and Set otherwise. They are encoded with the
+// cache codec as an entry holding the zero value. Since the marker deadline
+// comes from the writer's clock, a marker ending more than lease+maxSkew in
+// the future is treated as written by a skewed clock and ignored. Provider
+// errors let the refresh proceed. A non-positive lease disables it.
+func WithRefreshMarker[V any, S any](lease time.Duration, maxSkew time.Duration) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if lease <= 0 {
+			c.refreshMarker = nil
+
+			return
+		}
+		c.refreshMarker = &refreshMarker{lease: lease, maxSkew: max(maxSkew, 0)}
+	}
+}
+
+// held reports whether a marker ending at untilMillis still claims the
+// refresh at nowMillis.
+func (m *refreshMarker) held(untilMillis int64, nowMillis int64) bool {
+	return untilMillis > nowMillis && untilMillis-nowMillis <= (m.lease+m.maxSkew).Milliseconds()
+}
+
+// claimRefresh reports whether this call should refresh key, whose cached
+// entry expires at expireAtMillis. It is false only when the entry is still
+// fresh and another caller holds the refresh marker.
+func (c *cacheImpl[V, S]) claimRefresh(ctx context.Context, key string, nowMillis int64, expireAtMillis int64) bool {
+	m := c.refreshMarker
+	if m == nil || expireAtMillis <= nowMillis {
+		return true
+	}
+	markerKey := key + refreshMarkerSuffix
+	marker := CacheObject[V]{ExpireAtMillis: nowMillis + m.lease.Milliseconds()}
+	encoded, err := c.encode(markerKey, marker)
+	if err != nil {
+		return true
+	}
+
+	if setter, ok := providerAs[GetOrSetProvider[S]](c.provider); ok {
+		actual, loaded, err := setter.GetOrSet(ctx, markerKey, encoded, m.lease)
+		if err != nil || !loaded {
+			return true
+		}
+		if c.refreshMarkerHeld(markerKey, actual, nowMillis) {
+			c.logger.Debug("skipping refresh claimed by another caller", slog.String("key", key))
+
+			return false
+		}
+	} else if rv, exists, err := c.provider.Get(ctx, markerKey); err == nil && exists && c.refreshMarkerHeld(markerKey, rv, nowMillis) {
+		c.logger.Debug("skipping refresh claimed by another caller", slog.String("key", key))
+
+		return false
+	}
+	if err := c.provider.Set(ctx, markerKey, encoded, m.lease); err != nil {
+		c.logger.Debug("failed to store refresh marker", slog.String("key", key), slog.String("error", err.Error()))
+	}
+
+	return true
+}
+
+// refreshMarkerHeld decodes a stored marker and reports whether it is held.
+// Markers that fail to decode are not held.
+func (c *cacheImpl[V, S]) refreshMarkerHeld(markerKey string, stored S, nowMillis int64) bool {
+	marker, err := c.decode(markerKey, stored)
+	if err != nil {
+		return false
+	}
+
+	return c.refreshMarker.held(marker.ExpireAtMillis, nowMillis)
+}
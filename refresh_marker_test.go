@@ -0,0 +1,121 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithRefreshMarker_OneProcessRefreshesEarly(t *testing.T) {
+	t.Parallel()
+
+	providers := map[string]func() CacheProvider[CacheObject[int]]{
+		"get and set": func() CacheProvider[CacheObject[int]] {
+			return &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+		},
+		"get or set": func() CacheProvider[CacheObject[int]] {
+			return NewMemoryCacheProvider[CacheObject[int]]()
+		},
+	}
+	for name, newProvider := range providers {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := newProvider()
+			now := time.Now()
+			newCache := func() Cache[int, CacheObject[int]] {
+				cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+					WithRevalidationWindow[int, CacheObject[int]](time.Minute),
+					WithRefreshMarker[int, CacheObject[int]](10*time.Second, time.Second),
+				)
+				impl := cache.(*cacheImpl[int, CacheObject[int]])
+				impl.now = func() time.Time { return now }
+				impl.random = fakeRandom(0)
+
+				return cache
+			}
+			first, second := newCache(), newCache()
+			ctx := context.Background()
+			if err := first.Set(ctx, "key", CacheObject[int]{Value: 1, ExpireAtMillis: now.Add(30 * time.Second).UnixMilli()}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			loads := 0
+			loader := func(context.Context) (int, error) {
+				loads++
+
+				return 2, nil
+			}
+			if v, err := first.GetOrLoad(ctx, "key", 20*time.Second, loader); err != nil || v != 2 {
+				t.Fatalf("expected refreshed value 2, got %d, %v", v, err)
+			}
+			if err := second.Set(ctx, "key", CacheObject[int]{Value: 1, ExpireAtMillis: now.Add(30 * time.Second).UnixMilli()}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if v, err := second.GetOrLoad(ctx, "key", 20*time.Second, loader); err != nil || v != 1 {
+				t.Fatalf("expected cached value 1 while the refresh is claimed, got %d, %v", v, err)
+			}
+			if loads != 1 {
+				t.Fatalf("expected 1 load, got %d", loads)
+			}
+		})
+	}
+}
+
+func TestWithRefreshMarker_IgnoresExpiredAndSkewedMarkers(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithRevalidationWindow[int, CacheObject[int]](time.Minute),
+		WithRefreshMarker[int, CacheObject[int]](10*time.Second, time.Second),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(0)
+	ctx := context.Background()
+
+	loads := 0
+	loader := func(context.Context) (int, error) {
+		loads++
+
+		return loads, nil
+	}
+	markers := []struct {
+		name  string
+		until time.Time
+		want  int
+	}{
+		{name: "held", until: now.Add(5 * time.Second), want: 0},
+		{name: "held within skew", until: now.Add(11 * time.Second), want: 0},
+		{name: "passed", until: now.Add(-time.Second), want: 1},
+		{name: "skewed clock", until: now.Add(time.Hour), want: 1},
+	}
+	for _, marker := range markers {
+		loads = 0
+		provider.mu.Lock()
+		provider.items["key"] = CacheObject[int]{Value: -1, ExpireAtMillis: now.Add(30 * time.Second).UnixMilli()}
+		provider.items["key"+refreshMarkerSuffix] = CacheObject[int]{ExpireAtMillis: marker.until.UnixMilli()}
+		provider.mu.Unlock()
+		if _, err := cache.GetOrLoad(ctx, "key", time.Minute, loader); err != nil {
+			t.Fatalf("%s: %v", marker.name, err)
+		}
+		if loads != marker.want {
+			t.Fatalf("%s: expected %d loads, got %d", marker.name, marker.want, loads)
+		}
+	}
+
+	// Expired entries are reloaded even when the refresh is claimed.
+	loads = 0
+	provider.mu.Lock()
+	provider.items["key"] = CacheObject[int]{Value: -1, ExpireAtMillis: now.Add(-time.Second).UnixMilli()}
+	provider.items["key"+refreshMarkerSuffix] = CacheObject[int]{ExpireAtMillis: now.Add(5 * time.Second).UnixMilli()}
+	provider.mu.Unlock()
+	if _, err := cache.GetOrLoad(ctx, "key", time.Minute, loader); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if loads != 1 {
+		t.Fatalf("expected expired entry to be reloaded, got %d loads", loads)
+	}
+}
@@ -0,0 +1,149 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCacheNotRegistered is returned by Lookup for unknown names.
+	ErrCacheNotRegistered = errors.New("cache not registered")
+	// ErrCacheAlreadyRegistered is returned by Register for names in use.
+	ErrCacheAlreadyRegistered = errors.New("cache already registered")
+	// ErrCacheTypeMismatch is returned by Lookup when the registered cache
+	// holds a different value type.
+	ErrCacheTypeMismatch = errors.New("registered cache has a different type")
+)
+
+// RegistryCache is the subset of Cache returned by Lookup, which does not
+// depend on the storage type.
+type RegistryCache[V any] interface {
+	Get(ctx context.Context, key string) (CacheObject[V], bool, error)
+	Set(ctx context.Context, key string, value CacheObject[V]) error
+	Delete(ctx context.Context, key string) error
+	GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader CacheLoadFunc[V]) (V, error)
+	GetOrLoadConditional(ctx context.Context, key string, ttl time.Duration, loader ConditionalLoadFunc[V]) (V, error)
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
+	Stats() CacheStats
+}
+
+var _ RegistryCache[any] = Cache[any, any](nil)
+
+// registry holds the caches registered by name.
+var registry = struct {
+	mu     sync.RWMutex
+	caches map[string]any
+}{caches: make(map[string]any)}
+
+// Register makes cache resolvable by name through Lookup, so framework-level
+// middleware can find caches from configuration instead of having instances
+// threaded through. It returns ErrCacheAlreadyRegistered when name is taken.
+// Registered caches are closed by CloseRegistered.
+func Register[V any, S any](name string, cache Cache[V, S]) error {
+	if name == "" {
+		return errors.New("cache name must not be empty")
+	}
+	if cache == nil {
+		return fmt.Errorf("register %q: nil cache", name)
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.caches[name]; ok {
+		return fmt.Errorf("%w: %q", ErrCacheAlreadyRegistered, name)
+	}
+	registry.caches[name] = cache
+
+	return nil
+}
+
+// Lookup returns the cache registered under name. It returns
+// ErrCacheNotRegistered for unknown names and ErrCacheTypeMismatch when the
+// cache does not hold values of type V.
+func Lookup[V any](name string) (RegistryCache[V], error) {
+	return lookup[RegistryCache[V]](name)
+}
+
+// LookupCache is Lookup returning the full Cache, for callers that also know
+// the storage type.
+func LookupCache[V any, S any](name string) (Cache[V, S], error) {
+	return lookup[Cache[V, S]](name)
+}
+
+func lookup[C any](name string) (C, error) {
+	registry.mu.RLock()
+	cache, ok := registry.caches[name]
+	registry.mu.RUnlock()
+	var zero C
+	if !ok {
+		return zero, fmt.Errorf("%w: %q", ErrCacheNotRegistered, name)
+	}
+	typed, ok := cache.(C)
+	if !ok {
+		return zero, fmt.Errorf("%w: %q is %T", ErrCacheTypeMismatch, name, cache)
+	}
+
+	return typed, nil
+}
+
+// Unregister removes name from the registry without closing its cache and
+// reports whether it was registered.
+func Unregister(name string) bool {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	_, ok := registry.caches[name]
+	delete(registry.caches, name)
+
+	return ok
+}
+
+// RegisteredNames returns the registered cache names in sorted order.
+func RegisteredNames() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	names := make([]string, 0, len(registry.caches))
+	for name := range registry.caches {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// CloseRegistered unregisters every cache and closes them concurrently with
+// ctx, typically on application shutdown. It returns the joined Close
+// errors, each naming its cache.
+func CloseRegistered(ctx context.Context) error {
+	registry.mu.Lock()
+	caches := registry.caches
+	registry.caches = make(map[string]any)
+	registry.mu.Unlock()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for name, cache := range caches {
+		closer, ok := cache.(interface{ Close(context.Context) error })
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := closer.Close(ctx); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("close cache %q: %w", name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
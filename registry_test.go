@@ -0,0 +1,91 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRegistry_RegisterAndLookup(t *testing.T) {
+	t.Parallel()
+
+	cache := newTestCache[int]()
+	if err := Register("registry-lookup", cache); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer Unregister("registry-lookup")
+	if err := Register("registry-lookup", cache); !errors.Is(err, ErrCacheAlreadyRegistered) {
+		t.Fatalf("expected ErrCacheAlreadyRegistered, got %v", err)
+	}
+
+	found, err := Lookup[int]("registry-lookup")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ctx := context.Background()
+	if v, err := found.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) { return 7, nil }); err != nil || v != 7 {
+		t.Fatalf("expected 7, got %d, %v", v, err)
+	}
+	full, err := LookupCache[int, CacheObject[int]]("registry-lookup")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if full != cache {
+		t.Fatal("expected the registered cache")
+	}
+
+	if _, err := Lookup[string]("registry-lookup"); !errors.Is(err, ErrCacheTypeMismatch) {
+		t.Fatalf("expected ErrCacheTypeMismatch, got %v", err)
+	}
+	if _, err := LookupCache[int, []byte]("registry-lookup"); !errors.Is(err, ErrCacheTypeMismatch) {
+		t.Fatalf("expected ErrCacheTypeMismatch, got %v", err)
+	}
+	if _, err := Lookup[int]("registry-missing"); !errors.Is(err, ErrCacheNotRegistered) {
+		t.Fatalf("expected ErrCacheNotRegistered, got %v", err)
+	}
+	if err := Register[int, CacheObject[int]]("", cache); err == nil {
+		t.Fatal("expected error for empty name, got nil")
+	}
+}
+
+func TestRegistry_Unregister(t *testing.T) {
+	t.Parallel()
+
+	if err := Register("registry-unregister", newTestCache[int]()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !slices.Contains(RegisteredNames(), "registry-unregister") {
+		t.Fatalf("expected registry-unregister in %v", RegisteredNames())
+	}
+	if !Unregister("registry-unregister") {
+		t.Fatal("expected Unregister to report the cache")
+	}
+	if Unregister("registry-unregister") {
+		t.Fatal("expected second Unregister to report false")
+	}
+	if _, err := Lookup[int]("registry-unregister"); !errors.Is(err, ErrCacheNotRegistered) {
+		t.Fatalf("expected ErrCacheNotRegistered, got %v", err)
+	}
+}
+
+// TestCloseRegistered is not parallel because it empties the shared registry.
+func TestCloseRegistered(t *testing.T) {
+	first, second := newTestCache[int](), newTestCache[string]()
+	if err := Register("registry-close-1", first); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := Register("registry-close-2", second); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := CloseRegistered(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if names := RegisteredNames(); len(names) != 0 {
+		t.Fatalf("expected empty registry, got %v", names)
+	}
+	if _, err := first.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) { return 1, nil }); !errors.Is(err, ErrCacheClosed) {
+		t.Fatalf("expected ErrCacheClosed, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package crema
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReplicationHook receives every successful write and delete with the
+// encoded payload, for example to keep a cache in another region warm.
+// Hooks run in the background after the write; failures are reported like
+// other background errors (see WithErrorHandler and Cache.Errors).
+// Implementations must be safe for concurrent use by multiple goroutines.
+type ReplicationHook[S any] interface {
+	// AfterSet is called after value was stored for key with ttl. The value
+	// is shared with the cache provider and must not be modified; see
+	// EncodedValue.
+	AfterSet(ctx context.Context, key string, value S, ttl time.Duration) error
+	// AfterDelete is called after key was removed.
+	AfterDelete(ctx context.Context, key string) error
+}
+
+// ProviderReplicationHook replicates writes and deletes into another
+// CacheProvider holding entries encoded by the same codec.
+type ProviderReplicationHook[S any] struct {
+	target CacheProvider[S]
+}
+
+var _ ReplicationHook[any] = (*ProviderReplicationHook[any])(nil)
+
+// NewProviderReplicationHook constructs a hook replicating into target.
+func NewProviderReplicationHook[S any](target CacheProvider[S]) *ProviderReplicationHook[S] {
+	return &ProviderReplicationHook[S]{target: target}
+}
+
+// AfterSet stores value in the target provider.
+func (h *ProviderReplicationHook[S]) AfterSet(ctx context.Context, key string, value S, ttl time.Duration) error {
+	return h.target.Set(ctx, key, value, ttl)
+}
+
+// AfterDelete removes key from the target provider.
+func (h *ProviderReplicationHook[S]) AfterDelete(ctx context.Context, key string) error {
+	return h.target.Delete(ctx, key)
+}
+
+// WithReplicationHook calls hook in the background after every successful
+// Set, Delete and Take. Operations on one key reach the hook one at a time in
+// the order they happened; different keys replicate concurrently.
+func WithReplicationHook[V any, S any](hook ReplicationHook[S]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.replicationHook = hook
+	}
+}
+
+func (c *cacheImpl[V, S]) replicateSet(ctx context.Context, key string, value EncodedValue[S], ttl time.Duration) {
+	if c.replicationHook == nil {
+		return
+	}
+	c.replication.enqueue(c.runner, ctx, key, func(ctx context.Context) error {
+		if err := c.replicationHook.AfterSet(ctx, key, value.Value(), ttl); err != nil {
+			return fmt.Errorf("replicate set %q: %w", key, err)
+		}
+
+		return nil
+	})
+}
+
+func (c *cacheImpl[V, S]) replicateDelete(ctx context.Context, key string) {
+	if c.replicationHook == nil {
+		return
+	}
+	c.replication.enqueue(c.runner, ctx, key, func(ctx context.Context) error {
+		if err := c.replicationHook.AfterDelete(ctx, key); err != nil {
+			return fmt.Errorf("replicate delete %q: %w", key, err)
+		}
+
+		return nil
+	})
+}
+
+// replicationQueue forwards operations to the replication hook in the order
+// they happened for each key, so a delete cannot overtake the set before it
+// and bring the key back on the secondary. Different keys replicate
+// concurrently.
+type replicationQueue struct {
+	mu      sync.Mutex
+	pending map[string][]replicationOp
+}
+
+type replicationOp struct {
+	parent context.Context
+	fn     func(ctx context.Context) error
+}
+
+// enqueue appends fn to the queue of key, starting a worker for the key when
+// none is draining it. fn runs with a context that keeps the values of parent
+// but is cancelled only when the runner closes.
+func (q *replicationQueue) enqueue(runner *asyncRunner, parent context.Context, key string, fn func(ctx context.Context) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if ops, ok := q.pending[key]; ok {
+		q.pending[key] = append(ops, replicationOp{parent: parent, fn: fn})
+
+		return
+	}
+	if q.pending == nil {
+		q.pending = make(map[string][]replicationOp)
+	}
+	q.pending[key] = []replicationOp{{parent: parent, fn: fn}}
+	if !runner.goAsync(func(runnerCtx context.Context) error {
+		q.drain(runner, runnerCtx, key)
+
+		return nil
+	}) {
+		delete(q.pending, key)
+	}
+}
+
+func (q *replicationQueue) drain(runner *asyncRunner, runnerCtx context.Context, key string) {
+	for {
+		q.mu.Lock()
+		ops := q.pending[key]
+		if len(ops) == 0 {
+			delete(q.pending, key)
+			q.mu.Unlock()
+
+			return
+		}
+		op := ops[0]
+		q.pending[key] = ops[1:]
+		q.mu.Unlock()
+
+		ctx, cancel := context.WithCancel(context.WithoutCancel(op.parent))
+		stop := context.AfterFunc(runnerCtx, cancel)
+		if err := op.fn(ctx); err != nil {
+			runner.report(err)
+		}
+		stop()
+		cancel()
+	}
+}
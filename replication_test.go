@@ -0,0 +1,120 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type failingReplicationHook struct{}
+
+func (failingReplicationHook) AfterSet(context.Context, string, []byte, time.Duration) error {
+	return errors.New("region unavailable")
+}
+
+func (failingReplicationHook) AfterDelete(context.Context, string) error {
+	return nil
+}
+
+func TestWithReplicationHook_ForwardsWritesAndDeletes(t *testing.T) {
+	t.Parallel()
+
+	secondary := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache[string, []byte](NewMemoryCacheProvider[[]byte](), JSONByteStringCodec[string]{},
+		WithReplicationHook[string, []byte](NewProviderReplicationHook[[]byte](secondary)),
+	)
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "key", CacheObject[string]{Value: "v", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	raw, ok, _ := secondary.Get(ctx, "key")
+	if !ok {
+		t.Fatal("expected write to be replicated")
+	}
+	co, err := JSONByteStringCodec[string]{}.Decode(raw)
+	if err != nil || co.Value != "v" {
+		t.Fatalf("unexpected replicated entry %+v, %v", co, err)
+	}
+
+	if err := cache.Delete(ctx, "key"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if _, ok, _ := secondary.Get(ctx, "key"); ok {
+		t.Fatal("expected delete to be replicated")
+	}
+}
+
+func TestWithReplicationHook_ReportsFailures(t *testing.T) {
+	t.Parallel()
+
+	errs := make(chan error, 1)
+	cache := NewCache[string, []byte](NewMemoryCacheProvider[[]byte](), JSONByteStringCodec[string]{},
+		WithReplicationHook[string, []byte](failingReplicationHook{}),
+		WithErrorHandler[string, []byte](func(err error) { errs <- err }),
+	)
+	if err := cache.Set(context.Background(), "key", CacheObject[string]{Value: "v", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("expected local write to succeed, got %v", err)
+	}
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("expected replication error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for replication error")
+	}
+}
+
+type slowSetReplicationHook struct {
+	target CacheProvider[[]byte]
+}
+
+func (h slowSetReplicationHook) AfterSet(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	time.Sleep(5 * time.Millisecond)
+
+	return h.target.Set(ctx, key, value, ttl)
+}
+
+func (h slowSetReplicationHook) AfterDelete(ctx context.Context, key string) error {
+	return h.target.Delete(ctx, key)
+}
+
+func TestWithReplicationHook_KeepsOrderPerKey(t *testing.T) {
+	t.Parallel()
+
+	secondary := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache[string, []byte](NewMemoryCacheProvider[[]byte](), JSONByteStringCodec[string]{},
+		WithReplicationHook[string, []byte](slowSetReplicationHook{target: secondary}),
+	)
+	ctx := context.Background()
+	expireAt := time.Now().Add(time.Minute).UnixMilli()
+
+	for i := range 10 {
+		if err := cache.Set(ctx, "key", CacheObject[string]{Value: "v", ExpireAtMillis: expireAt}); err != nil {
+			t.Fatalf("set %d: %v", i, err)
+		}
+		if err := cache.Delete(ctx, "key"); err != nil {
+			t.Fatalf("delete %d: %v", i, err)
+		}
+		if err := cache.Set(ctx, "other", CacheObject[string]{Value: "o", ExpireAtMillis: expireAt}); err != nil {
+			t.Fatalf("set other %d: %v", i, err)
+		}
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if _, ok, _ := secondary.Get(ctx, "key"); ok {
+		t.Fatal("expected the final delete to win on the secondary")
+	}
+	if _, ok, _ := secondary.Get(ctx, "other"); !ok {
+		t.Fatal("expected other keys to be replicated")
+	}
+}
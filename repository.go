@@ -0,0 +1,82 @@
+package crema
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DataSource is the data-access layer behind a Repository.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type DataSource[K any, V any] interface {
+	// Load reads the value for id from the source of truth.
+	Load(ctx context.Context, id K) (V, error)
+	// Save writes value for id to the source of truth.
+	Save(ctx context.Context, id K, value V) error
+	// Remove deletes id from the source of truth.
+	Remove(ctx context.Context, id K) error
+}
+
+// RepositoryCache is the subset of Cache used by Repository.
+type RepositoryCache[V any] interface {
+	GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader CacheLoadFunc[V]) (V, error)
+	Delete(ctx context.Context, key string) error
+	CancelLoad(key string) bool
+}
+
+var _ RepositoryCache[any] = Cache[any, any](nil)
+
+// Repository wraps a DataSource with cache-aside reads and
+// invalidate-after-write updates, so call sites do not have to get the
+// ordering right themselves.
+type Repository[K any, V any] struct {
+	cache  RepositoryCache[V]
+	source DataSource[K, V]
+	key    func(id K) string
+	ttl    time.Duration
+}
+
+// NewRepository builds a Repository that caches source values for ttl under
+// the keys returned by key.
+func NewRepository[K any, V any](cache RepositoryCache[V], source DataSource[K, V], key func(id K) string, ttl time.Duration) *Repository[K, V] {
+	return &Repository[K, V]{cache: cache, source: source, key: key, ttl: ttl}
+}
+
+// Find returns the cached value for id, loading it from the source on a miss.
+func (r *Repository[K, V]) Find(ctx context.Context, id K) (V, error) {
+	return r.cache.GetOrLoad(ctx, r.key(id), r.ttl, func(ctx context.Context) (V, error) {
+		return r.source.Load(ctx, id)
+	})
+}
+
+// Save writes value to the source and then invalidates the cached entry. The
+// cache is left untouched when the write fails.
+func (r *Repository[K, V]) Save(ctx context.Context, id K, value V) error {
+	if err := r.source.Save(ctx, id, value); err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, id)
+}
+
+// Remove deletes id from the source and then invalidates the cached entry.
+// The cache is left untouched when the delete fails.
+func (r *Repository[K, V]) Remove(ctx context.Context, id K) error {
+	if err := r.source.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, id)
+}
+
+// invalidate aborts an inflight load that may have read the old value before
+// deleting the cached entry, so the old value cannot be written back.
+func (r *Repository[K, V]) invalidate(ctx context.Context, id K) error {
+	key := r.key(id)
+	r.cache.CancelLoad(key)
+	if err := r.cache.Delete(ctx, key); err != nil {
+		return fmt.Errorf("invalidate %q: %w", key, err)
+	}
+
+	return nil
+}
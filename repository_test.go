@@ -0,0 +1,119 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mapDataSource struct {
+	mu      sync.Mutex
+	values  map[int]string
+	loads   int
+	saveErr error
+}
+
+func (s *mapDataSource) Load(_ context.Context, id int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loads++
+
+	return s.values[id], nil
+}
+
+func (s *mapDataSource) Save(_ context.Context, id int, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.values[id] = value
+
+	return nil
+}
+
+func (s *mapDataSource) Remove(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, id)
+
+	return nil
+}
+
+func newTestRepository(t *testing.T, source *mapDataSource) (*Repository[int, string], *testMemoryProvider[string]) {
+	t.Helper()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{})
+
+	return NewRepository[int, string](cache, source, func(id int) string {
+		return "user:" + strconv.Itoa(id)
+	}, time.Hour), provider
+}
+
+func TestRepository_FindCachesValues(t *testing.T) {
+	t.Parallel()
+
+	source := &mapDataSource{values: map[int]string{1: "alice"}}
+	repo, _ := newTestRepository(t, source)
+
+	for range 3 {
+		v, err := repo.Find(context.Background(), 1)
+		if err != nil || v != "alice" {
+			t.Fatalf("expected alice, got %q, %v", v, err)
+		}
+	}
+	if source.loads != 1 {
+		t.Fatalf("expected 1 load, got %d", source.loads)
+	}
+}
+
+func TestRepository_SaveAndRemoveInvalidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source := &mapDataSource{values: map[int]string{1: "alice"}}
+	repo, provider := newTestRepository(t, source)
+
+	if _, err := repo.Find(ctx, 1); err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if err := repo.Save(ctx, 1, "bob"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, ok := provider.items["user:1"]; ok {
+		t.Fatal("expected save to invalidate the cached entry")
+	}
+	if v, err := repo.Find(ctx, 1); err != nil || v != "bob" {
+		t.Fatalf("expected bob after save, got %q, %v", v, err)
+	}
+
+	if err := repo.Remove(ctx, 1); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if _, ok := provider.items["user:1"]; ok {
+		t.Fatal("expected remove to invalidate the cached entry")
+	}
+}
+
+func TestRepository_FailedSaveKeepsCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errSave := errors.New("save failed")
+	source := &mapDataSource{values: map[int]string{1: "alice"}, saveErr: errSave}
+	repo, provider := newTestRepository(t, source)
+
+	if _, err := repo.Find(ctx, 1); err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	if err := repo.Save(ctx, 1, "bob"); !errors.Is(err, errSave) {
+		t.Fatalf("expected save error, got %v", err)
+	}
+	if got := provider.items["user:1"].Value; got != "alice" {
+		t.Fatalf("expected cached entry to be kept, got %q", got)
+	}
+}
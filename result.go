@@ -0,0 +1,134 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// Provenance describes how a GetOrLoadResult call was served.
+type Provenance int
+
+const (
+	// ProvenanceHit means the value was served from the cache.
+	ProvenanceHit Provenance = iota
+	// ProvenanceMiss means the value was loaded because no entry was cached.
+	ProvenanceMiss
+	// ProvenanceRefreshed means the value was loaded to replace an expired
+	// or revalidated entry.
+	ProvenanceRefreshed
+	// ProvenanceStale means a cached value was served after its reload
+	// failed.
+	ProvenanceStale
+)
+
+func (p Provenance) String() string {
+	switch p {
+	case ProvenanceHit:
+		return "hit"
+	case ProvenanceMiss:
+		return "miss"
+	case ProvenanceRefreshed:
+		return "refreshed"
+	case ProvenanceStale:
+		return "stale"
+	default:
+		return "unknown"
+	}
+}
+
+// Result is a value returned by GetOrLoadResult with how it was served, for
+// callers setting response headers such as Age or X-Cache, or making
+// freshness-dependent decisions.
+type Result[V any] struct {
+	// Value is the returned value.
+	Value V
+	// Provenance is how the value was served.
+	Provenance Provenance
+	// ExpireAt is when the served entry expires. It is zero for values not
+	// backed by an entry, such as those answered by WithAbsentKeyFilter.
+	ExpireAt time.Time
+	// Age estimates how long ago the served entry was written, as the ttl of
+	// the call minus its remaining lifetime. It is zero for loaded values.
+	Age time.Duration
+	// Leader reports whether this call ran the loader, as opposed to joining
+	// a load started by another caller. It is false for hits.
+	Leader bool
+	// LoadDuration is how long the call waited for the load, or zero for
+	// hits.
+	LoadDuration time.Duration
+	// Duration is how long the whole call took.
+	Duration time.Duration
+}
+
+// loadOutcome collects how a getOrLoadBound call was served.
+type loadOutcome struct {
+	status         BatchStatus
+	refreshed      bool
+	leader         bool
+	expireAtMillis int64
+	age            time.Duration
+	loadDuration   time.Duration
+}
+
+// served records a value served from an entry expiring at expireAtMillis.
+// It does nothing on a nil outcome.
+func (o *loadOutcome) served(status BatchStatus, expireAtMillis int64, age time.Duration) {
+	if o == nil {
+		return
+	}
+	o.status = status
+	o.expireAtMillis = expireAtMillis
+	o.age = max(age, 0)
+}
+
+// loaded records the load the call waited for. It does nothing on a nil
+// outcome.
+func (o *loadOutcome) loaded(refreshed bool, leader bool, duration time.Duration) {
+	if o == nil {
+		return
+	}
+	o.refreshed = refreshed
+	o.leader = leader
+	o.loadDuration = duration
+}
+
+// entryAge estimates the age of an entry expiring at expireAtMillis that was
+// written with ttl.
+func (c *cacheImpl[V, S]) entryAge(ttl time.Duration, expireAtMillis int64) time.Duration {
+	return ttl - time.UnixMilli(expireAtMillis).Sub(c.now())
+}
+
+// GetOrLoadResult is GetOrLoad returning the value with its provenance, age,
+// singleflight role and timings.
+func (c *cacheImpl[V, S]) GetOrLoadResult(ctx context.Context, key string, ttl time.Duration, loader CacheLoadFunc[V]) (Result[V], error) {
+	started := time.Now()
+	var out loadOutcome
+	v, err := c.getOrLoadBound(ctx, key, nil, ttl, func(*CacheObject[V]) CacheLoadFunc[V] {
+		return loader
+	}, &out)
+	if err != nil {
+		return Result[V]{}, err
+	}
+	result := Result[V]{
+		Value:        v,
+		Age:          out.age,
+		Leader:       out.leader,
+		LoadDuration: out.loadDuration,
+		Duration:     time.Since(started),
+	}
+	if out.expireAtMillis != 0 {
+		result.ExpireAt = time.UnixMilli(out.expireAtMillis)
+	}
+	switch {
+	case out.status == BatchStale:
+		result.Provenance = ProvenanceStale
+	case out.status == BatchLoaded && out.refreshed:
+		result.Provenance = ProvenanceRefreshed
+	case out.status == BatchLoaded:
+		result.Provenance = ProvenanceMiss
+	default:
+		result.Provenance = ProvenanceHit
+	}
+
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_GetOrLoadResultProvenance(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(1)
+	ctx := context.Background()
+	loader := func(v int) CacheLoadFunc[int] {
+		return func(context.Context) (int, error) { return v, nil }
+	}
+
+	result, err := cache.GetOrLoadResult(ctx, "key", time.Minute, loader(1))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if result.Value != 1 || result.Provenance != ProvenanceMiss || !result.Leader || result.Age != 0 {
+		t.Fatalf("expected leading miss, got %+v", result)
+	}
+	if !result.ExpireAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected expiry %v, got %v", now.Add(time.Minute), result.ExpireAt)
+	}
+
+	now = now.Add(20 * time.Second)
+	result, err = cache.GetOrLoadResult(ctx, "key", time.Minute, loader(2))
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if result.Value != 1 || result.Provenance != ProvenanceHit || result.Leader || result.LoadDuration != 0 {
+		t.Fatalf("expected hit, got %+v", result)
+	}
+	if result.Age != 20*time.Second {
+		t.Fatalf("expected age 20s, got %v", result.Age)
+	}
+
+	now = now.Add(time.Minute)
+	result, err = cache.GetOrLoadResult(ctx, "key", time.Minute, loader(3))
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if result.Value != 3 || result.Provenance != ProvenanceRefreshed || !result.Leader {
+		t.Fatalf("expected leading refresh, got %+v", result)
+	}
+}
+
+func TestCache_GetOrLoadResultStale(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"key": {Value: 1, ExpireAtMillis: 500},
+	}}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithDirectLoader[int, CacheObject[int]](),
+		WithDegradation[int, CacheObject[int]](DegradationPolicy{
+			Window:             time.Minute,
+			ErrorRateThreshold: 0.5,
+			MinLoads:           1,
+		}),
+	)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return time.UnixMilli(1000) }
+	failing := func(context.Context) (int, error) { return 0, errors.New("backend down") }
+
+	result, err := cache.GetOrLoadResult(context.Background(), "key", time.Second, failing)
+	if err != nil {
+		t.Fatalf("expected stale value, got %v", err)
+	}
+	if result.Value != 1 || result.Provenance != ProvenanceStale {
+		t.Fatalf("expected stale serve, got %+v", result)
+	}
+	if result.Age != 1500*time.Millisecond {
+		t.Fatalf("expected age 1.5s, got %v", result.Age)
+	}
+}
+
+func TestCache_GetOrLoadResultFollower(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	ctx := context.Background()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	leading := func(context.Context) (int, error) {
+		close(started)
+		<-release
+
+		return 1, nil
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results [2]Result[int]
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		results[0], _ = cache.GetOrLoadResult(ctx, "key", time.Minute, leading)
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		results[1], _ = cache.GetOrLoadResult(ctx, "key", time.Minute, func(context.Context) (int, error) {
+			t.Error("expected follower to join the running load")
+
+			return 2, nil
+		})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if !results[0].Leader || results[1].Leader {
+		t.Fatalf("expected one leader and one follower, got %+v", results)
+	}
+	for _, result := range results {
+		if result.Value != 1 || result.Provenance != ProvenanceMiss {
+			t.Fatalf("expected shared miss, got %+v", result)
+		}
+		if result.LoadDuration <= 0 || result.Duration < result.LoadDuration {
+			t.Fatalf("expected load timings, got %+v", result)
+		}
+	}
+}
+
+func TestProvenance_String(t *testing.T) {
+	t.Parallel()
+
+	for p, want := range map[Provenance]string{
+		ProvenanceHit:       "hit",
+		ProvenanceMiss:      "miss",
+		ProvenanceRefreshed: "refreshed",
+		ProvenanceStale:     "stale",
+		Provenance(-1):      "unknown",
+	} {
+		if got := p.String(); got != want {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package crema
+
+import (
+	"context"
+	"sync"
+)
+
+const asyncErrorBufferSize = 64
+
+// asyncRunner executes background work for async cache components and funnels
+// their failures into an error handler or a buffered error channel, so that
+// no background error is silently swallowed.
+type asyncRunner struct {
+	_            noCopy
+	mu           sync.Mutex
+	pending      int
+	idleCh       chan struct{}
+	ctx          context.Context
+	cancel       context.CancelFunc
+	errCh        chan error
+	errorHandler func(error)
+	onDrop       func(error)
+	closed       bool
+	errClosed    bool
+}
+
+func newAsyncRunner() *asyncRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	idleCh := make(chan struct{})
+	close(idleCh)
+
+	return &asyncRunner{
+		idleCh: idleCh,
+		ctx:    ctx,
+		cancel: cancel,
+		errCh:  make(chan error, asyncErrorBufferSize),
+	}
+}
+
+// goAsync runs fn on a new goroutine with the runner context. It returns false
+// without running fn once the runner is closed.
+func (r *asyncRunner) goAsync(fn func(ctx context.Context) error) bool {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+
+		return false
+	}
+	if r.pending == 0 {
+		r.idleCh = make(chan struct{})
+	}
+	r.pending++
+	r.mu.Unlock()
+
+	go func() {
+		defer r.done()
+		if err := fn(r.ctx); err != nil {
+			r.report(err)
+		}
+	}()
+
+	return true
+}
+
+// goDetached runs fn like goAsync with a context that keeps the values of
+// parent but is cancelled only when the runner closes.
+func (r *asyncRunner) goDetached(parent context.Context, fn func(ctx context.Context) error) bool {
+	return r.goAsync(func(runnerCtx context.Context) error {
+		ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+		defer cancel()
+		stop := context.AfterFunc(runnerCtx, cancel)
+		defer stop()
+
+		return fn(ctx)
+	})
+}
+
+func (r *asyncRunner) done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending--
+	if r.pending == 0 {
+		close(r.idleCh)
+	}
+}
+
+// report delivers err to the error handler, or to the error channel when no
+// handler is configured. Errors are dropped when the channel buffer is full.
+func (r *asyncRunner) report(err error) {
+	if r.errorHandler != nil {
+		r.errorHandler(err)
+
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.errClosed {
+		r.drop(err)
+
+		return
+	}
+	select {
+	case r.errCh <- err:
+	default:
+		r.drop(err)
+	}
+}
+
+func (r *asyncRunner) drop(err error) {
+	if r.onDrop != nil {
+		r.onDrop(err)
+	}
+}
+
+// errors returns the channel receiving background errors when no error
+// handler is configured. The channel is closed by close.
+func (r *asyncRunner) errors() <-chan error {
+	return r.errCh
+}
+
+// flush waits until all running work finishes or ctx is done.
+func (r *asyncRunner) flush(ctx context.Context) error {
+	r.mu.Lock()
+	idleCh := r.idleCh
+	r.mu.Unlock()
+
+	select {
+	case <-idleCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops accepting work and drains running work until ctx is done, after
+// which the remaining work is cancelled. It is safe to call more than once.
+func (r *asyncRunner) close(ctx context.Context) error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+
+		return nil
+	}
+	r.closed = true
+	r.mu.Unlock()
+
+	err := r.flush(ctx)
+	r.cancel()
+	_ = r.flush(context.Background())
+
+	r.mu.Lock()
+	r.errClosed = true
+	close(r.errCh)
+	r.mu.Unlock()
+
+	return err
+}
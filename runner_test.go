@@ -0,0 +1,94 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncRunner_ReportsErrorsToChannel(t *testing.T) {
+	t.Parallel()
+
+	runner := newAsyncRunner()
+	expectErr := errors.New("boom")
+	if !runner.goAsync(func(context.Context) error { return expectErr }) {
+		t.Fatal("expected work to be accepted")
+	}
+	if err := runner.flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if err := <-runner.errors(); !errors.Is(err, expectErr) {
+		t.Fatalf("expected %v, got %v", expectErr, err)
+	}
+}
+
+func TestAsyncRunner_ReportsErrorsToHandler(t *testing.T) {
+	t.Parallel()
+
+	var handled atomic.Int32
+	runner := newAsyncRunner()
+	runner.errorHandler = func(error) { handled.Add(1) }
+	runner.goAsync(func(context.Context) error { return errors.New("boom") })
+	if err := runner.flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if handled.Load() != 1 {
+		t.Fatalf("expected handler to be called once, got %d", handled.Load())
+	}
+	select {
+	case err := <-runner.errors():
+		t.Fatalf("expected no channel error, got %v", err)
+	default:
+	}
+}
+
+func TestAsyncRunner_DropsWhenBufferFull(t *testing.T) {
+	t.Parallel()
+
+	var dropped atomic.Int32
+	runner := newAsyncRunner()
+	runner.onDrop = func(error) { dropped.Add(1) }
+	for range asyncErrorBufferSize + 3 {
+		runner.goAsync(func(context.Context) error { return errors.New("boom") })
+	}
+	if err := runner.flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if dropped.Load() != 3 {
+		t.Fatalf("expected 3 dropped errors, got %d", dropped.Load())
+	}
+}
+
+func TestAsyncRunner_CloseCancelsAfterDeadline(t *testing.T) {
+	t.Parallel()
+
+	runner := newAsyncRunner()
+	started := make(chan struct{})
+	runner.goAsync(func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := runner.close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if err := <-runner.errors(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled work to report context.Canceled, got %v", err)
+	}
+	if _, ok := <-runner.errors(); ok {
+		t.Fatal("expected error channel to be closed")
+	}
+	if runner.goAsync(func(context.Context) error { return nil }) {
+		t.Fatal("expected work to be rejected after close")
+	}
+	if err := runner.close(context.Background()); err != nil {
+		t.Fatalf("expected second close to be a no-op, got %v", err)
+	}
+}
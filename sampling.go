@@ -0,0 +1,60 @@
+package crema
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// LoadSampleFunc receives a cached value together with a freshly loaded value
+// for the same key. It is called on a background goroutine and must be safe
+// for concurrent use.
+type LoadSampleFunc[V any] func(ctx context.Context, key string, cached V, fresh V)
+
+type loadSampler[V any] struct {
+	sampleRate float64
+	compare    LoadSampleFunc[V]
+}
+
+// WithLoadSampling re-runs the loader in the background for a sampled fraction
+// of cache hits and reports the cached and fresh values to compare, as a cheap
+// way to detect invalidation bugs in production. Each sample is reported
+// through MetricsProvider.RecordLoadSample, flagged as drifted when the values
+// differ by reflect.DeepEqual. Sampled loads bypass singleflight and never
+// update the stored entry. sampleRate is clamped to [0, 1].
+func WithLoadSampling[V any, S any](sampleRate float64, compare LoadSampleFunc[V]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if compare == nil {
+			c.loadSampler = nil
+
+			return
+		}
+		c.loadSampler = &loadSampler[V]{
+			sampleRate: min(max(sampleRate, 0), 1),
+			compare:    compare,
+		}
+	}
+}
+
+// sampleLoad schedules a sampled comparison of a cache hit against a fresh load.
+func (c *cacheImpl[V, S]) sampleLoad(ctx context.Context, key string, cached V, loader CacheLoadFunc[V]) {
+	if c.loadSampler == nil || !c.sampled(c.loadSampler.sampleRate) {
+		return
+	}
+	sampler := c.loadSampler
+	c.runner.goDetached(ctx, func(ctx context.Context) error {
+		if timeout := c.loadTimeout(key); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		fresh, err := loader(ctx)
+		if err != nil {
+			return fmt.Errorf("sample load %q: %w", key, err)
+		}
+		c.metrics.RecordLoadSample(ctx, !reflect.DeepEqual(cached, fresh))
+		sampler.compare(ctx, key, cached, fresh)
+
+		return nil
+	})
+}
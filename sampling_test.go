@@ -0,0 +1,134 @@
+package crema
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type loadSampleMetricsProvider struct {
+	BaseMetricsProvider
+	samples atomic.Int32
+	drifted atomic.Int32
+}
+
+func (m *loadSampleMetricsProvider) RecordLoadSample(_ context.Context, drifted bool) {
+	m.samples.Add(1)
+	if drifted {
+		m.drifted.Add(1)
+	}
+}
+
+func TestWithLoadSampling_ComparesHitsWithFreshLoads(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"same":  {Value: 1, ExpireAtMillis: 600_000},
+		"stale": {Value: 2, ExpireAtMillis: 600_000},
+	}}
+	metrics := &loadSampleMetricsProvider{}
+	var mu sync.Mutex
+	compared := make(map[string][2]int)
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithLoadSampling[int, CacheObject[int]](1, func(_ context.Context, key string, cached int, fresh int) {
+			mu.Lock()
+			defer mu.Unlock()
+			compared[key] = [2]int{cached, fresh}
+		}),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	impl.now = func() time.Time { return time.UnixMilli(1000) }
+	ctx := context.Background()
+
+	for key, fresh := range map[string]int{"same": 1, "stale": 20} {
+		v, err := cache.GetOrLoad(ctx, key, time.Minute, func(context.Context) (int, error) {
+			return fresh, nil
+		})
+		if err != nil {
+			t.Fatalf("get or load %s: %v", key, err)
+		}
+		if v != provider.items[key].Value {
+			t.Fatalf("expected cached value for %s, got %d", key, v)
+		}
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	if got := metrics.samples.Load(); got != 2 {
+		t.Fatalf("expected 2 samples, got %d", got)
+	}
+	if got := metrics.drifted.Load(); got != 1 {
+		t.Fatalf("expected 1 drifted sample, got %d", got)
+	}
+	if got := compared["stale"]; got != [2]int{2, 20} {
+		t.Fatalf("expected cached 2 and fresh 20, got %v", got)
+	}
+	if got := provider.items["stale"].Value; got != 2 {
+		t.Fatalf("expected sampled load not to update the entry, got %d", got)
+	}
+}
+
+func TestWithLoadSampling_ZeroRateNeverSamples(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: map[string]CacheObject[int]{"key": {Value: 1, ExpireAtMillis: 600_000}}}
+	var loads atomic.Int32
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithLoadSampling[int, CacheObject[int]](0, func(context.Context, string, int, int) {}),
+	)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return time.UnixMilli(1000) }
+
+	if _, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+		loads.Add(1)
+
+		return 2, nil
+	}); err != nil {
+		t.Fatalf("get or load: %v", err)
+	}
+	if err := cache.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := loads.Load(); got != 0 {
+		t.Fatalf("expected no sampled loads, got %d", got)
+	}
+}
+
+func TestWithLoadSampling_UsesPerKeyLoadTimeout(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"key": {Value: 1, ExpireAtMillis: 600_000},
+	}}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithMaxLoadTimeout[int, CacheObject[int]](time.Hour),
+		WithMaxLoadTimeoutFunc[int, CacheObject[int]](func(string) time.Duration { return time.Second }),
+		WithLoadSampling[int, CacheObject[int]](1, func(context.Context, string, int, int) {}),
+	)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return time.UnixMilli(1000) }
+	ctx := context.Background()
+
+	remaining := make(chan time.Duration, 1)
+	if _, err := cache.GetOrLoad(ctx, "key", time.Minute, func(ctx context.Context) (int, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			remaining <- 0
+
+			return 1, nil
+		}
+		remaining <- time.Until(deadline)
+
+		return 1, nil
+	}); err != nil {
+		t.Fatalf("get or load: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := <-remaining; got <= 0 || got > time.Second {
+		t.Fatalf("expected the per-key timeout of 1s, got %s left", got)
+	}
+}
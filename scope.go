@@ -0,0 +1,81 @@
+package crema
+
+import (
+	"context"
+	"sync"
+)
+
+type requestScopeKey struct{}
+
+type requestScopeEntry struct {
+	cache any
+	key   string
+}
+
+// requestScope memoizes cache entries for the lifetime of one request.
+type requestScope struct {
+	mu      sync.Mutex
+	entries map[requestScopeEntry]any
+}
+
+// RequestScope returns a context carrying a per-request memo of cache
+// entries. Within the returned context, repeated Get and GetOrLoad calls for
+// the same cache and key are served from process memory without provider
+// calls or revalidation, and writes through the same cache update the memo.
+// The memo is discarded with the context, so derive it from the incoming
+// request context. A context that is already scoped is returned unchanged.
+func RequestScope(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(requestScopeKey{}).(*requestScope); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, requestScopeKey{}, &requestScope{entries: make(map[requestScopeEntry]any)})
+}
+
+func requestScopeFrom(ctx context.Context) *requestScope {
+	scope, _ := ctx.Value(requestScopeKey{}).(*requestScope)
+
+	return scope
+}
+
+// scopeGet returns the unexpired memoized entry for key.
+func (c *cacheImpl[V, S]) scopeGet(ctx context.Context, key string) (CacheObject[V], bool) {
+	scope := requestScopeFrom(ctx)
+	if scope == nil {
+		return CacheObject[V]{}, false
+	}
+	scope.mu.Lock()
+	entry, ok := scope.entries[requestScopeEntry{cache: c, key: key}]
+	scope.mu.Unlock()
+	if !ok {
+		return CacheObject[V]{}, false
+	}
+	co := entry.(CacheObject[V])
+	if co.ExpireAtMillis <= c.now().UnixMilli() {
+		return CacheObject[V]{}, false
+	}
+
+	return co, true
+}
+
+// scopeStore memoizes co for key when ctx is request scoped.
+func (c *cacheImpl[V, S]) scopeStore(ctx context.Context, key string, co CacheObject[V]) {
+	scope := requestScopeFrom(ctx)
+	if scope == nil {
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.entries[requestScopeEntry{cache: c, key: key}] = co
+}
+
+// scopeDelete forgets the memoized entry for key.
+func (c *cacheImpl[V, S]) scopeDelete(ctx context.Context, key string) {
+	scope := requestScopeFrom(ctx)
+	if scope == nil {
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	delete(scope.entries, requestScopeEntry{cache: c, key: key})
+}
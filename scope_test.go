@@ -0,0 +1,80 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingProvider[S any] struct {
+	CacheProvider[S]
+	gets atomic.Int32
+}
+
+func (p *countingProvider[S]) Get(ctx context.Context, key string) (S, bool, error) {
+	p.gets.Add(1)
+
+	return p.CacheProvider.Get(ctx, key)
+}
+
+func TestRequestScope_MemoizesWithinRequest(t *testing.T) {
+	t.Parallel()
+
+	provider := &countingProvider[CacheObject[int]]{CacheProvider: &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}}
+	cache := NewCache[int, CacheObject[int]](provider, NoopCacheStorageCodec[int]{})
+	ctx := RequestScope(context.Background())
+	if RequestScope(ctx) != ctx {
+		t.Fatal("expected scoped context to be reused")
+	}
+
+	var loads atomic.Int32
+	loader := func(context.Context) (int, error) {
+		loads.Add(1)
+
+		return 5, nil
+	}
+	for range 3 {
+		v, err := cache.GetOrLoad(ctx, "key", time.Minute, loader)
+		if err != nil || v != 5 {
+			t.Fatalf("unexpected result %d, %v", v, err)
+		}
+	}
+	if loads.Load() != 1 {
+		t.Fatalf("expected 1 load, got %d", loads.Load())
+	}
+	if provider.gets.Load() != 1 {
+		t.Fatalf("expected 1 provider read, got %d", provider.gets.Load())
+	}
+
+	if err := cache.Delete(ctx, "key"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, ok, _ := cache.Get(ctx, "key"); ok {
+		t.Fatal("expected delete to drop the memoized entry")
+	}
+
+	// a new request starts with an empty memo
+	if _, err := cache.GetOrLoad(RequestScope(context.Background()), "key", time.Minute, loader); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loads.Load() != 2 {
+		t.Fatalf("expected a second load in a new request, got %d", loads.Load())
+	}
+}
+
+func TestRequestScope_SeparatesCaches(t *testing.T) {
+	t.Parallel()
+
+	first := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	second := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{})
+	ctx := RequestScope(context.Background())
+
+	if _, err := first.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	v, err := second.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) { return 2, nil })
+	if err != nil || v != 2 {
+		t.Fatalf("expected caches to keep separate memos, got %d, %v", v, err)
+	}
+}
@@ -6,9 +6,12 @@ MODULE_PREFIX=${REPO_REF}
 RELEASE_ORIGIN="https://${REPO_REF}"
 
 SUBMODULE_DIRS=(
+  "ext/brotli"
+  "ext/compress"
   "ext/go-json"
   "ext/golang-lru"
   "ext/gomemcache"
+  "ext/prometheus"
   "ext/protobuf"
   "ext/rueidis"
   "ext/ristretto"
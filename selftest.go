@@ -0,0 +1,137 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	selfTestKeyPrefix = "crema:selftest:"
+	selfTestTTL       = time.Minute
+)
+
+// ErrSelfTestFailed is returned by SelfTest when a step of the round trip fails.
+var ErrSelfTestFailed = errors.New("cache self-test failed")
+
+// ErrSelfTestMismatch is the error of a SelfTest get or decode step that did
+// not return the canary entry that was written.
+var ErrSelfTestMismatch = errors.New("self-test canary not read back")
+
+// SelfTestPhase identifies a step of the SelfTest round trip.
+type SelfTestPhase string
+
+const (
+	// SelfTestPhaseEncode encodes the canary entry with the codec.
+	SelfTestPhaseEncode SelfTestPhase = "encode"
+	// SelfTestPhaseSet writes the canary entry to the provider.
+	SelfTestPhaseSet SelfTestPhase = "set"
+	// SelfTestPhaseGet reads the canary entry back from the provider.
+	SelfTestPhaseGet SelfTestPhase = "get"
+	// SelfTestPhaseDecode decodes the canary entry read back.
+	SelfTestPhaseDecode SelfTestPhase = "decode"
+	// SelfTestPhaseDelete removes the canary entry from the provider.
+	SelfTestPhaseDelete SelfTestPhase = "delete"
+)
+
+// SelfTestStep is the outcome of one SelfTest step.
+type SelfTestStep struct {
+	// Phase is the step that ran.
+	Phase SelfTestPhase
+	// Duration is how long the step took.
+	Duration time.Duration
+	// Err is the error of the step, or nil when it succeeded.
+	Err error
+}
+
+// SelfTestReport describes a SelfTest round trip.
+type SelfTestReport struct {
+	// Key is the canary key written and deleted.
+	Key string
+	// Steps holds the steps that ran, in order. Steps after a failed encode,
+	// set, get or decode are skipped, except for the cleanup delete.
+	Steps []SelfTestStep
+	// Duration is how long the whole round trip took.
+	Duration time.Duration
+}
+
+// OK reports whether every step succeeded.
+func (r SelfTestReport) OK() bool {
+	return r.Err() == nil
+}
+
+// Err returns the errors of the failed steps joined, or nil.
+func (r SelfTestReport) Err() error {
+	var errs []error
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Phase, step.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SelfTest performs a full round trip of a canary entry holding the zero
+// value through the codec and provider (encode, set, get, decode, delete)
+// and reports the latency and error of each step. It is meant to run at
+// service startup and back readiness checks. Metrics, request scopes,
+// replication and set retries are bypassed. The returned error wraps
+// ErrSelfTestFailed and the failed steps' errors when any step fails.
+func (c *cacheImpl[V, S]) SelfTest(ctx context.Context) (SelfTestReport, error) {
+	report := SelfTestReport{Key: selfTestKeyPrefix + strconv.FormatInt(time.Now().UnixNano(), 36)}
+	started := time.Now()
+	step := func(phase SelfTestPhase, fn func() error) bool {
+		stepStarted := time.Now()
+		err := fn()
+		report.Steps = append(report.Steps, SelfTestStep{Phase: phase, Duration: time.Since(stepStarted), Err: err})
+
+		return err == nil
+	}
+
+	canary := CacheObject[V]{ExpireAtMillis: c.now().Add(selfTestTTL).UnixMilli()}
+	var (
+		raw    S
+		stored S
+		found  bool
+	)
+	ok := step(SelfTestPhaseEncode, func() (err error) {
+		raw, err = c.encode(report.Key, canary)
+
+		return err
+	})
+	ok = ok && step(SelfTestPhaseSet, func() error {
+		return c.provider.Set(ctx, report.Key, raw, selfTestTTL)
+	})
+	written := ok
+	ok = ok && step(SelfTestPhaseGet, func() (err error) {
+		stored, found, err = c.provider.Get(ctx, report.Key)
+		if err == nil && !found {
+			err = ErrSelfTestMismatch
+		}
+
+		return err
+	})
+	_ = ok && step(SelfTestPhaseDecode, func() error {
+		co, err := c.decode(report.Key, stored)
+		if err == nil && co.ExpireAtMillis != canary.ExpireAtMillis {
+			err = fmt.Errorf("%w: expiry %d, want %d", ErrSelfTestMismatch, co.ExpireAtMillis, canary.ExpireAtMillis)
+		}
+
+		return err
+	})
+	if written {
+		step(SelfTestPhaseDelete, func() error {
+			return c.provider.Delete(ctx, report.Key)
+		})
+	}
+
+	report.Duration = time.Since(started)
+	if err := report.Err(); err != nil {
+		return report, fmt.Errorf("%w: %w", ErrSelfTestFailed, err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,62 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCache_SelfTestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache(provider, JSONByteStringCodec[string]{})
+
+	report, err := cache.SelfTest(context.Background())
+	if err != nil {
+		t.Fatalf("self-test: %v", err)
+	}
+	if !report.OK() {
+		t.Fatalf("expected report to be ok, got %v", report.Err())
+	}
+	expected := []SelfTestPhase{SelfTestPhaseEncode, SelfTestPhaseSet, SelfTestPhaseGet, SelfTestPhaseDecode, SelfTestPhaseDelete}
+	if len(report.Steps) != len(expected) {
+		t.Fatalf("expected %d steps, got %+v", len(expected), report.Steps)
+	}
+	for i, step := range report.Steps {
+		if step.Phase != expected[i] {
+			t.Fatalf("expected step %d to be %s, got %s", i, expected[i], step.Phase)
+		}
+	}
+	if provider.Len() != 0 {
+		t.Fatalf("expected canary to be deleted, got %d entries", provider.Len())
+	}
+}
+
+func TestCache_SelfTestReportsFailedStep(t *testing.T) {
+	t.Parallel()
+
+	getErr := errors.New("get failed")
+	cache := NewCache(&errorProvider[[]byte]{getErr: getErr}, JSONByteStringCodec[string]{})
+
+	report, err := cache.SelfTest(context.Background())
+	if !errors.Is(err, ErrSelfTestFailed) || !errors.Is(err, getErr) {
+		t.Fatalf("expected self-test failure wrapping get error, got %v", err)
+	}
+	if report.OK() {
+		t.Fatal("expected report not to be ok")
+	}
+	phases := make([]SelfTestPhase, 0, len(report.Steps))
+	for _, step := range report.Steps {
+		phases = append(phases, step.Phase)
+	}
+	expected := []SelfTestPhase{SelfTestPhaseEncode, SelfTestPhaseSet, SelfTestPhaseGet, SelfTestPhaseDelete}
+	if len(phases) != len(expected) {
+		t.Fatalf("expected phases %v, got %v", expected, phases)
+	}
+	for i := range expected {
+		if phases[i] != expected[i] {
+			t.Fatalf("expected phases %v, got %v", expected, phases)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SetRetryPolicy bounds the background retries of failed provider writes.
+type SetRetryPolicy struct {
+	// MaxAttempts is the number of retries per write. Defaults to 3.
+	MaxAttempts int
+	// MaxAge drops a write once this long has passed since it first failed.
+	// Zero only drops writes whose entry has expired.
+	MaxAge time.Duration
+	// QueueSize caps the number of keys waiting for a retry. Defaults to 1024.
+	QueueSize int
+	// Backoff is the delay before the first retry, doubled for each further
+	// retry. Defaults to 100ms.
+	Backoff time.Duration
+}
+
+// SetRetryOutcome is how a queued write retry ended.
+type SetRetryOutcome int
+
+const (
+	// SetRetrySucceeded means a retry stored the entry.
+	SetRetrySucceeded SetRetryOutcome = iota
+	// SetRetryDroppedQueueFull means the write was not queued because the
+	// queue was full.
+	SetRetryDroppedQueueFull
+	// SetRetryDroppedExhausted means every retry failed.
+	SetRetryDroppedExhausted
+	// SetRetryDroppedExpired means the entry expired or the write exceeded
+	// MaxAge before a retry succeeded.
+	SetRetryDroppedExpired
+)
+
+type setRetryQueue[S any] struct {
+	policy  SetRetryPolicy
+	mu      sync.Mutex
+	pending map[string]*setRetry[S]
+}
+
+type setRetry[S any] struct {
+	value    S
+	expireAt time.Time
+	failedAt time.Time
+}
+
+// WithSetRetry retries provider writes that failed, e.g. during a Redis
+// failover, in the background with exponential backoff, so a transient
+// storage error does not leave a key cold until its next load. Set still
+// returns the original error. A later write or delete of the key supersedes
+// its queued retry, and context errors are not retried. Successful and
+// dropped retries are reported through RecordSetRetry.
+func WithSetRetry[V any, S any](policy SetRetryPolicy) CacheOption[V, S] {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 3
+	}
+	if policy.QueueSize <= 0 {
+		policy.QueueSize = 1024
+	}
+	if policy.Backoff <= 0 {
+		policy.Backoff = 100 * time.Millisecond
+	}
+
+	return func(c *cacheImpl[V, S]) {
+		c.setRetry = &setRetryQueue[S]{policy: policy, pending: make(map[string]*setRetry[S])}
+	}
+}
+
+// enqueueSetRetry queues a retry of a write of value for key that failed with err.
+func (c *cacheImpl[V, S]) enqueueSetRetry(ctx context.Context, key string, value S, expireAt time.Time, err error) {
+	q := c.setRetry
+	if q == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	retry := &setRetry[S]{value: value, expireAt: expireAt, failedAt: c.now()}
+
+	q.mu.Lock()
+	if _, queued := q.pending[key]; !queued && len(q.pending) >= q.policy.QueueSize {
+		q.mu.Unlock()
+		c.metrics.RecordSetRetry(ctx, SetRetryDroppedQueueFull)
+
+		return
+	}
+	q.pending[key] = retry
+	q.mu.Unlock()
+
+	if !c.runner.goDetached(ctx, func(ctx context.Context) error {
+		c.retrySet(ctx, key, retry)
+
+		return nil
+	}) {
+		c.cancelSetRetry(key)
+	}
+}
+
+// cancelSetRetry drops the queued retry for key, if any.
+func (c *cacheImpl[V, S]) cancelSetRetry(key string) {
+	if c.setRetry == nil {
+		return
+	}
+	c.setRetry.mu.Lock()
+	defer c.setRetry.mu.Unlock()
+	delete(c.setRetry.pending, key)
+}
+
+// current reports whether retry is still the queued retry for key.
+func (q *setRetryQueue[S]) current(key string, retry *setRetry[S]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.pending[key] == retry
+}
+
+// finish removes retry for key unless it was superseded.
+func (q *setRetryQueue[S]) finish(key string, retry *setRetry[S]) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pending[key] == retry {
+		delete(q.pending, key)
+	}
+}
+
+func (c *cacheImpl[V, S]) retrySet(ctx context.Context, key string, retry *setRetry[S]) {
+	q := c.setRetry
+	backoff := q.policy.Backoff
+	for range q.policy.MaxAttempts {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			q.finish(key, retry)
+
+			return
+		case <-timer.C:
+		}
+		backoff *= 2
+		if !q.current(key, retry) {
+			return
+		}
+		now := c.now()
+		ttl := retry.expireAt.Sub(now)
+		if ttl <= 0 || (q.policy.MaxAge > 0 && now.Sub(retry.failedAt) > q.policy.MaxAge) {
+			q.finish(key, retry)
+			c.metrics.RecordSetRetry(ctx, SetRetryDroppedExpired)
+
+			return
+		}
+		if err := c.provider.Set(ctx, key, retry.value, ttl); err != nil {
+			c.logger.Warn("failed to retry cache set", slog.String("key", key), slog.String("error", err.Error()))
+
+			continue
+		}
+		q.finish(key, retry)
+		c.metrics.RecordSetRetry(ctx, SetRetrySucceeded)
+
+		return
+	}
+	q.finish(key, retry)
+	c.metrics.RecordSetRetry(ctx, SetRetryDroppedExhausted)
+}
@@ -0,0 +1,134 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type failingSetProvider struct {
+	testMemoryProvider[int]
+	failures atomic.Int32
+	sets     atomic.Int32
+}
+
+func (p *failingSetProvider) Set(ctx context.Context, key string, value CacheObject[int], ttl time.Duration) error {
+	p.sets.Add(1)
+	if p.failures.Add(-1) >= 0 {
+		return errors.New("failover in progress")
+	}
+
+	return p.testMemoryProvider.Set(ctx, key, value, ttl)
+}
+
+type setRetryMetricsProvider struct {
+	BaseMetricsProvider
+	mu       sync.Mutex
+	outcomes []SetRetryOutcome
+}
+
+func (m *setRetryMetricsProvider) RecordSetRetry(_ context.Context, outcome SetRetryOutcome) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outcomes = append(m.outcomes, outcome)
+}
+
+func (m *setRetryMetricsProvider) snapshot() []SetRetryOutcome {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]SetRetryOutcome(nil), m.outcomes...)
+}
+
+func TestWithSetRetry_RetriesFailedWrites(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		failures int32
+		want     SetRetryOutcome
+		stored   bool
+	}{
+		{name: "recovers", failures: 2, want: SetRetrySucceeded, stored: true},
+		{name: "exhausted", failures: 100, want: SetRetryDroppedExhausted, stored: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &failingSetProvider{testMemoryProvider: testMemoryProvider[int]{items: make(map[string]CacheObject[int])}}
+			provider.failures.Store(tt.failures)
+			metrics := &setRetryMetricsProvider{}
+			cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+				WithMetricsProvider[int, CacheObject[int]](metrics),
+				WithSetRetry[int, CacheObject[int]](SetRetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}),
+			)
+			ctx := context.Background()
+
+			if err := cache.Set(ctx, "key", CacheObject[int]{Value: 1, ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err == nil {
+				t.Fatal("expected the first write to fail")
+			}
+			if err := cache.Flush(ctx); err != nil {
+				t.Fatalf("flush: %v", err)
+			}
+			if got := metrics.snapshot(); len(got) != 1 || got[0] != tt.want {
+				t.Fatalf("expected outcome %v, got %v", tt.want, got)
+			}
+			provider.mu.Lock()
+			_, stored := provider.items["key"]
+			provider.mu.Unlock()
+			if stored != tt.stored {
+				t.Fatalf("expected stored=%v, got %v", tt.stored, stored)
+			}
+		})
+	}
+}
+
+func TestWithSetRetry_DropsWhenQueueFull(t *testing.T) {
+	t.Parallel()
+
+	provider := &failingSetProvider{testMemoryProvider: testMemoryProvider[int]{items: make(map[string]CacheObject[int])}}
+	provider.failures.Store(2)
+	metrics := &setRetryMetricsProvider{}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithSetRetry[int, CacheObject[int]](SetRetryPolicy{QueueSize: 1, Backoff: 10 * time.Millisecond}),
+	)
+	ctx := context.Background()
+	expireAt := time.Now().Add(time.Minute).UnixMilli()
+
+	_ = cache.Set(ctx, "a", CacheObject[int]{Value: 1, ExpireAtMillis: expireAt})
+	_ = cache.Set(ctx, "b", CacheObject[int]{Value: 2, ExpireAtMillis: expireAt})
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	got := metrics.snapshot()
+	if len(got) != 2 || got[0] != SetRetryDroppedQueueFull || got[1] != SetRetrySucceeded {
+		t.Fatalf("expected queue-full drop then success, got %v", got)
+	}
+}
+
+func TestWithSetRetry_DeleteSupersedesRetry(t *testing.T) {
+	t.Parallel()
+
+	provider := &failingSetProvider{testMemoryProvider: testMemoryProvider[int]{items: make(map[string]CacheObject[int])}}
+	provider.failures.Store(1)
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithSetRetry[int, CacheObject[int]](SetRetryPolicy{Backoff: 10 * time.Millisecond}),
+	)
+	ctx := context.Background()
+
+	_ = cache.Set(ctx, "key", CacheObject[int]{Value: 1, ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()})
+	if err := cache.Delete(ctx, "key"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := provider.sets.Load(); got != 1 {
+		t.Fatalf("expected the deleted key not to be retried, got %d sets", got)
+	}
+}
@@ -0,0 +1,66 @@
+package crema
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithSetTimeout stores loaded values with a context detached from the
+// caller and bounded by timeout, so a caller whose deadline is nearly spent
+// still populates the cache. Without it, loaded values are stored with the
+// caller context and the write is skipped when that context is already done.
+// Writes abandoned because of a deadline or cancellation are reported through
+// MetricsProvider.RecordSetAbandoned, and writes completed only because they
+// were detached through MetricsProvider.RecordSetDetached. A non-positive timeout restores the
+// default.
+func WithSetTimeout[V any, S any](timeout time.Duration) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.setTimeout = timeout
+	}
+}
+
+// storeLoaded writes a value loaded by the leader and returns the value to
+// serve, which differs from co when the key was read as absent, atomic miss
+// fills are enabled and a racing writer stored first. Failures are logged and
+// never fail the load.
+func (c *cacheImpl[V, S]) storeLoaded(ctx context.Context, key string, co CacheObject[V], absent bool) V {
+	setCtx := ctx
+	if c.setTimeout > 0 {
+		var cancel context.CancelFunc
+		setCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), c.setTimeout)
+		defer cancel()
+	} else if err := ctx.Err(); err != nil {
+		c.abandonSet(ctx, key, err)
+
+		return co.Value
+	}
+
+	var err error
+	switch {
+	case c.extendUnmodified(setCtx, key, co):
+		// a conditional load kept the stored value; only its expiry moved
+	case absent && c.atomicMissFill:
+		// first writer wins on a miss, so racing loaders agree on one value
+		co, err = c.setIfAbsent(setCtx, key, co)
+	default:
+		err = c.set(setCtx, key, co)
+	}
+	switch {
+	case err == nil:
+		if setCtx != ctx && ctx.Err() != nil {
+			c.metrics.RecordSetDetached(ctx)
+		}
+	case setCtx.Err() != nil:
+		c.abandonSet(ctx, key, err)
+	default:
+		c.logger.Warn("failed to set cache", slog.String("key", key), slog.String("error", err.Error()))
+	}
+
+	return co.Value
+}
+
+func (c *cacheImpl[V, S]) abandonSet(ctx context.Context, key string, err error) {
+	c.metrics.RecordSetAbandoned(ctx)
+	c.logger.Warn("abandoned cache set", slog.String("key", key), slog.String("error", err.Error()))
+}
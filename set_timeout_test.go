@@ -0,0 +1,101 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type setAbandonedMetricsProvider struct {
+	BaseMetricsProvider
+	abandoned atomic.Int32
+	detached  atomic.Int32
+}
+
+func (m *setAbandonedMetricsProvider) RecordSetAbandoned(context.Context) {
+	m.abandoned.Add(1)
+}
+
+func (m *setAbandonedMetricsProvider) RecordSetDetached(context.Context) {
+	m.detached.Add(1)
+}
+
+type blockingSetProvider struct {
+	testMemoryProvider[int]
+}
+
+func (p *blockingSetProvider) Set(ctx context.Context, _ string, _ CacheObject[int], _ time.Duration) error {
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func TestWithSetTimeout_StoresAfterCallerCancellation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		opts          []CacheOption[int, CacheObject[int]]
+		wantStored    bool
+		wantAbandoned int32
+		wantDetached  int32
+	}{
+		{name: "default skips", wantStored: false, wantAbandoned: 1},
+		{name: "detached set", opts: []CacheOption[int, CacheObject[int]]{WithSetTimeout[int, CacheObject[int]](time.Second)}, wantStored: true, wantDetached: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+			metrics := &setAbandonedMetricsProvider{}
+			opts := append([]CacheOption[int, CacheObject[int]]{
+				WithMetricsProvider[int, CacheObject[int]](metrics),
+				WithDirectLoader[int, CacheObject[int]](),
+			}, tt.opts...)
+			cache := NewCache(provider, NoopCacheStorageCodec[int]{}, opts...)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			v, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+				cancel()
+
+				return 1, nil
+			})
+			if err != nil || v != 1 {
+				t.Fatalf("expected loaded value, got %d, %v", v, err)
+			}
+			if _, stored := provider.items["key"]; stored != tt.wantStored {
+				t.Fatalf("expected stored=%v, got %v", tt.wantStored, stored)
+			}
+			if got := metrics.abandoned.Load(); got != tt.wantAbandoned {
+				t.Fatalf("expected %d abandoned sets, got %d", tt.wantAbandoned, got)
+			}
+			if got := metrics.detached.Load(); got != tt.wantDetached {
+				t.Fatalf("expected %d detached sets, got %d", tt.wantDetached, got)
+			}
+		})
+	}
+}
+
+func TestWithSetTimeout_ReportsTimedOutSet(t *testing.T) {
+	t.Parallel()
+
+	metrics := &setAbandonedMetricsProvider{}
+	cache := NewCache(&blockingSetProvider{testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"key": {Value: 1, ExpireAtMillis: 1},
+	}}}, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithSetTimeout[int, CacheObject[int]](10*time.Millisecond),
+	)
+
+	v, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+		return 2, nil
+	})
+	if err != nil || v != 2 {
+		t.Fatalf("expected loaded value, got %d, %v", v, err)
+	}
+	if got := metrics.abandoned.Load(); got != 1 {
+		t.Fatalf("expected 1 abandoned set, got %d", got)
+	}
+}
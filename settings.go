@@ -0,0 +1,130 @@
+package crema
+
+import (
+	"log/slog"
+	"maps"
+	"time"
+)
+
+// Settings holds cache knobs that can be changed at runtime with
+// ModifySettings or UpdateSettings, for example from a feature flag system. Changes apply to
+// operations started after the update.
+type Settings struct {
+	// DefaultTTL replaces a non-positive ttl passed to GetOrLoad or
+	// GetOrBatchLoad when positive.
+	DefaultTTL time.Duration
+	// RevalidationWindow is the target window for probabilistic early
+	// revalidation. Zero disables early revalidation.
+	RevalidationWindow time.Duration
+	// FailOpen makes GetOrLoad call the loader when reading from the provider
+	// fails. When false, the read error is returned instead.
+	FailOpen bool
+	// MaxConcurrentLoads caps concurrently running loaders when positive.
+	MaxConcurrentLoads int
+	// Experiments holds the fraction of keys, in [0, 1], each experimental
+	// behavior is enabled for; see WithExperimental. Configured behaviors
+	// missing from it are fully enabled. A nil map passed to UpdateSettings
+	// keeps the current rollouts, so updates that do not know about
+	// experiments cannot enable dark ones.
+	Experiments map[Experiment]float64
+}
+
+// cacheSettings is an immutable snapshot of Settings with derived values.
+type cacheSettings struct {
+	Settings
+	steepness                      float64
+	revalidationWindowMilliseconds int64
+}
+
+func newCacheSettings(settings Settings) *cacheSettings {
+	windowMillis := settings.RevalidationWindow.Milliseconds()
+	if windowMillis < 0 {
+		windowMillis = defaultRevalidationWindowMilliseconds
+		settings.RevalidationWindow = time.Duration(windowMillis) * time.Millisecond
+	}
+	steepness, revalidationWindowMilliseconds := calculateSteepnessAndRevalidationWindow(windowMillis)
+	settings.Experiments = maps.Clone(settings.Experiments)
+
+	return &cacheSettings{
+		Settings:                       settings,
+		steepness:                      steepness,
+		revalidationWindowMilliseconds: revalidationWindowMilliseconds,
+	}
+}
+
+// Settings returns the settings currently in effect.
+func (c *cacheImpl[V, S]) Settings() Settings {
+	settings := c.settings.Load().Settings
+	settings.Experiments = maps.Clone(settings.Experiments)
+
+	return settings
+}
+
+// UpdateSettings atomically replaces the runtime settings, keeping the current
+// experiment rollouts when settings.Experiments is nil. Every other field is
+// replaced, so a zero-valued field disables its knob: start from Settings(),
+// or use ModifySettings to change individual fields. Loads already waiting
+// for a concurrency slot observe a new MaxConcurrentLoads immediately.
+func (c *cacheImpl[V, S]) UpdateSettings(settings Settings) {
+	c.ModifySettings(func(current *Settings) {
+		if settings.Experiments == nil {
+			settings.Experiments = current.Experiments
+		}
+		*current = settings
+	})
+}
+
+// ModifySettings atomically applies fn to a copy of the settings in effect
+// and stores the result, so concurrent updates of different fields do not
+// overwrite each other. fn must not call back into the cache's settings.
+func (c *cacheImpl[V, S]) ModifySettings(fn func(*Settings)) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+
+	settings := c.Settings()
+	fn(&settings)
+	c.storeSettings(settings)
+	c.logger.Info("cache settings updated",
+		slog.Duration("defaultTTL", settings.DefaultTTL),
+		slog.Duration("revalidationWindow", settings.RevalidationWindow),
+		slog.Bool("failOpen", settings.FailOpen),
+		slog.Int("maxConcurrentLoads", settings.MaxConcurrentLoads),
+		slog.Any("experiments", settings.Experiments),
+	)
+}
+
+// updateSettings applies fn to a copy of the current settings and stores it.
+// Options use it to configure the initial settings.
+func (c *cacheImpl[V, S]) updateSettings(fn func(*Settings)) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+
+	settings := c.Settings()
+	fn(&settings)
+	c.storeSettings(settings)
+}
+
+func (c *cacheImpl[V, S]) storeSettings(settings Settings) {
+	c.settings.Store(newCacheSettings(settings))
+	c.loadLimiter.setLimit(settings.MaxConcurrentLoads)
+	if c.shardIsolation != nil {
+		c.shardIsolation.setLimit(settings.MaxConcurrentLoads)
+	}
+}
+
+// effectiveTTL returns ttl, or the configured default when ttl is not positive.
+func (c *cacheImpl[V, S]) effectiveTTL(ttl time.Duration) time.Duration {
+	if ttl > 0 {
+		return ttl
+	}
+	if defaultTTL := c.settings.Load().DefaultTTL; defaultTTL > 0 {
+		return defaultTTL
+	}
+
+	return ttl
+}
+
+// failOpen reports whether GetOrLoad falls back to the loader on read errors.
+func (c *cacheImpl[V, S]) failOpen() bool {
+	return c.settings.Load().FailOpen
+}
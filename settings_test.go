@@ -0,0 +1,162 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_SettingsDefaults(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithRevalidationWindow[int, CacheObject[int]](time.Second),
+		WithMaxConcurrentLoads[int, CacheObject[int]](3),
+	)
+
+	settings := cache.Settings()
+	if settings.RevalidationWindow != time.Second {
+		t.Fatalf("expected revalidation window 1s, got %v", settings.RevalidationWindow)
+	}
+	if settings.MaxConcurrentLoads != 3 {
+		t.Fatalf("expected max concurrent loads 3, got %d", settings.MaxConcurrentLoads)
+	}
+	if !settings.FailOpen {
+		t.Fatal("expected fail open by default")
+	}
+}
+
+func TestCache_UpdateSettingsFailClosed(t *testing.T) {
+	t.Parallel()
+
+	getErr := errors.New("get failed")
+	cache := NewCache(&errorProvider[CacheObject[int]]{getErr: getErr}, NoopCacheStorageCodec[int]{})
+	loader := func(context.Context) (int, error) { return 1, nil }
+
+	if _, err := cache.GetOrLoad(context.Background(), "key", time.Minute, loader); err != nil {
+		t.Fatalf("expected fail open load to succeed, got %v", err)
+	}
+
+	settings := cache.Settings()
+	settings.FailOpen = false
+	cache.UpdateSettings(settings)
+	if _, err := cache.GetOrLoad(context.Background(), "key", time.Minute, loader); !errors.Is(err, getErr) {
+		t.Fatalf("expected %v, got %v", getErr, err)
+	}
+}
+
+func TestCache_UpdateSettingsDefaultTTL(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	now := time.UnixMilli(1_000_000)
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	impl.now = func() time.Time { return now }
+	cache.UpdateSettings(Settings{DefaultTTL: time.Minute, FailOpen: true})
+
+	if _, err := cache.GetOrLoad(context.Background(), "key", 0, func(context.Context) (int, error) {
+		return 1, nil
+	}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	co, ok := provider.items["key"]
+	if !ok {
+		t.Fatal("expected entry stored with default ttl")
+	}
+	if co.ExpireAtMillis != now.Add(time.Minute).UnixMilli() {
+		t.Fatalf("expected expiry %d, got %d", now.Add(time.Minute).UnixMilli(), co.ExpireAtMillis)
+	}
+}
+
+func TestCache_UpdateSettingsRaisesLoadLimit(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithMaxConcurrentLoads[int, CacheObject[int]](1),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	if err := impl.loadLimiter.acquire(context.Background(), LoadPriorityHigh); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+			return 1, nil
+		})
+		done <- err
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		impl.loadLimiter.mu.Lock()
+		queued := len(impl.loadLimiter.queues[LoadPriorityHigh])
+		impl.loadLimiter.mu.Unlock()
+		if queued == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for queued load")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	cache.UpdateSettings(Settings{FailOpen: true, MaxConcurrentLoads: 0})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected queued load to start after the limit was lifted")
+	}
+	impl.loadLimiter.release()
+}
+
+func TestCache_ModifySettingsKeepsOtherFields(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{},
+		WithRevalidationWindow[int, CacheObject[int]](time.Minute),
+	)
+	before := cache.Settings()
+	if !before.FailOpen {
+		t.Fatalf("expected fail open by default, got %+v", before)
+	}
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.ModifySettings(func(s *Settings) {
+				s.MaxConcurrentLoads += i
+			})
+		}()
+	}
+	wg.Wait()
+
+	got := cache.Settings()
+	if got.MaxConcurrentLoads != 36 {
+		t.Fatalf("expected every concurrent update applied, got %d", got.MaxConcurrentLoads)
+	}
+	if !got.FailOpen || got.RevalidationWindow != before.RevalidationWindow {
+		t.Fatalf("expected untouched fields kept, got %+v", got)
+	}
+}
+
+func TestCache_UpdateSettingsReplacesUnsetFields(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{})
+	cache.UpdateSettings(Settings{MaxConcurrentLoads: 4})
+	if got := cache.Settings(); got.FailOpen || got.RevalidationWindow != 0 {
+		t.Fatalf("expected zero-valued fields stored, got %+v", got)
+	}
+}
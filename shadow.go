@@ -0,0 +1,56 @@
+package crema
+
+import "context"
+
+// ShadowLoadFunc loads a candidate value for key during a shadow comparison.
+type ShadowLoadFunc[V any] func(ctx context.Context, key string) (V, error)
+
+// ShadowDiffFunc receives the primary loader result together with the shadow
+// loader result for the same key. It is called on a background goroutine and
+// must be safe for concurrent use.
+type ShadowDiffFunc[V any] func(ctx context.Context, key string, primary V, shadow V, shadowErr error)
+
+type shadowLoader[V any] struct {
+	loader     ShadowLoadFunc[V]
+	sampleRate float64
+	diff       ShadowDiffFunc[V]
+}
+
+// WithShadowLoader runs loader alongside a sampled fraction of successful
+// leader loads and reports both results to diff, for dark-launching a new data
+// source behind a cache. The shadow load runs in the background with a context
+// detached from the caller and never affects the returned or stored value.
+// sampleRate is clamped to [0, 1].
+func WithShadowLoader[V any, S any](loader ShadowLoadFunc[V], sampleRate float64, diff ShadowDiffFunc[V]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if loader == nil || diff == nil {
+			c.shadow = nil
+
+			return
+		}
+		c.shadow = &shadowLoader[V]{
+			loader:     loader,
+			sampleRate: min(max(sampleRate, 0), 1),
+			diff:       diff,
+		}
+	}
+}
+
+// runShadowLoad schedules a sampled shadow comparison for a primary load result.
+func (c *cacheImpl[V, S]) runShadowLoad(ctx context.Context, key string, primary V) {
+	if c.shadow == nil || !c.sampled(c.shadow.sampleRate) {
+		return
+	}
+	shadow := c.shadow
+	c.runner.goDetached(ctx, func(ctx context.Context) error {
+		if timeout := c.loadTimeout(key); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		v, err := shadow.loader(ctx, key)
+		shadow.diff(ctx, key, primary, v, err)
+
+		return nil
+	})
+}
@@ -0,0 +1,140 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithShadowLoader_ReportsDiff(t *testing.T) {
+	t.Parallel()
+
+	type diff struct {
+		key       string
+		primary   int
+		shadow    int
+		shadowErr error
+	}
+	var (
+		mu    sync.Mutex
+		diffs []diff
+	)
+	shadowErr := errors.New("candidate failed")
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithShadowLoader[int, CacheObject[int]](
+			func(_ context.Context, key string) (int, error) {
+				if key == "broken" {
+					return 0, shadowErr
+				}
+
+				return 43, nil
+			},
+			1,
+			func(_ context.Context, key string, primary int, shadow int, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				diffs = append(diffs, diff{key: key, primary: primary, shadow: shadow, shadowErr: err})
+			},
+		),
+	)
+	ctx := context.Background()
+
+	value, err := cache.GetOrLoad(ctx, "answer", time.Minute, func(context.Context) (int, error) { return 42, nil })
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if value != 42 {
+		t.Fatalf("expected primary value 42, got %d", value)
+	}
+	if _, err := cache.GetOrLoad(ctx, "broken", time.Minute, func(context.Context) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("expected shadow failure not to affect caller, got %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 shadow comparisons, got %d", len(diffs))
+	}
+	byKey := map[string]diff{diffs[0].key: diffs[0], diffs[1].key: diffs[1]}
+	if got := byKey["answer"]; got.primary != 42 || got.shadow != 43 || got.shadowErr != nil {
+		t.Fatalf("unexpected comparison for answer: %+v", got)
+	}
+	if got := byKey["broken"]; !errors.Is(got.shadowErr, shadowErr) {
+		t.Fatalf("expected shadow error to be reported, got %+v", got)
+	}
+	if stored := provider.items["answer"]; stored.Value != 42 {
+		t.Fatalf("expected primary value to be stored, got %d", stored.Value)
+	}
+}
+
+func TestWithShadowLoader_RespectsSampleRate(t *testing.T) {
+	t.Parallel()
+
+	called := make(chan struct{}, 1)
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithShadowLoader[int, CacheObject[int]](
+			func(context.Context, string) (int, error) {
+				called <- struct{}{}
+
+				return 0, nil
+			},
+			0.1,
+			func(context.Context, string, int, int, error) {},
+		),
+	)
+	cache.(*cacheImpl[int, CacheObject[int]]).random = fakeRandom(0.5)
+
+	if _, err := cache.GetOrLoad(context.Background(), "answer", time.Minute, func(context.Context) (int, error) { return 42, nil }); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := cache.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	select {
+	case <-called:
+		t.Fatal("expected shadow load to be sampled out")
+	default:
+	}
+}
+
+func TestWithShadowLoader_UsesPerKeyLoadTimeout(t *testing.T) {
+	t.Parallel()
+
+	remaining := make(chan time.Duration, 1)
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithMaxLoadTimeout[int, CacheObject[int]](time.Hour),
+		WithMaxLoadTimeoutFunc[int, CacheObject[int]](func(string) time.Duration { return time.Second }),
+		WithShadowLoader[int, CacheObject[int]](
+			func(ctx context.Context, _ string) (int, error) {
+				deadline, ok := ctx.Deadline()
+				if !ok {
+					remaining <- 0
+
+					return 0, nil
+				}
+				remaining <- time.Until(deadline)
+
+				return 0, nil
+			},
+			1,
+			func(context.Context, string, int, int, error) {},
+		),
+	)
+	ctx := context.Background()
+	if _, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := <-remaining; got <= 0 || got > time.Second {
+		t.Fatalf("expected the per-key timeout of 1s, got %s left", got)
+	}
+}
@@ -0,0 +1,61 @@
+package crema
+
+// shardIsolation partitions the load concurrency limiter by key group.
+type shardIsolation struct {
+	shardFunc func(key string) int
+	limiters  []*loadLimiter
+}
+
+// WithShardIsolation partitions the load concurrency limit configured with
+// WithMaxConcurrentLoads into shards independent limiters, and runs each load
+// within the limiter of shardFunc(key) modulo shards. Mapping each tenant to
+// its own shard keeps one tenant's hot keys from taking every load slot and
+// starving the loads of other tenants. Each shard admits the limit divided by
+// shards, rounded up, so up to shards-1 loads beyond the limit may run at
+// once. shardFunc must be deterministic and safe for concurrent use. Fewer
+// than two shards or a nil shardFunc disables it.
+func WithShardIsolation[V any, S any](shardFunc func(key string) int, shards int) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if shardFunc == nil || shards < 2 {
+			c.shardIsolation = nil
+
+			return
+		}
+		limiters := make([]*loadLimiter, shards)
+		for i := range limiters {
+			limiters[i] = newLoadLimiter(0)
+		}
+		c.shardIsolation = &shardIsolation{shardFunc: shardFunc, limiters: limiters}
+		c.shardIsolation.setLimit(c.settings.Load().MaxConcurrentLoads)
+	}
+}
+
+// setLimit splits limit across the shards.
+func (s *shardIsolation) setLimit(limit int) {
+	perShard := limit
+	if limit > 0 {
+		perShard = (limit + len(s.limiters) - 1) / len(s.limiters)
+	}
+	for _, limiter := range s.limiters {
+		limiter.setLimit(perShard)
+	}
+}
+
+// limiter returns the limiter of the shard key belongs to.
+func (s *shardIsolation) limiter(key string) *loadLimiter {
+	shard := s.shardFunc(key) % len(s.limiters)
+	if shard < 0 {
+		shard += len(s.limiters)
+	}
+
+	return s.limiters[shard]
+}
+
+// loadLimiterFor returns the load concurrency limiter applying to key.
+func (c *cacheImpl[V, S]) loadLimiterFor(key string) *loadLimiter {
+	if c.shardIsolation != nil {
+		return c.shardIsolation.limiter(key)
+	}
+
+	return c.loadLimiter
+}
@@ -0,0 +1,68 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tenantShard(key string) int {
+	if strings.HasPrefix(key, "a:") {
+		return 0
+	}
+
+	return 1
+}
+
+func TestWithShardIsolation_HotTenantDoesNotStarveOthers(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithMaxConcurrentLoads[int, CacheObject[int]](2),
+		WithShardIsolation[int, CacheObject[int]](tenantShard, 2),
+	)
+	ctx := context.Background()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_, _ = cache.GetOrLoad(ctx, "a:hot", time.Minute, func(context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 1, nil
+		})
+	}()
+	<-started
+
+	// Tenant a has used its only slot.
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := cache.GetOrLoad(waitCtx, "a:other", time.Minute, func(context.Context) (int, error) { return 2, nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected tenant a to wait for its slot, got %v", err)
+	}
+	// Tenant b still has its own slot.
+	if v, err := cache.GetOrLoad(ctx, "b:key", time.Minute, func(context.Context) (int, error) { return 3, nil }); err != nil || v != 3 {
+		t.Fatalf("expected tenant b to load, got %d, %v", v, err)
+	}
+	close(release)
+}
+
+func TestShardIsolation_SplitsLimit(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithShardIsolation[int, CacheObject[int]](func(key string) int { return -len(key) }, 3),
+	)
+	impl := cache.(*cacheImpl[int, CacheObject[int]])
+	impl.UpdateSettings(Settings{MaxConcurrentLoads: 7})
+	for i, limiter := range impl.shardIsolation.limiters {
+		if limiter.limit != 3 {
+			t.Fatalf("shard %d: expected limit 3, got %d", i, limiter.limit)
+		}
+	}
+	if got := impl.loadLimiterFor("ab"); got != impl.shardIsolation.limiters[1] {
+		t.Fatal("expected negative shard numbers to wrap around")
+	}
+}
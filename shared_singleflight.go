@@ -0,0 +1,33 @@
+package crema
+
+import "context"
+
+// WithSharedSingleflight deduplicates loads through group in addition to the
+// cache's own singleflight, so several Cache instances in one process that
+// hold the same logical data, such as an old and a new provider or codec
+// during a migration, run one loader per key between them. Each cache still
+// stores the shared result itself. Loads join by the key returned by
+// WithLoadKeyFunc, or the cache key otherwise, and run with the loader,
+// concurrency limit and cost guard of the cache that started them. The
+// group's own timeout from WithGroupTimeout applies instead of the cache's
+// max load timeout. A nil group disables sharing.
+func WithSharedSingleflight[V any, S any](group *Group[V]) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.sharedGroup = group
+	}
+}
+
+// shareLoad routes loader through the shared singleflight group under
+// loadKey when one is configured.
+func (c *cacheImpl[V, S]) shareLoad(loadKey string, loader CacheLoadFunc[V]) CacheLoadFunc[V] {
+	if c.sharedGroup == nil {
+		return loader
+	}
+	group := c.sharedGroup
+
+	return func(ctx context.Context) (V, error) {
+		v, _, err := group.Do(ctx, loadKey, loader)
+
+		return v, err
+	}
+}
@@ -0,0 +1,68 @@
+package crema
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_SharedSingleflightDedupesAcrossCaches(t *testing.T) {
+	t.Parallel()
+
+	group := NewGroup[string]()
+	oldCache := NewCache(NewMemoryCacheProvider[[]byte](), JSONByteStringCodec[string]{}, WithSharedSingleflight[string, []byte](group))
+	newCache := NewCache(NewMemoryCacheProvider[[]byte](), StringCodec{}, WithSharedSingleflight[string, []byte](group))
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+
+		return "value", nil
+	}
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	results := make([]string, 2)
+	errs := make([]error, 2)
+	for i, cache := range []Cache[string, []byte]{oldCache, newCache} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = cache.GetOrLoad(ctx, "key", time.Minute, loader)
+		}()
+	}
+	deadline := time.Now().Add(time.Second)
+	for !sharedLoadJoined(group, 2) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	for i := range results {
+		if errs[i] != nil {
+			t.Fatalf("load %d: %v", i, errs[i])
+		}
+		if results[i] != "value" {
+			t.Fatalf("expected value, got %q", results[i])
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 loader call, got %d", got)
+	}
+	for _, cache := range []Cache[string, []byte]{oldCache, newCache} {
+		if _, ok, err := cache.Get(ctx, "key"); err != nil || !ok {
+			t.Fatalf("expected both caches to store the value, got %v, %v", ok, err)
+		}
+	}
+}
+
+// sharedLoadJoined reports whether the load running in group has waiters
+// callers.
+func sharedLoadJoined[V any](group *Group[V], waiters int) bool {
+	loads := group.loader.inflightLoads()
+
+	return len(loads) == 1 && loads[0].Waiters == waiters
+}
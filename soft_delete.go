@@ -0,0 +1,104 @@
+package crema
+
+import (
+	"context"
+	"time"
+)
+
+// tombstoneSuffix is appended to a key to form the sibling key marking it as
+// soft-deleted.
+const tombstoneSuffix = markerKeyPrefix + "tombstone"
+
+// WithSoftDeleteFallback makes GetOrLoad look up the tombstone of a key whose
+// reload failed and serve the previous value of soft-deleted entries as
+// stale instead of the error. Without it, failed loads never read tombstones,
+// so caches that do not soft-delete pay no extra provider read. Every process
+// serving soft-deleted keys needs it, not only the one calling SoftDelete.
+func WithSoftDeleteFallback[V any, S any]() CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.softDeleteFallback = true
+	}
+}
+
+// SoftDelete tombstones the entry stored for key instead of removing it:
+// its expiry is brought forward to revalidateAfter from now, so GetOrLoad
+// reloads it once that passes (on the next access for a non-positive
+// revalidateAfter), while the provider keeps the previous payload until its
+// original expiry. With WithSoftDeleteFallback, a reload failing meanwhile
+// serves the previous value as stale instead of returning the error, as
+// WithDegradation does, which a hard Delete cannot since it drops the
+// fallback. The tombstone is a sibling
+// marker key encoded with the cache codec. SoftDelete reports false when key
+// is not cached; entries expiring within revalidateAfter are left as they
+// are. The read-modify-write is not atomic, so a concurrent write may be
+// overwritten.
+func (c *cacheImpl[V, S]) SoftDelete(ctx context.Context, key string, revalidateAfter time.Duration) (bool, error) {
+	ctx = c.metricsContext(ctx)
+	c.metrics.RecordCacheGet(ctx)
+
+	started := time.Now()
+	rv, exists, err := c.provider.Get(ctx, key)
+	if err != nil {
+		return false, c.observeError(ctx, ErrorPhaseProviderGet, key, started, err)
+	}
+	if !exists {
+		return false, nil
+	}
+
+	now := c.now()
+	revalidateAtMillis := now.Add(max(revalidateAfter, 0)).UnixMilli()
+	started = time.Now()
+	raw, co, expireAtMillis, decoded, phase, err := c.reexpireEncoded(key, rv, func(expireAtMillis int64) int64 {
+		return min(expireAtMillis, revalidateAtMillis)
+	})
+	if err != nil {
+		return false, c.observeError(ctx, phase, key, started, err)
+	}
+	retain := time.UnixMilli(expireAtMillis).Sub(now)
+	if co.ExpireAtMillis == expireAtMillis || retain <= 0 {
+		return true, nil
+	}
+
+	started = time.Now()
+	tombstoneKey := key + tombstoneSuffix
+	tombstone, err := c.encode(tombstoneKey, CacheObject[V]{ExpireAtMillis: expireAtMillis})
+	if err != nil {
+		return true, c.observeError(ctx, ErrorPhaseEncode, key, started, err)
+	}
+	c.metrics.RecordCacheSet(ctx)
+	started = time.Now()
+	if err := c.provider.Set(ctx, tombstoneKey, tombstone, retain); err != nil {
+		return true, c.observeError(ctx, ErrorPhaseProviderSet, key, started, err)
+	}
+	encoded := NewEncodedValue(raw)
+	started = time.Now()
+	if err := c.provider.Set(ctx, key, encoded.Value(), retain); err != nil {
+		return true, c.observeError(ctx, ErrorPhaseProviderSet, key, started, err)
+	}
+	c.cancelSetRetry(key)
+	if decoded {
+		c.scopeStore(ctx, key, co)
+	} else {
+		c.scopeDelete(ctx, key)
+	}
+	c.replicateSet(ctx, key, encoded, retain)
+
+	return true, nil
+}
+
+// tombstoned reports whether key was soft-deleted and its previous payload
+// may still be served. It is false without WithSoftDeleteFallback, and
+// provider and decode errors report false.
+func (c *cacheImpl[V, S]) tombstoned(ctx context.Context, key string) bool {
+	if !c.softDeleteFallback {
+		return false
+	}
+	tombstoneKey := key + tombstoneSuffix
+	rv, exists, err := c.provider.Get(ctx, tombstoneKey)
+	if err != nil || !exists {
+		return false
+	}
+	tombstone, err := c.decode(tombstoneKey, rv)
+
+	return err == nil && tombstone.ExpireAtMillis > c.now().UnixMilli()
+}
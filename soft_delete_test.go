@@ -0,0 +1,114 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache_SoftDeleteRevalidatesAndKeepsFallback(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewRawByteCache(provider, WithSoftDeleteFallback[[]byte, []byte]())
+	impl := cache.(*cacheImpl[[]byte, []byte])
+	now := time.UnixMilli(1_000_000)
+	impl.now = func() time.Time { return now }
+	impl.random = fakeRandom(1)
+	ctx := context.Background()
+	if err := cache.Set(ctx, "key", CacheObject[[]byte]{Value: []byte("old"), ExpireAtMillis: now.Add(time.Hour).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	ok, err := cache.SoftDelete(ctx, "key", 0)
+	if err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected key to be soft-deleted")
+	}
+	loads := 0
+	failing := func(context.Context) ([]byte, error) {
+		loads++
+
+		return nil, errors.New("boom")
+	}
+	got, err := cache.GetOrLoad(ctx, "key", time.Hour, failing)
+	if err != nil {
+		t.Fatalf("expected the previous value on load failure, got %v", err)
+	}
+	if string(got) != "old" || loads != 1 {
+		t.Fatalf("expected one revalidation serving %q, got %q after %d loads", "old", got, loads)
+	}
+
+	got, err = cache.GetOrLoad(ctx, "key", time.Hour, func(context.Context) ([]byte, error) {
+		return []byte("new"), nil
+	})
+	if err != nil || string(got) != "new" {
+		t.Fatalf("expected reloaded value %q, got %q, %v", "new", got, err)
+	}
+}
+
+func TestCache_SoftDeleteRevalidateAfter(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	now := time.UnixMilli(1_000_000)
+	provider.items["key"] = CacheObject[int]{Value: 7, ExpireAtMillis: now.Add(time.Hour).UnixMilli()}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{}, WithSoftDeleteFallback[int, CacheObject[int]]())
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return now }
+	ctx := context.Background()
+
+	ok, err := cache.SoftDelete(ctx, "key", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("expected key to be soft-deleted, got %v, %v", ok, err)
+	}
+	if want := now.Add(time.Minute).UnixMilli(); provider.items["key"].ExpireAtMillis != want {
+		t.Fatalf("expected expiry %d, got %d", want, provider.items["key"].ExpireAtMillis)
+	}
+	if _, ok := provider.items["key"+tombstoneSuffix]; !ok {
+		t.Fatal("expected a tombstone")
+	}
+	if !cache.(*cacheImpl[int, CacheObject[int]]).tombstoned(ctx, "key") {
+		t.Fatal("expected key to be tombstoned")
+	}
+
+	ok, err = cache.SoftDelete(ctx, "missing", 0)
+	if err != nil || ok {
+		t.Fatalf("expected missing key to report false, got %v, %v", ok, err)
+	}
+}
+
+func TestCache_LoadFailureWithoutTombstoneReturnsError(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	provider.items["key"] = CacheObject[int]{Value: 7, ExpireAtMillis: 1}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	if _, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+		return 0, errors.New("boom")
+	}); err == nil {
+		t.Fatal("expected the load error")
+	}
+}
+
+func TestCache_LoadFailureWithoutSoftDeleteFallbackSkipsTombstone(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	now := time.UnixMilli(1_000_000)
+	provider.items["key"] = CacheObject[int]{Value: 7, ExpireAtMillis: now.Add(time.Hour).UnixMilli()}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if ok, err := cache.SoftDelete(ctx, "key", 0); err != nil || !ok {
+		t.Fatalf("expected key to be soft-deleted, got %v, %v", ok, err)
+	}
+	if _, err := cache.GetOrLoad(ctx, "key", time.Minute, func(context.Context) (int, error) {
+		return 0, errors.New("boom")
+	}); err == nil {
+		t.Fatal("expected the load error without WithSoftDeleteFallback")
+	}
+}
@@ -0,0 +1,39 @@
+package crema
+
+// CacheStats is a point-in-time snapshot of a cache instance.
+type CacheStats struct {
+	// Sized reports whether the provider implements SizedProvider. Entries
+	// and Bytes are zero otherwise.
+	Sized bool
+	// Entries is the number of entries held by the provider.
+	Entries int
+	// Bytes is the approximate number of bytes held by the provider.
+	Bytes int64
+	// Degraded reports whether WithDegradation currently serves stale values
+	// on load failures and lengthens ttls.
+	Degraded bool
+	// ReadCircuit and WriteCircuit are the states of the provider circuit
+	// breakers configured with WithProviderCircuitBreakers, CircuitClosed
+	// otherwise.
+	ReadCircuit  CircuitState
+	WriteCircuit CircuitState
+	// Experiments holds the rollout of each configured experimental
+	// behavior, as set by WithExperimental and Settings.Experiments.
+	Experiments map[Experiment]float64
+}
+
+// Stats returns a snapshot of the cache state.
+func (c *cacheImpl[V, S]) Stats() CacheStats {
+	stats := CacheStats{Degraded: c.degraded(), Experiments: c.experiments()}
+	if c.circuitBreakers != nil {
+		stats.ReadCircuit = c.circuitBreakers.read.currentState()
+		stats.WriteCircuit = c.circuitBreakers.write.currentState()
+	}
+	if sized, ok := providerAs[SizedProvider](c.provider); ok {
+		stats.Sized = true
+		stats.Entries = sized.Len()
+		stats.Bytes = sized.SizeBytes()
+	}
+
+	return stats
+}
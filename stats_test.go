@@ -0,0 +1,49 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type cacheSizeMetricsProvider struct {
+	BaseMetricsProvider
+	entries int
+	bytes   int64
+}
+
+func (m *cacheSizeMetricsProvider) RecordCacheSize(_ context.Context, entries int, bytes int64) {
+	m.entries = entries
+	m.bytes = bytes
+}
+
+func TestCache_StatsReportsProviderSize(t *testing.T) {
+	t.Parallel()
+
+	metrics := &cacheSizeMetricsProvider{}
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache(provider, JSONByteStringCodec[string]{}, WithMetricsProvider[string, []byte](metrics))
+
+	if err := cache.Set(context.Background(), "k", CacheObject[string]{Value: "v", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	stats := cache.Stats()
+	if !stats.Sized || stats.Entries != 1 || stats.Bytes != provider.SizeBytes() {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if metrics.entries != 1 || metrics.bytes != provider.SizeBytes() {
+		t.Fatalf("expected size metrics to be recorded, got %d entries, %d bytes", metrics.entries, metrics.bytes)
+	}
+}
+
+func TestCache_StatsWithoutSizedProvider(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+
+	if stats := cache.Stats(); stats.Sized || stats.Entries != 0 {
+		t.Fatalf("expected unsized stats, got %+v", stats)
+	}
+}
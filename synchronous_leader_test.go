@@ -0,0 +1,92 @@
+package crema
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func goroutineID() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	id, _, _ := bytes.Cut(bytes.TrimPrefix(buf, []byte("goroutine ")), []byte(" "))
+
+	return string(id)
+}
+
+func TestWithSynchronousLeader_RunsLoaderOnCaller(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     []CacheOption[int, CacheObject[int]]
+		sameGoID bool
+	}{
+		{name: "default", sameGoID: false},
+		{name: "synchronous", opts: []CacheOption[int, CacheObject[int]]{WithSynchronousLeader[int, CacheObject[int]]()}, sameGoID: true},
+		{name: "synchronous with timeout", opts: []CacheOption[int, CacheObject[int]]{
+			WithSynchronousLeader[int, CacheObject[int]](),
+			WithMaxLoadTimeout[int, CacheObject[int]](time.Second),
+		}, sameGoID: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{}, tt.opts...)
+			caller := goroutineID()
+			var loaderID string
+			v, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+				loaderID = goroutineID()
+
+				return 1, nil
+			})
+			if err != nil || v != 1 {
+				t.Fatalf("expected 1, got %d, %v", v, err)
+			}
+			if got := loaderID == caller; got != tt.sameGoID {
+				t.Fatalf("expected loader on caller goroutine to be %v, got caller=%s loader=%s", tt.sameGoID, caller, loaderID)
+			}
+		})
+	}
+}
+
+func TestWithSynchronousLeader_FollowersShareResult(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(&testMemoryProvider[int]{items: make(map[string]CacheObject[int])}, NoopCacheStorageCodec[int]{},
+		WithSynchronousLeader[int, CacheObject[int]](),
+	)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 7, nil
+		})
+		leaderDone <- err
+	}()
+	<-started
+
+	followerDone := make(chan int, 1)
+	go func() {
+		v, _ := cache.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (int, error) {
+			return 0, nil
+		})
+		followerDone <- v
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if err := <-leaderDone; err != nil {
+		t.Fatalf("leader: %v", err)
+	}
+	if v := <-followerDone; v != 7 {
+		t.Fatalf("expected follower to share 7, got %d", v)
+	}
+}
@@ -0,0 +1,335 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultRefreshTagConcurrency = 16
+
+// ErrTagIndexNotConfigured is returned by RefreshTag when the cache has no
+// TagIndex.
+var ErrTagIndexNotConfigured = errors.New("cache has no tag index")
+
+// TagIndex records which keys were stored under which tags, so groups of
+// keys can be enumerated without scanning the provider.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type TagIndex interface {
+	// Add records key under every tag in tags.
+	Add(ctx context.Context, key string, tags []string) error
+	// Remove forgets key under every tag.
+	Remove(ctx context.Context, key string) error
+	// Keys calls yield for every key recorded under tag until yield returns
+	// false.
+	Keys(ctx context.Context, tag string, yield func(key string) bool) error
+}
+
+// MemoryTagIndex keeps tags in process memory.
+type MemoryTagIndex struct {
+	_    noCopy
+	mu   sync.Mutex
+	keys map[string]map[string]struct{}
+	tags map[string][]string
+}
+
+var _ TagIndex = (*MemoryTagIndex)(nil)
+
+// NewMemoryTagIndex constructs an empty MemoryTagIndex.
+func NewMemoryTagIndex() *MemoryTagIndex {
+	return &MemoryTagIndex{keys: make(map[string]map[string]struct{}), tags: make(map[string][]string)}
+}
+
+// Add records key under tags.
+func (m *MemoryTagIndex) Add(_ context.Context, key string, tags []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, tag := range tags {
+		keys, ok := m.keys[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			m.keys[tag] = keys
+		}
+		if _, ok := keys[key]; ok {
+			continue
+		}
+		keys[key] = struct{}{}
+		m.tags[key] = append(m.tags[key], tag)
+	}
+
+	return nil
+}
+
+// Remove forgets key under every tag.
+func (m *MemoryTagIndex) Remove(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, tag := range m.tags[key] {
+		delete(m.keys[tag], key)
+		if len(m.keys[tag]) == 0 {
+			delete(m.keys, tag)
+		}
+	}
+	delete(m.tags, key)
+
+	return nil
+}
+
+// Keys yields a snapshot of the keys under tag in sorted order.
+func (m *MemoryTagIndex) Keys(ctx context.Context, tag string, yield func(key string) bool) error {
+	m.mu.Lock()
+	keys := make([]string, 0, len(m.keys[tag]))
+	for key := range m.keys[tag] {
+		keys = append(keys, key)
+	}
+	m.mu.Unlock()
+	slices.Sort(keys)
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !yield(key) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+type tagsKey struct{}
+
+// WithTags returns a context whose writes, including values stored by
+// GetOrLoad, are recorded under tags in the TagIndex configured with
+// WithTagIndex.
+func WithTags(ctx context.Context, tags ...string) context.Context {
+	return context.WithValue(ctx, tagsKey{}, slices.Clone(tags))
+}
+
+// WithTagIndex records keys written with WithTags in index in the
+// background, and forgets deleted keys, so RefreshTag can enumerate them.
+// Index failures are reported like other background errors.
+func WithTagIndex[V any, S any](index TagIndex) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.tagIndex = index
+	}
+}
+
+// tagKey records key under the tags of ctx.
+func (c *cacheImpl[V, S]) tagKey(ctx context.Context, key string) {
+	if c.tagIndex == nil {
+		return
+	}
+	tags, _ := ctx.Value(tagsKey{}).([]string)
+	if len(tags) == 0 {
+		return
+	}
+	c.runner.goAsync(func(ctx context.Context) error {
+		if err := c.tagIndex.Add(ctx, key, tags); err != nil {
+			return fmt.Errorf("tag %q: %w", key, err)
+		}
+
+		return nil
+	})
+}
+
+// forgetTags removes key from the tag index.
+func (c *cacheImpl[V, S]) forgetTags(key string) {
+	if c.tagIndex == nil {
+		return
+	}
+	c.runner.goAsync(func(ctx context.Context) error {
+		if err := c.tagIndex.Remove(ctx, key); err != nil {
+			return fmt.Errorf("untag %q: %w", key, err)
+		}
+
+		return nil
+	})
+}
+
+// LoaderRegistry maps key layouts to the loaders that produce their values,
+// so keys can be refreshed without the call site that first loaded them.
+// Register every layout before use; lookups are safe for concurrent use.
+type LoaderRegistry[V any] struct {
+	entries []registeredLoader[V]
+}
+
+type registeredLoader[V any] struct {
+	template *KeyTemplate
+	ttl      time.Duration
+	loader   func(ctx context.Context, params []string) (V, error)
+}
+
+// NewLoaderRegistry constructs an empty LoaderRegistry.
+func NewLoaderRegistry[V any]() *LoaderRegistry[V] {
+	return &LoaderRegistry[V]{}
+}
+
+// Register makes keys matching template load with loader, which receives
+// the placeholder values parsed from the key, and be stored for ttl.
+// Templates are tried in registration order.
+func (r *LoaderRegistry[V]) Register(template *KeyTemplate, ttl time.Duration, loader func(ctx context.Context, params []string) (V, error)) *LoaderRegistry[V] {
+	r.entries = append(r.entries, registeredLoader[V]{template: template, ttl: ttl, loader: loader})
+
+	return r
+}
+
+// Lookup returns the loader and ttl registered for key.
+func (r *LoaderRegistry[V]) Lookup(key string) (CacheLoadFunc[V], time.Duration, bool) {
+	for _, entry := range r.entries {
+		params, ok := entry.template.Parse(key)
+		if !ok {
+			continue
+		}
+		loader := entry.loader
+
+		return func(ctx context.Context) (V, error) {
+			return loader(ctx, params)
+		}, entry.ttl, true
+	}
+
+	return nil, 0, false
+}
+
+// RefreshTagResult counts the outcome of RefreshTag.
+type RefreshTagResult struct {
+	// Refreshed is the number of keys reloaded and stored.
+	Refreshed int
+	// Skipped is the number of keys no registered loader matched or the
+	// provider no longer held.
+	Skipped int
+	// Failed is the number of keys whose load or write failed.
+	Failed int
+}
+
+// RefreshTagOption configures a RefreshTag call.
+type RefreshTagOption func(*refreshTagConfig)
+
+type refreshTagConfig struct {
+	concurrency int
+	onError     func(key string, err error)
+}
+
+// WithRefreshConcurrency sets how many keys RefreshTag reloads at once, 16
+// by default. Non-positive values are ignored.
+func WithRefreshConcurrency(n int) RefreshTagOption {
+	return func(cfg *refreshTagConfig) {
+		if n > 0 {
+			cfg.concurrency = n
+		}
+	}
+}
+
+// WithRefreshErrorHandler calls fn for every key RefreshTag failed to
+// refresh. fn may be called concurrently.
+func WithRefreshErrorHandler(fn func(key string, err error)) RefreshTagOption {
+	return func(cfg *refreshTagConfig) {
+		cfg.onError = fn
+	}
+}
+
+// RefreshTag reloads every key recorded under tag with the loader registry
+// resolves for it and overwrites its entry, bounded by a concurrency limit.
+// Use it after a bulk upstream change, where deleting the keys instead would
+// send every reader to the origin at once. Entries keep serving their old
+// value until replaced. Each key is loaded through the same singleflight,
+// load limiter and cost guard as GetOrLoad, so a concurrent read of the key
+// shares the load. Keys the provider no longer holds are skipped and removed
+// from the tag index rather than brought back. Failed keys are counted and reported to the error
+// handler without stopping the refresh; the returned error is that of the
+// tag index or ctx.
+func (c *cacheImpl[V, S]) RefreshTag(ctx context.Context, tag string, registry *LoaderRegistry[V], opts ...RefreshTagOption) (RefreshTagResult, error) {
+	if c.tagIndex == nil {
+		return RefreshTagResult{}, ErrTagIndexNotConfigured
+	}
+	cfg := refreshTagConfig{concurrency: defaultRefreshTagConcurrency}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	var (
+		wg                         sync.WaitGroup
+		refreshed, skipped, failed atomic.Int64
+	)
+	slots := make(chan struct{}, cfg.concurrency)
+	err := c.tagIndex.Keys(ctx, tag, func(key string) bool {
+		loader, ttl, ok := registry.Lookup(key)
+		if !ok {
+			skipped.Add(1)
+
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case slots <- struct{}{}:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			cached, err := c.refreshKey(ctx, key, ttl, loader)
+			if err != nil {
+				failed.Add(1)
+				if cfg.onError != nil {
+					cfg.onError(key, err)
+				}
+
+				return
+			}
+			if !cached {
+				skipped.Add(1)
+				if err := c.tagIndex.Remove(ctx, key); err != nil {
+					c.logger.Warn("failed to untag uncached key", slog.String("key", key), slog.String("error", err.Error()))
+				}
+
+				return
+			}
+			refreshed.Add(1)
+		}()
+
+		return true
+	})
+	wg.Wait()
+	if err == nil {
+		err = ctx.Err()
+	}
+	result := RefreshTagResult{Refreshed: int(refreshed.Load()), Skipped: int(skipped.Load()), Failed: int(failed.Load())}
+
+	return result, err
+}
+
+// forcedRefresh marks a load that RefreshTag forces for a cached key.
+type forcedRefresh struct {
+	// uncached records that the provider no longer held the key.
+	uncached bool
+}
+
+type forcedRefreshKey struct{}
+
+// forcedRefreshFrom returns the forced refresh ctx carries, or nil.
+func forcedRefreshFrom(ctx context.Context) *forcedRefresh {
+	forced, _ := ctx.Value(forcedRefreshKey{}).(*forcedRefresh)
+
+	return forced
+}
+
+// refreshKey reloads key with loader through the cache's load path, sharing
+// the singleflight, load limiter and cost guard with concurrent reads, and
+// stores the value for ttl. It reports false when the provider no longer
+// holds key, which is then left uncached.
+func (c *cacheImpl[V, S]) refreshKey(ctx context.Context, key string, ttl time.Duration, loader CacheLoadFunc[V]) (bool, error) {
+	forced := &forcedRefresh{}
+	ctx = context.WithValue(ctx, forcedRefreshKey{}, forced)
+	_, err := c.serveOrLoad(ctx, key, nil, ttl, func(*CacheObject[V]) CacheLoadFunc[V] {
+		return loader
+	}, nil)
+
+	return !forced.uncached, err
+}
@@ -0,0 +1,215 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func tagKeys(t *testing.T, index TagIndex, tag string) []string {
+	t.Helper()
+
+	var keys []string
+	if err := index.Keys(context.Background(), tag, func(key string) bool {
+		keys = append(keys, key)
+
+		return true
+	}); err != nil {
+		t.Fatalf("keys: %v", err)
+	}
+
+	return keys
+}
+
+func TestWithTagIndex_RecordsTaggedWrites(t *testing.T) {
+	t.Parallel()
+
+	index := NewMemoryTagIndex()
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{},
+		WithTagIndex[int, CacheObject[int]](index),
+	)
+	ctx := context.Background()
+	loader := func(context.Context) (int, error) { return 1, nil }
+	if _, err := cache.GetOrLoad(WithTags(ctx, "import", "user"), "user:1", time.Minute, loader); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, err := cache.GetOrLoad(WithTags(ctx, "import"), "user:2", time.Minute, loader); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, err := cache.GetOrLoad(ctx, "user:3", time.Minute, loader); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := tagKeys(t, index, "import"); !slices.Equal(got, []string{"user:1", "user:2"}) {
+		t.Fatalf("expected tagged keys, got %v", got)
+	}
+
+	if err := cache.Delete(ctx, "user:1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := tagKeys(t, index, "import"); !slices.Equal(got, []string{"user:2"}) {
+		t.Fatalf("expected deleted key untagged, got %v", got)
+	}
+	if got := tagKeys(t, index, "user"); len(got) != 0 {
+		t.Fatalf("expected deleted key removed from every tag, got %v", got)
+	}
+}
+
+func TestCache_RefreshTag(t *testing.T) {
+	t.Parallel()
+
+	index := NewMemoryTagIndex()
+	provider := NewMemoryCacheProvider[CacheObject[string]]()
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithTagIndex[string, CacheObject[string]](index),
+	)
+	ctx := context.Background()
+	for _, key := range []string{"user:1", "user:2", "user:fail", "other:1"} {
+		if err := index.Add(ctx, key, []string{"import"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		if err := cache.Set(ctx, key, CacheObject[string]{Value: "old", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	errBackend := errors.New("backend down")
+	registry := NewLoaderRegistry[string]().Register(MustKeyTemplate("user:{id}"), time.Hour, func(_ context.Context, params []string) (string, error) {
+		if params[0] == "fail" {
+			return "", errBackend
+		}
+
+		return "new:" + params[0], nil
+	})
+
+	var failedKeys []string
+	result, err := cache.RefreshTag(ctx, "import", registry,
+		WithRefreshConcurrency(1),
+		WithRefreshErrorHandler(func(key string, err error) {
+			if !errors.Is(err, errBackend) {
+				t.Errorf("expected backend error, got %v", err)
+			}
+			failedKeys = append(failedKeys, key)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if result != (RefreshTagResult{Refreshed: 2, Skipped: 1, Failed: 1}) {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if !slices.Equal(failedKeys, []string{"user:fail"}) {
+		t.Fatalf("expected user:fail reported, got %v", failedKeys)
+	}
+	for key, want := range map[string]string{"user:1": "new:1", "user:2": "new:2", "user:fail": "old", "other:1": "old"} {
+		co, ok, err := cache.Get(ctx, key)
+		if err != nil || !ok || co.Value != want {
+			t.Fatalf("expected %s=%q, got %q, %v, %v", key, want, co.Value, ok, err)
+		}
+	}
+}
+
+func TestCache_RefreshTagWithoutIndex(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{})
+	if _, err := cache.RefreshTag(context.Background(), "tag", NewLoaderRegistry[int]()); !errors.Is(err, ErrTagIndexNotConfigured) {
+		t.Fatalf("expected ErrTagIndexNotConfigured, got %v", err)
+	}
+}
+
+func TestCache_RefreshTagSkipsAndUntagsUncachedKeys(t *testing.T) {
+	t.Parallel()
+
+	index := NewMemoryTagIndex()
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[string]](), NoopCacheStorageCodec[string]{},
+		WithTagIndex[string, CacheObject[string]](index),
+	)
+	ctx := context.Background()
+	if err := index.Add(ctx, "user:gone", []string{"import"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	var loads atomic.Int32
+	registry := NewLoaderRegistry[string]().Register(MustKeyTemplate("user:{id}"), time.Hour, func(context.Context, []string) (string, error) {
+		loads.Add(1)
+
+		return "new", nil
+	})
+
+	result, err := cache.RefreshTag(ctx, "import", registry)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if result != (RefreshTagResult{Skipped: 1}) {
+		t.Fatalf("expected the uncached key skipped, got %+v", result)
+	}
+	if got := loads.Load(); got != 0 {
+		t.Fatalf("expected no loads, got %d", got)
+	}
+	if _, ok, err := cache.Get(ctx, "user:gone"); err != nil || ok {
+		t.Fatalf("expected the key to stay uncached, got %v, %v", ok, err)
+	}
+	if got := tagKeys(t, index, "import"); len(got) != 0 {
+		t.Fatalf("expected the uncached key untagged, got %v", got)
+	}
+}
+
+func TestCache_RefreshTagSharesConcurrentLoad(t *testing.T) {
+	t.Parallel()
+
+	index := NewMemoryTagIndex()
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[string]](), NoopCacheStorageCodec[string]{},
+		WithTagIndex[string, CacheObject[string]](index),
+	)
+	ctx := context.Background()
+	if err := index.Add(ctx, "user:1", []string{"import"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if err := cache.Set(ctx, "user:1", CacheObject[string]{Value: "old", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	var loads atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(context.Context, []string) (string, error) {
+		if loads.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+
+		return "new", nil
+	}
+	registry := NewLoaderRegistry[string]().Register(MustKeyTemplate("user:{id}"), time.Hour, loader)
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.RefreshTag(ctx, "import", registry)
+		first <- err
+	}()
+	<-started
+	second := make(chan error, 1)
+	go func() {
+		_, err := cache.RefreshTag(ctx, "import", registry)
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	for _, done := range []chan error{first, second} {
+		if err := <-done; err != nil {
+			t.Fatalf("refresh: %v", err)
+		}
+	}
+	if got := loads.Load(); got != 1 {
+		t.Fatalf("expected one shared load, got %d", got)
+	}
+	if co, ok, err := cache.Get(ctx, "user:1"); err != nil || !ok || co.Value != "new" {
+		t.Fatalf("expected refreshed value, got %q, %v, %v", co.Value, ok, err)
+	}
+}
@@ -0,0 +1,86 @@
+package crema
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TakeProvider is an optional CacheProvider extension that reads and removes
+// an entry in one atomic step, such as Redis GETDEL.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type TakeProvider[S any] interface {
+	CacheProvider[S]
+	// Take returns the value stored for key and removes it, so that at most
+	// one caller receives it.
+	Take(ctx context.Context, key string) (S, bool, error)
+}
+
+// Take returns the cached entry for key and removes it, for one-shot tokens
+// and idempotency keys. Single consumption is only guaranteed when the
+// provider implements TakeProvider; otherwise the entry is read and then
+// deleted, and concurrent callers may both receive it. Expired entries are
+// removed and reported as missing.
+func (c *cacheImpl[V, S]) Take(ctx context.Context, key string) (CacheObject[V], bool, error) {
+	ctx = c.metricsContext(ctx)
+	c.metrics.RecordCacheGet(ctx)
+	c.metrics.RecordCacheDelete(ctx)
+	c.scopeDelete(ctx, key)
+
+	started := time.Now()
+	rv, exists, err := c.take(ctx, key)
+	if err != nil {
+		return CacheObject[V]{}, false, c.observeError(ctx, ErrorPhaseProviderGet, key, started, err)
+	}
+	if !exists {
+		return CacheObject[V]{}, false, nil
+	}
+	c.forgetRecency(key)
+	c.forgetTags(key)
+	c.replicateDelete(ctx, key)
+
+	started = time.Now()
+	co, err := c.decode(key, rv)
+	if err != nil {
+		return CacheObject[V]{}, false, c.observeError(ctx, ErrorPhaseDecode, key, started, err)
+	}
+	if nowMillis := c.now().UnixMilli(); c.skewedExpiry(ctx, nowMillis, co.ExpireAtMillis) <= nowMillis {
+		return CacheObject[V]{}, false, nil
+	}
+	c.metrics.RecordCacheHit(ctx)
+
+	return co, true, nil
+}
+
+func (c *cacheImpl[V, S]) take(ctx context.Context, key string) (S, bool, error) {
+	if taker, ok := providerAs[TakeProvider[S]](c.provider); ok {
+		return taker.Take(ctx, key)
+	}
+
+	rv, exists, err := c.provider.Get(ctx, key)
+	if err != nil || !exists {
+		return rv, exists, err
+	}
+	if err := c.provider.Delete(ctx, key); err != nil {
+		var zero S
+
+		return zero, false, err
+	}
+
+	return rv, true, nil
+}
+
+// forgetRecency asynchronously removes the access record for key.
+func (c *cacheImpl[V, S]) forgetRecency(key string) {
+	if c.recencyTracker == nil {
+		return
+	}
+	c.recencyQueue.forget(key)
+	c.runner.goAsync(func(ctx context.Context) error {
+		if err := c.recencyTracker.Remove(ctx, key); err != nil {
+			return fmt.Errorf("remove recency record for %q: %w", key, err)
+		}
+
+		return nil
+	})
+}
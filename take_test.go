@@ -0,0 +1,59 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCache_TakeConsumesOnce(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	cache := NewCache(provider, JSONByteStringCodec[string]{})
+	ctx := context.Background()
+	if err := cache.Set(ctx, "token", CacheObject[string]{Value: "once", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	co, ok, err := cache.Take(ctx, "token")
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if !ok || co.Value != "once" {
+		t.Fatalf("unexpected take result: %+v, %v", co, ok)
+	}
+	if _, ok, _ := cache.Take(ctx, "token"); ok {
+		t.Fatal("expected token to be consumed")
+	}
+	if provider.Len() != 0 {
+		t.Fatalf("expected empty provider, got %d entries", provider.Len())
+	}
+}
+
+func TestCache_TakeFallsBackToGetDelete(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	provider.items["key"] = CacheObject[int]{Value: 1, ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}
+	provider.items["expired"] = CacheObject[int]{Value: 2, ExpireAtMillis: 1}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+
+	co, ok, err := cache.Take(context.Background(), "key")
+	if err != nil {
+		t.Fatalf("take: %v", err)
+	}
+	if !ok || co.Value != 1 {
+		t.Fatalf("unexpected take result: %+v, %v", co, ok)
+	}
+	if _, ok := provider.items["key"]; ok {
+		t.Fatal("expected key to be deleted")
+	}
+
+	if _, ok, _ := cache.Take(context.Background(), "expired"); ok {
+		t.Fatal("expected expired entry to be reported missing")
+	}
+	if _, ok := provider.items["expired"]; ok {
+		t.Fatal("expected expired entry to be deleted")
+	}
+}
@@ -0,0 +1,178 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+const defaultTieredL1TTL = time.Minute
+
+// TieredReadPreference selects how a TieredCacheProvider reads its tiers.
+type TieredReadPreference int
+
+const (
+	// TieredReadL1WithinSLA serves L1 hits and reads L2 only on an L1 miss,
+	// backfilling L1. L1 entries live at most TieredReadPolicy.L1TTL, which
+	// bounds how stale an L1 hit can be.
+	TieredReadL1WithinSLA TieredReadPreference = iota
+	// TieredReadRace reads both tiers in parallel and returns the first hit,
+	// trading extra L2 load for the latency of the faster tier.
+	TieredReadRace
+	// TieredReadL2VerifySampled serves L1 hits like TieredReadL1WithinSLA but
+	// reads L2 as well for a sampled fraction of them, returning and
+	// backfilling the L2 entry when it differs.
+	TieredReadL2VerifySampled
+)
+
+// String returns the preference name.
+func (p TieredReadPreference) String() string {
+	switch p {
+	case TieredReadRace:
+		return "race"
+	case TieredReadL2VerifySampled:
+		return "l2-verify-sampled"
+	default:
+		return "l1-within-sla"
+	}
+}
+
+// TieredReadPolicy configures the reads of a TieredCacheProvider.
+type TieredReadPolicy struct {
+	// Preference selects the read strategy.
+	Preference TieredReadPreference
+	// L1TTL caps the ttl of entries written to L1, including backfills.
+	// Zero defaults to one minute.
+	L1TTL time.Duration
+	// VerifySampleRate is the fraction of L1 hits verified against L2 under
+	// TieredReadL2VerifySampled, clamped to [0, 1].
+	VerifySampleRate float64
+}
+
+// TieredCacheProvider layers a fast local provider (L1) over a shared one
+// (L2). Writes and deletes go to L2 and then L1; reads follow the configured
+// TieredReadPolicy. Both tiers must hold entries encoded by the same codec,
+// since the cache checks expiry after decoding.
+type TieredCacheProvider[S any] struct {
+	l1     CacheProvider[S]
+	l2     CacheProvider[S]
+	policy TieredReadPolicy
+	random func() float64
+}
+
+var _ CacheProvider[any] = (*TieredCacheProvider[any])(nil)
+
+// NewTieredCacheProvider layers l1 over l2 with policy.
+func NewTieredCacheProvider[S any](l1 CacheProvider[S], l2 CacheProvider[S], policy TieredReadPolicy) *TieredCacheProvider[S] {
+	if policy.L1TTL <= 0 {
+		policy.L1TTL = defaultTieredL1TTL
+	}
+	policy.VerifySampleRate = min(max(policy.VerifySampleRate, 0), 1)
+
+	return &TieredCacheProvider[S]{l1: l1, l2: l2, policy: policy, random: rand.Float64}
+}
+
+// Get reads key according to the read policy. L1 errors fall back to L2.
+func (t *TieredCacheProvider[S]) Get(ctx context.Context, key string) (S, bool, error) {
+	if t.policy.Preference == TieredReadRace {
+		return t.race(ctx, key)
+	}
+
+	value, ok, err := t.l1.Get(ctx, key)
+	if err == nil && ok {
+		if t.policy.Preference == TieredReadL2VerifySampled && t.random() < t.policy.VerifySampleRate {
+			return t.verify(ctx, key, value)
+		}
+
+		return value, true, nil
+	}
+
+	return t.getL2(ctx, key)
+}
+
+// getL2 reads key from L2 and backfills L1 on a hit.
+func (t *TieredCacheProvider[S]) getL2(ctx context.Context, key string) (S, bool, error) {
+	value, ok, err := t.l2.Get(ctx, key)
+	if err != nil || !ok {
+		return value, ok, err
+	}
+	_ = t.l1.Set(ctx, key, value, t.policy.L1TTL)
+
+	return value, true, nil
+}
+
+// verify compares an L1 hit with L2, preferring L2 when they differ.
+func (t *TieredCacheProvider[S]) verify(ctx context.Context, key string, l1Value S) (S, bool, error) {
+	value, ok, err := t.l2.Get(ctx, key)
+	switch {
+	case err != nil:
+		return l1Value, true, nil
+	case !ok:
+		_ = t.l1.Delete(ctx, key)
+
+		return value, false, nil
+	case !equalStorageValues(l1Value, value):
+		_ = t.l1.Set(ctx, key, value, t.policy.L1TTL)
+	}
+
+	return value, true, nil
+}
+
+type tieredResult[S any] struct {
+	l1    bool
+	value S
+	ok    bool
+	err   error
+}
+
+// race reads both tiers in parallel and returns the first hit. An L2 hit
+// backfills L1, since L1 either missed or had not answered yet.
+func (t *TieredCacheProvider[S]) race(ctx context.Context, key string) (S, bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan tieredResult[S], 2)
+	go func() {
+		value, ok, err := t.l1.Get(ctx, key)
+		results <- tieredResult[S]{l1: true, value: value, ok: ok, err: err}
+	}()
+	go func() {
+		value, ok, err := t.l2.Get(ctx, key)
+		results <- tieredResult[S]{value: value, ok: ok, err: err}
+	}()
+
+	var l2 tieredResult[S]
+	for range 2 {
+		result := <-results
+		if result.err == nil && result.ok {
+			if !result.l1 {
+				_ = t.l1.Set(context.WithoutCancel(ctx), key, result.value, t.policy.L1TTL)
+			}
+
+			return result.value, true, nil
+		}
+		if !result.l1 {
+			l2 = result
+		}
+	}
+
+	return l2.value, false, l2.err
+}
+
+// Set writes value to L2 and then to L1 with its ttl capped at L1TTL.
+func (t *TieredCacheProvider[S]) Set(ctx context.Context, key string, value S, ttl time.Duration) error {
+	if err := t.l2.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	l1TTL := t.policy.L1TTL
+	if ttl > 0 {
+		l1TTL = min(ttl, l1TTL)
+	}
+
+	return t.l1.Set(ctx, key, value, l1TTL)
+}
+
+// Delete removes key from L2 and L1.
+func (t *TieredCacheProvider[S]) Delete(ctx context.Context, key string) error {
+	return errors.Join(t.l2.Delete(ctx, key), t.l1.Delete(ctx, key))
+}
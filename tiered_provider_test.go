@@ -0,0 +1,120 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// blockingProvider blocks reads until their context is done.
+type blockingProvider struct {
+	*MemoryCacheProvider[[]byte]
+}
+
+func (b blockingProvider) Get(ctx context.Context, _ string) ([]byte, bool, error) {
+	<-ctx.Done()
+
+	return nil, false, ctx.Err()
+}
+
+func TestTieredCacheProvider_L1WithinSLA(t *testing.T) {
+	t.Parallel()
+
+	l1, l2 := NewMemoryCacheProvider[[]byte](), NewMemoryCacheProvider[[]byte]()
+	tiered := NewTieredCacheProvider[[]byte](l1, l2, TieredReadPolicy{L1TTL: time.Second})
+	ctx := context.Background()
+
+	if err := l2.Set(ctx, "key", []byte("v2"), time.Hour); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	value, ok, err := tiered.Get(ctx, "key")
+	if err != nil || !ok || string(value) != "v2" {
+		t.Fatalf("expected L2 hit, got %q, %v, %v", value, ok, err)
+	}
+	if value, ok, _ := l1.Get(ctx, "key"); !ok || string(value) != "v2" {
+		t.Fatalf("expected L1 backfill, got %q, %v", value, ok)
+	}
+
+	// L1 hits are served without reading L2.
+	if err := l2.Set(ctx, "key", []byte("v3"), time.Hour); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if value, _, _ := tiered.Get(ctx, "key"); string(value) != "v2" {
+		t.Fatalf("expected L1 value within its ttl, got %q", value)
+	}
+
+	if err := tiered.Set(ctx, "other", []byte("x"), time.Hour); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	l1.mu.RLock()
+	l1ExpireAt := l1.items["other"].expireAt
+	l1.mu.RUnlock()
+	if remaining := time.Until(l1ExpireAt); remaining > time.Second {
+		t.Fatalf("expected L1 ttl capped at 1s, got %v", remaining)
+	}
+	if err := tiered.Delete(ctx, "other"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, ok, _ := l2.Get(ctx, "other"); ok {
+		t.Fatal("expected delete to reach L2")
+	}
+}
+
+func TestTieredCacheProvider_Race(t *testing.T) {
+	t.Parallel()
+
+	l2 := NewMemoryCacheProvider[[]byte]()
+	slowL1 := blockingProvider{NewMemoryCacheProvider[[]byte]()}
+	tiered := NewTieredCacheProvider[[]byte](slowL1, l2, TieredReadPolicy{Preference: TieredReadRace})
+	ctx := context.Background()
+	if err := l2.Set(ctx, "key", []byte("v2"), time.Hour); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	value, ok, err := tiered.Get(ctx, "key")
+	if err != nil || !ok || string(value) != "v2" {
+		t.Fatalf("expected L2 to win the race, got %q, %v, %v", value, ok, err)
+	}
+
+	l1 := NewMemoryCacheProvider[[]byte]()
+	tiered = NewTieredCacheProvider[[]byte](l1, l2, TieredReadPolicy{Preference: TieredReadRace})
+	if _, ok, err := tiered.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("expected miss, got %v, %v", ok, err)
+	}
+	if _, _, err := tiered.Get(ctx, "key"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if value, ok, _ := l1.Get(ctx, "key"); !ok || string(value) != "v2" {
+		t.Fatalf("expected L1 backfill after an L2 hit, got %q, %v", value, ok)
+	}
+}
+
+func TestTieredCacheProvider_L2VerifySampled(t *testing.T) {
+	t.Parallel()
+
+	l1, l2 := NewMemoryCacheProvider[[]byte](), NewMemoryCacheProvider[[]byte]()
+	tiered := NewTieredCacheProvider[[]byte](l1, l2, TieredReadPolicy{Preference: TieredReadL2VerifySampled, VerifySampleRate: 1})
+	ctx := context.Background()
+	_ = l1.Set(ctx, "key", []byte("stale"), time.Hour)
+	_ = l2.Set(ctx, "key", []byte("fresh"), time.Hour)
+
+	if value, _, _ := tiered.Get(ctx, "key"); string(value) != "fresh" {
+		t.Fatalf("expected L2 value on mismatch, got %q", value)
+	}
+	if value, _, _ := l1.Get(ctx, "key"); string(value) != "fresh" {
+		t.Fatalf("expected L1 to be repaired, got %q", value)
+	}
+
+	_ = l1.Set(ctx, "gone", []byte("stale"), time.Hour)
+	if _, ok, _ := tiered.Get(ctx, "gone"); ok {
+		t.Fatal("expected miss when L2 no longer holds the entry")
+	}
+	if _, ok, _ := l1.Get(ctx, "gone"); ok {
+		t.Fatal("expected L1 entry to be dropped")
+	}
+
+	tiered.random = fakeRandom(1)
+	_ = l1.Set(ctx, "key", []byte("unverified"), time.Hour)
+	if value, _, _ := tiered.Get(ctx, "key"); string(value) != "unverified" {
+		t.Fatalf("expected unsampled L1 hit, got %q", value)
+	}
+}
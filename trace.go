@@ -0,0 +1,29 @@
+package crema
+
+import "context"
+
+// LoadTraceLinker connects singleflight loads, which run with a context
+// detached from the caller, to the requests that consume their result.
+// A tracing integration typically starts a span in StartLoad and adds a span
+// link to the caller's trace context in Link.
+// Implementations must be safe for concurrent use and should avoid blocking.
+type LoadTraceLinker interface {
+	// StartLoad is called by the leader before the loader runs. It receives the
+	// detached load context and the leader caller's context, and returns the
+	// context passed to the loader plus a function called when the load ends.
+	StartLoad(loadCtx context.Context, callerCtx context.Context, key string) (context.Context, func())
+	// Link is called for each follower that joins an inflight load, with the
+	// context returned by StartLoad and the follower caller's context.
+	Link(loadCtx context.Context, callerCtx context.Context)
+}
+
+// WithLoadTraceLinker links singleflight leader loads to the traces of all
+// callers that joined them. It has no effect with WithDirectLoader, where
+// loaders already run with the caller context.
+func WithLoadTraceLinker[V any, S any](linker LoadTraceLinker) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if loader, ok := c.internalLoader.(*singleflightLoader[V]); ok {
+			loader.traceLinker = linker
+		}
+	}
+}
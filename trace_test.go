@@ -0,0 +1,102 @@
+package crema
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type traceKey struct{}
+
+type testTraceLinker struct {
+	mu      sync.Mutex
+	started []string
+	links   []string
+	ended   int
+}
+
+func (l *testTraceLinker) StartLoad(loadCtx context.Context, callerCtx context.Context, key string) (context.Context, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	caller, _ := callerCtx.Value(traceKey{}).(string)
+	l.started = append(l.started, key+"<-"+caller)
+
+	return context.WithValue(loadCtx, traceKey{}, "load:"+key), func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.ended++
+	}
+}
+
+func (l *testTraceLinker) Link(loadCtx context.Context, callerCtx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	load, _ := loadCtx.Value(traceKey{}).(string)
+	caller, _ := callerCtx.Value(traceKey{}).(string)
+	l.links = append(l.links, load+"<-"+caller)
+}
+
+func TestWithLoadTraceLinker_LinksFollowers(t *testing.T) {
+	t.Parallel()
+
+	linker := &testTraceLinker{}
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{}, WithLoadTraceLinker[string, CacheObject[string]](linker))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (string, error) {
+		close(started)
+		<-release
+		value, _ := ctx.Value(traceKey{}).(string)
+
+		return value, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx := context.WithValue(context.Background(), traceKey{}, "leader")
+		results[0], _ = cache.GetOrLoad(ctx, "key", time.Minute, loader)
+	}()
+	<-started
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctx := context.WithValue(context.Background(), traceKey{}, "follower")
+		results[1], _ = cache.GetOrLoad(ctx, "key", time.Minute, loader)
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		linker.mu.Lock()
+		linked := len(linker.links)
+		linker.mu.Unlock()
+		if linked == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timed out waiting for follower link")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	if results[0] != "load:key" || results[1] != "load:key" {
+		t.Fatalf("expected loader to receive linker context, got %v", results)
+	}
+	if len(linker.started) != 1 || linker.started[0] != "key<-leader" {
+		t.Fatalf("unexpected started loads: %v", linker.started)
+	}
+	if linker.links[0] != "load:key<-follower" {
+		t.Fatalf("unexpected links: %v", linker.links)
+	}
+	if linker.ended != 1 {
+		t.Fatalf("expected load trace to end once, got %d", linker.ended)
+	}
+}
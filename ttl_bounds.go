@@ -0,0 +1,68 @@
+package crema
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type ttlBounds struct {
+	min time.Duration
+	max time.Duration
+}
+
+// WithTTLBounds clamps the ttl passed to GetOrLoad and the remaining lifetime
+// of entries written with Set to [minTTL, maxTTL], so a bug passing ttl=0 or
+// a year-long ttl can neither disable caching nor pin entries forever. A
+// non-positive bound is not enforced. Entries that are already expired are
+// still skipped by Set. Clamping is reported through
+// MetricsProvider.RecordTTLClamped and logged at debug level.
+func WithTTLBounds[V any, S any](minTTL time.Duration, maxTTL time.Duration) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if minTTL <= 0 && maxTTL <= 0 {
+			c.ttlBounds = nil
+
+			return
+		}
+		c.ttlBounds = &ttlBounds{min: minTTL, max: maxTTL}
+	}
+}
+
+// boundTTL clamps ttl to the configured bounds.
+func (c *cacheImpl[V, S]) boundTTL(ctx context.Context, key string, ttl time.Duration) time.Duration {
+	if c.ttlBounds == nil {
+		return ttl
+	}
+	bounded := ttl
+	if c.ttlBounds.min > 0 && bounded < c.ttlBounds.min {
+		bounded = c.ttlBounds.min
+	}
+	if c.ttlBounds.max > 0 && bounded > c.ttlBounds.max {
+		bounded = c.ttlBounds.max
+	}
+	if bounded != ttl {
+		c.metrics.RecordTTLClamped(ctx)
+		c.logger.Debug("clamped cache ttl", slog.String("key", key),
+			slog.Duration("requested", ttl), slog.Duration("ttl", bounded))
+	}
+
+	return bounded
+}
+
+// boundExpiry clamps the remaining lifetime of an unexpired entry to the
+// configured bounds.
+func (c *cacheImpl[V, S]) boundExpiry(ctx context.Context, key string, value CacheObject[V]) CacheObject[V] {
+	if c.ttlBounds == nil {
+		return value
+	}
+	now := c.now()
+	ttl := time.UnixMilli(value.ExpireAtMillis).Sub(now)
+	if ttl <= 0 {
+		return value
+	}
+	if bounded := c.boundTTL(ctx, key, ttl); bounded != ttl {
+		value.ExpireAtMillis = now.Add(bounded).UnixMilli()
+	}
+
+	return value
+}
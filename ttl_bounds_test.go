@@ -0,0 +1,84 @@
+package crema
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ttlClampedMetricsProvider struct {
+	BaseMetricsProvider
+	clamped atomic.Int32
+}
+
+func (m *ttlClampedMetricsProvider) RecordTTLClamped(context.Context) {
+	m.clamped.Add(1)
+}
+
+func TestWithTTLBounds_ClampsGetOrLoadTTL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		wantTTL     time.Duration
+		wantClamped int32
+	}{
+		{name: "zero", ttl: 0, wantTTL: time.Second, wantClamped: 1},
+		{name: "too short", ttl: time.Millisecond, wantTTL: time.Second, wantClamped: 1},
+		{name: "within", ttl: time.Minute, wantTTL: time.Minute},
+		{name: "too long", ttl: 365 * 24 * time.Hour, wantTTL: time.Hour, wantClamped: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+			metrics := &ttlClampedMetricsProvider{}
+			cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+				WithMetricsProvider[int, CacheObject[int]](metrics),
+				WithTTLBounds[int, CacheObject[int]](time.Second, time.Hour),
+			)
+			now := time.UnixMilli(1_000_000)
+			cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return now }
+
+			if _, err := cache.GetOrLoad(context.Background(), "key", tt.ttl, func(context.Context) (int, error) {
+				return 1, nil
+			}); err != nil {
+				t.Fatalf("load: %v", err)
+			}
+			if got := provider.items["key"].ExpireAtMillis; got != now.Add(tt.wantTTL).UnixMilli() {
+				t.Fatalf("expected expiry %d, got %d", now.Add(tt.wantTTL).UnixMilli(), got)
+			}
+			if got := metrics.clamped.Load(); got != tt.wantClamped {
+				t.Fatalf("expected %d clamps, got %d", tt.wantClamped, got)
+			}
+		})
+	}
+}
+
+func TestWithTTLBounds_ClampsSet(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+		WithTTLBounds[int, CacheObject[int]](0, time.Hour),
+	)
+	now := time.UnixMilli(1_000_000)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := cache.Set(ctx, "forever", CacheObject[int]{Value: 1, ExpireAtMillis: now.Add(24 * time.Hour).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got := provider.items["forever"].ExpireAtMillis; got != now.Add(time.Hour).UnixMilli() {
+		t.Fatalf("expected expiry clamped to an hour, got %d", got)
+	}
+	if err := cache.Set(ctx, "expired", CacheObject[int]{Value: 1, ExpireAtMillis: now.UnixMilli() - 1}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, ok := provider.items["expired"]; ok {
+		t.Fatal("expected expired entry to be skipped")
+	}
+}
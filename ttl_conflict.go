@@ -0,0 +1,141 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// maxTrackedTTLsPerShard bounds the keys remembered by TTL conflict detection.
+const maxTrackedTTLsPerShard = 1024
+
+// ErrTTLConflict is returned by GetOrLoad under TTLConflictError when a key
+// is requested with a different ttl than before.
+var ErrTTLConflict = errors.New("conflicting ttl for cache key")
+
+// TTLConflictPolicy decides the ttl used when call sites request the same key
+// with different ttls.
+type TTLConflictPolicy int
+
+const (
+	// TTLConflictLastWins uses the ttl of each call, so the latest write
+	// decides the stored expiry. This matches the behavior without a policy
+	// and only adds detection.
+	TTLConflictLastWins TTLConflictPolicy = iota
+	// TTLConflictMaxWins uses the largest ttl requested for the key so far.
+	TTLConflictMaxWins
+	// TTLConflictFirstWins uses the first ttl requested for the key.
+	TTLConflictFirstWins
+	// TTLConflictError fails calls whose ttl differs from the first ttl
+	// requested for the key with ErrTTLConflict.
+	TTLConflictError
+)
+
+// String returns the policy name.
+func (p TTLConflictPolicy) String() string {
+	switch p {
+	case TTLConflictMaxWins:
+		return "max-wins"
+	case TTLConflictFirstWins:
+		return "first-wins"
+	case TTLConflictError:
+		return "error"
+	default:
+		return "last-wins"
+	}
+}
+
+// ttlConflictTracker remembers the ttl requested per key to detect and
+// resolve conflicts.
+type ttlConflictTracker struct {
+	policy TTLConflictPolicy
+	shards []ttlConflictShard
+}
+
+type ttlConflictShard struct {
+	_    noCopy
+	mu   sync.Mutex
+	ttls map[string]time.Duration
+}
+
+func newTTLConflictTracker(policy TTLConflictPolicy) *ttlConflictTracker {
+	shards := make([]ttlConflictShard, shardCount)
+	for i := range shards {
+		shards[i].ttls = make(map[string]time.Duration)
+	}
+
+	return &ttlConflictTracker{policy: policy, shards: shards}
+}
+
+// WithTTLConflictPolicy makes GetOrLoad detect when the same key is requested
+// with different ttls and resolve the ttl according to policy. Conflicts are
+// reported through MetricsProvider.RecordTTLConflict and logged at debug
+// level. Ttls are remembered for a bounded number of recent keys.
+func WithTTLConflictPolicy[V any, S any](policy TTLConflictPolicy) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.ttlConflicts = newTTLConflictTracker(policy)
+	}
+}
+
+// resolve records ttl for key and returns the ttl to use and whether it
+// conflicted with the remembered one.
+func (t *ttlConflictTracker) resolve(key string, ttl time.Duration) (time.Duration, bool, error) {
+	shard := &t.shards[hashKey(key)%uint64(len(t.shards))]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	prev, ok := shard.ttls[key]
+	if !ok {
+		if len(shard.ttls) >= maxTrackedTTLsPerShard {
+			for k := range shard.ttls {
+				delete(shard.ttls, k)
+
+				break
+			}
+		}
+		shard.ttls[key] = ttl
+
+		return ttl, false, nil
+	}
+	if prev == ttl {
+		return ttl, false, nil
+	}
+
+	switch t.policy {
+	case TTLConflictMaxWins:
+		resolved := max(prev, ttl)
+		shard.ttls[key] = resolved
+
+		return resolved, true, nil
+	case TTLConflictFirstWins:
+		return prev, true, nil
+	case TTLConflictError:
+		return ttl, true, fmt.Errorf("%w: %q requested with %s, previously %s", ErrTTLConflict, key, ttl, prev)
+	default:
+		shard.ttls[key] = ttl
+
+		return ttl, true, nil
+	}
+}
+
+// resolveTTL applies the TTL conflict policy to a GetOrLoad call.
+func (c *cacheImpl[V, S]) resolveTTL(ctx context.Context, key string, ttl time.Duration) (time.Duration, error) {
+	if c.ttlConflicts == nil {
+		return ttl, nil
+	}
+	resolved, conflict, err := c.ttlConflicts.resolve(key, ttl)
+	if conflict {
+		c.metrics.RecordTTLConflict(ctx)
+		c.logger.Debug("conflicting ttl for cache key",
+			slog.String("key", key),
+			slog.Duration("ttl", ttl),
+			slog.Duration("resolved", resolved),
+			slog.String("policy", c.ttlConflicts.policy.String()),
+		)
+	}
+
+	return resolved, err
+}
@@ -0,0 +1,87 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type ttlConflictMetricsProvider struct {
+	BaseMetricsProvider
+	conflicts atomic.Int32
+}
+
+func (m *ttlConflictMetricsProvider) RecordTTLConflict(context.Context) {
+	m.conflicts.Add(1)
+}
+
+func TestWithTTLConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		policy      TTLConflictPolicy
+		expectedTTL time.Duration
+		expectErr   bool
+	}{
+		{policy: TTLConflictLastWins, expectedTTL: time.Minute},
+		{policy: TTLConflictMaxWins, expectedTTL: time.Hour},
+		{policy: TTLConflictFirstWins, expectedTTL: time.Hour},
+		{policy: TTLConflictError, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			t.Parallel()
+
+			metrics := &ttlConflictMetricsProvider{}
+			provider := &testMemoryProvider[int]{items: make(map[string]CacheObject[int])}
+			now := time.UnixMilli(1_000_000)
+			cache := NewCache(provider, NoopCacheStorageCodec[int]{},
+				WithTTLConflictPolicy[int, CacheObject[int]](tt.policy),
+				WithMetricsProvider[int, CacheObject[int]](metrics),
+			)
+			cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return now }
+			loader := func(context.Context) (int, error) { return 1, nil }
+			ctx := context.Background()
+
+			if _, err := cache.GetOrLoad(ctx, "key", time.Hour, loader); err != nil {
+				t.Fatalf("first load: %v", err)
+			}
+			delete(provider.items, "key")
+			_, err := cache.GetOrLoad(ctx, "key", time.Minute, loader)
+			if metrics.conflicts.Load() != 1 {
+				t.Fatalf("expected 1 conflict, got %d", metrics.conflicts.Load())
+			}
+			if tt.expectErr {
+				if !errors.Is(err, ErrTTLConflict) {
+					t.Fatalf("expected ErrTTLConflict, got %v", err)
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("second load: %v", err)
+			}
+			if got := provider.items["key"].ExpireAtMillis; got != now.Add(tt.expectedTTL).UnixMilli() {
+				t.Fatalf("expected expiry %d, got %d", now.Add(tt.expectedTTL).UnixMilli(), got)
+			}
+		})
+	}
+}
+
+func TestTTLConflictTracker_Bounded(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTTLConflictTracker(TTLConflictFirstWins)
+	for i := range shardCount * (maxTrackedTTLsPerShard + 1) {
+		if _, _, err := tracker.resolve(HashedKey(i), time.Second); err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+	}
+	for i := range tracker.shards {
+		if n := len(tracker.shards[i].ttls); n > maxTrackedTTLsPerShard {
+			t.Fatalf("expected at most %d keys per shard, got %d", maxTrackedTTLsPerShard, n)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrLoadedValueTooLarge is matched by LoadedValueCostError.
+var ErrLoadedValueTooLarge = errors.New("loaded value too large")
+
+// LoadedValueCostError is returned by GetOrLoad when the loader result
+// exceeds the cost limit configured with WithMaxLoadedValueCost.
+type LoadedValueCostError struct {
+	// Key is the key the value was loaded for.
+	Key string
+	// Cost is the cost of the rejected value.
+	Cost int
+	// Max is the configured limit.
+	Max int
+}
+
+// Error describes the rejected value.
+func (e *LoadedValueCostError) Error() string {
+	return fmt.Sprintf("%s: %q costs %d, max %d", ErrLoadedValueTooLarge, e.Key, e.Cost, e.Max)
+}
+
+// Unwrap returns ErrLoadedValueTooLarge.
+func (e *LoadedValueCostError) Unwrap() error {
+	return ErrLoadedValueTooLarge
+}
+
+// loadedValueCost limits the cost of loader results.
+type loadedValueCost[V any] struct {
+	cost func(value V) int
+	max  int
+}
+
+// WithMaxLoadedValueCost rejects loader results whose cost, such as a byte
+// size or an element count, exceeds maxCost. The load then fails with a
+// *LoadedValueCostError before the value is encoded or stored, so a
+// pathological upstream response neither fills the cache nor reaches callers
+// waiting on the load. cost runs once per load, on the loading goroutine. A
+// nil cost or a non-positive maxCost disables the limit.
+func WithMaxLoadedValueCost[V any, S any](cost func(value V) int, maxCost int) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if cost == nil || maxCost <= 0 {
+			c.loadedValueCost = nil
+
+			return
+		}
+		c.loadedValueCost = &loadedValueCost[V]{cost: cost, max: maxCost}
+	}
+}
+
+// guardLoadedCost wraps loader so that results above the configured cost are
+// rejected.
+func (c *cacheImpl[V, S]) guardLoadedCost(key string, loader CacheLoadFunc[V]) CacheLoadFunc[V] {
+	guard := c.loadedValueCost
+	if guard == nil {
+		return loader
+	}
+
+	return func(ctx context.Context) (V, error) {
+		v, err := loader(ctx)
+		if err != nil {
+			return v, err
+		}
+		if cost := guard.cost(v); cost > guard.max {
+			var zero V
+
+			return zero, &LoadedValueCostError{Key: key, Cost: cost, Max: guard.max}
+		}
+
+		return v, nil
+	}
+}
@@ -0,0 +1,34 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithMaxLoadedValueCost_RejectsLargeValues(t *testing.T) {
+	t.Parallel()
+
+	provider := &testMemoryProvider[string]{items: make(map[string]CacheObject[string])}
+	cache := NewCache(provider, NoopCacheStorageCodec[string]{},
+		WithMaxLoadedValueCost[string, CacheObject[string]](func(v string) int { return len(v) }, 4),
+	)
+	ctx := context.Background()
+
+	_, err := cache.GetOrLoad(ctx, "big", time.Minute, func(context.Context) (string, error) { return "too large", nil })
+	if !errors.Is(err, ErrLoadedValueTooLarge) {
+		t.Fatalf("expected ErrLoadedValueTooLarge, got %v", err)
+	}
+	var costErr *LoadedValueCostError
+	if !errors.As(err, &costErr) || costErr.Key != "big" || costErr.Cost != 9 || costErr.Max != 4 {
+		t.Fatalf("expected cost error for big costing 9, got %v", err)
+	}
+	if _, found, _ := cache.Get(ctx, "big"); found {
+		t.Fatal("expected rejected value not to be stored")
+	}
+
+	if v, err := cache.GetOrLoad(ctx, "small", time.Minute, func(context.Context) (string, error) { return "ok", nil }); err != nil || v != "ok" {
+		t.Fatalf("expected ok, got %q, %v", v, err)
+	}
+}
@@ -0,0 +1,73 @@
+package crema
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+type doubleReadVerifier[V any, S any] struct {
+	secondary  CacheProvider[S]
+	sampleRate float64
+	equal      func(primary V, secondary V) bool
+}
+
+// WithDoubleReadVerification reads a sampled fraction of cache hits from
+// secondary as well and compares the decoded values, to build confidence
+// before cutting over between storage backends. Both providers must hold
+// entries encoded by the cache codec. Mismatches, including entries missing
+// from secondary, are reported through MetricsProvider.RecordDoubleReadMismatch
+// and logged as warnings. A nil equal falls back to reflect.DeepEqual.
+// sampleRate is clamped to [0, 1].
+func WithDoubleReadVerification[V any, S any](secondary CacheProvider[S], sampleRate float64, equal func(primary V, secondary V) bool) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		if secondary == nil {
+			c.doubleRead = nil
+
+			return
+		}
+		if equal == nil {
+			equal = func(a V, b V) bool { return reflect.DeepEqual(a, b) }
+		}
+		c.doubleRead = &doubleReadVerifier[V, S]{
+			secondary:  secondary,
+			sampleRate: min(max(sampleRate, 0), 1),
+			equal:      equal,
+		}
+	}
+}
+
+// verifyDoubleRead schedules a sampled comparison of a primary hit against the
+// secondary provider.
+func (c *cacheImpl[V, S]) verifyDoubleRead(ctx context.Context, key string, primary CacheObject[V]) {
+	if c.doubleRead == nil || !c.sampled(c.doubleRead.sampleRate) {
+		return
+	}
+	verifier := c.doubleRead
+	c.runner.goDetached(ctx, func(ctx context.Context) error {
+		rv, exists, err := verifier.secondary.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("double read %q: %w", key, err)
+		}
+		if !exists {
+			c.reportDoubleReadMismatch(ctx, key, "missing")
+
+			return nil
+		}
+		secondary, err := c.decode(key, rv)
+		if err != nil {
+			return fmt.Errorf("double read decode %q: %w", key, err)
+		}
+		if !verifier.equal(primary.Value, secondary.Value) {
+			c.reportDoubleReadMismatch(ctx, key, "value")
+		}
+
+		return nil
+	})
+}
+
+func (c *cacheImpl[V, S]) reportDoubleReadMismatch(ctx context.Context, key string, reason string) {
+	c.metrics.RecordDoubleReadMismatch(ctx)
+	c.logger.Warn("double read mismatch", slog.String("key", key), slog.String("reason", reason))
+}
@@ -0,0 +1,85 @@
+package crema
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type doubleReadMetricsProvider struct {
+	BaseMetricsProvider
+	mismatches atomic.Int32
+}
+
+func (m *doubleReadMetricsProvider) RecordDoubleReadMismatch(context.Context) {
+	m.mismatches.Add(1)
+}
+
+func TestWithDoubleReadVerification_ReportsMismatches(t *testing.T) {
+	t.Parallel()
+
+	primary := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"same":    {Value: 1, ExpireAtMillis: 5000},
+		"differs": {Value: 2, ExpireAtMillis: 5000},
+		"missing": {Value: 3, ExpireAtMillis: 5000},
+	}}
+	secondary := &testMemoryProvider[int]{items: map[string]CacheObject[int]{
+		"same":    {Value: 1, ExpireAtMillis: 5000},
+		"differs": {Value: 20, ExpireAtMillis: 5000},
+	}}
+	metrics := &doubleReadMetricsProvider{}
+	var logs bytes.Buffer
+	cache := NewCache(primary, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithLogger[int, CacheObject[int]](slog.New(slog.NewTextHandler(&logs, nil))),
+		WithDoubleReadVerification[int, CacheObject[int]](secondary, 1, nil),
+	)
+	cache.(*cacheImpl[int, CacheObject[int]]).now = func() time.Time { return time.UnixMilli(1000) }
+	ctx := context.Background()
+
+	for _, key := range []string{"same", "differs", "missing"} {
+		if _, ok, err := cache.Get(ctx, key); err != nil || !ok {
+			t.Fatalf("get %s: ok=%v err=%v", key, ok, err)
+		}
+	}
+	if err := cache.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	if got := metrics.mismatches.Load(); got != 2 {
+		t.Fatalf("expected 2 mismatches, got %d", got)
+	}
+	output := logs.String()
+	if !strings.Contains(output, "key=differs reason=value") || !strings.Contains(output, "key=missing reason=missing") {
+		t.Fatalf("expected mismatch logs, got %q", output)
+	}
+	if strings.Contains(output, "key=same") {
+		t.Fatalf("expected no log for matching entry, got %q", output)
+	}
+}
+
+func TestWithDoubleReadVerification_UsesEqualFunc(t *testing.T) {
+	t.Parallel()
+
+	primary := &testMemoryProvider[int]{items: map[string]CacheObject[int]{"key": {Value: 1, ExpireAtMillis: 5000}}}
+	secondary := &testMemoryProvider[int]{items: map[string]CacheObject[int]{"key": {Value: 2, ExpireAtMillis: 5000}}}
+	metrics := &doubleReadMetricsProvider{}
+	cache := NewCache(primary, NoopCacheStorageCodec[int]{},
+		WithMetricsProvider[int, CacheObject[int]](metrics),
+		WithDoubleReadVerification[int, CacheObject[int]](secondary, 1, func(int, int) bool { return true }),
+	)
+
+	if _, _, err := cache.Get(context.Background(), "key"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if err := cache.Flush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if got := metrics.mismatches.Load(); got != 0 {
+		t.Fatalf("expected no mismatches, got %d", got)
+	}
+}
@@ -0,0 +1,156 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	windowMinuteLayout = "2006-01-02T15:04"
+	windowSecondLayout = "2006-01-02T15:04:05"
+	// maxWindowBuckets caps the buckets a single Buckets call reads.
+	maxWindowBuckets = 10_000
+)
+
+// ErrWindowTooWide is returned by Buckets when the requested range spans more
+// buckets than a single call reads.
+var ErrWindowTooWide = errors.New("window range spans too many buckets")
+
+// WindowBucket is the cached value of one time bucket.
+type WindowBucket[V any] struct {
+	// Start is the UTC start of the bucket.
+	Start time.Time
+	// Value is the cached value of the bucket.
+	Value V
+}
+
+// WindowedCache stores values per fixed-width time bucket under keys such as
+// "key@2024-06-01T10:05", for rolling aggregates like per-minute metrics and
+// leaderboards. Each bucket is kept for retention bucket widths after it
+// starts.
+type WindowedCache[V any, S any] struct {
+	cache     Cache[V, S]
+	width     time.Duration
+	retention int
+	now       func() time.Time
+}
+
+// NewWindowedCache wraps cache with buckets of the given width that are kept
+// for retention buckets. Non-positive widths default to one minute and
+// non-positive retentions to one bucket.
+func NewWindowedCache[V any, S any](cache Cache[V, S], width time.Duration, retention int) *WindowedCache[V, S] {
+	if width <= 0 {
+		width = time.Minute
+	}
+
+	return &WindowedCache[V, S]{
+		cache:     cache,
+		width:     width,
+		retention: max(retention, 1),
+		now:       time.Now,
+	}
+}
+
+// Bucket returns the UTC start of the bucket containing at.
+func (w *WindowedCache[V, S]) Bucket(at time.Time) time.Time {
+	return at.UTC().Truncate(w.width)
+}
+
+// BucketKey returns the cache key of the bucket containing at. Buckets are
+// formatted to the minute when the width is a whole number of minutes, to the
+// second when it is a whole number of seconds, and as Unix milliseconds
+// otherwise so that sub-second buckets never share a key.
+func (w *WindowedCache[V, S]) BucketKey(key string, at time.Time) string {
+	start := w.Bucket(at)
+	switch {
+	case w.width%time.Minute == 0:
+		return key + "@" + start.Format(windowMinuteLayout)
+	case w.width%time.Second == 0:
+		return key + "@" + start.Format(windowSecondLayout)
+	default:
+		return key + "@" + strconv.FormatInt(start.UnixMilli(), 10)
+	}
+}
+
+// expireAt returns when the bucket containing at leaves the retention window.
+func (w *WindowedCache[V, S]) expireAt(at time.Time) time.Time {
+	return w.Bucket(at).Add(w.width * time.Duration(w.retention))
+}
+
+// Get returns the cached value of the bucket containing at.
+func (w *WindowedCache[V, S]) Get(ctx context.Context, key string, at time.Time) (V, bool, error) {
+	co, ok, err := w.cache.Get(ctx, w.BucketKey(key, at))
+	if err != nil || !ok {
+		var zero V
+
+		return zero, false, err
+	}
+
+	return co.Value, true, nil
+}
+
+// Set stores value for the bucket containing at, expiring when the bucket
+// leaves the retention window.
+func (w *WindowedCache[V, S]) Set(ctx context.Context, key string, at time.Time, value V) error {
+	return w.cache.Set(ctx, w.BucketKey(key, at), CacheObject[V]{
+		Value:          value,
+		ExpireAtMillis: w.expireAt(at).UnixMilli(),
+	})
+}
+
+// GetOrLoad returns the cached value of the bucket containing at or loads it.
+// Buckets already outside the retention window are loaded without caching.
+func (w *WindowedCache[V, S]) GetOrLoad(ctx context.Context, key string, at time.Time, loader CacheLoadFunc[V]) (V, error) {
+	ttl := w.expireAt(at).Sub(w.now())
+	if ttl <= 0 {
+		return loader(ctx)
+	}
+
+	return w.cache.GetOrLoad(ctx, w.BucketKey(key, at), ttl, loader)
+}
+
+// Buckets returns the cached buckets from the bucket containing from through
+// the bucket containing to, oldest first. Missing buckets are skipped, and the
+// range starts no earlier than the oldest bucket still in the retention
+// window. Ranges spanning more buckets than a single call reads return
+// ErrWindowTooWide.
+func (w *WindowedCache[V, S]) Buckets(ctx context.Context, key string, from time.Time, to time.Time) ([]WindowBucket[V], error) {
+	first := w.Bucket(from)
+	if oldest := w.Bucket(w.now()).Add(-w.width * time.Duration(w.retention-1)); first.Before(oldest) {
+		first = oldest
+	}
+	last := w.Bucket(to)
+	if last.Sub(first)/w.width >= maxWindowBuckets {
+		return nil, ErrWindowTooWide
+	}
+
+	var buckets []WindowBucket[V]
+	for start := first; !start.After(last); start = start.Add(w.width) {
+		value, ok, err := w.Get(ctx, key, start)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			buckets = append(buckets, WindowBucket[V]{Start: start, Value: value})
+		}
+	}
+
+	return buckets, nil
+}
+
+// AggregateWindow folds the cached buckets between from and to into a single
+// result, oldest first, starting from init.
+func AggregateWindow[V any, S any, A any](ctx context.Context, w *WindowedCache[V, S], key string, from time.Time, to time.Time, init A, fn func(acc A, bucket WindowBucket[V]) A) (A, error) {
+	buckets, err := w.Buckets(ctx, key, from, to)
+	if err != nil {
+		return init, err
+	}
+	acc := init
+	for _, bucket := range buckets {
+		acc = fn(acc, bucket)
+	}
+
+	return acc, nil
+}
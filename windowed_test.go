@@ -0,0 +1,125 @@
+package crema
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWindowedCache_BucketKey(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{})
+	at := time.Date(2024, 6, 1, 10, 5, 42, 0, time.UTC)
+
+	minutes := NewWindowedCache[int, CacheObject[int]](cache, 5*time.Minute, 3)
+	if got := minutes.BucketKey("views", at); got != "views@2024-06-01T10:05" {
+		t.Fatalf("unexpected minute bucket key %q", got)
+	}
+	if got := minutes.BucketKey("views", at.Add(4*time.Minute)); got != "views@2024-06-01T10:05" {
+		t.Fatalf("expected alignment to the bucket start, got %q", got)
+	}
+
+	seconds := NewWindowedCache[int, CacheObject[int]](cache, 30*time.Second, 3)
+	if got := seconds.BucketKey("views", at); got != "views@2024-06-01T10:05:30" {
+		t.Fatalf("unexpected second bucket key %q", got)
+	}
+
+	millis := NewWindowedCache[int, CacheObject[int]](cache, 500*time.Millisecond, 3)
+	first := millis.BucketKey("views", at)
+	second := millis.BucketKey("views", at.Add(500*time.Millisecond))
+	if first == second {
+		t.Fatalf("expected distinct sub-second bucket keys, got %q twice", first)
+	}
+	if expected := "views@" + strconv.FormatInt(at.UnixMilli(), 10); first != expected {
+		t.Fatalf("expected %q, got %q", expected, first)
+	}
+}
+
+func TestWindowedCache_BucketsBoundsRange(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCache(NewMemoryCacheProvider[CacheObject[int]](), NoopCacheStorageCodec[int]{})
+	now := time.Now().UTC().Truncate(time.Minute)
+	windowed := NewWindowedCache[int, CacheObject[int]](cache, time.Minute, 2)
+	windowed.now = func() time.Time { return now }
+	cache.(*cacheImpl[int, CacheObject[int]]).now = windowed.now
+	ctx := context.Background()
+
+	loads := 0
+	counting := &countingGetCache[int]{Cache: cache, gets: &loads}
+	windowed.cache = counting
+	if err := windowed.Set(ctx, "hits", now, 1); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	buckets, err := windowed.Buckets(ctx, "hits", time.Unix(0, 0), now)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(buckets) != 1 {
+		t.Fatalf("expected 1 bucket, got %d", len(buckets))
+	}
+	if loads != 2 {
+		t.Fatalf("expected reads limited to the 2 retained buckets, got %d", loads)
+	}
+
+	if _, err := windowed.Buckets(ctx, "hits", now, now.Add(maxWindowBuckets*time.Minute)); !errors.Is(err, ErrWindowTooWide) {
+		t.Fatalf("expected ErrWindowTooWide, got %v", err)
+	}
+}
+
+type countingGetCache[V any] struct {
+	Cache[V, CacheObject[V]]
+	gets *int
+}
+
+func (c *countingGetCache[V]) Get(ctx context.Context, key string) (CacheObject[V], bool, error) {
+	*c.gets++
+
+	return c.Cache.Get(ctx, key)
+}
+
+func TestWindowedCache_TTLAndAggregate(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[CacheObject[int]]()
+	cache := NewCache(provider, NoopCacheStorageCodec[int]{})
+	now := time.Now().UTC().Truncate(time.Minute)
+	windowed := NewWindowedCache[int, CacheObject[int]](cache, time.Minute, 2)
+	windowed.now = func() time.Time { return now }
+	cache.(*cacheImpl[int, CacheObject[int]]).now = windowed.now
+	ctx := context.Background()
+
+	for i, value := range []int{1, 2, 4} {
+		at := now.Add(time.Duration(i-2) * time.Minute)
+		if _, err := windowed.GetOrLoad(ctx, "hits", at, func(context.Context) (int, error) {
+			return value, nil
+		}); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	}
+	// the oldest bucket is outside the retention window and is not cached
+	if provider.Len() != 2 {
+		t.Fatalf("expected 2 cached buckets, got %d", provider.Len())
+	}
+	co, ok, _ := cache.Get(ctx, windowed.BucketKey("hits", now))
+	if !ok {
+		t.Fatal("expected current bucket to be cached")
+	}
+	if expected := now.Add(2 * time.Minute).UnixMilli(); co.ExpireAtMillis != expected {
+		t.Fatalf("expected expiry %d, got %d", expected, co.ExpireAtMillis)
+	}
+
+	sum, err := AggregateWindow(ctx, windowed, "hits", now.Add(-2*time.Minute), now, 0, func(acc int, bucket WindowBucket[int]) int {
+		return acc + bucket.Value
+	})
+	if err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if sum != 6 {
+		t.Fatalf("expected sum 6, got %d", sum)
+	}
+}
@@ -0,0 +1,199 @@
+package crema
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// writerHeaderMagic starts payloads carrying a writer header. No codec in
+// this module produces payloads starting with it, and its first byte is
+// reserved so that RegisterCompression cannot claim it.
+var writerHeaderMagic = []byte{0xc3, 'w', 1}
+
+// ErrMalformedWriterHeader is returned when a payload starts like a writer
+// header but cannot be parsed.
+var ErrMalformedWriterHeader = errors.New("malformed writer header")
+
+// WriterIdentity identifies the deployment that wrote a cache entry.
+type WriterIdentity struct {
+	// Service is the name of the writing service.
+	Service string
+	// Pod is the instance that wrote the entry, such as a pod or host name.
+	Pod string
+	// Build identifies the writing build, such as a commit hash.
+	Build string
+}
+
+// EntryInfo describes a stored entry as returned by Inspect.
+type EntryInfo struct {
+	// Key is the inspected key.
+	Key string
+	// ExpireAtMillis is the logical expiry of the entry.
+	ExpireAtMillis int64
+	// Writer identifies who wrote the entry when HasWriter is true.
+	Writer WriterIdentity
+	// WrittenAt is when the writer stored the entry, by its clock.
+	WrittenAt time.Time
+	// HasWriter reports whether the entry was written by a cache configured
+	// with WithWriterIdentity.
+	HasWriter bool
+}
+
+// WithWriterIdentity prefixes every []byte payload the cache writes with
+// writer and the write time, so Inspect can tell which deployment wrote a
+// bad entry in a shared backend. The header wraps the configured codec,
+// including compression, so it stays readable in the backend. Payloads
+// without a header are still decoded, but instances without this option
+// cannot decode payloads with one: enable it on every instance reading the
+// keys before any of them writes. It has no effect on caches whose storage
+// type is not []byte.
+func WithWriterIdentity[V any, S any](writer WriterIdentity) CacheOption[V, S] {
+	return func(c *cacheImpl[V, S]) {
+		c.writerIdentity = &writer
+	}
+}
+
+// writerCodec prefixes payloads of inner with a writer header.
+type writerCodec[V any] struct {
+	inner  CacheStorageCodec[V, []byte]
+	writer WriterIdentity
+	now    func() time.Time
+}
+
+var (
+	_ CacheStorageCodec[any, []byte]      = &writerCodec[any]{}
+	_ KeyedCacheStorageCodec[any, []byte] = &writerCodec[any]{}
+	_ BufferReleasePolicy                 = &writerCodec[any]{}
+)
+
+// withWriterHeader wraps codec with a writerCodec when it stores []byte.
+func withWriterHeader[V any, S any](codec CacheStorageCodec[V, S], writer WriterIdentity, now func() time.Time) CacheStorageCodec[V, S] {
+	inner, ok := any(codec).(CacheStorageCodec[V, []byte])
+	if !ok {
+		return codec
+	}
+
+	return any(&writerCodec[V]{inner: inner, writer: writer, now: now}).(CacheStorageCodec[V, S])
+}
+
+func (w *writerCodec[V]) Encode(value CacheObject[V]) ([]byte, error) {
+	return w.EncodeKeyed("", value)
+}
+
+// EncodeKeyed encodes value with the inner codec behind the writer header.
+func (w *writerCodec[V]) EncodeKeyed(key string, value CacheObject[V]) ([]byte, error) {
+	data, err := encodeKeyed(w.inner, key, value)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, len(writerHeaderMagic)+binary.MaxVarintLen64+3*binary.MaxVarintLen16+
+		len(w.writer.Service)+len(w.writer.Pod)+len(w.writer.Build)+len(data))
+	buf = append(buf, writerHeaderMagic...)
+	buf = binary.AppendVarint(buf, w.now().UnixMilli())
+	for _, field := range []string{w.writer.Service, w.writer.Pod, w.writer.Build} {
+		buf = binary.AppendUvarint(buf, uint64(len(field)))
+		buf = append(buf, field...)
+	}
+
+	return append(buf, data...), nil
+}
+
+func (w *writerCodec[V]) Decode(data []byte) (CacheObject[V], error) {
+	return w.DecodeKeyed("", data)
+}
+
+// DecodeKeyed strips the writer header, if any, and decodes the rest with
+// the inner codec.
+func (w *writerCodec[V]) DecodeKeyed(key string, data []byte) (CacheObject[V], error) {
+	_, payload, _, err := parseWriterHeader(data)
+	if err != nil {
+		return CacheObject[V]{}, err
+	}
+
+	return decodeKeyed(w.inner, key, payload)
+}
+
+// CanReleaseBufferOnDecode forwards the buffer release policy of the inner codec.
+func (w *writerCodec[V]) CanReleaseBufferOnDecode() bool {
+	if policy, ok := any(w.inner).(BufferReleasePolicy); ok {
+		return policy.CanReleaseBufferOnDecode()
+	}
+
+	return false
+}
+
+// stripWriterHeader returns data without its writer header, if any.
+func stripWriterHeader(data []byte) []byte {
+	_, payload, _, err := parseWriterHeader(data)
+	if err != nil {
+		return data
+	}
+
+	return payload
+}
+
+// parseWriterHeader splits data into the writer header and the payload
+// behind it. ok is false, and payload is data, when there is no header.
+func parseWriterHeader(data []byte) (info EntryInfo, payload []byte, ok bool, err error) {
+	if !bytes.HasPrefix(data, writerHeaderMagic) {
+		return EntryInfo{}, data, false, nil
+	}
+	rest := data[len(writerHeaderMagic):]
+	writtenAt, n := binary.Varint(rest)
+	if n <= 0 {
+		return EntryInfo{}, nil, false, ErrMalformedWriterHeader
+	}
+	rest = rest[n:]
+	var fields [3]string
+	for i := range fields {
+		size, n := binary.Uvarint(rest)
+		if n <= 0 || uint64(len(rest)-n) < size {
+			return EntryInfo{}, nil, false, ErrMalformedWriterHeader
+		}
+		fields[i] = string(rest[n : n+int(size)])
+		rest = rest[n+int(size):]
+	}
+	info = EntryInfo{
+		Writer:    WriterIdentity{Service: fields[0], Pod: fields[1], Build: fields[2]},
+		WrittenAt: time.UnixMilli(writtenAt),
+		HasWriter: true,
+	}
+
+	return info, rest, true, nil
+}
+
+// Inspect returns the metadata of the entry stored for key without serving
+// it: its expiry and, for entries written with WithWriterIdentity, the
+// writer and write time. Expired entries still held by the provider are
+// reported too. It reports false when key is not stored.
+func (c *cacheImpl[V, S]) Inspect(ctx context.Context, key string) (EntryInfo, bool, error) {
+	ctx = c.metricsContext(ctx)
+
+	started := time.Now()
+	rv, exists, err := c.provider.Get(ctx, key)
+	if err != nil {
+		return EntryInfo{}, false, c.observeError(ctx, ErrorPhaseProviderGet, key, started, err)
+	}
+	if !exists {
+		return EntryInfo{}, false, nil
+	}
+
+	started = time.Now()
+	var info EntryInfo
+	if data, ok := any(rv).([]byte); ok {
+		if info, _, _, err = parseWriterHeader(data); err != nil {
+			return EntryInfo{}, false, c.observeError(ctx, ErrorPhaseDecode, key, started, err)
+		}
+	}
+	co, err := c.decode(key, rv)
+	if err != nil {
+		return EntryInfo{}, false, c.observeError(ctx, ErrorPhaseDecode, key, started, err)
+	}
+	info.Key = key
+	info.ExpireAtMillis = co.ExpireAtMillis
+
+	return info, true, nil
+}
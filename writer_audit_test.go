@@ -0,0 +1,68 @@
+package crema
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCache_InspectReportsWriter(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	writer := WriterIdentity{Service: "api", Pod: "api-7d9f", Build: "abc123"}
+	cache := NewCache(provider, NewBinaryCompressionCodec[string](JSONByteStringCodec[string]{}, 16),
+		WithWriterIdentity[string, []byte](writer),
+	)
+	now := time.UnixMilli(1_000_000)
+	cache.(*cacheImpl[string, []byte]).now = func() time.Time { return now }
+	ctx := context.Background()
+	expireAt := now.Add(time.Minute).UnixMilli()
+	if err := cache.Set(ctx, "key", CacheObject[string]{Value: "a value long enough to compress", ExpireAtMillis: expireAt}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	info, ok, err := cache.Inspect(ctx, "key")
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	if !ok || !info.HasWriter {
+		t.Fatalf("expected entry with writer, got %+v, %v", info, ok)
+	}
+	if info.Writer != writer {
+		t.Fatalf("expected writer %+v, got %+v", writer, info.Writer)
+	}
+	if !info.WrittenAt.Equal(now) || info.ExpireAtMillis != expireAt || info.Key != "key" {
+		t.Fatalf("unexpected entry info: %+v", info)
+	}
+	co, ok, err := cache.Get(ctx, "key")
+	if err != nil || !ok || co.Value != "a value long enough to compress" {
+		t.Fatalf("expected value to decode behind the header, got %+v, %v, %v", co, ok, err)
+	}
+}
+
+func TestCache_InspectWithoutWriter(t *testing.T) {
+	t.Parallel()
+
+	provider := NewMemoryCacheProvider[[]byte]()
+	plain := NewCache(provider, JSONByteStringCodec[string]{})
+	audited := NewCache(provider, JSONByteStringCodec[string]{}, WithWriterIdentity[string, []byte](WriterIdentity{Service: "api"}))
+	ctx := context.Background()
+	if err := plain.Set(ctx, "key", CacheObject[string]{Value: "v", ExpireAtMillis: time.Now().Add(time.Minute).UnixMilli()}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	info, ok, err := audited.Inspect(ctx, "key")
+	if err != nil || !ok {
+		t.Fatalf("inspect: %v, %v", ok, err)
+	}
+	if info.HasWriter {
+		t.Fatalf("expected entry without writer, got %+v", info)
+	}
+	if _, ok, err := audited.Get(ctx, "key"); err != nil || !ok {
+		t.Fatalf("expected entry without header to decode, got %v, %v", ok, err)
+	}
+	if _, ok, _ := audited.Inspect(ctx, "missing"); ok {
+		t.Fatal("expected missing key to be reported")
+	}
+}